@@ -1,3 +1,7 @@
+// Command check_tournament_fields lists the labels found in FIDE tournament
+// details pages. It is kept for reference; src/scraper/fields_report.py does the
+// same through the scraper's fetcher and rate limiter, and can also read archived
+// raw HTML offline.
 package main
 
 import (
@@ -8,7 +12,7 @@ import (
 	"log"
 	"net/http"
 	"os"
-	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -28,6 +32,7 @@ func main() {
 		inputFile   = flag.String("input", "", "Path to file containing tournament IDs (one per line)")
 		maxCheck    = flag.Int("max", 100, "Maximum number of tournaments to check (0 = all)")
 		concurrency = flag.Int("concurrency", 5, "Maximum number of concurrent requests")
+		outputFile  = flag.String("output", "", "Write the fields JSON to this file (default: stdout)")
 	)
 	flag.Parse()
 
@@ -114,12 +119,14 @@ func main() {
 
 	wg.Wait()
 
-	// Print results
-	fmt.Println("\n" + strings.Repeat("=", 80))
-	fmt.Println("FIELDS FOUND IN TOURNAMENT PAGES")
-	fmt.Println(strings.Repeat("=", 80))
-	fmt.Printf("\nTotal tournaments checked: %d\n", len(tournamentIDs))
-	fmt.Printf("Total unique fields found: %d\n\n", len(fieldsMap))
+	// Print the human-readable report to stderr; stdout carries only the JSON
+	// (when no -output file is given), so the tool can be piped into jq etc.
+	report := os.Stderr
+	fmt.Fprintln(report, "\n"+strings.Repeat("=", 80))
+	fmt.Fprintln(report, "FIELDS FOUND IN TOURNAMENT PAGES")
+	fmt.Fprintln(report, strings.Repeat("=", 80))
+	fmt.Fprintf(report, "\nTotal tournaments checked: %d\n", len(tournamentIDs))
+	fmt.Fprintf(report, "Total unique fields found: %d\n\n", len(fieldsMap))
 
 	// Sort fields by count (most common first)
 	type fieldEntry struct {
@@ -137,24 +144,23 @@ func main() {
 		})
 	}
 
-	// Simple sort by count (descending)
-	for i := 0; i < len(sortedFields)-1; i++ {
-		for j := i + 1; j < len(sortedFields); j++ {
-			if sortedFields[i].Count < sortedFields[j].Count {
-				sortedFields[i], sortedFields[j] = sortedFields[j], sortedFields[i]
-			}
+	// Sort by count (descending), then name, so the output is deterministic
+	sort.Slice(sortedFields, func(i, j int) bool {
+		if sortedFields[i].Count != sortedFields[j].Count {
+			return sortedFields[i].Count > sortedFields[j].Count
 		}
-	}
+		return sortedFields[i].Name < sortedFields[j].Name
+	})
 
 	// Print fields
 	for _, entry := range sortedFields {
-		fmt.Printf("Field: %-35s | Count: %4d/%d", entry.Name, entry.Info.Count, len(tournamentIDs))
+		fmt.Fprintf(report, "Field: %-35s | Count: %4d/%d", entry.Name, entry.Info.Count, len(tournamentIDs))
 		if entry.Info.HasLinks {
-			fmt.Print(" | Has Links: YES")
+			fmt.Fprint(report, " | Has Links: YES")
 		}
-		fmt.Println()
+		fmt.Fprintln(report)
 		if len(entry.Info.SampleValues) > 0 {
-			fmt.Printf("  Sample values:\n")
+			fmt.Fprintf(report, "  Sample values:\n")
 			for _, val := range entry.Info.SampleValues {
 				// Truncate long values
 				displayVal := val
@@ -168,35 +174,32 @@ func main() {
 				displayVal = strings.ReplaceAll(displayVal, "</strong>", "")
 				displayVal = strings.ReplaceAll(displayVal, "<a ", "[LINK]")
 				displayVal = strings.ReplaceAll(displayVal, "</a>", "")
-				fmt.Printf("    - %s\n", displayVal)
+				fmt.Fprintf(report, "    - %s\n", displayVal)
 			}
 		}
-		fmt.Println()
+		fmt.Fprintln(report)
 	}
 
-	// Save to JSON file
+	// Write JSON to -output, or to stdout
 	outputData := make(map[string]interface{})
 	outputData["total_tournaments_checked"] = len(tournamentIDs)
 	outputData["total_unique_fields"] = len(fieldsMap)
 	outputData["fields"] = fieldsMap
 
-	outputFile := strings.TrimSuffix(*inputFile, filepath.Base(*inputFile)) + "tournament_fields.json"
-	if strings.HasSuffix(*inputFile, ".txt") {
-		outputFile = strings.TrimSuffix(*inputFile, ".txt") + "_fields.json"
-	} else {
-		outputFile = *inputFile + "_fields.json"
-	}
-
 	jsonData, err := json.MarshalIndent(outputData, "", "  ")
 	if err != nil {
-		log.Printf("Error marshaling JSON: %v", err)
-	} else {
-		if err := os.WriteFile(outputFile, jsonData, 0644); err != nil {
-			log.Printf("Error writing JSON file: %v", err)
-		} else {
-			fmt.Printf("Results saved to: %s\n", outputFile)
+		log.Fatalf("Error marshaling JSON: %v", err)
+	}
+	if *outputFile == "" {
+		if _, err := os.Stdout.Write(append(jsonData, '\n')); err != nil {
+			log.Fatalf("Error writing JSON to stdout: %v", err)
 		}
+		return
+	}
+	if err := os.WriteFile(*outputFile, jsonData, 0644); err != nil {
+		log.Fatalf("Error writing JSON file: %v", err)
 	}
+	log.Printf("Results saved to: %s", *outputFile)
 }
 
 func readTournamentIDs(filePath string) ([]string, error) {
@@ -22,6 +22,7 @@ if str(_SCRAPER_DIR) not in sys.path:
 
 import pandas as pd
 
+import redact  # noqa: E402
 from validate_pipeline import validate_details_vs_reports, validate_player_list_vs_reports
 
 logging.basicConfig(
@@ -174,6 +175,7 @@ def main() -> int:
         help="Reduce log output",
     )
     args = parser.parse_args()
+    redact.install()
 
     if args.quiet:
         logging.getLogger().setLevel(logging.WARNING)
@@ -18,13 +18,24 @@ Event shape:
 - override: If true, overwrite existing output (default: false)
 - save_raw: If true, save raw HTML to raw/details/details_chunk_{i}.html.gz (default: true)
 - details_rate_limit: Requests per second to FIDE (default: 0.33; 0 = unlimited)
+- user_agents: Optional list of browser identities (header dicts with "User-Agent");
+  chunk_index picks one per chunk (default: built-in pool)
+- connect_timeout, read_timeout, total_timeout: Seconds per FIDE request (defaults:
+  15, 45, no total limit; see http_timeouts)
+- circuit_breaker, anomaly_check, control_file, otlp_endpoint, traceparent and
+  related keys: circuit breaker, anomaly monitor, live settings and tracing, on by
+  default as in the CLI (see scrape_options)
 """
 
 import logging
 
 from .lambda_logging import configure
+from . import scrape_options
 from s3_io import build_s3_uri_for_run, output_exists
 from get_tournament_details import run
+from run_summary import EXIT_FATAL, STATUS, summary_path
+import http_timeouts
+import user_agents
 
 logger = logging.getLogger(__name__)
 
@@ -90,6 +101,22 @@ def lambda_handler(event: dict, context) -> dict:
             "success": False,
             "error": "chunk_count is required",
         }
+    try:
+        user_agents.configure(event.get("user_agents"), worker=int(chunk_index))
+    except (TypeError, ValueError) as e:
+        return {
+            "statusCode": 400,
+            "success": False,
+            "error": f"Invalid user_agents: {e}",
+        }
+    try:
+        http_timeouts.from_mapping(event)
+    except ValueError as e:
+        return {
+            "statusCode": 400,
+            "success": False,
+            "error": f"Invalid timeout: {e}",
+        }
 
     input_path = build_s3_uri_for_run(
         bucket,
@@ -122,38 +149,61 @@ def lambda_handler(event: dict, context) -> dict:
             "message": "Output already exists; left as-is (pass override=true to replace)",
         }
 
+    try:
+        options = scrape_options.setup(
+            "details", event, bucket, run_type, run_name, chunk_index
+        )
+    except ValueError as e:
+        return {
+            "statusCode": 400,
+            "success": False,
+            "error": f"Invalid scraper option: {e}",
+        }
+
     logger.info(
         "Starting tournament details scrape: input=%s output=%s",
         input_path,
         output_path,
     )
 
-    exit_code = run(
-        input_path=input_path,
-        output_path=output_path,
-        rate_limit=rate_limit,
-        max_retries=3,
-        checkpoint=0,
-        quiet=False,
-        output_sample_path=output_sample_path,
-        output_reports_base=output_reports_base,
-        save_raw=save_raw,
-    )
-
-    if exit_code != 0:
+    try:
+        exit_code = run(
+            input_path=input_path,
+            output_path=output_path,
+            rate_limit=rate_limit,
+            max_retries=3,
+            checkpoint=0,
+            quiet=False,
+            output_sample_path=output_sample_path,
+            output_reports_base=output_reports_base,
+            save_raw=save_raw,
+            abort_after_connect_timeouts=2,
+            **options,
+        )
+    finally:
+        scrape_options.teardown()
+
+    summary_uri = summary_path(output_reports_base or output_path)
+    if exit_code == EXIT_FATAL:
         logger.error("Tournament details scrape failed with exit code %d", exit_code)
         return {
             "statusCode": 500,
             "success": False,
+            "exit_code": exit_code,
             "input_path": input_path,
             "output_path": output_path,
+            "summary_path": summary_uri,
             "error": "Scrape failed",
         }
 
-    logger.info("Tournament details scrape completed successfully")
+    # Partial runs (exit code 2) keep their output; failures are in the summary
+    logger.info("Tournament details scrape finished: %s", STATUS[exit_code])
     return {
         "statusCode": 200,
         "success": True,
+        "status": STATUS[exit_code],
+        "exit_code": exit_code,
         "input_path": input_path,
         "output_path": output_path,
+        "summary_path": summary_uri,
     }
@@ -10,6 +10,8 @@ Call configure() at the start of each handler.
 import logging
 import sys
 
+import redact
+
 
 def configure() -> None:
     """
@@ -18,6 +20,8 @@ def configure() -> None:
     - Enables line buffering on stdout/stderr so output flushes on newline.
     - Replaces root logger handlers with a flushing handler so logging flushes
       after each record (covers Lambda timeout kills where buffers may not flush).
+    - Masks secrets (proxy passwords, tokens, webhook URLs) in every record
+      (see redact).
     """
     # Line buffering: flush on newline
     if hasattr(sys.stdout, "reconfigure"):
@@ -34,6 +38,7 @@ def configure() -> None:
     root.handlers.clear()
     root.addHandler(handler)
     root.setLevel(logging.INFO)
+    redact.install()
 
 
 class _FlushingStreamHandler(logging.StreamHandler):
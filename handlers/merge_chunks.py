@@ -19,8 +19,11 @@ Inputs: {base}/data/tournament_details_chunks/details_chunk_*_of_{n}.parquet,
         (n=chunk_count from run_metadata.json)
 Outputs: {base}/data/tournament_details.parquet,
          {base}/data/tournament_reports_players.parquet,
-         {base}/data/tournament_reports_games.parquet
-Returns: details_uri, reports_players_uri, reports_games_uri
+         {base}/data/tournament_reports_games.parquet,
+         {base}/reports/tournament_details_summary.json,
+         {base}/reports/tournament_reports_summary.json (merged chunk summaries)
+Returns: details_uri, reports_players_uri, reports_games_uri, details_summary_uri,
+         reports_summary_uri
 """
 
 import logging
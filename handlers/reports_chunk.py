@@ -20,6 +20,13 @@ Event shape:
 - save_raw: If true, save raw HTML to raw/reports/reports_chunk_{i}.html.gz (default: true)
 - details_path: Optional S3 URI to details chunk parquet for date inference.
 - reports_rate_limit: Requests per second to FIDE (default: 0.33; 0 = unlimited)
+- user_agents: Optional list of browser identities (header dicts with "User-Agent");
+  chunk_index picks one per chunk (default: built-in pool)
+- connect_timeout, read_timeout, total_timeout: Seconds per FIDE request (defaults:
+  15, 45, no total limit; see http_timeouts)
+- circuit_breaker, anomaly_check, control_file, otlp_endpoint, traceparent and
+  related keys: circuit breaker, anomaly monitor, live settings and tracing, on by
+  default as in the CLI (see scrape_options)
 
 Outputs: parquet, plus reports_chunk_{i}_verbose_sample.json and reports_chunk_{i}_games_sample.csv.
 When tournaments have no original report (page says "updated or replaced"), writes
@@ -29,8 +36,12 @@ reports_chunk_{i}_skipped.json to reports/.
 import logging
 
 from .lambda_logging import configure
+from . import scrape_options
 from s3_io import build_s3_uri_for_run, output_exists
 from get_tournament_reports import run
+from run_summary import EXIT_FATAL, STATUS, summary_path
+import http_timeouts
+import user_agents
 
 logger = logging.getLogger(__name__)
 
@@ -100,6 +111,22 @@ def lambda_handler(event: dict, context) -> dict:
             "success": False,
             "error": "chunk_count is required",
         }
+    try:
+        user_agents.configure(event.get("user_agents"), worker=int(chunk_index))
+    except (TypeError, ValueError) as e:
+        return {
+            "statusCode": 400,
+            "success": False,
+            "error": f"Invalid user_agents: {e}",
+        }
+    try:
+        http_timeouts.from_mapping(event)
+    except ValueError as e:
+        return {
+            "statusCode": 400,
+            "success": False,
+            "error": f"Invalid timeout: {e}",
+        }
 
     input_path = build_s3_uri_for_run(
         bucket,
@@ -142,38 +169,61 @@ def lambda_handler(event: dict, context) -> dict:
             f"details_chunk_{chunk_index}_of_{chunk_count}.parquet",
         )
 
+    try:
+        options = scrape_options.setup(
+            "reports", event, bucket, run_type, run_name, chunk_index
+        )
+    except ValueError as e:
+        return {
+            "statusCode": 400,
+            "success": False,
+            "error": f"Invalid scraper option: {e}",
+        }
+
     logger.info(
         "Starting tournament reports scrape: input=%s output=%s",
         input_path,
         output_path,
     )
 
-    exit_code = run(
-        input_path=input_path,
-        output_path=output_path,
-        details_path=details_path,
-        rate_limit=rate_limit,
-        quiet=False,
-        save_raw=save_raw,
-        output_sample_json=output_sample_json,
-        output_sample_csv=output_sample_csv,
-        output_reports_base=output_reports_base,
-    )
+    try:
+        exit_code = run(
+            input_path=input_path,
+            output_path=output_path,
+            details_path=details_path,
+            rate_limit=rate_limit,
+            quiet=False,
+            save_raw=save_raw,
+            output_sample_json=output_sample_json,
+            output_sample_csv=output_sample_csv,
+            output_reports_base=output_reports_base,
+            abort_after_connect_timeouts=2,
+            **options,
+        )
+    finally:
+        scrape_options.teardown()
 
-    if exit_code != 0:
+    summary_uri = summary_path(output_reports_base or output_path)
+    if exit_code == EXIT_FATAL:
         logger.error("Tournament reports scrape failed with exit code %d", exit_code)
         return {
             "statusCode": 500,
             "success": False,
+            "exit_code": exit_code,
             "input_path": input_path,
             "output_path": output_path,
+            "summary_path": summary_uri,
             "error": "Scrape failed",
         }
 
-    logger.info("Tournament reports scrape completed successfully")
+    # Partial runs (exit code 2) keep their output; failures are in the summary
+    logger.info("Tournament reports scrape finished: %s", STATUS[exit_code])
     return {
         "statusCode": 200,
         "success": True,
+        "status": STATUS[exit_code],
+        "exit_code": exit_code,
         "input_path": input_path,
         "output_path": output_path,
+        "summary_path": summary_uri,
     }
@@ -0,0 +1,112 @@
+"""
+Scraper safeguards for the details and reports chunk Lambdas.
+
+The CLI scrapers set these up from flags in main(). The chunk handlers call
+setup() instead, so run() gets the same circuit breaker, anomaly monitor, live
+settings and tracing spans on Lambda. Optional event keys:
+
+- circuit_breaker: false disables the circuit breaker (default: on)
+- breaker_window, breaker_error_ratio, breaker_cooldown, breaker_max_trips: as the
+  --breaker-* flags; the cooldown and trips default lower than the CLI's, so an
+  outage stops the chunk well inside the 15-minute Lambda timeout and the Step
+  Function retries it
+- anomaly_check: false disables the response anomaly monitor (default: on)
+- anomaly_pause: seconds to pause after anomalous responses (default: 60)
+- control_file: live_config settings file, polled every control_poll seconds
+  (default: s3://{bucket}/{run base}/control/{stage}.json, every 30 seconds; a
+  missing file changes nothing)
+- otlp_endpoint: OTLP/HTTP endpoint for traces (default:
+  $OTEL_EXPORTER_OTLP_ENDPOINT; off if unset)
+- traceparent: W3C trace context for the chunk's span to join (default:
+  $TRACEPARENT)
+
+Call teardown() when the run is over: it ends the chunk's root span and flushes
+the spans, which atexit would not do before Lambda freezes the process.
+"""
+
+from typing import Any, Dict, Optional
+
+import anomaly
+import circuit_breaker
+import live_config
+import tracing
+from s3_io import build_s3_uri_for_run
+
+LAMBDA_BREAKER_COOLDOWN = 15.0
+LAMBDA_BREAKER_MAX_COOLDOWN = 120.0
+LAMBDA_BREAKER_MAX_TRIPS = 3
+LAMBDA_ANOMALY_PAUSE = 60.0
+
+
+def _number(event: dict, key: str, default: float) -> float:
+    value = event.get(key)
+    if value is None:
+        return default
+    if isinstance(value, bool):
+        raise ValueError(f"{key} must be a number, got {value!r}")
+    try:
+        return float(value)
+    except (TypeError, ValueError):
+        raise ValueError(f"{key} must be a number, got {value!r}") from None
+
+
+def breaker_from_event(
+    name: str, event: dict
+) -> Optional[circuit_breaker.CircuitBreaker]:
+    """CircuitBreaker with the Lambda defaults, or None if the event disables it."""
+    if event.get("circuit_breaker", True) is False:
+        return None
+    return circuit_breaker.CircuitBreaker(
+        name,
+        window=int(_number(event, "breaker_window", circuit_breaker.DEFAULT_WINDOW)),
+        error_ratio=_number(
+            event, "breaker_error_ratio", circuit_breaker.DEFAULT_ERROR_RATIO
+        ),
+        cooldown=_number(event, "breaker_cooldown", LAMBDA_BREAKER_COOLDOWN),
+        max_cooldown=LAMBDA_BREAKER_MAX_COOLDOWN,
+        max_trips=int(_number(event, "breaker_max_trips", LAMBDA_BREAKER_MAX_TRIPS)),
+    )
+
+
+def setup(
+    stage: str,
+    event: dict,
+    bucket: str,
+    run_type: str,
+    run_name: Optional[str],
+    chunk_index: Any,
+) -> Dict[str, Any]:
+    """
+    Configure anomaly monitoring and tracing for stage ("details" or "reports")
+    and return the run() keyword arguments for the breaker and control file.
+    Raises ValueError for invalid event values.
+    """
+    breaker = breaker_from_event(stage, event)
+    if event.get("anomaly_check", True) is False:
+        anomaly.disable(stage)
+    else:
+        anomaly.configure(
+            stage, pause_seconds=_number(event, "anomaly_pause", LAMBDA_ANOMALY_PAUSE)
+        )
+    control_file = event.get("control_file") or build_s3_uri_for_run(
+        bucket, run_type, run_name, "control", f"{stage}.json"
+    )
+    control_poll = _number(event, "control_poll", live_config.DEFAULT_POLL_SECONDS)
+    tracing.configure(
+        f"tournament_{stage}",
+        event.get("otlp_endpoint"),
+        event.get("traceparent"),
+        run_type=run_type,
+        run_name=run_name,
+        chunk_index=chunk_index,
+    )
+    return {
+        "breaker": breaker,
+        "control_file": control_file,
+        "control_poll": control_poll,
+    }
+
+
+def teardown() -> None:
+    """End the chunk's root span and flush traces (no-op when tracing is off)."""
+    tracing.shutdown()
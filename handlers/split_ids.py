@@ -18,7 +18,9 @@ Event shape:
 - ids_uri: Path to tournament IDs file. If not set, uses {base}/data/tournament_ids.txt.
   Must exist; Step Function runs tournaments Lambda first.
 - chunk_size: Max tournaments per chunk (default: 300).
-- override: Overwrite existing chunk files (default: false).
+- override: Overwrite existing chunk files (default: false). Without it, a retry
+  on the same IDs file resumes where the last invocation stopped, and a changed
+  file is split again from the start (see split_tournament_ids).
 
 Returns: { statusCode, success, chunks: [{ input_path, output_path, tournament_count, chunk_index }, ...] }
 """
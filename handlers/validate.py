@@ -15,7 +15,8 @@ Event shape:
 
 Inputs: {base}/data/tournament_details.parquet, {base}/data/tournament_reports_games.parquet,
         latest player_lists/data/player_list_*.parquet
-Output: {base}/reports/validation_report.json
+Output: {base}/reports/validation_report.json; player IDs missing from the player
+        list are added to player_lists/queue/missing_player_ids.txt
 Returns: report_uri, has_issues, player_list_vs_reports, details_vs_reports
 """
 
@@ -0,0 +1,112 @@
+#!/usr/bin/env python3
+"""
+Show the settings the pipeline tools read, optionally with secrets masked.
+
+`show` prints JSON with:
+
+  environment   set variables the tools read: GitHub, OpenTelemetry, AWS and
+                proxy settings (requests honours HTTP(S)_PROXY)
+  files         the contents of each --file, e.g. a scraper --control-file or a
+                rating_input.py --weights file (JSON)
+
+With --redacted, URL passwords, tokens, webhook URLs and values under sensitive
+names are masked as *** (see src/scraper/redact.py). Use it before pasting
+config into an issue or a chat.
+
+Usage:
+  uv run scripts/config.py show --redacted
+  uv run scripts/config.py show --redacted --file control.json
+"""
+
+import argparse
+import json
+import logging
+import os
+import sys
+from pathlib import Path
+from typing import Dict, List, Mapping, Optional
+
+sys.path.insert(0, str(Path(__file__).parent.parent / "src" / "scraper"))
+
+import redact  # noqa: E402
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+ENV_SETTINGS = (
+    "GITHUB_REPOSITORY",
+    "GITHUB_TOKEN",
+    "OTEL_EXPORTER_OTLP_ENDPOINT",
+    "TRACEPARENT",
+    "PIPELINE_CONFIG_SSM_PARAM",
+    "AWS_PROFILE",
+    "AWS_REGION",
+    "AWS_DEFAULT_REGION",
+    "AWS_ACCESS_KEY_ID",
+    "AWS_SECRET_ACCESS_KEY",
+    "AWS_SESSION_TOKEN",
+    "HTTP_PROXY",
+    "HTTPS_PROXY",
+    "ALL_PROXY",
+    "NO_PROXY",
+    "NO_COLOR",
+)
+
+
+def collect(
+    files: List[str], env: Optional[Mapping[str, str]] = None
+) -> Dict[str, Dict]:
+    """Settings from env (default os.environ) and the given JSON files."""
+    env = os.environ if env is None else env
+    config = {
+        "environment": {k: env[k] for k in ENV_SETTINGS if k in env},
+        "files": {},
+    }
+    for path in files:
+        with open(path, "r", encoding="utf-8") as f:
+            config["files"][path] = json.load(f)
+    return config
+
+
+def show(config: Dict, redacted: bool) -> str:
+    """config as indented JSON, masked when redacted."""
+    if redacted:
+        config = redact.redact_value(config)
+    return json.dumps(config, indent=2, sort_keys=True)
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="Show pipeline settings",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    commands = parser.add_subparsers(dest="command", required=True)
+    show_parser = commands.add_parser("show", help="Print settings as JSON")
+    show_parser.add_argument(
+        "--redacted", action="store_true", help="Mask secrets in the output"
+    )
+    show_parser.add_argument(
+        "--file",
+        action="append",
+        default=[],
+        help="JSON config file to include (repeatable)",
+    )
+    args = parser.parse_args()
+    redact.install()
+
+    try:
+        config = collect(args.file)
+    except (OSError, ValueError) as e:
+        logger.error("%s", e)
+        return 1
+    print(show(config, args.redacted))
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -0,0 +1,257 @@
+#!/usr/bin/env python3
+"""
+Estimate the cost of a details/reports scrape before running it.
+
+Given tournament IDs (a file, or an ID range) and rate settings, prints the
+expected request count, bytes downloaded, duration and completion time. Each
+tournament costs one details request and, if it has details, one reports request.
+
+For ranges most IDs may not be tournaments, so --probe N fetches the details page
+for N random IDs to measure the hit rate (and page sizes); ID lists from the
+tournaments step are assumed to all hit. Probing a list also replaces the default
+page sizes and fetch times with measured ones.
+
+Usage:
+  uv run scripts/estimate_scrape.py --ids data/tournament_ids/2024_01
+  uv run scripts/estimate_scrape.py --range 380000-420000 --probe 30
+  uv run scripts/estimate_scrape.py --ids ids.txt --workers 5 --details-rate-limit 0.33
+"""
+
+import argparse
+import logging
+import random
+import sys
+import time
+from dataclasses import dataclass
+from datetime import datetime, timedelta, timezone
+from pathlib import Path
+from typing import List, Optional
+
+sys.path.insert(0, str(Path(__file__).parent.parent / "src" / "scraper"))
+
+import redact  # noqa: E402
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+# Rough per-page defaults (candidates_24_details.html is ~44 KB; reports range from
+# a few KB to ~850 KB for the World Cup). --probe replaces them with measurements.
+DEFAULT_DETAILS_BYTES = 45_000
+DEFAULT_REPORTS_BYTES = 60_000
+# Natural fetch + parse time per page when not rate limited (src/scraper/README.md)
+DEFAULT_DETAILS_SECONDS = 1.0
+DEFAULT_REPORTS_SECONDS = 1.1
+# Scraper CLI defaults: details 0.5 req/s, reports unlimited
+DEFAULT_DETAILS_RATE = 0.5
+DEFAULT_REPORTS_RATE = 0.0
+
+
+@dataclass
+class PageCost:
+    bytes: float
+    seconds: float
+
+
+@dataclass
+class Estimate:
+    ids: int
+    hit_rate: float
+    details_requests: int
+    reports_requests: int
+    bytes: float
+    seconds: float
+
+    @property
+    def requests(self) -> int:
+        return self.details_requests + self.reports_requests
+
+
+def parse_id_range(s: str) -> range:
+    """Parse "START-END" (inclusive) to a range of IDs."""
+    try:
+        start, end = (int(p) for p in s.split("-", 1))
+    except ValueError:
+        raise argparse.ArgumentTypeError(
+            f"Invalid range '{s}': expected START-END (e.g. 380000-420000)"
+        ) from None
+    if start < 0 or end < start:
+        raise argparse.ArgumentTypeError(f"Invalid range '{s}': END must be >= START")
+    return range(start, end + 1)
+
+
+def seconds_per_request(rate: float, natural_seconds: float) -> float:
+    """Time per request for one worker: the rate limit or natural throughput."""
+    if rate > 0:
+        return max(1.0 / rate, natural_seconds)
+    return natural_seconds
+
+
+def estimate(
+    n_ids: int,
+    hit_rate: float,
+    details: PageCost,
+    reports: PageCost,
+    workers: int = 1,
+) -> Estimate:
+    """Requests, bytes and wall time for n_ids spread evenly over workers."""
+    details_requests = n_ids
+    reports_requests = round(n_ids * hit_rate)
+    seconds = (
+        details_requests * details.seconds + reports_requests * reports.seconds
+    ) / max(workers, 1)
+    return Estimate(
+        ids=n_ids,
+        hit_rate=hit_rate,
+        details_requests=details_requests,
+        reports_requests=reports_requests,
+        bytes=details_requests * details.bytes + reports_requests * reports.bytes,
+        seconds=seconds,
+    )
+
+
+def probe(ids: List[str], sample: int) -> dict:
+    """
+    Fetch details (and, for hits, reports) for a random sample of ids.
+    Returns hit_rate plus mean bytes and seconds per details/reports page.
+    """
+    import requests
+
+    from get_tournament_details import fetch_tournament_details
+    from get_tournament_reports import fetch_tournament_report
+
+    session = requests.Session()
+    chosen = random.sample(ids, min(sample, len(ids)))
+    hits = 0
+    details_bytes: List[int] = []
+    details_seconds: List[float] = []
+    reports_bytes: List[int] = []
+    reports_seconds: List[float] = []
+    for tid in chosen:
+        t0 = time.perf_counter()
+        details, _, _, raw = fetch_tournament_details(tid, session, return_raw=True)
+        details_seconds.append(time.perf_counter() - t0)
+        if not details:
+            continue
+        hits += 1
+        details_bytes.append(len(raw or b""))
+        t0 = time.perf_counter()
+        _, _, _, raw = fetch_tournament_report(tid, session, return_raw=True)
+        reports_seconds.append(time.perf_counter() - t0)
+        reports_bytes.append(len(raw or b""))
+
+    def mean(values, default):
+        return sum(values) / len(values) if values else default
+
+    return {
+        "sampled": len(chosen),
+        "hit_rate": hits / len(chosen) if chosen else 1.0,
+        "details": PageCost(
+            mean(details_bytes, DEFAULT_DETAILS_BYTES),
+            mean(details_seconds, DEFAULT_DETAILS_SECONDS),
+        ),
+        "reports": PageCost(
+            mean(reports_bytes, DEFAULT_REPORTS_BYTES),
+            mean(reports_seconds, DEFAULT_REPORTS_SECONDS),
+        ),
+    }
+
+
+def format_report(est: Estimate, workers: int, now: Optional[datetime] = None) -> str:
+    now = now or datetime.now(timezone.utc)
+    done = now + timedelta(seconds=est.seconds)
+    lines = [
+        f"IDs:            {est.ids:,}",
+        f"Hit rate:       {est.hit_rate:.1%}",
+        f"Requests:       {est.requests:,} "
+        f"({est.details_requests:,} details + {est.reports_requests:,} reports)",
+        f"Download:       {est.bytes / 1e6:,.1f} MB",
+        f"Workers:        {workers}",
+        f"Duration:       {timedelta(seconds=round(est.seconds))}",
+        f"Completes at:   {done:%Y-%m-%d %H:%M} UTC",
+    ]
+    return "\n".join(lines)
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="Estimate requests, bytes and duration of a details/reports scrape",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    src = parser.add_mutually_exclusive_group(required=True)
+    src.add_argument("--ids", help="Tournament IDs file (one per line)")
+    src.add_argument(
+        "--range", type=parse_id_range, help="Inclusive ID range START-END"
+    )
+    parser.add_argument(
+        "--probe",
+        type=int,
+        default=0,
+        metavar="N",
+        help="Fetch N random IDs to measure hit rate and page sizes (default: 0)",
+    )
+    parser.add_argument(
+        "--details-rate-limit",
+        type=float,
+        default=DEFAULT_DETAILS_RATE,
+        help=f"Details req/s per worker (default: {DEFAULT_DETAILS_RATE})",
+    )
+    parser.add_argument(
+        "--reports-rate-limit",
+        type=float,
+        default=DEFAULT_REPORTS_RATE,
+        help="Reports req/s per worker (default: 0 = natural throughput)",
+    )
+    parser.add_argument(
+        "--workers",
+        type=int,
+        default=1,
+        help="Parallel workers, e.g. Step Function chunks (default: 1)",
+    )
+    args = parser.parse_args()
+    redact.install()
+
+    if args.workers < 1:
+        logger.error("--workers must be >= 1")
+        return 1
+
+    if args.ids:
+        try:
+            ids = [
+                line.strip()
+                for line in Path(args.ids).read_text(encoding="utf-8").splitlines()
+                if line.strip()
+            ]
+        except OSError as e:
+            logger.error("Error reading %s: %s", args.ids, e)
+            return 1
+    else:
+        ids = [str(i) for i in args.range]
+    if not ids:
+        logger.error("No IDs to estimate")
+        return 1
+
+    hit_rate = 1.0
+    details = PageCost(DEFAULT_DETAILS_BYTES, DEFAULT_DETAILS_SECONDS)
+    reports = PageCost(DEFAULT_REPORTS_BYTES, DEFAULT_REPORTS_SECONDS)
+    if args.probe > 0:
+        result = probe(ids, args.probe)
+        hit_rate = result["hit_rate"]
+        details, reports = result["details"], result["reports"]
+        logger.info("Probed %d IDs: hit rate %.1f%%", result["sampled"], 100 * hit_rate)
+    elif args.range:
+        logger.warning("No --probe for a range: assuming every ID is a tournament")
+
+    details.seconds = seconds_per_request(args.details_rate_limit, details.seconds)
+    reports.seconds = seconds_per_request(args.reports_rate_limit, reports.seconds)
+    est = estimate(len(ids), hit_rate, details, reports, workers=args.workers)
+    print(format_report(est, args.workers))
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
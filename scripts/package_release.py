@@ -0,0 +1,398 @@
+#!/usr/bin/env python3
+"""
+Package scraped data as a versioned, checksummed release bundle.
+
+Collects the latest player list and federations plus per-month tournament details,
+players and games for prod runs, and writes:
+
+  {output_dir}/fide-glicko-{version}.tar.gz
+    fide-glicko-{version}/
+      manifest.json                  # version, months, files with sha256 + bytes
+      LICENSE                        # data source and attribution notice
+      federations/federations_{ts}.csv
+      players/player_list_{ts}.parquet
+      months/{YYYY-MM}/tournament_details.parquet
+      months/{YYYY-MM}/tournament_reports_players.parquet
+      months/{YYYY-MM}/tournament_reports_games.parquet
+  {output_dir}/fide-glicko-{version}.tar.gz.sha256
+
+Months missing any of the three monthly files are skipped with a warning.
+
+With --sqlite the same data is also written as one ready-to-query SQLite file,
+{output_dir}/fide-glicko-{version}.sqlite (plus .sha256), for users who prefer a
+single database over a Parquet tree. It has these tables:
+
+  release        key/value: name, version, created_at, months (JSON list)
+  federations    code, name
+  players        player list columns
+  tournaments    details columns + month
+  tournament_players, games
+                 reports columns + month
+
+Each monthly table is created from the union of the columns of all months, so a
+column only some months have (e.g. report_kind) is NULL in the others. Datetimes
+are ISO 8601 UTC text (2024-01-05T00:00:00Z); list columns are JSON arrays.
+Player and tournament ID columns are indexed. The pipeline computes no ratings
+yet, so there is no ratings table.
+
+Example:
+  uv run scripts/package_release.py --version 2025.01 --start 2024-01 --end 2024-12
+"""
+
+import argparse
+import hashlib
+import io
+import json
+import logging
+import sqlite3
+import sys
+import tarfile
+from datetime import datetime, timezone
+from pathlib import Path
+
+sys.path.insert(0, str(Path(__file__).parent.parent / "src" / "scraper"))
+
+import redact  # noqa: E402
+from months import month_range, parse_month  # noqa: E402
+from s3_io import (
+    FEDERATIONS_DATA_PREFIX,
+    PLAYER_LISTS_DATA_PREFIX,
+    build_local_path_for_run,
+    get_latest_in_local_prefix,
+)
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+RELEASE_NAME = "fide-glicko"
+MANIFEST_SCHEMA_VERSION = 1
+MONTHLY_FILES = (
+    "tournament_details.parquet",
+    "tournament_reports_players.parquet",
+    "tournament_reports_games.parquet",
+)
+LICENSE_TEXT = """\
+FIDE Glicko dataset
+
+Source: FIDE rating data published at https://ratings.fide.com (federations,
+player lists, tournament details and tournament reports), scraped by the
+fide-glicko project (https://github.com/maxjiang216/fide-glicko).
+
+The underlying data belongs to FIDE. Redistributions must keep this notice and
+credit FIDE as the original source. The fide-glicko code that produced this
+bundle is MIT licensed; that license does not cover the data itself.
+"""
+
+
+def sha256_file(path: Path) -> str:
+    """Hex sha256 of a file, read in 1 MiB blocks."""
+    h = hashlib.sha256()
+    with open(path, "rb") as f:
+        for block in iter(lambda: f.read(1 << 20), b""):
+            h.update(block)
+    return h.hexdigest()
+
+
+def collect_files(local_root: Path, months: list[str]) -> tuple[list, list[str]]:
+    """
+    Resolve files to package. Returns ([(source_path, arcname)], included_months).
+    """
+    files: list[tuple[Path, str]] = []
+
+    fed_path, _ = get_latest_in_local_prefix(local_root, FEDERATIONS_DATA_PREFIX)
+    if fed_path:
+        files.append((fed_path, f"federations/{fed_path.name}"))
+    else:
+        logger.warning("No federations file under %s", local_root)
+
+    players_path, _ = get_latest_in_local_prefix(local_root, PLAYER_LISTS_DATA_PREFIX)
+    if players_path:
+        files.append((players_path, f"players/{players_path.name}"))
+    else:
+        logger.warning("No player list under %s", local_root)
+
+    included = []
+    for month in months:
+        paths = [
+            build_local_path_for_run(local_root, "prod", month, "data", name)
+            for name in MONTHLY_FILES
+        ]
+        missing = [p.name for p in paths if not p.exists()]
+        if missing:
+            logger.warning("Skipping %s: missing %s", month, ", ".join(missing))
+            continue
+        for p in paths:
+            files.append((p, f"months/{month}/{p.name}"))
+        included.append(month)
+    return files, included
+
+
+def build_manifest(
+    version: str, files: list[tuple[Path, str]], months: list[str]
+) -> dict:
+    """Manifest with one entry (path, bytes, sha256) per packaged file."""
+    return {
+        "name": RELEASE_NAME,
+        "version": version,
+        "schema_version": MANIFEST_SCHEMA_VERSION,
+        "created_at": datetime.now(timezone.utc).strftime("%Y-%m-%dT%H:%M:%SZ"),
+        "months": months,
+        "files": [
+            {
+                "path": arcname,
+                "bytes": src.stat().st_size,
+                "sha256": sha256_file(src),
+            }
+            for src, arcname in files
+        ],
+    }
+
+
+SQLITE_TABLES = {
+    "tournament_details.parquet": "tournaments",
+    "tournament_reports_players.parquet": "tournament_players",
+    "tournament_reports_games.parquet": "games",
+}
+SQLITE_INDEXES = {
+    "players": ["id"],
+    "tournaments": ["tournament_id", "month"],
+    "tournament_players": ["player_id", "tournament_id"],
+    "games": ["white_player_id", "black_player_id", "tournament_id", "month"],
+}
+
+
+def _sqlite_ready(df):
+    """
+    Datetimes as ISO 8601 UTC text, the form SQLite's date functions read (naive
+    values are already UTC); list columns (e.g. chief_arbiter_names) as JSON
+    arrays, which json_each() reads.
+    """
+    import pandas as pd
+
+    df = df.copy()
+    for col in df.columns:
+        if pd.api.types.is_datetime64_any_dtype(df[col]):
+            values = df[col]
+            if values.dt.tz is not None:
+                values = values.dt.tz_convert("UTC")
+            text = values.dt.strftime("%Y-%m-%dT%H:%M:%SZ")
+            df[col] = text.where(df[col].notna(), None)
+        elif df[col].dtype == object and df[col].map(_is_list).any():
+            df[col] = df[col].map(
+                lambda v: json.dumps(list(v)) if _is_list(v) else None
+            )
+    return df
+
+
+def _is_list(value) -> bool:
+    """List cell from Parquet (numpy array) or pandas (list)."""
+    return isinstance(value, list) or getattr(value, "ndim", 0) == 1
+
+
+def _sqlite_type(arrow_type) -> str:
+    """SQLite column type for a Parquet column (lists and datetimes are TEXT)."""
+    import pyarrow.types as pat
+
+    if pat.is_boolean(arrow_type) or pat.is_integer(arrow_type):
+        return "INTEGER"
+    if pat.is_floating(arrow_type) or pat.is_decimal(arrow_type):
+        return "REAL"
+    return "TEXT"
+
+
+def _union_columns(paths: list[Path]) -> dict[str, str]:
+    """Column -> SQLite type over the Parquet files, in first-seen order."""
+    import pyarrow.parquet as pq
+
+    columns: dict[str, str] = {}
+    for path in paths:
+        for field in pq.read_schema(path):
+            if not field.name.startswith("__index_level_"):
+                columns.setdefault(field.name, _sqlite_type(field.type))
+    return columns
+
+
+def _quote(name: str) -> str:
+    return '"' + name.replace('"', '""') + '"'
+
+
+def write_sqlite(
+    db_path: Path, files: list[tuple[Path, str]], months: list[str], version: str
+) -> None:
+    """Write the packaged files into one SQLite database at db_path."""
+    import pandas as pd
+
+    monthly: dict[str, list[Path]] = {}
+    for src, arcname in files:
+        if arcname.startswith("months/"):
+            monthly.setdefault(SQLITE_TABLES[arcname.rsplit("/", 1)[1]], []).append(src)
+
+    db_path.unlink(missing_ok=True)
+    with sqlite3.connect(db_path) as conn:
+        release = {
+            "name": RELEASE_NAME,
+            "version": version,
+            "created_at": datetime.now(timezone.utc).strftime("%Y-%m-%dT%H:%M:%SZ"),
+            "months": json.dumps(months),
+        }
+        conn.execute("CREATE TABLE release (key TEXT PRIMARY KEY, value TEXT)")
+        conn.executemany("INSERT INTO release VALUES (?, ?)", release.items())
+        # Months can differ in columns; append each month into the union schema
+        table_columns = {}
+        for table, paths in monthly.items():
+            columns = {**_union_columns(paths), "month": "TEXT"}
+            conn.execute(
+                f"CREATE TABLE {table} ("
+                + ", ".join(f"{_quote(c)} {t}" for c, t in columns.items())
+                + ")"
+            )
+            table_columns[table] = list(columns)
+        for src, arcname in files:
+            if arcname.startswith("federations/"):
+                pd.read_csv(src, keep_default_na=False).to_sql(
+                    "federations", conn, index=False
+                )
+            elif arcname.startswith("players/"):
+                df = _sqlite_ready(pd.read_parquet(src))
+                df.to_sql("players", conn, index=False)
+            else:
+                _, month, name = arcname.split("/")
+                table = SQLITE_TABLES[name]
+                df = _sqlite_ready(pd.read_parquet(src)).assign(month=month)
+                df = df.reindex(columns=table_columns[table]).astype(object)
+                df = df.where(df.notna(), None)
+                df.to_sql(table, conn, index=False, if_exists="append")
+        tables = {
+            row[0]
+            for row in conn.execute("SELECT name FROM sqlite_master WHERE type='table'")
+        }
+        for table, columns in SQLITE_INDEXES.items():
+            if table not in tables:
+                continue
+            present = {row[1] for row in conn.execute(f"PRAGMA table_info({table})")}
+            for col in columns:
+                if col in present:
+                    conn.execute(f"CREATE INDEX idx_{table}_{col} ON {table} ({col})")
+    conn.close()
+
+
+def _add_bytes(tar: tarfile.TarFile, arcname: str, content: bytes) -> None:
+    info = tarfile.TarInfo(arcname)
+    info.size = len(content)
+    info.mtime = int(datetime.now(timezone.utc).timestamp())
+    tar.addfile(info, io.BytesIO(content))
+
+
+def package_release(
+    local_root: str | Path,
+    months: list[str],
+    version: str,
+    output_dir: str | Path,
+    override: bool = False,
+    sqlite: bool = False,
+) -> Path:
+    """
+    Build the release tarball and its .sha256 file (and with sqlite, the SQLite
+    file and its .sha256). Returns the tarball path.
+
+    Raises:
+        FileExistsError: If the tarball exists and override is False.
+        ValueError: If there is nothing to package.
+    """
+    out_dir = Path(output_dir)
+    top = f"{RELEASE_NAME}-{version}"
+    tar_path = out_dir / f"{top}.tar.gz"
+    if tar_path.exists() and not override:
+        raise FileExistsError(f"{tar_path} exists; use --override to replace")
+
+    files, included = collect_files(Path(local_root), months)
+    if not included:
+        raise ValueError("No complete months to package")
+
+    manifest = build_manifest(version, files, included)
+    out_dir.mkdir(parents=True, exist_ok=True)
+    with tarfile.open(tar_path, "w:gz") as tar:
+        _add_bytes(
+            tar,
+            f"{top}/manifest.json",
+            json.dumps(manifest, indent=2).encode("utf-8"),
+        )
+        _add_bytes(tar, f"{top}/LICENSE", LICENSE_TEXT.encode("utf-8"))
+        for src, arcname in files:
+            tar.add(src, arcname=f"{top}/{arcname}")
+
+    sha_path = tar_path.with_name(tar_path.name + ".sha256")
+    sha_path.write_text(f"{sha256_file(tar_path)}  {tar_path.name}\n")
+    logger.info(
+        "Packaged %d files (%d months) into %s",
+        len(files),
+        len(included),
+        tar_path,
+    )
+
+    if sqlite:
+        db_path = out_dir / f"{top}.sqlite"
+        write_sqlite(db_path, files, included, version)
+        db_sha_path = db_path.with_name(db_path.name + ".sha256")
+        db_sha_path.write_text(f"{sha256_file(db_path)}  {db_path.name}\n")
+        logger.info("Wrote SQLite database %s", db_path)
+    return tar_path
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="Package scraped data as a versioned release bundle",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    parser.add_argument(
+        "--version", required=True, help="Release version (e.g. 2025.01)"
+    )
+    parser.add_argument("--start", type=parse_month, required=True, metavar="YYYY-MM")
+    parser.add_argument("--end", type=parse_month, required=True, metavar="YYYY-MM")
+    parser.add_argument(
+        "--local-root",
+        default="data",
+        help="Local bucket root (default: data)",
+    )
+    parser.add_argument(
+        "--output-dir",
+        default="releases",
+        help="Directory for the bundle (default: releases)",
+    )
+    parser.add_argument(
+        "--override",
+        "-o",
+        action="store_true",
+        help="Overwrite an existing bundle with the same version",
+    )
+    parser.add_argument(
+        "--sqlite",
+        action="store_true",
+        help="Also write the release as a single SQLite file",
+    )
+    args = parser.parse_args()
+    redact.install()
+
+    try:
+        months = [f"{y:04d}-{m:02d}" for y, m in month_range(args.start, args.end)]
+        package_release(
+            args.local_root,
+            months,
+            args.version,
+            args.output_dir,
+            args.override,
+            sqlite=args.sqlite,
+        )
+    except (FileExistsError, ValueError) as e:
+        logger.error("%s", e)
+        return 1
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -0,0 +1,326 @@
+#!/usr/bin/env python3
+"""
+Publish a month's data as a GitHub release after a successful monthly update.
+
+Builds the release bundle for one prod month with package_release.py (version
+YYYY.MM, optionally with the SQLite file), writes a summary report and uploads
+everything to a GitHub release tagged {tag-prefix}{YYYY-MM}:
+
+  fide-glicko-{YYYY.MM}.tar.gz (+ .sha256)      months/, players/, federations/
+  fide-glicko-{YYYY.MM}.sqlite (+ .sha256)      with --sqlite
+  summary-{YYYY-MM}.json                        scrape summaries, validation,
+                                                bundle manifest
+
+A month counts as successful when the details and reports summaries
+({local_root}/prod/{YYYY-MM}/reports/*_summary.json) exist and report exit code
+0. Partial or failed months are refused unless --allow-partial is given. The
+release notes list the months, files and counts. The pipeline computes no
+ratings yet, so the bundle has no top lists or rating snapshots, and the default
+tag prefix is "data-" rather than "ratings-".
+
+Needs a token with contents:write on the repository in GITHUB_TOKEN. --dry-run
+builds the bundle and prints the release without calling GitHub.
+
+Usage:
+  GITHUB_TOKEN=... uv run scripts/publish_release.py --month 2025-01 --sqlite
+  uv run scripts/publish_release.py --month 2025-01 --dry-run
+"""
+
+import argparse
+import json
+import logging
+import os
+import sys
+import tarfile
+from pathlib import Path
+from typing import Dict, List, Optional
+
+import requests
+
+sys.path.insert(0, str(Path(__file__).parent))
+sys.path.insert(0, str(Path(__file__).parent.parent / "src" / "scraper"))
+
+import redact  # noqa: E402
+from months import parse_month  # noqa: E402
+from package_release import RELEASE_NAME, package_release  # noqa: E402
+from s3_io import build_local_path_for_run  # noqa: E402
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+DEFAULT_REPO = "maxjiang216/fide-glicko"
+DEFAULT_TAG_PREFIX = "data-"
+API_URL = "https://api.github.com"
+UPLOAD_URL = "https://uploads.github.com"
+SUMMARY_FILES = ("tournament_details_summary.json", "tournament_reports_summary.json")
+CONTENT_TYPES = {
+    ".gz": "application/gzip",
+    ".sqlite": "application/vnd.sqlite3",
+    ".json": "application/json",
+    ".sha256": "text/plain",
+}
+
+
+class PublishError(Exception):
+    """The month is not ready to publish or GitHub rejected a request."""
+
+
+def read_run_summaries(local_root: str | Path, month: str) -> Dict[str, dict]:
+    """Scrape summaries for a prod month by file name ({} for missing ones)."""
+    summaries = {}
+    for name in SUMMARY_FILES:
+        path = build_local_path_for_run(local_root, "prod", month, "reports", name)
+        if path.exists():
+            summaries[name] = json.loads(path.read_text(encoding="utf-8"))
+    return summaries
+
+
+def check_month(summaries: Dict[str, dict], allow_partial: bool = False) -> None:
+    """Raise PublishError unless every scrape summary exists and succeeded."""
+    missing = [name for name in SUMMARY_FILES if name not in summaries]
+    if missing:
+        raise PublishError(f"missing run summaries: {', '.join(missing)}")
+    failed = {
+        name: s.get("status") for name, s in summaries.items() if s.get("exit_code")
+    }
+    if failed and not allow_partial:
+        detail = ", ".join(f"{n}: {status}" for n, status in failed.items())
+        raise PublishError(f"month did not finish cleanly ({detail})")
+
+
+def read_manifest(tar_path: Path) -> dict:
+    """manifest.json from a package_release.py bundle."""
+    with tarfile.open(tar_path, "r:gz") as tar:
+        member = next(m for m in tar if m.name.endswith("/manifest.json"))
+        return json.load(tar.extractfile(member))
+
+
+def release_notes(month: str, manifest: dict, summaries: Dict[str, dict]) -> str:
+    """Markdown body for the release."""
+    lines = [
+        f"FIDE data for {month}, scraped from https://ratings.fide.com.",
+        "",
+        "| Step | Status | Items | Failed |",
+        "|------|--------|-------|--------|",
+    ]
+    for name, s in summaries.items():
+        counts = s.get("counts", {})
+        lines.append(
+            f"| {s.get('command', name)} | {s.get('status')} | "
+            f"{counts.get('total', 0)} | {counts.get('failed', 0)} |"
+        )
+    lines += ["", "Files:", ""]
+    lines += [f"- `{f['path']}` ({f['bytes']} bytes)" for f in manifest["files"]]
+    lines += [
+        "",
+        "The data belongs to FIDE; see LICENSE in the bundle for attribution.",
+    ]
+    return "\n".join(lines) + "\n"
+
+
+def write_summary_report(
+    path: Path,
+    month: str,
+    manifest: dict,
+    summaries: Dict[str, dict],
+    validation: Optional[str],
+) -> Path:
+    """JSON summary uploaded next to the bundle."""
+    report = {
+        "month": month,
+        "version": manifest["version"],
+        "run_summaries": summaries,
+        "validation": validation,
+        "manifest": manifest,
+    }
+    path.write_text(json.dumps(report, indent=2) + "\n", encoding="utf-8")
+    return path
+
+
+class GitHubReleases:
+    """The few GitHub REST calls a release needs."""
+
+    def __init__(
+        self, repo: str, token: str, session: Optional[requests.Session] = None
+    ):
+        self.repo = repo
+        self.session = session or requests.Session()
+        self.session.headers.update(
+            {
+                "Authorization": f"Bearer {token}",
+                "Accept": "application/vnd.github+json",
+                "X-GitHub-Api-Version": "2022-11-28",
+            }
+        )
+
+    def _check(self, response: requests.Response, what: str) -> dict:
+        if response.status_code >= 400:
+            raise PublishError(
+                f"{what} failed: HTTP {response.status_code} {response.text[:200]}"
+            )
+        return response.json()
+
+    def create_release(self, tag: str, name: str, body: str, draft: bool) -> dict:
+        response = self.session.post(
+            f"{API_URL}/repos/{self.repo}/releases",
+            json={"tag_name": tag, "name": name, "body": body, "draft": draft},
+            timeout=60,
+        )
+        return self._check(response, f"creating release {tag}")
+
+    def upload_asset(self, release_id: int, path: Path) -> dict:
+        content_type = CONTENT_TYPES.get(path.suffix, "application/octet-stream")
+        with open(path, "rb") as f:
+            response = self.session.post(
+                f"{UPLOAD_URL}/repos/{self.repo}/releases/{release_id}/assets",
+                params={"name": path.name},
+                headers={"Content-Type": content_type},
+                data=f,
+                timeout=600,
+            )
+        return self._check(response, f"uploading {path.name}")
+
+
+def publish(
+    month: str,
+    local_root: str | Path,
+    output_dir: str | Path,
+    repo: str,
+    token: Optional[str],
+    *,
+    tag_prefix: str = DEFAULT_TAG_PREFIX,
+    sqlite: bool = False,
+    allow_partial: bool = False,
+    override: bool = False,
+    draft: bool = False,
+    dry_run: bool = False,
+    validation_report: Optional[str | Path] = None,
+    session: Optional[requests.Session] = None,
+) -> List[Path]:
+    """Build and (unless dry_run) publish the release. Returns the asset paths."""
+    summaries = read_run_summaries(local_root, month)
+    check_month(summaries, allow_partial)
+    if not dry_run and not token:
+        raise PublishError("GITHUB_TOKEN is not set")
+
+    version = month.replace("-", ".")
+    tar_path = package_release(
+        local_root, [month], version, output_dir, override, sqlite=sqlite
+    )
+    manifest = read_manifest(tar_path)
+    validation = None
+    if validation_report and Path(validation_report).exists():
+        validation = Path(validation_report).read_text(encoding="utf-8")
+    summary_path = write_summary_report(
+        Path(output_dir) / f"summary-{month}.json",
+        month,
+        manifest,
+        summaries,
+        validation,
+    )
+
+    assets = [tar_path, tar_path.with_name(tar_path.name + ".sha256")]
+    if sqlite:
+        db_path = Path(output_dir) / f"{RELEASE_NAME}-{version}.sqlite"
+        assets += [db_path, db_path.with_name(db_path.name + ".sha256")]
+    assets.append(summary_path)
+
+    tag = f"{tag_prefix}{month}"
+    body = release_notes(month, manifest, summaries)
+    if dry_run:
+        logger.info("Dry run: would create release %s on %s", tag, repo)
+        for path in assets:
+            logger.info("  asset %s (%d bytes)", path.name, path.stat().st_size)
+        print(body)
+        return assets
+
+    github = GitHubReleases(repo, token, session)
+    release = github.create_release(tag, f"FIDE data {month}", body, draft)
+    for path in assets:
+        github.upload_asset(release["id"], path)
+        logger.info("Uploaded %s", path.name)
+    logger.info("Published %s: %s", tag, release.get("html_url", ""))
+    return assets
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="Publish a month's data bundle as a GitHub release",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    parser.add_argument("--month", type=parse_month, required=True, metavar="YYYY-MM")
+    parser.add_argument(
+        "--repo",
+        default=os.environ.get("GITHUB_REPOSITORY", DEFAULT_REPO),
+        help=f"owner/name (default: $GITHUB_REPOSITORY or {DEFAULT_REPO})",
+    )
+    parser.add_argument(
+        "--local-root", default="data", help="Local bucket root (default: data)"
+    )
+    parser.add_argument(
+        "--output-dir",
+        default="releases",
+        help="Directory for the bundle (default: releases)",
+    )
+    parser.add_argument(
+        "--tag-prefix",
+        default=DEFAULT_TAG_PREFIX,
+        help=f"Release tag prefix (default: {DEFAULT_TAG_PREFIX})",
+    )
+    parser.add_argument(
+        "--validation-report",
+        help="Validation report to include (default: "
+        "{local_root}/validation_reports/YYYY_MM.txt if present)",
+    )
+    parser.add_argument("--sqlite", action="store_true", help="Also ship SQLite")
+    parser.add_argument(
+        "--allow-partial",
+        action="store_true",
+        help="Publish even if a scrape summary reports failures",
+    )
+    parser.add_argument(
+        "--override",
+        "-o",
+        action="store_true",
+        help="Rebuild an existing bundle with the same version",
+    )
+    parser.add_argument("--draft", action="store_true", help="Create a draft release")
+    parser.add_argument(
+        "--dry-run", action="store_true", help="Build and print, do not publish"
+    )
+    args = parser.parse_args()
+    redact.install()
+
+    year, month_num = args.month
+    month = f"{year:04d}-{month_num:02d}"
+    validation = args.validation_report or (
+        Path(args.local_root) / "validation_reports" / f"{year}_{month_num:02d}.txt"
+    )
+    try:
+        publish(
+            month,
+            args.local_root,
+            args.output_dir,
+            args.repo,
+            os.environ.get("GITHUB_TOKEN"),
+            tag_prefix=args.tag_prefix,
+            sqlite=args.sqlite,
+            allow_partial=args.allow_partial,
+            override=args.override,
+            draft=args.draft,
+            dry_run=args.dry_run,
+            validation_report=validation,
+        )
+    except (PublishError, FileExistsError, ValueError, OSError) as e:
+        logger.error("%s", e)
+        return 1
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -2,23 +2,61 @@
 """
 Full FIDE data pipeline for a given month.
 
-Runs the complete pipeline in order:
-1. Get federations -> {local_root}/{run_type}/{run_name}/data/federations.csv
-2. Get tournaments -> {local_root}/{run_type}/{run_name}/data/tournament_ids.txt
-3. Get tournament details -> {local_root}/{run_type}/{run_name}/data/tournament_details.parquet
-4. Get player list -> {local_root}/{run_type}/{run_name}/data/players_list.parquet
-5. Get tournament reports -> ...
+The pipeline is a DAG of stages, each with declared dependencies and outputs:
+
+  federations   -> {local_root}/federations/data/federations_{timestamp}.csv (shared)
+  ids           -> {local_root}/{run_type}/{run_name}/data/tournament_ids.txt
+  details       -> .../data/tournament_details.parquet                  (needs ids)
+  players       -> {local_root}/player_lists/data/player_list_{timestamp}.parquet
+  reports       -> .../data/tournament_reports_players.parquet, _games.parquet
+                   (crosstables and games; needs details, players)
+  rating_input  -> .../data/rating_input_games.parquet                  (needs reports)
+  validate      -> {local_root}/validation_reports/YYYY_MM.txt  (needs reports, players)
+
+ids also needs federations. Federations and the player list are shared across
+months and refreshed on their own schedule, so they are order-only dependencies:
+they must exist, but a newer file does not make a month's stages out of date.
+
+Without --until every stage runs in order (minus --skip-*). With --until STAGE only
+STAGE and the stages it depends on are considered, and a stage runs only if it is
+out of date, make-style: an output is missing, a dependency is older-than-newer
+(its newest output is newer than this stage's oldest output), or a dependency
+runs in this invocation. --force reruns them all; --dry-run prints the plan.
+There is no ratings or exports stage yet; rating_input is the last data stage.
 
 Default local_root=data, run_type=prod, run_name=YYYY-MM (from year/month).
 
 Use --test for a quick smoke run with limited sampling.
+
+Usage:
+  uv run scripts/run_full_pipeline.py --year 2025 --month 12
+  uv run scripts/run_full_pipeline.py --until rating_input --month 2024-06
+  uv run scripts/run_full_pipeline.py --until details --month 2024-06 --dry-run
 """
 
 import argparse
 import logging
 import subprocess
 import sys
+from dataclasses import dataclass
 from pathlib import Path
+from typing import Callable, Dict, List, Optional, Set, Tuple
+
+SCRAPER_DIR = Path(__file__).resolve().parent.parent / "src" / "scraper"
+SCRIPTS_DIR = Path(__file__).resolve().parent
+
+sys.path.insert(0, str(SCRAPER_DIR))
+
+import months  # noqa: E402
+import redact  # noqa: E402
+import tracing  # noqa: E402
+from run_summary import EXIT_PARTIAL  # noqa: E402
+from s3_io import (  # noqa: E402
+    FEDERATIONS_DATA_PREFIX,
+    PLAYER_LISTS_DATA_PREFIX,
+    build_local_path_for_run,
+    get_latest_in_local_prefix,
+)
 
 logging.basicConfig(
     level=logging.INFO,
@@ -27,26 +65,272 @@ logging.basicConfig(
 )
 logger = logging.getLogger(__name__)
 
-SCRAPER_DIR = Path(__file__).resolve().parent.parent / "src" / "scraper"
-SCRIPTS_DIR = Path(__file__).resolve().parent
-
 # Test mode defaults: limit sampling for slow scripts
 TEST_LIMIT_TOURNAMENTS = 5
 TEST_LIMIT_DETAILS = 5
 TEST_LIMIT_REPORTS = 5
 
 
-def run(cmd: list[str], cwd: Path, desc: str) -> bool:
-    """Run a command; return True on success."""
+@dataclass
+class RunContext:
+    """Everything a stage needs to build its command and locate its outputs."""
+
+    base_dir: Path
+    year: int
+    month: int
+    local_root: str = "data"
+    run_type: str = "prod"
+    quiet: bool = False
+    override: bool = False
+    no_validation: bool = False
+    limit_tournaments: int = 0
+    limit_details: int = 0
+    limit_reports: int = 0
+
+    @property
+    def run_name(self) -> str:
+        return f"{self.year}-{self.month:02d}"
+
+    def run_path(self, subfolder: str, name: str) -> Path:
+        return self.base_dir / build_local_path_for_run(
+            self.local_root, self.run_type, self.run_name, subfolder, name
+        )
+
+    def shared_latest(self, prefix: str) -> List[Path]:
+        latest, _ = get_latest_in_local_prefix(self.base_dir / self.local_root, prefix)
+        # A placeholder path that never exists marks the output missing
+        return [latest or self.base_dir / self.local_root / prefix / "*"]
+
+    def run_args(self, month: bool = True) -> List[str]:
+        args = ["--year", str(self.year), "--month", str(self.month)] if month else []
+        return args + [
+            "--local-root",
+            self.local_root,
+            "--run-type",
+            self.run_type,
+            "--run-name",
+            self.run_name,
+        ]
+
+
+@dataclass
+class Stage:
+    name: str
+    desc: str
+    outputs: Callable[[RunContext], List[Path]]
+    command: Callable[[RunContext], List[str]]
+    deps: Tuple[str, ...] = ()
+    # Must have run, but newer outputs here do not make this stage out of date
+    order_only: Tuple[str, ...] = ()
+
+
+def _federations_cmd(ctx: RunContext) -> List[str]:
+    cmd = [sys.executable, str(SCRAPER_DIR / "get_federations.py")]
+    cmd += ctx.run_args(month=False) + (["--quiet"] if ctx.quiet else [])
+    return cmd + (["--override"] if ctx.override else [])
+
+
+def _ids_cmd(ctx: RunContext) -> List[str]:
+    cmd = [sys.executable, str(SCRAPER_DIR / "get_tournaments.py")] + ctx.run_args()
+    cmd += ["--quiet"] if ctx.quiet else []
+    if ctx.limit_tournaments > 0:
+        cmd += ["--limit", str(ctx.limit_tournaments)]
+    return cmd
+
+
+def _details_cmd(ctx: RunContext) -> List[str]:
+    cmd = [sys.executable, str(SCRAPER_DIR / "get_tournament_details.py")]
+    cmd += ctx.run_args()
+    if ctx.limit_details > 0:
+        cmd += ["--limit", str(ctx.limit_details)]
+    return cmd
+
+
+def _players_cmd(ctx: RunContext) -> List[str]:
+    cmd = [sys.executable, str(SCRAPER_DIR / "get_player_list.py")]
+    cmd += ctx.run_args(month=False) + (["--quiet"] if ctx.quiet else [])
+    # An interrupted download stays here, so the next run resumes it
+    download_dir = ctx.base_dir / ctx.local_root / "player_lists" / "download"
+    cmd += ["--download-dir", str(download_dir)]
+    return cmd + (["--override"] if ctx.override else [])
+
+
+def _reports_cmd(ctx: RunContext) -> List[str]:
+    cmd = [sys.executable, str(SCRAPER_DIR / "get_tournament_reports.py")]
+    cmd += ctx.run_args()
+    if ctx.limit_reports > 0:
+        cmd += ["--limit", str(ctx.limit_reports)]
+    if ctx.no_validation:
+        cmd.append("--no-validation")
+    return cmd
+
+
+def _rating_input_cmd(ctx: RunContext) -> List[str]:
+    return [
+        sys.executable,
+        str(SCRAPER_DIR / "rating_input.py"),
+        "--input",
+        str(ctx.run_path("data", "tournament_reports_games.parquet")),
+        "--output",
+        str(ctx.run_path("data", "rating_input_games.parquet")),
+        "--report",
+        str(ctx.run_path("reports", "rating_input_report.json")),
+    ]
+
+
+def _validation_report(ctx: RunContext) -> Path:
+    # Where publish_release.py looks for it
+    name = f"{ctx.year}_{ctx.month:02d}.txt"
+    return ctx.base_dir / ctx.local_root / "validation_reports" / name
+
+
+def _validate_cmd(ctx: RunContext) -> List[str]:
+    cmd = [
+        sys.executable,
+        str(SCRIPTS_DIR.parent / "exploratory" / "validate_pipeline.py"),
+    ] + ctx.run_args()
+    cmd += ["--output", str(_validation_report(ctx))]
+    return cmd + (["--quiet"] if ctx.quiet else [])
+
+
+STAGES: List[Stage] = [
+    Stage(
+        "federations",
+        "Fetch federations",
+        lambda ctx: ctx.shared_latest(FEDERATIONS_DATA_PREFIX),
+        _federations_cmd,
+    ),
+    Stage(
+        "ids",
+        "Get tournaments (by federation)",
+        lambda ctx: [ctx.run_path("data", "tournament_ids.txt")],
+        _ids_cmd,
+        order_only=("federations",),
+    ),
+    Stage(
+        "details",
+        "Get tournament details",
+        lambda ctx: [ctx.run_path("data", "tournament_details.parquet")],
+        _details_cmd,
+        deps=("ids",),
+    ),
+    # Player list before reports so default validation has data
+    Stage(
+        "players",
+        "Get player list",
+        lambda ctx: ctx.shared_latest(PLAYER_LISTS_DATA_PREFIX),
+        _players_cmd,
+        order_only=("federations",),
+    ),
+    Stage(
+        "reports",
+        "Get tournament reports (crosstables and games)",
+        lambda ctx: [
+            ctx.run_path("data", "tournament_reports_players.parquet"),
+            ctx.run_path("data", "tournament_reports_games.parquet"),
+        ],
+        _reports_cmd,
+        deps=("details",),
+        order_only=("players",),
+    ),
+    Stage(
+        "rating_input",
+        "Select rated games (rating input policy)",
+        lambda ctx: [ctx.run_path("data", "rating_input_games.parquet")],
+        _rating_input_cmd,
+        deps=("reports",),
+    ),
+    Stage(
+        "validate",
+        "Validate (player list vs reports, details vs reports)",
+        lambda ctx: [_validation_report(ctx)],
+        _validate_cmd,
+        deps=("reports",),
+        order_only=("players",),
+    ),
+]
+STAGES_BY_NAME: Dict[str, Stage] = {s.name: s for s in STAGES}
+
+
+def required_stages(target: str) -> List[Stage]:
+    """target and everything it (transitively) depends on, in pipeline order."""
+    needed: Set[str] = set()
+    stack = [target]
+    while stack:
+        name = stack.pop()
+        if name in needed:
+            continue
+        needed.add(name)
+        stage = STAGES_BY_NAME[name]
+        stack.extend(stage.deps + stage.order_only)
+    return [s for s in STAGES if s.name in needed]
+
+
+def _mtimes(paths: List[Path]) -> Optional[List[float]]:
+    """Modification times, or None if any path is missing."""
+    if not all(p.exists() for p in paths):
+        return None
+    return [p.stat().st_mtime for p in paths]
+
+
+def out_of_date(stage: Stage, ctx: RunContext, rerun: Set[str]) -> Optional[str]:
+    """Why stage must run (None if up to date). rerun = stages already scheduled."""
+    outputs = stage.outputs(ctx)
+    mtimes = _mtimes(outputs)
+    if mtimes is None:
+        missing = next(p for p in outputs if not p.exists())
+        return f"missing {missing}"
+    oldest = min(mtimes)
+    for dep in stage.deps:
+        if dep in rerun:
+            return f"{dep} reruns"
+        dep_mtimes = _mtimes(STAGES_BY_NAME[dep].outputs(ctx))
+        if dep_mtimes and max(dep_mtimes) > oldest:
+            return f"older than {dep} outputs"
+    return None
+
+
+def plan(
+    target: str, ctx: RunContext, force: bool = False, skip: Set[str] = frozenset()
+) -> List[Tuple[Stage, str]]:
+    """Stages to run for target, in order, each with the reason it is out of date."""
+    steps: List[Tuple[Stage, str]] = []
+    rerun: Set[str] = set()
+    for stage in required_stages(target):
+        if stage.name in skip:
+            continue
+        reason = "forced" if force else out_of_date(stage, ctx, rerun)
+        if reason:
+            steps.append((stage, reason))
+            rerun.add(stage.name)
+    return steps
+
+
+def run(cmd: list[str], cwd: Path, desc: str) -> int:
+    """Run a command; return its exit code (0 success, 2 partial, else failed)."""
     logger.info("")
     logger.info("=" * 80)
     logger.info("%s", desc)
     logger.info("-" * 80)
-    result = subprocess.run(cmd, cwd=str(cwd))
-    if result.returncode != 0:
+    # Stage scripts join this run's trace through TRACEPARENT (when tracing is on)
+    result = subprocess.run(cmd, cwd=str(cwd), env=tracing.child_env())
+    if result.returncode == EXIT_PARTIAL:
+        logger.warning("%s finished with some failures; continuing", desc)
+    elif result.returncode != 0:
         logger.error("%s failed (exit code %d)", desc, result.returncode)
-        return False
-    return True
+    return result.returncode
+
+
+def parse_month(s: str) -> Tuple[Optional[int], int]:
+    """Parse --month as "6" (year from --year) or "2024-06"."""
+    if "-" in s:
+        return months.parse_month(s)
+    try:
+        return None, int(s)
+    except ValueError:
+        raise argparse.ArgumentTypeError(
+            f"Invalid month '{s}': expected 1-12 or YYYY-MM"
+        ) from None
 
 
 def main() -> int:
@@ -58,14 +342,30 @@ def main() -> int:
     parser.add_argument(
         "--year",
         type=int,
-        required=True,
-        help="Year (e.g. 2025)",
+        default=None,
+        help="Year (e.g. 2025); not needed when --month is YYYY-MM",
     )
     parser.add_argument(
         "--month",
-        type=int,
+        type=parse_month,
         required=True,
-        help="Month 1-12",
+        help="Month 1-12, or YYYY-MM",
+    )
+    parser.add_argument(
+        "--until",
+        choices=[s.name for s in STAGES],
+        default=None,
+        help="Run only this stage and its dependencies, skipping up-to-date ones",
+    )
+    parser.add_argument(
+        "--force",
+        action="store_true",
+        help="With --until, rerun every required stage even if up to date",
+    )
+    parser.add_argument(
+        "--dry-run",
+        action="store_true",
+        help="Print the stages that would run (and why) without running them",
     )
     parser.add_argument(
         "--data-dir",
@@ -97,12 +397,12 @@ def main() -> int:
     parser.add_argument(
         "--skip-federations",
         action="store_true",
-        help="Skip federation fetch (use existing data/federations.csv)",
+        help="Skip federation fetch (use existing federations file)",
     )
     parser.add_argument(
         "--skip-player-list",
         action="store_true",
-        help="Skip player list download (use existing src/data/players_list.parquet)",
+        help="Skip player list download (use existing player list)",
     )
     parser.add_argument(
         "--skip-validation",
@@ -126,20 +426,22 @@ def main() -> int:
         action="store_true",
         help="Overwrite existing outputs (federations, player list) instead of skipping",
     )
+    tracing.add_arguments(parser)
     args = parser.parse_args()
+    redact.install()
 
-    if args.month < 1 or args.month > 12:
+    year, month = args.month
+    year = year or args.year
+    if year is None:
+        logger.error("--year is required unless --month is YYYY-MM")
+        return 1
+    if month < 1 or month > 12:
         logger.error("Month must be 1-12")
         return 1
 
     if args.quiet:
         logging.getLogger().setLevel(logging.WARNING)
 
-    base_dir = Path(__file__).resolve().parent.parent
-    month_key = f"{args.year}_{args.month:02d}"
-    run_name = f"{args.year}-{args.month:02d}"
-    local_root = getattr(args, "local_root", "data")
-
     # Determine limits for test mode
     limit_details = args.limit or (TEST_LIMIT_DETAILS if args.test else 0)
     limit_reports = args.limit or (TEST_LIMIT_REPORTS if args.test else 0)
@@ -153,131 +455,65 @@ def main() -> int:
             limit_reports,
         )
 
-    common_quiet = ["--quiet"] if args.quiet else []
-
-    # Step 1: Federations
-    if not args.skip_federations:
-        fed_cmd = [
-            sys.executable,
-            str(SCRAPER_DIR / "get_federations.py"),
-            "--local-root",
-            local_root,
-            "--run-type",
-            args.run_type,
-            "--run-name",
-            run_name,
-        ] + common_quiet
-        if args.override:
-            fed_cmd.append("--override")
-        if not run(fed_cmd, base_dir, "STEP 1: Fetch federations"):
-            return 1
-
-    # Step 2: Tournaments
-    tournaments_cmd = [
-        sys.executable,
-        str(SCRAPER_DIR / "get_tournaments.py"),
-        "--year",
-        str(args.year),
-        "--month",
-        str(args.month),
-        "--local-root",
-        local_root,
-        "--run-type",
-        args.run_type,
-        "--run-name",
-        run_name,
-    ] + common_quiet
-    if limit_tournaments > 0:
-        tournaments_cmd.extend(["--limit", str(limit_tournaments)])
-    if not run(tournaments_cmd, base_dir, "STEP 2: Get tournaments (by federation)"):
-        return 1
-
-    # Step 3: Tournament details
-    details_cmd = [
-        sys.executable,
-        str(SCRAPER_DIR / "get_tournament_details.py"),
-        "--year",
-        str(args.year),
-        "--month",
-        str(args.month),
-        "--local-root",
-        local_root,
-        "--run-type",
-        args.run_type,
-        "--run-name",
-        run_name,
-    ]
-    if limit_details > 0:
-        details_cmd.extend(["--limit", str(limit_details)])
-    if not run(details_cmd, base_dir, "STEP 3: Get tournament details"):
-        return 1
-
-    # Step 4: Player list (before reports so default validation has data)
-    if not args.skip_player_list:
-        player_cmd = [
-            sys.executable,
-            str(SCRAPER_DIR / "get_player_list.py"),
-            "--local-root",
-            local_root,
-            "--run-type",
-            args.run_type,
-            "--run-name",
-            run_name,
-        ] + common_quiet
-        if args.override:
-            player_cmd.append("--override")
-        if not run(player_cmd, base_dir, "STEP 4: Get player list"):
-            return 1
+    ctx = RunContext(
+        base_dir=Path(__file__).resolve().parent.parent,
+        year=year,
+        month=month,
+        local_root=args.local_root,
+        run_type=args.run_type,
+        quiet=args.quiet,
+        override=args.override,
+        no_validation=args.no_validation,
+        limit_tournaments=limit_tournaments,
+        limit_details=limit_details,
+        limit_reports=limit_reports,
+    )
+    skip = set()
+    if args.skip_federations:
+        skip.add("federations")
+    if args.skip_player_list:
+        skip.add("players")
+    if args.skip_validation:
+        skip.add("validate")
 
-    # Step 5: Tournament reports
-    reports_cmd = [
-        sys.executable,
-        str(SCRAPER_DIR / "get_tournament_reports.py"),
-        "--year",
-        str(args.year),
-        "--month",
-        str(args.month),
-        "--local-root",
-        local_root,
-        "--run-type",
-        args.run_type,
-        "--run-name",
-        run_name,
-    ]
-    if limit_reports > 0:
-        reports_cmd.extend(["--limit", str(limit_reports)])
-    if args.no_validation:
-        reports_cmd.append("--no-validation")
-    if not run(reports_cmd, base_dir, "STEP 5: Get tournament reports (games)"):
-        return 1
+    if args.until:
+        steps = plan(args.until, ctx, force=args.force, skip=skip)
+    else:
+        steps = [(s, "full run") for s in STAGES if s.name not in skip]
 
-    # Step 6: Validation
-    if not args.skip_validation:
-        validate_cmd = [
-            sys.executable,
-            str(SCRIPTS_DIR.parent / "exploratory" / "validate_pipeline.py"),
-            "--year",
-            str(args.year),
-            "--month",
-            str(args.month),
-            "--local-root",
-            local_root,
-            "--run-type",
-            args.run_type,
-            "--run-name",
-            run_name,
-        ]
-        if args.quiet:
-            validate_cmd.append("--quiet")
-        if not run(
-            validate_cmd,
-            base_dir,
-            "STEP 6: Validate (player list vs reports, details vs reports)",
-        ):
+    if not steps:
+        logger.info("Everything up to date for %s", args.until)
+        return 0
+    if not args.dry_run:
+        tracing.configure_from_args(
+            args, "pipeline", month=f"{year:04d}-{month:02d}", run_type=args.run_type
+        )
+    partial: List[str] = []
+    for i, (stage, reason) in enumerate(steps, 1):
+        if args.dry_run:
+            print(f"{stage.name}: {reason}")
+            continue
+        desc = f"STEP {i}/{len(steps)}: {stage.desc} [{stage.name}: {reason}]"
+        with tracing.span("stage", stage=stage.name, reason=reason) as stage_span:
+            code = run(stage.command(ctx), ctx.base_dir, desc)
+            tracing.set_attributes(
+                stage_span,
+                exit_code=code,
+                error=f"exit code {code}" if code not in (0, EXIT_PARTIAL) else None,
+            )
+        if code == EXIT_PARTIAL:
+            partial.append(stage.name)
+        elif code != 0:
             return 1
+    if args.dry_run:
+        return 0
 
     logger.info("")
     logger.info("=" * 80)
+    if partial:
+        logger.warning("Pipeline completed with failures in: %s", ", ".join(partial))
+        logger.info("=" * 80)
+        return EXIT_PARTIAL
     logger.info("Pipeline completed successfully!")
     logger.info("=" * 80)
     return 0
@@ -9,6 +9,8 @@ Example:
   uv run scripts/run_prod_backfill.py --start 2024-01 --end 2024-12
   uv run scripts/run_prod_backfill.py --start 2024-01 --end 2024-06 --concurrency 2
   uv run scripts/run_prod_backfill.py --start 2024-01 --end 2024-03 --details-rate-limit 0.4 --reports-rate-limit 0.3
+  uv run scripts/run_prod_backfill.py --start 2010-01 --end 2015-12 --active-hours 00:00-06:00
+  uv run scripts/run_prod_backfill.py --start 2008-01 --end 2012-12 -c 4 --global-rate-limit 2
 
 Requires AWS credentials (e.g. aws configure). Uses default region unless --region.
 """
@@ -19,13 +21,18 @@ import logging
 import sys
 import time
 from dataclasses import dataclass
-from datetime import datetime, timezone
-from typing import Iterator
+from datetime import datetime, time as dt_time, timezone
+from pathlib import Path
 
 import boto3
 import requests
 from botocore.exceptions import ClientError
 
+sys.path.insert(0, str(Path(__file__).parent.parent / "src" / "scraper"))
+
+import redact  # noqa: E402
+from months import month_range, parse_month  # noqa: E402
+
 # FIDE periods API (same as pipeline_historical); RUS has long history
 PERIODS_URL = "https://ratings.fide.com/a_tournaments_panel.php"
 REFERENCE_FED = "RUS"
@@ -43,37 +50,42 @@ POLL_INTERVAL_SEC = 60
 STATE_MACHINE_NAME = "fide-glicko-pipeline"
 
 
-def parse_month(s: str) -> tuple[int, int]:
-    """Parse YYYY-MM to (year, month)."""
+def parse_active_hours(s: str) -> tuple[dt_time, dt_time]:
+    """Parse HH:MM-HH:MM (UTC) to (start, end). The window may wrap past midnight."""
     try:
-        parts = s.split("-")
-        if len(parts) != 2:
-            raise ValueError()
-        year, month = int(parts[0]), int(parts[1])
-        if not (1 <= month <= 12):
-            raise ValueError("month must be 1-12")
-        return year, month
-    except (ValueError, IndexError) as e:
+        start_s, end_s = s.split("-")
+        start = datetime.strptime(start_s.strip(), "%H:%M").time()
+        end = datetime.strptime(end_s.strip(), "%H:%M").time()
+    except ValueError as e:
         raise argparse.ArgumentTypeError(
-            f"Invalid month '{s}': expected YYYY-MM (e.g. 2024-01)"
+            f"Invalid active hours '{s}': expected HH:MM-HH:MM (e.g. 00:00-06:00)"
         ) from e
+    if start == end:
+        raise argparse.ArgumentTypeError(
+            f"Invalid active hours '{s}': start and end must differ"
+        )
+    return start, end
+
+
+def in_active_window(now: dt_time, window: tuple[dt_time, dt_time] | None) -> bool:
+    """True if now falls in [start, end). No window means always active."""
+    if window is None:
+        return True
+    start, end = window
+    if start < end:
+        return start <= now < end
+    # Wraps midnight, e.g. 22:00-04:00
+    return now >= start or now < end
 
 
-def month_range(
-    start: tuple[int, int], end: tuple[int, int]
-) -> Iterator[tuple[int, int]]:
-    """Yield (year, month) for each month from start (incl) to end (incl)."""
-    sy, sm = start
-    ey, em = end
-    if (sy, sm) > (ey, em):
-        raise ValueError("start month must be <= end month")
-    y, m = sy, sm
-    while (y, m) <= (ey, em):
-        yield y, m
-        m += 1
-        if m > 12:
-            m = 1
-            y += 1
+def per_chunk_rate_limit(
+    global_rate: float, concurrency: int, max_concurrency: int
+) -> float:
+    """
+    Split a global FIDE requests/second budget across every chunk that can run at
+    once: concurrency executions x max_concurrency Map branches each.
+    """
+    return global_rate / (concurrency * max_concurrency)
 
 
 def fetch_available_periods() -> list[tuple[int, int]]:
@@ -206,17 +218,62 @@ def main() -> int:
         metavar="REQ_PER_S",
         help="Reports chunk: FIDE requests per second (omit to use SSM or pipeline default)",
     )
+    parser.add_argument(
+        "--global-rate-limit",
+        type=float,
+        default=None,
+        metavar="REQ_PER_S",
+        help="Total FIDE requests per second across all concurrent months. Sets "
+        "details/reports rate limits to this / (concurrency x max-concurrency)",
+    )
+    parser.add_argument(
+        "--active-hours",
+        type=parse_active_hours,
+        default=None,
+        metavar="HH:MM-HH:MM",
+        help="Only start new executions inside this UTC window (e.g. 00:00-06:00). "
+        "This holds back new starts only: executions already running are not "
+        "paused and keep sending FIDE requests past the end of the window. "
+        "Pending months wait for the next window",
+    )
     parser.add_argument(
         "--dry-run",
         action="store_true",
         help="Print months that would run, do not start",
     )
     args = parser.parse_args()
-
+    redact.install()
     if args.concurrency < 1:
         logger.error("--concurrency must be >= 1")
         return 1
 
+    if args.max_concurrency < 1:
+        logger.error("--max-concurrency must be >= 1")
+        return 1
+
+    if args.global_rate_limit is not None:
+        if args.global_rate_limit <= 0:
+            logger.error("--global-rate-limit must be > 0")
+            return 1
+        if args.details_rate_limit is not None or args.reports_rate_limit is not None:
+            logger.error(
+                "--global-rate-limit cannot be combined with "
+                "--details-rate-limit/--reports-rate-limit"
+            )
+            return 1
+        chunk_rate = per_chunk_rate_limit(
+            args.global_rate_limit, args.concurrency, args.max_concurrency
+        )
+        args.details_rate_limit = args.reports_rate_limit = chunk_rate
+        logger.info(
+            "Global budget %.2f req/s -> %.3f req/s per chunk "
+            "(%d executions x %d chunks)",
+            args.global_rate_limit,
+            chunk_rate,
+            args.concurrency,
+            args.max_concurrency,
+        )
+
     try:
         range_months = list(month_range(args.start, args.end))
     except ValueError as e:
@@ -269,6 +326,12 @@ def main() -> int:
         *args.end,
         args.concurrency,
     )
+    if args.active_hours:
+        logger.info(
+            "Active hours: %s-%s UTC",
+            args.active_hours[0].strftime("%H:%M"),
+            args.active_hours[1].strftime("%H:%M"),
+        )
 
     if args.dry_run:
         for y, m in months:
@@ -290,9 +353,26 @@ def main() -> int:
     failed: list[tuple[int, int, str, str]] = []
     total = len(months)
 
+    paused = False
+
     def start_next() -> bool:
+        nonlocal paused
         if not pending or len(running) >= args.concurrency:
             return False
+        if not in_active_window(datetime.now(timezone.utc).time(), args.active_hours):
+            if not paused:
+                logger.info(
+                    "Outside active hours (%s-%s UTC); holding back %d pending "
+                    "months (running executions continue)",
+                    args.active_hours[0].strftime("%H:%M"),
+                    args.active_hours[1].strftime("%H:%M"),
+                    len(pending),
+                )
+                paused = True
+            return False
+        if paused:
+            logger.info("Inside active hours; starting pending months again")
+            paused = False
         y, m = pending.pop(0)
         name = f"backfill-{y:04d}-{m:02d}-{datetime.now(timezone.utc).strftime('%Y%m%d%H%M%S')}"
         input_dict = {
@@ -45,6 +45,7 @@ from pathlib import Path
 
 sys.path.insert(0, str(Path(__file__).parent.parent / "src" / "scraper"))
 
+import redact  # noqa: E402
 from s3_io import write_output
 
 logging.basicConfig(
@@ -140,6 +141,7 @@ def main() -> int:
         help="Output path: local file or s3://bucket/key",
     )
     args = parser.parse_args()
+    redact.install()
 
     start = time.time()
 
@@ -0,0 +1,207 @@
+#!/usr/bin/env python3
+"""
+Live preflight: check that FIDE still serves and we still parse each source.
+
+Run it before a large scrape. Each check makes one or two requests:
+
+  details      tournament_information.phtml for a known tournament (368261,
+               the 2024 Candidates; tests/fixtures/candidates_24_details.html)
+               parses to its name, dates and player count
+  profile      the profile page of a known player (1503014) parses to name,
+               federation, sex, birth year and title
+  player_list  the first KB of players_list_xml.zip (HTTP Range) is a zip whose
+               first entry is an XML file; the 500 MB download is not fetched
+
+Prints PASS/FAIL per check with the reason, or JSON with --json. Exit code 0
+if every check passed, else 1. A FAIL on details or profile with a response
+usually means FIDE changed the page layout; on all checks, a network problem.
+
+Usage:
+  uv run scripts/selftest.py
+  uv run scripts/selftest.py --check details --check profile --json
+"""
+
+import argparse
+import json
+import logging
+import struct
+import sys
+import time
+from dataclasses import asdict, dataclass
+from pathlib import Path
+from typing import Callable, Dict, List, Optional
+
+sys.path.insert(0, str(Path(__file__).parent.parent / "src" / "scraper"))
+
+import requests  # noqa: E402
+
+import redact  # noqa: E402
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+KNOWN_TOURNAMENT = "368261"
+KNOWN_PLAYER = "1503014"
+# Field -> expected value, or None for "present and non-empty". Values are ones
+# that cannot change for a finished tournament or a retired title.
+EXPECTED_DETAILS = {
+    "name": "FIDE Candidates Tournament 2024",
+    "start_date": "2024-04-03",
+    "end_date": "2024-04-23",
+    "n_players": "8",
+    "time_control": None,
+}
+EXPECTED_PROFILE = {
+    "name": None,
+    "fed": None,
+    "sex": "M",
+    "byear": 1990,
+    "title": "GM",
+}
+ZIP_MAGIC = b"PK\x03\x04"
+HEADER_BYTES = 1024
+
+
+@dataclass
+class CheckResult:
+    check: str
+    ok: bool
+    detail: str
+    seconds: float
+
+
+def field_errors(record: Optional[Dict], expected: Dict) -> List[str]:
+    """Fields of record that are missing or differ from expected."""
+    if not record:
+        return ["nothing parsed"]
+    errors = []
+    for field, want in expected.items():
+        got = record.get(field)
+        if got in (None, ""):
+            errors.append(f"{field} missing")
+        elif want is not None and got != want:
+            errors.append(f"{field}={got!r}, expected {want!r}")
+    return errors
+
+
+def zip_first_entry(header: bytes) -> str:
+    """Name of the first entry in a zip's local file header; ValueError if not a zip."""
+    if not header.startswith(ZIP_MAGIC) or len(header) < 30:
+        raise ValueError("not a zip file")
+    (name_length,) = struct.unpack("<H", header[26:28])
+    if len(header) < 30 + name_length:
+        raise ValueError("zip header truncated")
+    return header[30 : 30 + name_length].decode("utf-8", errors="replace")
+
+
+def check_details(session: requests.Session) -> str:
+    from get_tournament_details import fetch_tournament_details
+
+    details, error, _, _ = fetch_tournament_details(KNOWN_TOURNAMENT, session)
+    if error:
+        raise ValueError(error)
+    errors = field_errors(details, EXPECTED_DETAILS)
+    if errors:
+        raise ValueError("; ".join(errors))
+    return f"{KNOWN_TOURNAMENT}: {details['name']}"
+
+
+def check_profile(session: requests.Session) -> str:
+    from get_player_profiles import fetch_profile
+
+    profile, error = fetch_profile(KNOWN_PLAYER, session)
+    if error:
+        raise ValueError(error)
+    errors = field_errors(profile, EXPECTED_PROFILE)
+    if errors:
+        raise ValueError("; ".join(errors))
+    return f"{KNOWN_PLAYER}: {profile['name']} ({profile['fed']})"
+
+
+def check_player_list(session: requests.Session) -> str:
+    import http_timeouts
+    import user_agents
+    from get_player_list import DOWNLOAD_URL
+
+    headers = user_agents.browser_headers({"Range": f"bytes=0-{HEADER_BYTES - 1}"})
+    response = session.get(
+        DOWNLOAD_URL,
+        headers=headers,
+        timeout=http_timeouts.requests_timeout(),
+        stream=True,
+    )
+    try:
+        if response.status_code not in (200, 206):
+            raise ValueError(f"HTTP {response.status_code}")
+        header = next(response.iter_content(HEADER_BYTES), b"")
+    finally:
+        response.close()
+    name = zip_first_entry(header)
+    if not name.lower().endswith(".xml"):
+        raise ValueError(f"first zip entry is {name!r}, expected an .xml file")
+    size = response.headers.get("Content-Range", "").rpartition("/")[2]
+    return name + (f", {int(size) / 1e6:.0f} MB" if size.isdigit() else "")
+
+
+CHECKS: Dict[str, Callable[[requests.Session], str]] = {
+    "details": check_details,
+    "profile": check_profile,
+    "player_list": check_player_list,
+}
+
+
+def run_checks(
+    names: List[str], session: Optional[requests.Session] = None
+) -> List[CheckResult]:
+    """Run the named checks in order; a check fails on any exception."""
+    session = session or requests.Session()
+    results = []
+    for name in names:
+        t0 = time.perf_counter()
+        try:
+            ok, detail = True, CHECKS[name](session)
+        except Exception as e:
+            ok, detail = False, str(e) or type(e).__name__
+        results.append(
+            CheckResult(name, ok, detail, round(time.perf_counter() - t0, 2))
+        )
+    return results
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="Live preflight check of FIDE sources and parsers",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    parser.add_argument(
+        "--check",
+        action="append",
+        choices=list(CHECKS),
+        help="Check to run (repeatable; default: all)",
+    )
+    parser.add_argument("--json", action="store_true", help="Print results as JSON")
+    args = parser.parse_args()
+    redact.install()
+
+    results = run_checks(args.check or list(CHECKS))
+    if args.json:
+        print(json.dumps([asdict(r) for r in results], indent=2))
+    else:
+        for r in results:
+            log = logger.info if r.ok else logger.error
+            status = "PASS" if r.ok else "FAIL"
+            log("%s %-12s %s (%.1fs)", status, r.check, r.detail, r.seconds)
+    failed = [r.check for r in results if not r.ok]
+    if failed:
+        logger.error("Selftest failed: %s", ", ".join(failed))
+        return 1
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
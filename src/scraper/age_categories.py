@@ -0,0 +1,66 @@
+"""
+FIDE age-category eligibility by birth year.
+
+FIDE only records birth year, so eligibility is by calendar year rather than
+birthday: a player's age for a list is list year - birth year, i.e. the age they
+reach at some point during that year. A player turning 20 in December 2024 is
+still 20 (junior) on the January 2024 list, and one turning 50 in December is
+already senior on that January list. Categories:
+
+  U8 .. U20   age <= N   (juniors are U20)
+  S50, S65    age >= N   (seniors)
+
+Players without a birth year are in no age category. The same rules apply to the
+girls' lists; combine with sex == "F" for those.
+"""
+
+from typing import List, Optional
+
+import pandas as pd
+
+JUNIOR_CATEGORIES = {f"U{n}": n for n in (8, 10, 12, 14, 16, 18, 20)}
+SENIOR_CATEGORIES = {"S50": 50, "S65": 65}
+CATEGORIES = {**JUNIOR_CATEGORIES, **SENIOR_CATEGORIES}
+
+
+def age_in_year(byear: Optional[int], list_year: int) -> Optional[int]:
+    """FIDE age for a list in list_year (None without a birth year)."""
+    if byear is None or byear != byear or byear <= 0:  # NaN from Parquet
+        return None
+    return list_year - int(byear)
+
+
+def is_eligible(byear: Optional[int], list_year: int, category: str) -> bool:
+    """True if a player born in byear belongs in category on a list_year list."""
+    if category not in CATEGORIES:
+        raise ValueError(f"Unknown age category '{category}'")
+    age = age_in_year(byear, list_year)
+    if age is None:
+        return False
+    if category in JUNIOR_CATEGORIES:
+        return age <= JUNIOR_CATEGORIES[category]
+    return age >= SENIOR_CATEGORIES[category]
+
+
+def is_junior(byear: Optional[int], list_year: int) -> bool:
+    return is_eligible(byear, list_year, "U20")
+
+
+def is_senior(byear: Optional[int], list_year: int) -> bool:
+    return is_eligible(byear, list_year, "S50")
+
+
+def eligible_categories(byear: Optional[int], list_year: int) -> List[str]:
+    """All categories a player is eligible for, youngest/oldest first."""
+    return [c for c in CATEGORIES if is_eligible(byear, list_year, c)]
+
+
+def category_mask(byear: pd.Series, list_year: int, category: str) -> pd.Series:
+    """Boolean mask over a byear column for leaderboard filtering."""
+    if category not in CATEGORIES:
+        raise ValueError(f"Unknown age category '{category}'")
+    age = list_year - pd.to_numeric(byear, errors="coerce")
+    valid = pd.to_numeric(byear, errors="coerce") > 0
+    if category in JUNIOR_CATEGORIES:
+        return valid & (age <= JUNIOR_CATEGORIES[category])
+    return valid & (age >= SENIOR_CATEGORIES[category])
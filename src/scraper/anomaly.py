@@ -0,0 +1,148 @@
+"""
+Response anomaly detection for the details and reports scrapers.
+
+FIDE occasionally serves stub pages (maintenance notices, throttling pages) with
+HTTP 200. Each is parsed as "no data found" or an empty table, and a long scrape
+would record thousands of them. A ResponseMonitor keeps rolling medians of
+response size and table-row count over recent normal responses, one monitor per
+page type. After `consecutive` responses in a row that are empty or much smaller
+than usual, it raises an alert (via disk_guard.send_alert, so --alert-command is
+notified), saves the latest response for inspection, and pauses before scraping
+continues.
+
+Both "empty" and "much smaller" also need the response to be under `min_size`
+bytes. Crosstables grow with the number of players, so a run of small tournaments
+(a blitz playoff is a few KB against a median of tens of KB) is normal, and so is
+FIDE's full-size page for a tournament with no data; a stub page is smaller than
+any real page, whose layout alone is several KB.
+
+Monitors are off until configure() is called (the CLI scrapers do this in main()).
+"""
+
+import logging
+import statistics
+import tempfile
+import time
+from collections import deque
+from pathlib import Path
+from typing import Callable, Dict, Optional
+
+import disk_guard
+from timestamps import utc_compact_stamp
+
+logger = logging.getLogger(__name__)
+
+DEFAULT_PAUSE_SECONDS = 600.0
+DEFAULT_MIN_SIZE = 4096
+DEFAULT_SNAPSHOT_DIR = Path(tempfile.gettempdir()) / "fide-glicko-anomalies"
+
+
+class ResponseMonitor:
+    """Rolling size/row-count baseline for one kind of page."""
+
+    def __init__(
+        self,
+        name: str,
+        window: int = 100,
+        min_samples: int = 20,
+        shrink_ratio: float = 0.25,
+        consecutive: int = 5,
+        min_size: int = DEFAULT_MIN_SIZE,
+        pause_seconds: float = DEFAULT_PAUSE_SECONDS,
+        snapshot_dir: Optional[str | Path] = None,
+        sleep: Callable[[float], None] = time.sleep,
+    ):
+        self.name = name
+        self.min_samples = min_samples
+        self.shrink_ratio = shrink_ratio
+        self.consecutive = consecutive
+        self.min_size = min_size
+        self.pause_seconds = pause_seconds
+        self.snapshot_dir = Path(snapshot_dir or DEFAULT_SNAPSHOT_DIR)
+        self._sleep = sleep
+        self._sizes: deque = deque(maxlen=window)
+        self._rows: deque = deque(maxlen=window)
+        self._streak = 0
+        self.alarms = 0
+
+    def is_anomalous(self, size: int, rows: int) -> bool:
+        """
+        A response under min_size bytes that has an empty table, or (once warmed
+        up) its size or rows far below the rolling median.
+        """
+        if size >= self.min_size:
+            return False
+        if rows == 0:
+            return True
+        if len(self._sizes) < self.min_samples:
+            return False
+        return (
+            size < self.shrink_ratio * statistics.median(self._sizes)
+            or rows < self.shrink_ratio * statistics.median(self._rows)
+        )
+
+    def observe(self, content: bytes, rows: int, key: str = "") -> bool:
+        """
+        Record one response. Returns True if it completed an anomalous streak, in
+        which case the alert, snapshot and pause have already happened.
+        """
+        size = len(content or b"")
+        if not self.is_anomalous(size, rows):
+            # A full-size page with no table is a real tournament with no data:
+            # it ends a streak but is left out of the baseline
+            if rows:
+                self._sizes.append(size)
+                self._rows.append(rows)
+            self._streak = 0
+            return False
+
+        self._streak += 1
+        if self._streak < self.consecutive:
+            return False
+
+        self._streak = 0
+        self.alarms += 1
+        snapshot = self._snapshot(content, key)
+        baseline = (
+            f"median {statistics.median(self._sizes):.0f} bytes / "
+            f"{statistics.median(self._rows):.0f} rows"
+            if self._sizes
+            else "no baseline yet"
+        )
+        disk_guard.send_alert(
+            f"{self.consecutive} anomalous {self.name} responses in a row "
+            f"(last: {key or '?'}, {size} bytes, {rows} rows; {baseline}); "
+            f"saved {snapshot}; pausing {self.pause_seconds:.0f}s"
+        )
+        self._sleep(self.pause_seconds)
+        return True
+
+    def _snapshot(self, content: bytes, key: str) -> Optional[Path]:
+        stamp = utc_compact_stamp()
+        path = self.snapshot_dir / f"{self.name}_{key or 'unknown'}_{stamp}.html"
+        try:
+            path.parent.mkdir(parents=True, exist_ok=True)
+            path.write_bytes(content or b"")
+        except OSError as e:
+            logger.error("Could not save anomaly snapshot %s: %s", path, e)
+            return None
+        return path
+
+
+_monitors: Dict[str, ResponseMonitor] = {}
+
+
+def configure(name: str, **kwargs) -> ResponseMonitor:
+    """Enable monitoring for name ("details", "reports"); kwargs go to the monitor."""
+    _monitors[name] = ResponseMonitor(name, **kwargs)
+    return _monitors[name]
+
+
+def disable(name: str) -> None:
+    _monitors.pop(name, None)
+
+
+def observe(name: str, content: bytes, rows: int, key: str = "") -> bool:
+    """Record a response with the monitor for name; no-op unless configured."""
+    monitor = _monitors.get(name)
+    return monitor.observe(content, rows, key) if monitor else False
@@ -0,0 +1,199 @@
+#!/usr/bin/env python3
+"""
+Pseudonymized export of games, players and rating data for sharing.
+
+Replaces FIDE IDs with keyed pseudonyms and drops name columns, so datasets can be
+shared where redistributing personal identifiers is a concern:
+
+  ID columns     player_id, white_player_id, black_player_id, id (player list
+                 only), *_fide_ids lists (tournament details arbiters and
+                 organizers) -> HMAC-SHA256(key, FIDE ID), first 16 hex digits
+  name columns   player_name, name (player list only), *_names lists
+                 (tournament details) -> dropped
+
+In tournament details, id and name are the event code and tournament name and
+are kept. A table is a player list when it has an id column and no tournament_id.
+
+The same key gives the same pseudonym in every file and every export, so exported
+tables still join. Without the key the mapping cannot be rebuilt by hashing known
+IDs. The key file (created with a random key if missing) and the --mapping file
+(FIDE ID -> pseudonym, for re-identification by the data owner) must be kept
+apart from the exported data; both are refused inside --output-dir.
+
+This is pseudonymization, not differential privacy: birth year, federation, sex
+and game records can still single out well-known players.
+
+Usage:
+  uv run src/scraper/anonymize_export.py \\
+    data/prod/2025-01/data/tournament_reports_games.parquet \\
+    data/prod/2025-01/data/tournament_reports_players.parquet \\
+    --output-dir export/2025-01 --key-file ~/.fide-glicko/pseudonym.key \\
+    --mapping ~/.fide-glicko/pseudonyms_2025-01.csv
+"""
+
+import argparse
+import hashlib
+import hmac
+import logging
+import os
+import secrets
+import sys
+from pathlib import Path
+from typing import Dict, List, Tuple
+
+import pandas as pd
+
+import redact
+from provenance import dataframe_to_parquet_bytes
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+ID_COLUMNS = ("player_id", "white_player_id", "black_player_id")
+NAME_COLUMNS = ("player_name",)
+# Player list only: elsewhere (tournament details) these are not about players
+PLAYER_LIST_ID_COLUMNS = ("id",)
+PLAYER_LIST_NAME_COLUMNS = ("name",)
+# List columns of people in tournament details (chief_arbiter_fide_ids, ...)
+ID_LIST_SUFFIX = "_fide_ids"
+NAME_LIST_SUFFIX = "_names"
+PSEUDONYM_HEX_DIGITS = 16
+
+
+def load_or_create_key(path: str | Path) -> bytes:
+    """Read the hex key at path, or write a new random 256-bit key there."""
+    path = Path(path).expanduser()
+    if path.exists():
+        return bytes.fromhex(path.read_text(encoding="utf-8").strip())
+    path.parent.mkdir(parents=True, exist_ok=True)
+    key = secrets.token_bytes(32)
+    fd = os.open(path, os.O_WRONLY | os.O_CREAT | os.O_EXCL, 0o600)
+    with os.fdopen(fd, "w", encoding="utf-8") as f:
+        f.write(key.hex() + "\n")
+    logger.info("Created new pseudonym key at %s", path)
+    return key
+
+
+def pseudonym(key: bytes, fide_id) -> str:
+    """Keyed pseudonym for one FIDE ID ("" stays "")."""
+    value = str(fide_id).strip()
+    if not value:
+        return ""
+    digest = hmac.new(key, value.encode("utf-8"), hashlib.sha256).hexdigest()
+    return digest[:PSEUDONYM_HEX_DIGITS]
+
+
+def is_player_list(df: pd.DataFrame) -> bool:
+    """True for player list tables, whose id and name columns are the player's."""
+    return "id" in df.columns and "tournament_id" not in df.columns
+
+
+def person_columns(df: pd.DataFrame) -> Tuple[List[str], List[str], List[str]]:
+    """(ID columns, ID list columns, name columns) of df that identify people."""
+    ids, names = list(ID_COLUMNS), list(NAME_COLUMNS)
+    if is_player_list(df):
+        ids += PLAYER_LIST_ID_COLUMNS
+        names += PLAYER_LIST_NAME_COLUMNS
+    cols = [str(c) for c in df.columns]
+    id_lists = [c for c in cols if c.endswith(ID_LIST_SUFFIX)]
+    names += [c for c in cols if c.endswith(NAME_LIST_SUFFIX)]
+    return (
+        [c for c in ids if c in cols],
+        id_lists,
+        [c for c in names if c in cols],
+    )
+
+
+def anonymize(df: pd.DataFrame, key: bytes, mapping: Dict[str, str]) -> pd.DataFrame:
+    """Copy of df with ID columns pseudonymized and names dropped; fills mapping."""
+    ids, id_lists, names = person_columns(df)
+    out = df.drop(columns=names)
+    for col in ids:
+        values = out[col].astype("string").fillna("")
+        unique = {i: pseudonym(key, i) for i in values.unique()}
+        mapping.update({i: p for i, p in unique.items() if i})
+        out[col] = values.map(unique).astype(str)
+
+    def _pseudonyms(cell):
+        if cell is None or not hasattr(cell, "__len__"):
+            return cell  # null list
+        result = []
+        for fide_id in cell:
+            value = "" if fide_id is None else str(fide_id).strip()
+            if value:
+                mapping.setdefault(value, pseudonym(key, value))
+            result.append(mapping[value] if value else None)
+        return result
+
+    for col in id_lists:
+        out[col] = out[col].map(_pseudonyms)
+    return out
+
+
+def _is_inside(path: Path, directory: Path) -> bool:
+    return path.expanduser().resolve().is_relative_to(directory.resolve())
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="Export Parquet files with pseudonymized FIDE IDs and no names",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    parser.add_argument("inputs", nargs="+", help="Parquet files to export")
+    parser.add_argument("--output-dir", required=True, help="Export directory")
+    parser.add_argument(
+        "--key-file",
+        required=True,
+        help="Hex key file (created if missing); keep outside the export",
+    )
+    parser.add_argument(
+        "--mapping",
+        help="Write FIDE ID -> pseudonym CSV here (kept outside the export)",
+    )
+    args = parser.parse_args()
+    redact.install()
+
+    out_dir = Path(args.output_dir)
+    for label, path in (("--key-file", args.key_file), ("--mapping", args.mapping)):
+        if path and _is_inside(Path(path), out_dir):
+            logger.error("%s must not be inside --output-dir", label)
+            return 1
+
+    try:
+        key = load_or_create_key(args.key_file)
+    except (OSError, ValueError) as e:
+        logger.error("Cannot read key %s: %s", args.key_file, e)
+        return 1
+
+    mapping: Dict[str, str] = {}
+    out_dir.mkdir(parents=True, exist_ok=True)
+    written: List[Path] = []
+    for src in map(Path, args.inputs):
+        try:
+            df = pd.read_parquet(src)
+        except (OSError, ValueError) as e:
+            logger.error("Cannot read %s: %s", src, e)
+            return 1
+        dest = out_dir / src.name
+        dest.write_bytes(dataframe_to_parquet_bytes(anonymize(df, key, mapping)))
+        written.append(dest)
+        logger.info("Exported %d rows to %s", len(df), dest)
+
+    if args.mapping:
+        mapping_path = Path(args.mapping).expanduser()
+        mapping_path.parent.mkdir(parents=True, exist_ok=True)
+        pd.DataFrame(
+            sorted(mapping.items()), columns=["fide_id", "pseudonym"]
+        ).to_csv(mapping_path, index=False)
+        logger.info("Wrote %d pseudonyms to %s", len(mapping), mapping_path)
+    logger.info("Exported %d files (%d players)", len(written), len(mapping))
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -0,0 +1,265 @@
+#!/usr/bin/env python3
+"""
+Published JSON Schemas for pipeline artifacts, and a strict validator.
+
+Each Parquet artifact has a JSON Schema in schemas/{name}.schema.json describing one
+row as an object (column -> value). These are the contract for consumers: columns,
+types, nullability and allowed values. Timestamps are "format": "date-time" (ISO
+8601 with offset when serialized); nulls are JSON null.
+
+  tournament_details          tournament_details.parquet
+  tournament_reports_players  tournament_reports_players.parquet
+  tournament_reports_games    tournament_reports_games.parquet
+  player_list                 player_list_{timestamp}.parquet
+
+The schemas use only type, enum, pattern, minimum, minLength, format, items
+(for list columns), required and additionalProperties: false, so any JSON Schema
+validator accepts them. The
+validator here implements that subset column by column, without an extra
+dependency. Missing required columns and unexpected columns are errors.
+
+Usage:
+  uv run src/scraper/artifact_schemas.py list
+  uv run src/scraper/artifact_schemas.py validate \\
+    data/prod/2025-01/data/tournament_reports_games.parquet
+  uv run src/scraper/artifact_schemas.py validate --schema player_list \\
+    data/player_lists/data/player_list_20250101-000000.parquet
+"""
+
+import argparse
+import json
+import logging
+import re
+import sys
+from datetime import date, datetime
+from pathlib import Path
+from typing import Dict, List, Optional
+
+import redact
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+SCHEMA_DIR = Path(__file__).resolve().parent / "schemas"
+DEFAULT_MAX_ERRORS = 20
+
+# File name -> schema name, for validate without --schema
+_FILENAME_PATTERNS = [
+    (re.compile(r"_players\.parquet$"), "tournament_reports_players"),
+    (re.compile(r"_games\.parquet$"), "tournament_reports_games"),
+    (re.compile(r"^players?_list.*\.parquet$"), "player_list"),
+    (re.compile(r"^tournament_details.*\.parquet$"), "tournament_details"),
+]
+
+
+def schema_names() -> List[str]:
+    return sorted(p.name[: -len(".schema.json")] for p in SCHEMA_DIR.glob("*.json"))
+
+
+def load_schema(name: str) -> Dict:
+    path = SCHEMA_DIR / f"{name}.schema.json"
+    if not path.exists():
+        raise ValueError(f"Unknown schema '{name}' (have: {', '.join(schema_names())})")
+    return json.loads(path.read_text(encoding="utf-8"))
+
+
+def schema_for_path(path: str | Path) -> Optional[str]:
+    """Schema name inferred from an artifact's file name, or None."""
+    name = Path(path).name
+    for pattern, schema in _FILENAME_PATTERNS:
+        if pattern.search(name):
+            return schema
+    return None
+
+
+def to_json_value(value):
+    """A Parquet/pandas cell as the JSON value the schema describes."""
+    if isinstance(value, (list, tuple)) or getattr(value, "ndim", 0) > 0:
+        return [to_json_value(v) for v in value]  # list column (numpy array)
+    try:
+        if value is None or value != value:  # NaN and NaT are not equal to themselves
+            return None
+    except TypeError:  # pd.NA refuses comparison
+        return None
+    if isinstance(value, (datetime, date)):
+        return value.isoformat()
+    if hasattr(value, "item") and not isinstance(value, (str, bytes)):
+        value = value.item()  # numpy scalar -> Python
+    return value
+
+
+def _is_type(value, t: str) -> bool:
+    if t == "null":
+        return value is None
+    if t == "boolean":
+        return isinstance(value, bool)
+    if t == "string":
+        return isinstance(value, str)
+    if t == "array":
+        return isinstance(value, list)
+    if isinstance(value, bool):
+        return False
+    if t == "integer":
+        return isinstance(value, int) or (
+            isinstance(value, float) and value.is_integer()
+        )
+    if t == "number":
+        return isinstance(value, (int, float))
+    return False
+
+
+def _is_datetime(value: str) -> bool:
+    try:
+        datetime.fromisoformat(value.replace("Z", "+00:00"))
+    except ValueError:
+        return False
+    return "T" in value
+
+
+def check_value(value, prop: Dict) -> Optional[str]:
+    """Why value fails the property schema, or None if it is valid."""
+    if "type" in prop:
+        types = prop["type"] if isinstance(prop["type"], list) else [prop["type"]]
+        if not any(_is_type(value, t) for t in types):
+            return f"{value!r} is not of type {' or '.join(types)}"
+    if "enum" in prop and value not in prop["enum"]:
+        return f"{value!r} is not one of {prop['enum']}"
+    if value is None:
+        return None
+    if "items" in prop and isinstance(value, list):
+        for i, item in enumerate(value):
+            message = check_value(item, prop["items"])
+            if message:
+                return f"item {i}: {message}"
+        return None
+    if "pattern" in prop and isinstance(value, str):
+        if not re.search(prop["pattern"], value):
+            return f"{value!r} does not match {prop['pattern']}"
+    if "minLength" in prop and isinstance(value, str):
+        if len(value) < prop["minLength"]:
+            return f"{value!r} is shorter than {prop['minLength']}"
+    if "minimum" in prop and isinstance(value, (int, float)):
+        if value < prop["minimum"]:
+            return f"{value!r} is less than {prop['minimum']}"
+    if prop.get("format") == "date-time" and isinstance(value, str):
+        if not _is_datetime(value):
+            return f"{value!r} is not a date-time"
+    return None
+
+
+def validate_dataframe(df, schema: Dict, max_errors: int = DEFAULT_MAX_ERRORS) -> Dict:
+    """
+    Check every row of df against a row schema. Returns {"rows", "errors" (up to
+    max_errors samples of {column, row, message}), "error_count" (all of them)}.
+    """
+    props = schema.get("properties", {})
+    errors: List[Dict] = []
+    count = 0
+
+    def add(column: str, row, message: str) -> None:
+        nonlocal count
+        count += 1
+        if len(errors) < max_errors:
+            errors.append({"column": column, "row": row, "message": message})
+
+    for col in schema.get("required", []):
+        if col not in df.columns:
+            add(col, None, "required column missing")
+    if schema.get("additionalProperties") is False:
+        for col in df.columns:
+            if col not in props:
+                add(str(col), None, "column not in schema")
+    for col, prop in props.items():
+        if col not in df.columns:
+            continue
+        for row, value in zip(df.index.tolist(), df[col].tolist()):
+            message = check_value(to_json_value(value), prop)
+            if message:
+                add(col, row if isinstance(row, (int, str)) else str(row), message)
+    return {"rows": len(df), "error_count": count, "errors": errors}
+
+
+def validate_file(
+    path: str | Path,
+    schema_name: Optional[str] = None,
+    max_errors: int = DEFAULT_MAX_ERRORS,
+) -> Dict:
+    """Validate one Parquet artifact; schema inferred from the file name if None."""
+    import pandas as pd
+
+    schema_name = schema_name or schema_for_path(path)
+    if schema_name is None:
+        raise ValueError(f"Cannot infer schema for {path}; pass --schema")
+    result = validate_dataframe(
+        pd.read_parquet(path), load_schema(schema_name), max_errors
+    )
+    return {"path": str(path), "schema": schema_name, **result}
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="List published artifact schemas or validate files against them",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    sub = parser.add_subparsers(dest="command", required=True)
+    sub.add_parser("list", help="Print schema names and their files")
+    val = sub.add_parser("validate", help="Check Parquet files against a schema")
+    val.add_argument("files", nargs="+", help="Parquet files to check")
+    val.add_argument(
+        "--schema",
+        choices=schema_names(),
+        help="Schema to check against (default: inferred from each file name)",
+    )
+    val.add_argument(
+        "--max-errors",
+        type=int,
+        default=DEFAULT_MAX_ERRORS,
+        help=f"Sample errors reported per file (default: {DEFAULT_MAX_ERRORS})",
+    )
+    val.add_argument("--report", help="Write results as JSON")
+    args = parser.parse_args()
+    redact.install()
+
+    if args.command == "list":
+        for name in schema_names():
+            print(f"{name}\t{SCHEMA_DIR / f'{name}.schema.json'}")
+        return 0
+
+    results = []
+    failed = False
+    for path in args.files:
+        try:
+            result = validate_file(path, args.schema, args.max_errors)
+        except (OSError, ValueError) as e:
+            logger.error("%s: %s", path, e)
+            failed = True
+            continue
+        results.append(result)
+        if result["error_count"]:
+            failed = True
+            logger.error(
+                "%s: %d errors against %s",
+                path,
+                result["error_count"],
+                result["schema"],
+            )
+            for err in result["errors"]:
+                logger.error(
+                    "  %s row %s: %s", err["column"], err["row"], err["message"]
+                )
+        else:
+            logger.info(
+                "%s: %d rows valid (%s)", path, result["rows"], result["schema"]
+            )
+    if args.report:
+        Path(args.report).write_text(json.dumps(results, indent=2), encoding="utf-8")
+    return 1 if failed else 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -0,0 +1,227 @@
+#!/usr/bin/env python3
+"""
+Month-boundary audit: tournaments whose rating period is ambiguous.
+
+Reads tournament_details Parquet files and, for each tournament, the rating period
+(YYYY-MM) each assignment policy would choose:
+
+  listed          the month whose FIDE tournament list the event came from
+                  (from a prod/YYYY-MM/ path; empty if unknown)
+  start_date      month the event started
+  end_date        month the event ended
+  date_received   month FIDE received the report
+
+A tournament is flagged when it is ambiguous:
+
+  spans_months    start and end fall in different months
+  near_boundary   end date fewer than --near-days from a month boundary
+  late_received   received over --late-days after the end date, in a later month
+  policies_differ the policies above do not all agree
+
+Output is one row per flagged tournament (CSV, or Parquet if --output ends in
+.parquet), plus a summary of how often each policy disagrees with `listed`.
+
+Usage:
+  uv run src/scraper/boundary_audit.py --local-root data \\
+    --output data/stats/boundary_audit.csv --summary data/stats/boundary_summary.json
+  uv run src/scraper/boundary_audit.py \\
+    --input data/prod/2024-01/data/tournament_details.parquet \\
+    --output audit.csv --near-days 2 --late-days 45
+"""
+
+import argparse
+import json
+import logging
+import re
+import sys
+from pathlib import Path
+from typing import Dict, List
+
+import pandas as pd
+
+import redact
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+DETAILS_FILENAME = "tournament_details.parquet"
+POLICIES = ["listed", "start_date", "end_date", "date_received"]
+DEFAULT_NEAR_DAYS = 3
+DEFAULT_LATE_DAYS = 30
+
+_RUN_MONTH_RE = re.compile(r"(?:^|/)prod/(\d{4}-\d{2})/")
+
+
+def listed_period(path: str | Path) -> str:
+    """YYYY-MM from a prod/YYYY-MM/... path, or "" if the path has none."""
+    m = _RUN_MONTH_RE.search(Path(path).as_posix())
+    return m.group(1) if m else ""
+
+
+def find_details_files(local_root: str | Path) -> List[Path]:
+    """Merged monthly details files under {local_root}/prod/*/data/, oldest first."""
+    return sorted(Path(local_root).glob(f"prod/*/data/{DETAILS_FILENAME}"))
+
+
+def _period(dates: pd.Series) -> pd.Series:
+    return pd.to_datetime(dates, errors="coerce", utc=True).dt.strftime("%Y-%m")
+
+
+def _days_to_boundary(dates: pd.Series) -> pd.Series:
+    """Days from each date to the nearest month boundary (0 = first/last day)."""
+    d = pd.to_datetime(dates, errors="coerce", utc=True)
+    after_start = d.dt.day - 1
+    before_end = d.dt.days_in_month - d.dt.day
+    return pd.concat([after_start, before_end], axis=1).min(axis=1)
+
+
+def audit(
+    details: pd.DataFrame,
+    near_days: int = DEFAULT_NEAR_DAYS,
+    late_days: int = DEFAULT_LATE_DAYS,
+) -> pd.DataFrame:
+    """
+    Per-tournament policy periods and ambiguity flags for details rows (with an
+    optional "listed" column). Returns only flagged tournaments, with a "reasons"
+    column (semicolon-separated flag names).
+    """
+    df = pd.DataFrame({"tournament_id": details["id"].astype(str)})
+    if "name" in details.columns:
+        df["name"] = details["name"]
+    for col in ("start_date", "end_date", "date_received"):
+        raw = details[col] if col in details.columns else pd.NaT
+        df[col] = pd.to_datetime(raw, errors="coerce", utc=True)
+    df["listed"] = details["listed"] if "listed" in details.columns else ""
+    for col in ("start_date", "end_date", "date_received"):
+        df[f"period_{col}"] = _period(df[col])
+    df["period_listed"] = df["listed"].fillna("")
+
+    flags = pd.DataFrame(index=df.index)
+    flags["spans_months"] = (
+        df["period_start_date"].notna()
+        & df["period_end_date"].notna()
+        & (df["period_start_date"] != df["period_end_date"])
+    )
+    flags["near_boundary"] = _days_to_boundary(df["end_date"]) < near_days
+    late = (df["date_received"] - df["end_date"]).dt.days > late_days
+    flags["late_received"] = late & (df["period_date_received"] > df["period_end_date"])
+    period_cols = [f"period_{p}" for p in POLICIES]
+    flags["policies_differ"] = df[period_cols].apply(
+        lambda row: len({v for v in row if isinstance(v, str) and v}) > 1, axis=1
+    )
+
+    df["reasons"] = flags.apply(
+        lambda row: ";".join(name for name, hit in row.items() if hit), axis=1
+    )
+    flagged = df[df["reasons"] != ""]
+    return flagged.drop(columns=["listed"]).reset_index(drop=True)
+
+
+def summarize(flagged: pd.DataFrame, total: int) -> Dict:
+    """Counts per reason and how often each policy disagrees with the listed month."""
+    reasons: Dict[str, int] = {}
+    for value in flagged["reasons"]:
+        for reason in value.split(";"):
+            reasons[reason] = reasons.get(reason, 0) + 1
+    has_listed = flagged["period_listed"] != ""
+    disagree = {
+        p: int(
+            (
+                has_listed
+                & flagged[f"period_{p}"].notna()
+                & (flagged[f"period_{p}"] != flagged["period_listed"])
+            ).sum()
+        )
+        for p in POLICIES
+        if p != "listed"
+    }
+    return {
+        "tournaments": total,
+        "flagged": len(flagged),
+        "by_reason": reasons,
+        "disagrees_with_listed": disagree,
+    }
+
+
+def load_details(paths: List[Path]) -> pd.DataFrame:
+    frames = []
+    for p in paths:
+        df = pd.read_parquet(p)
+        if "success" in df.columns:
+            df = df[df["success"].fillna(False).astype(bool)]
+        frames.append(df.assign(listed=listed_period(p)))
+    return pd.concat(frames, ignore_index=True) if frames else pd.DataFrame()
+
+
+def _write(df: pd.DataFrame, path: str) -> None:
+    out = Path(path)
+    out.parent.mkdir(parents=True, exist_ok=True)
+    if out.suffix == ".parquet":
+        from provenance import dataframe_to_parquet_bytes
+
+        out.write_bytes(dataframe_to_parquet_bytes(df))
+    else:
+        df.to_csv(out, index=False)
+    logger.info("Saved %d flagged tournaments to %s", len(df), out)
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="List tournaments with an ambiguous rating-period assignment",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    src = parser.add_mutually_exclusive_group(required=True)
+    src.add_argument("--local-root", help="Local bucket root (reads prod months)")
+    src.add_argument("--input", nargs="+", help="Explicit details Parquet files")
+    parser.add_argument("--output", required=True, help="CSV or .parquet output")
+    parser.add_argument("--summary", help="Optional JSON summary path")
+    parser.add_argument(
+        "--near-days",
+        type=int,
+        default=DEFAULT_NEAR_DAYS,
+        help="Flag end dates fewer than this many days from a month boundary "
+        f"(default: {DEFAULT_NEAR_DAYS})",
+    )
+    parser.add_argument(
+        "--late-days",
+        type=int,
+        default=DEFAULT_LATE_DAYS,
+        help="Flag reports received more than this many days after the end date "
+        f"(default: {DEFAULT_LATE_DAYS})",
+    )
+    args = parser.parse_args()
+    redact.install()
+
+    paths = (
+        [Path(p) for p in args.input]
+        if args.input
+        else find_details_files(args.local_root)
+    )
+    if not paths:
+        logger.error("No %s files found", DETAILS_FILENAME)
+        return 1
+    try:
+        details = load_details(paths)
+    except (OSError, ValueError) as e:
+        logger.error("%s", e)
+        return 1
+    if details.empty or "id" not in details.columns:
+        logger.error("No tournament details with an id column in %d files", len(paths))
+        return 1
+
+    flagged = audit(details, near_days=args.near_days, late_days=args.late_days)
+    _write(flagged, args.output)
+    summary = summarize(flagged, len(details))
+    logger.info("Summary: %s", json.dumps(summary))
+    if args.summary:
+        Path(args.summary).write_text(json.dumps(summary, indent=2), encoding="utf-8")
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
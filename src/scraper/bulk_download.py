@@ -0,0 +1,176 @@
+"""
+Streaming download with resume and verification for large FIDE bulk files.
+
+download_file() streams a URL to disk through a {dest}.part file, so a dropped
+connection does not cost the whole transfer:
+
+- resume: each retry (and a rerun with the same dest) continues from the .part
+  size with an HTTP Range request; a server that ignores Range (200 instead of
+  206) restarts the file from zero
+- checksum: an optional sha256 is checked on the finished file, and zip files get
+  every member's CRC-32 checked (FIDE publishes no checksums); .gz files are read
+  through to check the gzip CRC. A file that fails verification is deleted and
+  downloaded again from zero
+- bounded disk: the transfer stops if the file grows past max_bytes or the disk
+  would drop below min_free_bytes free
+
+Only a complete, verified file is renamed to dest.
+"""
+
+import gzip
+import hashlib
+import logging
+import re
+import time
+import zipfile
+from pathlib import Path
+from typing import Optional
+
+import requests
+
+from disk_guard import free_bytes
+
+logger = logging.getLogger(__name__)
+
+DEFAULT_CHUNK_SIZE = 1024 * 1024
+DEFAULT_MIN_FREE_BYTES = 64 * 1024 * 1024
+_CONTENT_RANGE_TOTAL = re.compile(r"/(\d+)\s*$")
+
+
+class DownloadError(Exception):
+    """Download could not complete (size or disk bound, or bad content)."""
+
+
+class VerificationError(DownloadError):
+    """Finished file failed its checksum or zip CRC check."""
+
+
+def sha256_file(path: str | Path) -> str:
+    """Hex sha256 of a file, read in chunks."""
+    digest = hashlib.sha256()
+    with open(path, "rb") as f:
+        for chunk in iter(lambda: f.read(DEFAULT_CHUNK_SIZE), b""):
+            digest.update(chunk)
+    return digest.hexdigest()
+
+
+def verify_file(
+    path: str | Path, sha256: Optional[str] = None, name: Optional[str] = None
+) -> None:
+    """
+    Raise VerificationError on a sha256 mismatch or a bad zip/gzip CRC. The format
+    is taken from name (default: path), e.g. the final name of a .part file.
+    """
+    name = (name or str(path)).lower()
+    if sha256 and sha256_file(path).lower() != sha256.lower():
+        raise VerificationError(f"sha256 mismatch for {path}")
+    if name.endswith(".zip"):
+        try:
+            with zipfile.ZipFile(path) as zf:
+                bad = zf.testzip()
+        except zipfile.BadZipFile as e:
+            raise VerificationError(f"Corrupt zip {path}: {e}") from e
+        if bad is not None:
+            raise VerificationError(f"CRC mismatch in {path} member {bad}")
+    elif name.endswith(".gz"):
+        try:
+            with gzip.open(path, "rb") as f:
+                while f.read(DEFAULT_CHUNK_SIZE):
+                    pass
+        except (OSError, EOFError) as e:
+            raise VerificationError(f"Corrupt gzip {path}: {e}") from e
+
+
+def _total_size(resp: requests.Response, offset: int) -> Optional[int]:
+    """Full file size from Content-Range (206) or offset + Content-Length."""
+    match = _CONTENT_RANGE_TOTAL.search(resp.headers.get("Content-Range", ""))
+    if match:
+        return int(match.group(1))
+    length = resp.headers.get("Content-Length")
+    return offset + int(length) if length and length.isdigit() else None
+
+
+def _check_disk(part: Path, remaining: int, min_free_bytes: int) -> None:
+    if free_bytes(part.parent) - remaining < min_free_bytes:
+        raise DownloadError(
+            f"Not enough disk for {part.name}: {remaining} bytes to go, "
+            f"{free_bytes(part.parent)} free, {min_free_bytes} must stay free"
+        )
+
+
+def _fetch_into(
+    session: requests.Session,
+    url: str,
+    part: Path,
+    timeout: float,
+    chunk_size: int,
+    max_bytes: Optional[int],
+    min_free_bytes: int,
+) -> None:
+    """One attempt: append the rest of url to part (or restart it)."""
+    offset = part.stat().st_size if part.exists() else 0
+    headers = {"Range": f"bytes={offset}-"} if offset else {}
+    with session.get(url, headers=headers, stream=True, timeout=timeout) as resp:
+        if offset and resp.status_code == 416:
+            return  # .part already holds the whole file; verification decides
+        resp.raise_for_status()
+        if offset and resp.status_code != 206:
+            logger.info("Server ignored Range for %s; restarting", url)
+            offset = 0
+        total = _total_size(resp, offset)
+        if total is not None:
+            if max_bytes is not None and total > max_bytes:
+                raise DownloadError(f"{url} is {total} bytes, over {max_bytes}")
+            _check_disk(part, total - offset, min_free_bytes)
+        size = offset
+        with open(part, "ab" if offset else "wb") as f:
+            for chunk in resp.iter_content(chunk_size):
+                f.write(chunk)
+                size += len(chunk)
+                if max_bytes is not None and size > max_bytes:
+                    f.truncate(0)  # not resumable: the next run starts over
+                    raise DownloadError(f"{url} grew past {max_bytes} bytes")
+    if total is not None and size < total:
+        raise requests.ConnectionError(f"Incomplete download: {size} of {total}")
+
+
+def download_file(
+    url: str,
+    dest: str | Path,
+    session: Optional[requests.Session] = None,
+    max_retries: int = 3,
+    retry_delay: float = 2.0,
+    timeout: float = 120,
+    sha256: Optional[str] = None,
+    max_bytes: Optional[int] = None,
+    min_free_bytes: int = DEFAULT_MIN_FREE_BYTES,
+    chunk_size: int = DEFAULT_CHUNK_SIZE,
+) -> Path:
+    """
+    Download url to dest, resuming from dest.part across retries and reruns.
+
+    Network errors and failed verification are retried (max_retries attempts,
+    linear backoff). Raises DownloadError at once for oversize files or low disk;
+    the .part file is kept for low disk so a rerun can resume.
+    """
+    dest = Path(dest)
+    dest.parent.mkdir(parents=True, exist_ok=True)
+    part = dest.with_name(dest.name + ".part")
+    sess = session or requests.Session()
+    for attempt in range(max_retries):
+        try:
+            _fetch_into(sess, url, part, timeout, chunk_size, max_bytes, min_free_bytes)
+            verify_file(part, sha256, name=dest.name)
+            part.replace(dest)
+            logger.info("Downloaded %s (%d bytes)", dest, dest.stat().st_size)
+            return dest
+        except VerificationError as e:
+            error = e
+            logger.warning("%s; downloading again from the start", e)
+            part.unlink(missing_ok=True)
+        except requests.RequestException as e:
+            error = e
+            logger.warning("Download attempt %d failed: %s", attempt + 1, e)
+        if attempt < max_retries - 1:
+            time.sleep(retry_delay * (attempt + 1))
+    raise error
@@ -0,0 +1,118 @@
+"""
+Checkpoint scheduling for the details and reports scrapers.
+
+A checkpoint is due every N successful fetches (--checkpoint N) and/or every
+interval of wall-clock time (--checkpoint-interval 2m). The time trigger keeps
+saving during error storms, when successes (and count-based checkpoints) stall.
+
+Local checkpoints are rotated: the last K are kept as {path}.1.gz (newest) ...
+{path}.K.gz, so a corrupted final write does not destroy the only recovery point.
+"""
+
+import argparse
+import gzip
+import os
+import re
+import time
+from pathlib import Path
+from typing import Callable, Optional
+
+_DURATION_RE = re.compile(r"^\s*(\d+(?:\.\d+)?)\s*([smh]?)\s*$", re.IGNORECASE)
+_UNIT_SECONDS = {"": 1, "s": 1, "m": 60, "h": 3600}
+
+DEFAULT_KEEP = 3
+
+
+def parse_duration(s: str) -> float:
+    """Parse a duration like "90", "90s", "2m" or "1h" to seconds."""
+    m = _DURATION_RE.match(s or "")
+    if not m:
+        raise argparse.ArgumentTypeError(
+            f"Invalid duration '{s}': expected e.g. 90s, 2m, 1h"
+        )
+    return float(m.group(1)) * _UNIT_SECONDS[m.group(2).lower()]
+
+
+class CheckpointSchedule:
+    """
+    Decide when to save a checkpoint.
+
+    Call due(success_count) after each processed item (success or failure) and
+    mark(success_count) after saving.
+    """
+
+    def __init__(
+        self,
+        every: int = 0,
+        interval: float = 0.0,
+        clock: Callable[[], float] = time.monotonic,
+    ):
+        self.every = every
+        self.interval = interval
+        self._clock = clock
+        self._last_time = clock()
+        self._last_count = 0
+
+    @property
+    def enabled(self) -> bool:
+        return self.every > 0 or self.interval > 0
+
+    def due(self, success_count: int) -> bool:
+        if (
+            self.every > 0
+            and success_count != self._last_count
+            and success_count % self.every == 0
+        ):
+            return True
+        return self.interval > 0 and self._clock() - self._last_time >= self.interval
+
+    def mark(self, success_count: int) -> None:
+        self._last_count = success_count
+        self._last_time = self._clock()
+
+
+def rotated_path(path: str | Path, n: int) -> Path:
+    """Path of the n-th newest rotated checkpoint (1 = newest)."""
+    return Path(f"{path}.{n}.gz")
+
+
+def write_rotated(path: str | Path, content: bytes, keep: int = DEFAULT_KEEP) -> Path:
+    """
+    Save content gzipped as {path}.1.gz, shifting older checkpoints up to
+    {path}.{keep}.gz and dropping the oldest.
+
+    The new file is written to a temp name and renamed into place, so a crash
+    mid-write leaves the previous checkpoints intact. Returns the new path.
+    """
+    if keep < 1:
+        raise ValueError("keep must be >= 1")
+    newest = rotated_path(path, 1)
+    newest.parent.mkdir(parents=True, exist_ok=True)
+    tmp = newest.with_name(newest.name + ".tmp")
+    with gzip.open(tmp, "wb", compresslevel=6) as f:
+        f.write(content)
+
+    rotated_path(path, keep).unlink(missing_ok=True)
+    for n in range(keep - 1, 0, -1):
+        src = rotated_path(path, n)
+        if src.exists():
+            os.replace(src, rotated_path(path, n + 1))
+    os.replace(tmp, newest)
+    return newest
+
+
+def load_latest(path: str | Path, keep: int = DEFAULT_KEEP) -> Optional[bytes]:
+    """
+    Content of the newest rotated checkpoint that decompresses cleanly, or None.
+    Corrupt (e.g. truncated) files are skipped in favour of older ones.
+    """
+    for n in range(1, keep + 1):
+        p = rotated_path(path, n)
+        if not p.exists():
+            continue
+        try:
+            with gzip.open(p, "rb") as f:
+                return f.read()
+        except (OSError, EOFError):
+            continue
+    return None
@@ -0,0 +1,186 @@
+"""
+Circuit breaker around FIDE endpoints for the details and reports scrapers.
+
+When FIDE starts failing (timeouts, connection resets), carrying on sends a
+request per tournament into the outage, and each one spends its HTTP retries and
+a place in the next retry pass. The breaker watches the outcome of recent
+requests instead:
+
+  closed     normal operation; opens once at least `min_requests` of the last
+             `window` requests are known and `error_ratio` of them failed
+  open       no requests are sent; before_request() waits out the cooldown
+  half-open  one probe request is let through: success closes the breaker,
+             failure re-opens it with the cooldown doubled (up to max_cooldown)
+
+Only network failures count as errors; "no data" pages and parse errors mean FIDE
+answered. Tournaments whose fetch fails while the breaker is open (the request
+that tripped it, failed probes) are re-queued by the scrapers without using up a
+retry pass. After `max_trips` openings in a row without a successful probe,
+before_request() raises CircuitOpenError and the run stops.
+"""
+
+import argparse
+import logging
+import time
+from collections import deque
+from typing import Callable, Optional
+
+import disk_guard
+
+logger = logging.getLogger(__name__)
+
+CLOSED = "closed"
+OPEN = "open"
+HALF_OPEN = "half-open"
+
+DEFAULT_WINDOW = 20
+DEFAULT_ERROR_RATIO = 0.5
+DEFAULT_COOLDOWN = 60.0
+DEFAULT_MAX_TRIPS = 5
+
+
+class CircuitOpenError(RuntimeError):
+    """The breaker re-opened max_trips times in a row; FIDE is treated as down."""
+
+
+class CircuitBreaker:
+    """Error-ratio breaker for one endpoint; see the module docstring."""
+
+    def __init__(
+        self,
+        name: str,
+        window: int = DEFAULT_WINDOW,
+        min_requests: Optional[int] = None,
+        error_ratio: float = DEFAULT_ERROR_RATIO,
+        cooldown: float = DEFAULT_COOLDOWN,
+        max_cooldown: float = 600.0,
+        max_trips: int = DEFAULT_MAX_TRIPS,
+        clock: Callable[[], float] = time.monotonic,
+        sleep: Callable[[float], None] = time.sleep,
+    ):
+        if window < 1:
+            raise ValueError(f"window must be >= 1, got {window}")
+        if not 0 < error_ratio <= 1:
+            raise ValueError(f"error_ratio must be in (0, 1], got {error_ratio}")
+        self.name = name
+        self.min_requests = (
+            min(window, max(1, window // 2)) if min_requests is None else min_requests
+        )
+        self.error_ratio = error_ratio
+        self.cooldown = cooldown
+        self.max_cooldown = max_cooldown
+        self.max_trips = max_trips
+        self._clock = clock
+        self._sleep = sleep
+        self._outcomes: deque = deque(maxlen=window)
+        self.state = CLOSED
+        self.trips = 0  # openings since the breaker last closed
+        self.total_trips = 0
+        self._probe_at = 0.0
+
+    def current_cooldown(self) -> float:
+        return min(self.cooldown * 2 ** max(self.trips - 1, 0), self.max_cooldown)
+
+    def allow(self) -> bool:
+        """True if a request may be sent now (moves open -> half-open when due)."""
+        if self.state == OPEN and self._clock() >= self._probe_at:
+            self.state = HALF_OPEN
+            logger.info("Circuit %s half-open: sending a probe request", self.name)
+        return self.state != OPEN
+
+    def before_request(self) -> None:
+        """Wait while the breaker is open; raise CircuitOpenError if it gave up."""
+        if self.state == OPEN and self.trips >= self.max_trips:
+            raise CircuitOpenError(
+                f"{self.name}: circuit opened {self.trips} times in a row; "
+                "FIDE looks unavailable"
+            )
+        if not self.allow():
+            self._sleep(max(self._probe_at - self._clock(), 0.0))
+            self.allow()
+
+    def record(self, ok: bool) -> bool:
+        """Record one request outcome. Returns True if the breaker is now open."""
+        if self.state == HALF_OPEN:
+            if ok:
+                self._close()
+            else:
+                self._open("probe failed")
+            return self.state == OPEN
+        if self.state == OPEN:
+            return True
+        self._outcomes.append(ok)
+        errors = self._outcomes.count(False)
+        if (
+            len(self._outcomes) >= self.min_requests
+            and errors >= self.error_ratio * len(self._outcomes)
+        ):
+            self._open(f"{errors}/{len(self._outcomes)} recent requests failed")
+        return self.state == OPEN
+
+    def _open(self, reason: str) -> None:
+        self.state = OPEN
+        self.trips += 1
+        self.total_trips += 1
+        wait = self.current_cooldown()
+        self._probe_at = self._clock() + wait
+        disk_guard.send_alert(
+            f"Circuit {self.name} open ({reason}); pausing requests for {wait:.0f}s "
+            f"(trip {self.trips}/{self.max_trips})"
+        )
+
+    def _close(self) -> None:
+        logger.info("Circuit %s closed after %d trip(s)", self.name, self.trips)
+        self.state = CLOSED
+        self.trips = 0
+        self._outcomes.clear()
+
+
+def add_arguments(parser: argparse.ArgumentParser) -> None:
+    """Add --breaker-window, --breaker-error-ratio, --breaker-cooldown and friends."""
+    parser.add_argument(
+        "--breaker-window",
+        type=int,
+        default=DEFAULT_WINDOW,
+        help="Recent requests the circuit breaker looks at "
+        f"(default: {DEFAULT_WINDOW})",
+    )
+    parser.add_argument(
+        "--breaker-error-ratio",
+        type=float,
+        default=DEFAULT_ERROR_RATIO,
+        help="Share of failed recent requests that opens the circuit breaker "
+        f"(default: {DEFAULT_ERROR_RATIO:g})",
+    )
+    parser.add_argument(
+        "--breaker-cooldown",
+        type=float,
+        default=DEFAULT_COOLDOWN,
+        help="Seconds the open breaker waits before a probe request; doubles after "
+        f"each failed probe (default: {DEFAULT_COOLDOWN:g})",
+    )
+    parser.add_argument(
+        "--breaker-max-trips",
+        type=int,
+        default=DEFAULT_MAX_TRIPS,
+        help="Stop the run after this many openings in a row "
+        f"(default: {DEFAULT_MAX_TRIPS})",
+    )
+    parser.add_argument(
+        "--no-circuit-breaker",
+        action="store_true",
+        help="Disable the circuit breaker",
+    )
+
+
+def from_args(name: str, args: argparse.Namespace) -> Optional[CircuitBreaker]:
+    """CircuitBreaker from the add_arguments() flags, or None if disabled."""
+    if args.no_circuit_breaker:
+        return None
+    return CircuitBreaker(
+        name,
+        window=args.breaker_window,
+        error_ratio=args.breaker_error_ratio,
+        cooldown=args.breaker_cooldown,
+        max_trips=args.breaker_max_trips,
+    )
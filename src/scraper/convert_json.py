@@ -0,0 +1,278 @@
+#!/usr/bin/env python3
+"""
+Convert historical JSON artifacts to the canonical Parquet outputs.
+
+Older runs (and the scrapers with --output x.json) left full result lists as JSON.
+This walks one or more directories, recognizes each JSON file by its records and
+converts it with the serializers the scrapers write Parquet with, so the output is
+the same as a fresh scrape's:
+
+  details   [{"tournament_id", "success", "details": {...}}, ...]
+            -> {stem}.parquet                 (as tournament_details.parquet)
+  reports   [{"tournament_code", "success", "players": [...]}, ...]
+            -> {stem}_players.parquet, {stem}_games.parquet
+
+Round dates in reports are parsed with the tournaments' start and end dates, as
+the reports scraper does with --details: pass the details Parquet files of the
+same tournaments with --details. Without them, dates are inferred from the
+crosstables alone.
+
+Anything else (samples, reports, failures, run metadata) is skipped. Each output is
+read back and validated: row count as expected and key columns present and
+non-empty. Existing outputs are left alone unless --override. Files convert in
+parallel (--workers processes). The conversion report (--report) lists every file
+with its kind, status (converted, exists, skipped, error), outputs and issues.
+
+Usage:
+  uv run src/scraper/convert_json.py data/tournament_details data/tournament_reports \\
+    --report data/convert_report.json
+  uv run src/scraper/convert_json.py old_runs/ --workers 8 --override \
+    --details data/prod/*/data/tournament_details.parquet
+"""
+
+import argparse
+import json
+import logging
+import os
+import sys
+from concurrent.futures import ProcessPoolExecutor
+from functools import partial
+from pathlib import Path
+from typing import Dict, List, Optional, Tuple
+
+import redact
+
+DetailsMap = Dict[str, Tuple[Optional[str], Optional[str]]]
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+KIND_DETAILS = "details"
+KIND_REPORTS = "reports"
+
+# Outputs the scrapers write next to full results; never full result lists
+_SKIP_SUFFIXES = (
+    "_sample.json",
+    "_report.json",
+    "_failures.json",
+    "_skipped.json",
+    "_partial.json",
+)
+
+# Columns each output must have, non-empty on every row
+REQUIRED_COLUMNS = {
+    "details": ["tournament_id", "success"],
+    "players": ["player_id", "tournament_id"],
+    "games": ["white_player_id", "black_player_id", "tournament_id"],
+}
+
+
+def classify(records) -> Optional[str]:
+    """KIND_DETAILS or KIND_REPORTS for a JSON result list, else None."""
+    if not isinstance(records, list) or not records:
+        return None
+    if not all(isinstance(r, dict) for r in records):
+        return None
+    if all("tournament_id" in r and "success" in r for r in records):
+        if any("details" in r for r in records):
+            return KIND_DETAILS
+    if all("tournament_code" in r and "success" in r for r in records):
+        return KIND_REPORTS
+    return None
+
+
+def output_paths(path: Path, kind: str) -> Dict[str, Path]:
+    """Parquet outputs for a JSON file of the given kind, keyed by table."""
+    stem = path.with_suffix("")
+    if kind == KIND_DETAILS:
+        return {"details": stem.with_suffix(".parquet")}
+    return {
+        "players": Path(f"{stem}_players.parquet"),
+        "games": Path(f"{stem}_games.parquet"),
+    }
+
+
+def find_json_files(roots: List[str | Path]) -> List[Path]:
+    """JSON files under roots (files are taken as-is), minus known non-results."""
+    files = []
+    for root in map(Path, roots):
+        candidates = [root] if root.is_file() else sorted(root.rglob("*.json"))
+        files.extend(p for p in candidates if not p.name.endswith(_SKIP_SUFFIXES))
+    return files
+
+
+def validate_output(df, table: str, expected_rows: Optional[int] = None) -> List[str]:
+    """Issues with a converted table (empty list if it looks right)."""
+    issues = []
+    if expected_rows is not None and len(df) != expected_rows:
+        issues.append(f"{table}: {len(df)} rows, expected {expected_rows}")
+    for col in REQUIRED_COLUMNS[table]:
+        if col not in df.columns:
+            issues.append(f"{table}: missing column {col}")
+            continue
+        blank = int((df[col].isna() | (df[col].astype(str) == "")).sum())
+        if blank:
+            issues.append(f"{table}: {blank} rows with empty {col}")
+    return issues
+
+
+def load_details_maps(paths: List[str | Path]) -> DetailsMap:
+    """event_code -> (start ISO, end ISO) over details Parquet files."""
+    from get_tournament_reports import load_details_map
+
+    details_map: DetailsMap = {}
+    for path in paths:
+        details_map.update(load_details_map(str(path)))
+    return details_map
+
+
+def _tables(
+    records: List[Dict], kind: str, details_map: Optional[DetailsMap] = None
+) -> Dict:
+    """
+    Parquet bytes from the scrapers' serializers (and expected row counts, where
+    known) for one result list.
+    """
+    if kind == KIND_DETAILS:
+        from get_tournament_details import results_to_parquet_bytes
+
+        return {"details": (results_to_parquet_bytes(records), len(records))}
+
+    from get_tournament_reports import games_parquet_bytes, players_parquet_bytes
+
+    n_players = sum(len(r.get("players", [])) for r in records if r.get("success"))
+    return {
+        "players": (players_parquet_bytes(records)[0], n_players),
+        "games": (games_parquet_bytes(records, details_map)[0], None),
+    }
+
+
+def convert_file(
+    path: str | Path, override: bool = False, details_map: Optional[DetailsMap] = None
+) -> Dict:
+    """Convert one JSON file; returns its conversion report entry."""
+    import pandas as pd
+
+    path = Path(path)
+    entry = {"path": str(path), "kind": None, "status": "skipped", "outputs": {}}
+    try:
+        with open(path, encoding="utf-8") as f:
+            records = json.load(f)
+    except (OSError, ValueError) as e:
+        return {**entry, "status": "error", "error": f"unreadable JSON: {e}"}
+
+    kind = classify(records)
+    entry["kind"] = kind
+    if kind is None:
+        return entry
+    outputs = output_paths(path, kind)
+    entry["outputs"] = {table: str(p) for table, p in outputs.items()}
+    if not override and all(p.exists() for p in outputs.values()):
+        return {**entry, "status": "exists"}
+
+    try:
+        issues = []
+        rows = {}
+        for table, (content, expected) in _tables(records, kind, details_map).items():
+            outputs[table].write_bytes(content)
+            written = pd.read_parquet(outputs[table])
+            rows[table] = len(written)
+            issues.extend(validate_output(written, table, expected))
+    except Exception as e:
+        return {**entry, "status": "error", "error": str(e)}
+    return {**entry, "status": "converted", "rows": rows, "issues": issues}
+
+
+def convert_all(
+    paths: List[Path],
+    workers: int = 1,
+    override: bool = False,
+    details_map: Optional[DetailsMap] = None,
+) -> List[Dict]:
+    """Convert paths, in parallel when workers > 1; entries in input order."""
+    convert = partial(convert_file, override=override, details_map=details_map)
+    if workers <= 1:
+        return [convert(p) for p in paths]
+    with ProcessPoolExecutor(max_workers=workers) as pool:
+        return list(pool.map(convert, paths))
+
+
+def summarize(entries: List[Dict]) -> Dict:
+    by_status: Dict[str, int] = {}
+    for e in entries:
+        by_status[e["status"]] = by_status.get(e["status"], 0) + 1
+    return {
+        "files": len(entries),
+        "by_status": by_status,
+        "with_issues": sum(1 for e in entries if e.get("issues")),
+    }
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="Convert historical details/reports JSON to Parquet",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    parser.add_argument("paths", nargs="+", help="Directories or JSON files")
+    parser.add_argument(
+        "--workers",
+        type=int,
+        default=os.cpu_count() or 1,
+        help="Parallel conversion processes (default: CPU count)",
+    )
+    parser.add_argument(
+        "--override",
+        "-o",
+        action="store_true",
+        help="Rewrite Parquet outputs that already exist",
+    )
+    parser.add_argument(
+        "--details",
+        nargs="+",
+        default=[],
+        help="Details Parquet files with the tournaments' start/end dates, for "
+        "round date inference in reports (as the reports scraper's --details)",
+    )
+    parser.add_argument("--report", help="Write the conversion report as JSON")
+    args = parser.parse_args()
+    redact.install()
+
+    files = find_json_files(args.paths)
+    if not files:
+        logger.error("No JSON files found under %s", ", ".join(args.paths))
+        return 1
+    try:
+        details_map = load_details_maps(args.details)
+    except (OSError, ValueError) as e:
+        logger.error("Could not read --details: %s", e)
+        return 1
+    if args.details:
+        logger.info("Loaded dates for %d tournaments", len(details_map))
+    logger.info("Converting %d JSON files with %d workers", len(files), args.workers)
+
+    entries = convert_all(
+        files, workers=args.workers, override=args.override, details_map=details_map
+    )
+    for e in entries:
+        if e["status"] == "error":
+            logger.error("%s: %s", e["path"], e["error"])
+        for issue in e.get("issues", []):
+            logger.warning("%s: %s", e["path"], issue)
+
+    summary = summarize(entries)
+    logger.info("Summary: %s", json.dumps(summary))
+    if args.report:
+        report = {"summary": summary, "files": entries}
+        Path(args.report).parent.mkdir(parents=True, exist_ok=True)
+        Path(args.report).write_text(json.dumps(report, indent=2), encoding="utf-8")
+        logger.info("Wrote conversion report to %s", args.report)
+    return 1 if summary["by_status"].get("error") else 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -0,0 +1,103 @@
+"""
+Pause on disk-full write failures instead of logging and scraping on.
+
+Local writes in the details and reports scrapers go through guarded_write(). When a
+write fails with ENOSPC/EDQUOT, the scraper stops (no further FIDE requests), sends
+an alert, polls until enough space is free, sends a resume alert, and retries the
+write.
+
+Alerts are logged at CRITICAL and, if configured, passed to an alert command as its
+last argument (e.g. --alert-command "notify-send fide-glicko" or a script that posts
+to Slack/email).
+"""
+
+import errno
+import logging
+import shlex
+import shutil
+import subprocess
+import time
+from pathlib import Path
+from typing import Callable, Optional, TypeVar
+
+logger = logging.getLogger(__name__)
+
+DEFAULT_MIN_FREE_BYTES = 512 * 1024 * 1024
+DEFAULT_POLL_SECONDS = 60.0
+_DISK_FULL_ERRNOS = {errno.ENOSPC, errno.EDQUOT}
+
+T = TypeVar("T")
+
+_config = {
+    "alert_command": None,
+    "min_free_bytes": DEFAULT_MIN_FREE_BYTES,
+    "poll_seconds": DEFAULT_POLL_SECONDS,
+}
+
+
+def configure(
+    alert_command: Optional[str] = None,
+    min_free_bytes: int = DEFAULT_MIN_FREE_BYTES,
+    poll_seconds: float = DEFAULT_POLL_SECONDS,
+) -> None:
+    """Set the alert command and resume threshold (called once from main())."""
+    _config["alert_command"] = alert_command
+    _config["min_free_bytes"] = min_free_bytes
+    _config["poll_seconds"] = poll_seconds
+
+
+def is_disk_full(exc: BaseException) -> bool:
+    """True if exc is an OSError for no space left / quota exceeded."""
+    return isinstance(exc, OSError) and exc.errno in _DISK_FULL_ERRNOS
+
+
+def free_bytes(path: str | Path) -> int:
+    """Free bytes on the filesystem holding path (or its nearest existing parent)."""
+    p = Path(path).resolve()
+    while not p.exists() and p != p.parent:
+        p = p.parent
+    return shutil.disk_usage(p).free
+
+
+def send_alert(message: str) -> None:
+    """Log message at CRITICAL and run the configured alert command, if any."""
+    logger.critical(message)
+    command = _config["alert_command"]
+    if not command:
+        return
+    try:
+        subprocess.run(shlex.split(command) + [message], timeout=30, check=False)
+    except (OSError, subprocess.SubprocessError) as e:
+        logger.error("Alert command failed: %s", e)
+
+
+def wait_for_space(
+    path: str | Path, sleep: Callable[[float], None] = time.sleep
+) -> None:
+    """Block until at least min_free_bytes are free where path is written."""
+    needed = _config["min_free_bytes"]
+    while free_bytes(path) < needed:
+        sleep(_config["poll_seconds"])
+
+
+def guarded_write(
+    write: Callable[[], T],
+    path: str | Path,
+    sleep: Callable[[float], None] = time.sleep,
+) -> T:
+    """
+    Run write(); on a disk-full error, alert, wait for space, alert again and retry.
+    Other errors propagate unchanged.
+    """
+    while True:
+        try:
+            return write()
+        except OSError as e:
+            if not is_disk_full(e):
+                raise
+            send_alert(
+                f"Disk full writing {path} ({e.strerror}); scraping paused until "
+                f"{_config['min_free_bytes'] // (1024 * 1024)} MiB are free"
+            )
+            wait_for_space(path, sleep=sleep)
+            send_alert(f"Disk space available again; resuming writes to {path}")
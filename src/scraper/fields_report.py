@@ -0,0 +1,315 @@
+#!/usr/bin/env python3
+"""
+Fields report: every label in tournament details tables, how often it appears
+and sample values. Use it to find labels the details parser does not map yet.
+
+Pages come from one of:
+
+  --input     tournament IDs file (or - for stdin), fetched live through
+              get_tournament_details.fetch_tournament_details (the scraper's
+              retries, timeouts and User-Agent) at --rate-limit req/s
+  --raw       archived raw/details/*.html.gz chunks (written by the Step
+              Function details chunks), read offline
+  --html      saved details pages, read offline; the file name (without .html
+              or .html.gz) is the tournament ID
+
+For each label as shown on the page the report has count, known (the label maps
+to a details column through field_for_label), field, has_links and up to
+--samples distinct non-empty values (the value cell's HTML). Fields are sorted
+by count, most common first, then by label.
+
+Unknown labels also get examples: up to --examples distinct raw value snippets,
+each with the tournament_id it came from. Adding a parser case can then start from
+those pages (or the snippets, as test input) without searching for them again.
+With --examples-dir, each example is also written as
+{dir}/{label slug}/{tournament_id}.html.
+
+JSON goes to --output, or to stdout; logs go to stderr. This replaces
+exploratory/check_tournament_fields.go, which fetches with its own loop.
+
+Usage:
+  uv run src/scraper/fields_report.py --input data/tournament_ids/2024_01 --max 50
+  uv run src/scraper/fields_report.py --raw data/prod/2024-01/raw/details/*.html.gz \\
+    --output fields.json
+"""
+
+import argparse
+import gzip
+import json
+import logging
+import re
+import sys
+from pathlib import Path
+from typing import Dict, Iterable, Iterator, List, Optional, Tuple
+
+import requests
+from bs4 import BeautifulSoup
+
+import http_timeouts
+import redact
+from get_tournament_details import (
+    field_for_label,
+    fetch_tournament_details,
+    read_tournament_ids,
+)
+from rate_limiter import RateLimiter
+from raw_utils import iter_tournaments
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+DEFAULT_SAMPLES = 5
+DEFAULT_EXAMPLES = 3
+DEFAULT_MAX = 100
+
+
+def page_fields(html: bytes | str) -> Dict[str, str]:
+    """Label -> value cell HTML for each row of a details page's details table."""
+    soup = BeautifulSoup(html, "html.parser")
+    table = soup.find("table", class_="details_table")
+    if not table:
+        return {}
+    fields = {}
+    for row in table.find_all("tr"):
+        label_cell = row.find("td", class_="info_table_l")
+        cells = row.find_all("td")
+        if not label_cell or len(cells) < 2:
+            continue
+        label = label_cell.get_text(strip=True)
+        if label:
+            fields[label] = cells[1].decode_contents().strip()
+    return fields
+
+
+class FieldsReport:
+    """Accumulates label counts and samples over pages."""
+
+    def __init__(
+        self, samples: int = DEFAULT_SAMPLES, examples: int = DEFAULT_EXAMPLES
+    ):
+        self.samples = samples
+        self.examples = examples
+        self.pages = 0
+        self.failures: Dict[str, str] = {}
+        self.fields: Dict[str, Dict] = {}
+
+    def add_page(self, tournament_id: str, fields: Dict[str, str]) -> None:
+        self.pages += 1
+        for label, value in fields.items():
+            info = self.fields.get(label)
+            if info is None:
+                field = field_for_label(label)
+                info = self.fields[label] = {
+                    "count": 0,
+                    "known": field is not None,
+                    "field": field,
+                    "has_links": False,
+                    "sample_values": [],
+                }
+                if field is None:
+                    info["examples"] = []
+            info["count"] += 1
+            info["has_links"] = info["has_links"] or "<a" in value
+            text = BeautifulSoup(value, "html.parser").get_text(strip=True)
+            if (
+                text
+                and len(info["sample_values"]) < self.samples
+                and value not in info["sample_values"]
+            ):
+                info["sample_values"].append(value)
+            if (
+                text
+                and "examples" in info
+                and len(info["examples"]) < self.examples
+                and all(e["html"] != value for e in info["examples"])
+            ):
+                info["examples"].append({"tournament_id": tournament_id, "html": value})
+
+    def add_failure(self, tournament_id: str, error: str) -> None:
+        self.failures[tournament_id] = error
+
+    def to_dict(self) -> Dict:
+        ordered = sorted(self.fields.items(), key=lambda kv: (-kv[1]["count"], kv[0]))
+        return {
+            "pages": self.pages,
+            "failures": self.failures,
+            "unique_fields": len(self.fields),
+            "unknown_fields": sum(1 for _, info in ordered if not info["known"]),
+            "fields": [{"label": label, **info} for label, info in ordered],
+        }
+
+
+def label_slug(label: str) -> str:
+    """File-system friendly name for a label, e.g. "Rated for:" -> "rated_for"."""
+    return re.sub(r"[^a-z0-9]+", "_", label.lower()).strip("_") or "label"
+
+
+def write_examples(report: Dict, directory: str) -> int:
+    """Write each unknown field's examples to {directory}/{slug}/{id}.html."""
+    written = 0
+    for field in report["fields"]:
+        for example in field.get("examples", []):
+            path = Path(directory) / label_slug(field["label"])
+            path.mkdir(parents=True, exist_ok=True)
+            (path / f"{example['tournament_id']}.html").write_text(
+                example["html"] + "\n", encoding="utf-8"
+            )
+            written += 1
+    return written
+
+
+def live_pages(
+    ids: Iterable[str], rate_limit: float
+) -> Iterator[Tuple[str, Optional[bytes], Optional[str]]]:
+    """(tournament_id, html, error) per ID, fetched like the details scraper."""
+    session = requests.Session()
+    limiter = RateLimiter(rate_limit)
+    for tid in ids:
+        limiter.wait()
+        _, error, _, raw = fetch_tournament_details(tid, session, return_raw=True)
+        yield tid, raw, error
+
+
+def raw_pages(paths: Iterable[str]) -> Iterator[Tuple[str, bytes, None]]:
+    """(tournament_id, html, None) for every page in raw .html.gz chunks."""
+    for path in paths:
+        for tid, html in iter_tournaments(path):
+            yield tid, html, None
+
+
+def html_pages(paths: Iterable[str]) -> Iterator[Tuple[str, bytes, None]]:
+    """(tournament_id, html, None) for saved pages named {tournament_id}.html[.gz]."""
+    for path in paths:
+        p = Path(path)
+        tid = p.name.removesuffix(".gz").removesuffix(".html")
+        if p.suffix == ".gz":
+            yield tid, gzip.decompress(p.read_bytes()), None
+        else:
+            yield tid, p.read_bytes(), None
+
+
+def build_report(
+    pages: Iterable[Tuple[str, Optional[bytes], Optional[str]]],
+    samples: int = DEFAULT_SAMPLES,
+    limit: int = 0,
+    examples: int = DEFAULT_EXAMPLES,
+) -> FieldsReport:
+    """Report over up to limit pages (0 = all); pages without a table are failures."""
+    report = FieldsReport(samples, examples)
+    seen = 0
+    for tid, html, error in pages:
+        if limit > 0 and seen >= limit:
+            break
+        seen += 1
+        fields = page_fields(html) if html else {}
+        if not fields:
+            report.add_failure(tid, error or "no details table")
+            continue
+        report.add_page(tid, fields)
+        if seen % 10 == 0:
+            logger.info("Processed %d pages", seen)
+    return report
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="Count the labels in tournament details pages",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    source = parser.add_mutually_exclusive_group(required=True)
+    source.add_argument("--input", help="Tournament IDs file, or - for stdin")
+    source.add_argument("--raw", nargs="+", help="Raw details .html.gz chunks")
+    source.add_argument("--html", nargs="+", help="Saved details pages")
+    parser.add_argument(
+        "--max",
+        type=int,
+        default=DEFAULT_MAX,
+        help=f"Pages to check (0 = all; default: {DEFAULT_MAX})",
+    )
+    parser.add_argument(
+        "--rate-limit",
+        type=float,
+        default=0.5,
+        help="Live requests per second (default: 0.5, as the details scraper)",
+    )
+    parser.add_argument(
+        "--samples",
+        type=int,
+        default=DEFAULT_SAMPLES,
+        help=f"Sample values per field (default: {DEFAULT_SAMPLES})",
+    )
+    parser.add_argument(
+        "--examples",
+        type=int,
+        default=DEFAULT_EXAMPLES,
+        help="Example snippets (with tournament IDs) per unknown field "
+        f"(default: {DEFAULT_EXAMPLES})",
+    )
+    parser.add_argument(
+        "--examples-dir", help="Also write unknown-field examples as HTML files here"
+    )
+    parser.add_argument("--output", help="JSON output path (default: stdout)")
+    http_timeouts.add_arguments(parser)
+    args = parser.parse_args()
+    redact.install()
+    try:
+        http_timeouts.configure_from_args(args)
+    except ValueError as e:
+        logger.error("Error: %s", e)
+        return 1
+
+    try:
+        if args.input:
+            ids: List[str] = read_tournament_ids(args.input, limit=args.max)
+            logger.info("Fetching %d tournaments", len(ids))
+            pages = live_pages(ids, args.rate_limit)
+        elif args.raw:
+            pages = raw_pages(args.raw)
+        else:
+            pages = html_pages(args.html)
+        report = build_report(
+            pages, samples=args.samples, limit=args.max, examples=args.examples
+        )
+    except (OSError, ValueError) as e:
+        logger.error("%s", e)
+        return 1
+
+    result = report.to_dict()
+    logger.info(
+        "%d pages, %d failed, %d fields (%d unknown)",
+        result["pages"],
+        len(result["failures"]),
+        result["unique_fields"],
+        result["unknown_fields"],
+    )
+    for field in result["fields"]:
+        if not field["known"]:
+            logger.info(
+                "Unknown field %r on %d pages (e.g. %s)",
+                field["label"],
+                field["count"],
+                ", ".join(e["tournament_id"] for e in field["examples"]) or "-",
+            )
+    if args.examples_dir:
+        try:
+            n = write_examples(result, args.examples_dir)
+        except OSError as e:
+            logger.error("%s", e)
+            return 1
+        logger.info("Wrote %d examples to %s", n, args.examples_dir)
+    text = json.dumps(result, indent=2, ensure_ascii=False)
+    if args.output:
+        Path(args.output).write_text(text + "\n", encoding="utf-8")
+        logger.info("Saved report to %s", args.output)
+    else:
+        print(text)
+    return 0 if result["pages"] else 1
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -0,0 +1,179 @@
+#!/usr/bin/env python3
+"""
+Export the player-vs-player game graph per period as an edge list or GraphML.
+
+Players are nodes; an undirected edge joins two players who met in the period
+(month of round_date, "unknown" when missing), weighted by games played:
+
+  source, target     player IDs, source < target (as strings)
+  weight             games between them
+  source_score       source's total score in those games (target's = weight - it)
+
+One file per period in --output-dir: game_graph_{period}.csv (edge list) or
+game_graph_{period}.graphml. GraphML nodes carry fed (and title) from --player-list
+when given, for federation-level studies in networkx or Gephi. Rows without both
+player IDs or without a score (byes, unplayed) are skipped; filter forfeits first
+with rating_input.py if they should not count.
+
+Usage:
+  uv run src/scraper/game_graph.py \\
+    --games data/prod/2025-*/data/rating_input_games.parquet \\
+    --player-list data/player_lists/data/player_list_20250101-000000.parquet \\
+    --output-dir data/stats/game_graph --format graphml
+"""
+
+import argparse
+import logging
+import sys
+import xml.etree.ElementTree as ET
+from pathlib import Path
+from typing import Dict, Optional, Set
+
+import pandas as pd
+
+import redact
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+UNKNOWN_PERIOD = "unknown"
+EDGE_COLUMNS = ["period", "source", "target", "weight", "source_score"]
+NODE_ATTRIBUTES = ("fed", "title")
+GRAPHML_NS = "http://graphml.graphdrawing.org/xmlns"
+
+
+def game_edges(games: pd.DataFrame) -> pd.DataFrame:
+    """Weighted undirected edges per period (EDGE_COLUMNS)."""
+    white = games["white_player_id"].fillna("").astype(str).str.strip()
+    black = games["black_player_id"].fillna("").astype(str).str.strip()
+    played = (white != "") & (black != "") & (white != black) & games["score"].notna()
+    white, black = white[played], black[played]
+    score = pd.to_numeric(games.loc[played, "score"], errors="coerce").fillna(0.0)
+    dates = pd.to_datetime(games.loc[played, "round_date"], errors="coerce")
+
+    swap = white > black
+    pairs = pd.DataFrame(
+        {
+            "period": dates.dt.strftime("%Y-%m").fillna(UNKNOWN_PERIOD),
+            "source": black.where(swap, white),
+            "target": white.where(swap, black),
+            "source_score": (1.0 - score).where(swap, score),
+        }
+    )
+    edges = (
+        pairs.groupby(["period", "source", "target"])
+        .agg(weight=("source_score", "size"), source_score=("source_score", "sum"))
+        .reset_index()
+    )
+    return edges[EDGE_COLUMNS]
+
+
+def node_attributes(
+    player_list: pd.DataFrame, ids: Set[str]
+) -> Dict[str, Dict[str, str]]:
+    """player ID -> {fed, title} for ids (missing values left out)."""
+    cols = [c for c in NODE_ATTRIBUTES if c in player_list.columns]
+    table = player_list.assign(id=player_list["id"].astype(str))
+    table = table[table["id"].isin(ids)].set_index("id")
+    return {
+        pid: {k: str(v) for k, v in row.items() if pd.notna(v) and str(v)}
+        for pid, row in table[cols].iterrows()
+    }
+
+
+def _element(parent: ET.Element, tag: str, text: Optional[str] = None, **attrib):
+    el = ET.SubElement(parent, f"{{{GRAPHML_NS}}}{tag}", attrib)
+    el.text = text
+    return el
+
+
+def to_graphml(
+    edges: pd.DataFrame, nodes: Optional[Dict[str, Dict[str, str]]] = None
+) -> bytes:
+    """GraphML document for one period's edges (undirected)."""
+    nodes = nodes or {}
+    ET.register_namespace("", GRAPHML_NS)
+    root = ET.Element(f"{{{GRAPHML_NS}}}graphml")
+    keys = [(k, "node", "string") for k in NODE_ATTRIBUTES]
+    keys += [("weight", "edge", "int"), ("source_score", "edge", "double")]
+    for key, domain, kind in keys:
+        attrib = {"id": key, "for": domain, "attr.name": key, "attr.type": kind}
+        _element(root, "key", **attrib)
+    graph = _element(root, "graph", edgedefault="undirected")
+    for pid in sorted(set(edges["source"]) | set(edges["target"])):
+        node = _element(graph, "node", id=pid)
+        for key, value in nodes.get(pid, {}).items():
+            _element(node, "data", value, key=key)
+    for e in edges.itertuples(index=False):
+        edge = _element(graph, "edge", source=e.source, target=e.target)
+        _element(edge, "data", str(int(e.weight)), key="weight")
+        _element(edge, "data", f"{e.source_score:g}", key="source_score")
+    ET.indent(root)
+    return ET.tostring(root, encoding="utf-8", xml_declaration=True)
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="Export the game graph per period as an edge list or GraphML",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    parser.add_argument("--games", nargs="+", required=True, help="Games Parquet files")
+    parser.add_argument("--output-dir", required=True, help="Directory for the graphs")
+    parser.add_argument(
+        "--format",
+        choices=["edgelist", "graphml"],
+        default="edgelist",
+        help="edgelist (CSV) or graphml (default: edgelist)",
+    )
+    parser.add_argument("--player-list", help="Player list Parquet for node attributes")
+    parser.add_argument("--period", help="Only this period (YYYY-MM)")
+    args = parser.parse_args()
+    redact.install()
+
+    try:
+        games = pd.concat([pd.read_parquet(p) for p in args.games], ignore_index=True)
+    except (OSError, ValueError) as e:
+        logger.error("%s", e)
+        return 1
+
+    edges = game_edges(games)
+    if args.period:
+        edges = edges[edges["period"] == args.period]
+    if edges.empty:
+        logger.error("No games to export")
+        return 1
+
+    nodes = None
+    if args.player_list:
+        try:
+            players = pd.read_parquet(
+                args.player_list, columns=["id", *NODE_ATTRIBUTES]
+            )
+        except (OSError, ValueError) as e:
+            logger.error("%s", e)
+            return 1
+        nodes = node_attributes(players, set(edges["source"]) | set(edges["target"]))
+
+    out_dir = Path(args.output_dir)
+    out_dir.mkdir(parents=True, exist_ok=True)
+    for period, period_edges in edges.groupby("period"):
+        if args.format == "graphml":
+            path = out_dir / f"game_graph_{period}.graphml"
+            path.write_bytes(to_graphml(period_edges, nodes))
+        else:
+            path = out_dir / f"game_graph_{period}.csv"
+            period_edges.drop(columns="period").to_csv(path, index=False)
+        n_players = len(set(period_edges["source"]) | set(period_edges["target"]))
+        logger.info(
+            "%s: %d players, %d edges -> %s", period, n_players, len(period_edges), path
+        )
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -0,0 +1,337 @@
+#!/usr/bin/env python3
+"""
+Geocode tournament City/Country to latitude/longitude from an offline GeoNames dump.
+
+Optional enrichment stage (not part of the Step Function). Reads tournament details
+Parquet (tournament_id, city, fed) and writes one row per tournament:
+
+  tournament_id, city, fed, country_code, lat, lon, geo_match
+    geo_match = "city"             city matched a GeoNames place in the federation's
+                                   country
+                "country"          city not found; coordinates of the country's
+                                   capital
+                "capital_missing"  city not found and the country's capital is not
+                                   in the cities file; no coordinates
+                None               federation could not be mapped to a country
+
+GeoNames files (https://download.geonames.org/export/dump/, CC BY 4.0):
+  cities15000.txt (or cities5000.txt / cities1000.txt for more small towns)
+  countryInfo.txt
+
+Usage:
+  uv run src/scraper/geocode_tournaments.py \\
+    --input data/prod/2025-01/data/tournament_details.parquet \\
+    --geonames-dir data/geonames \\
+    --output data/prod/2025-01/data/tournament_geo.parquet
+"""
+
+import argparse
+import csv
+import logging
+import re
+import sys
+import unicodedata
+from pathlib import Path
+from typing import Dict, Optional, Tuple
+
+import pandas as pd
+
+import redact
+from provenance import build_provenance, dataframe_to_parquet_bytes
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+DEFAULT_CITIES_FILE = "cities15000.txt"
+COUNTRY_INFO_FILE = "countryInfo.txt"
+
+MATCH_CITY = "city"
+MATCH_COUNTRY = "country"
+MATCH_CAPITAL_MISSING = "capital_missing"
+
+# FIDE federation codes (IOC-style) that differ from ISO 3166 alpha-3.
+# Codes equal to their alpha-3 (USA, FRA, IND, ...) are resolved via countryInfo.txt.
+FIDE_TO_ISO2 = {
+    "AHO": "CW",
+    "ALG": "DZ",
+    "ANG": "AO",
+    "ANT": "AG",
+    "ARU": "AW",
+    "BAH": "BS",
+    "BAN": "BD",
+    "BAR": "BB",
+    "BER": "BM",
+    "BHU": "BT",
+    "BIZ": "BZ",
+    "BOT": "BW",
+    "BRN": "BH",
+    "BRU": "BN",
+    "BUL": "BG",
+    "BUR": "BF",
+    "CAM": "KH",
+    "CAY": "KY",
+    "CGO": "CG",
+    "CHA": "TD",
+    "CHI": "CL",
+    "CRC": "CR",
+    "CRO": "HR",
+    "DEN": "DK",
+    "ENG": "GB",
+    "ESA": "SV",
+    "FAI": "FO",
+    "FIJ": "FJ",
+    "GAM": "GM",
+    "GCI": "GG",
+    "GEQ": "GQ",
+    "GER": "DE",
+    "GRE": "GR",
+    "GRN": "GD",
+    "GUA": "GT",
+    "GUI": "GN",
+    "HAI": "HT",
+    "HON": "HN",
+    "INA": "ID",
+    "IOM": "IM",
+    "IRI": "IR",
+    "ISV": "VI",
+    "IVB": "VG",
+    "JCI": "JE",
+    "KOS": "XK",
+    "KSA": "SA",
+    "KUW": "KW",
+    "LAT": "LV",
+    "LBA": "LY",
+    "LES": "LS",
+    "LIB": "LB",
+    "MAD": "MG",
+    "MAS": "MY",
+    "MAW": "MW",
+    "MGL": "MN",
+    "MNC": "MC",
+    "MRI": "MU",
+    "MTN": "MR",
+    "MYA": "MM",
+    "NCA": "NI",
+    "NED": "NL",
+    "NEP": "NP",
+    "NGR": "NG",
+    "NIG": "NE",
+    "OMA": "OM",
+    "PAR": "PY",
+    "PHI": "PH",
+    "PLE": "PS",
+    "POR": "PT",
+    "PUR": "PR",
+    "RSA": "ZA",
+    "SAM": "WS",
+    "SCO": "GB",
+    "SEY": "SC",
+    "SIN": "SG",
+    "SLO": "SI",
+    "SOL": "SB",
+    "SRI": "LK",
+    "SUD": "SD",
+    "SUI": "CH",
+    "TAN": "TZ",
+    "TOG": "TG",
+    "TPE": "TW",
+    "UAE": "AE",
+    "URU": "UY",
+    "VIE": "VN",
+    "WLS": "GB",
+    "ZAM": "ZM",
+    "ZIM": "ZW",
+}
+
+# GeoNames cities*.txt column indexes
+_COL_NAME = 1
+_COL_ASCII = 2
+_COL_ALT = 3
+_COL_LAT = 4
+_COL_LON = 5
+_COL_COUNTRY = 8
+_COL_POPULATION = 14
+
+Coords = Tuple[float, float]
+
+
+def normalize_place(name: str) -> str:
+    """
+    Normalize a place name for matching: drop accents, parenthesized or
+    comma-separated suffixes, punctuation and case ("Wijk aan Zee (NED)" ->
+    "wijk aan zee").
+    """
+    if not name:
+        return ""
+    s = re.sub(r"\(.*?\)", " ", name).split(",")[0].split("/")[0]
+    s = unicodedata.normalize("NFKD", s)
+    s = "".join(c for c in s if not unicodedata.combining(c))
+    s = re.sub(r"[^\w\s]", " ", s.casefold())
+    return " ".join(s.split())
+
+
+def load_country_info(path: str | Path) -> Tuple[Dict[str, str], Dict[str, str]]:
+    """
+    Parse countryInfo.txt. Returns (iso3 -> iso2, iso2 -> capital name).
+    """
+    iso3_to_iso2: Dict[str, str] = {}
+    capitals: Dict[str, str] = {}
+    with open(path, encoding="utf-8") as f:
+        for row in csv.reader(f, delimiter="\t"):
+            if not row or row[0].startswith("#") or len(row) < 6:
+                continue
+            iso2, iso3, capital = row[0], row[1], row[5]
+            iso3_to_iso2[iso3] = iso2
+            if capital:
+                capitals[iso2] = capital
+    return iso3_to_iso2, capitals
+
+
+def load_cities(path: str | Path) -> Dict[Tuple[str, str], Coords]:
+    """
+    Parse a GeoNames cities*.txt dump into (iso2, normalized name) -> (lat, lon).
+
+    Names, ASCII names and alternate names are all indexed; when several places
+    in a country share a name, the most populous one wins.
+    """
+    index: Dict[Tuple[str, str], Tuple[int, Coords]] = {}
+    with open(path, encoding="utf-8") as f:
+        for line in f:
+            row = line.rstrip("\n").split("\t")
+            if len(row) <= _COL_POPULATION:
+                continue
+            country = row[_COL_COUNTRY]
+            coords = (float(row[_COL_LAT]), float(row[_COL_LON]))
+            population = int(row[_COL_POPULATION] or 0)
+            names = {row[_COL_NAME], row[_COL_ASCII]}
+            names.update(n for n in row[_COL_ALT].split(",") if n)
+            for name in names:
+                key = (country, normalize_place(name))
+                if key[1] and (key not in index or population > index[key][0]):
+                    index[key] = (population, coords)
+    return {k: coords for k, (_, coords) in index.items()}
+
+
+class Geocoder:
+    """Resolve (city, fed) pairs against GeoNames data loaded into memory."""
+
+    def __init__(
+        self,
+        cities: Dict[Tuple[str, str], Coords],
+        iso3_to_iso2: Dict[str, str],
+        capitals: Dict[str, str],
+    ):
+        self.cities = cities
+        self.iso3_to_iso2 = iso3_to_iso2
+        self.capitals = capitals
+
+    @classmethod
+    def from_dir(
+        cls, geonames_dir: str | Path, cities_file: str = DEFAULT_CITIES_FILE
+    ) -> "Geocoder":
+        d = Path(geonames_dir)
+        iso3_to_iso2, capitals = load_country_info(d / COUNTRY_INFO_FILE)
+        return cls(load_cities(d / cities_file), iso3_to_iso2, capitals)
+
+    def country_code(self, fed: str) -> Optional[str]:
+        """ISO 3166 alpha-2 for a FIDE federation code, or None."""
+        fed = (fed or "").strip().upper()
+        return FIDE_TO_ISO2.get(fed) or self.iso3_to_iso2.get(fed)
+
+    def geocode(
+        self, city: str, fed: str
+    ) -> Tuple[Optional[str], Optional[Coords], Optional[str]]:
+        """Return (country_code, (lat, lon) or None, geo_match)."""
+        iso2 = self.country_code(fed)
+        if iso2 is None:
+            return None, None, None
+        coords = self.cities.get((iso2, normalize_place(city)))
+        if coords is not None:
+            return iso2, coords, MATCH_CITY
+        capital = self.capitals.get(iso2)
+        coords = self.cities.get((iso2, normalize_place(capital))) if capital else None
+        if coords is not None:
+            return iso2, coords, MATCH_COUNTRY
+        return iso2, None, MATCH_CAPITAL_MISSING
+
+
+def geocode_details(details: pd.DataFrame, geocoder: Geocoder) -> pd.DataFrame:
+    """Geocode each tournament in a details DataFrame (needs city and fed)."""
+    rows = []
+    cache: Dict[Tuple[str, str], tuple] = {}
+    for tid, city, fed in zip(
+        details["tournament_id"], details["city"].fillna(""), details["fed"].fillna("")
+    ):
+        key = (city, fed)
+        if key not in cache:
+            cache[key] = geocoder.geocode(city, fed)
+        iso2, coords, match = cache[key]
+        rows.append(
+            {
+                "tournament_id": tid,
+                "city": city,
+                "fed": fed,
+                "country_code": iso2,
+                "lat": coords[0] if coords else None,
+                "lon": coords[1] if coords else None,
+                "geo_match": match,
+            }
+        )
+    return pd.DataFrame(rows)
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="Geocode tournament City/Country with an offline GeoNames dump",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    parser.add_argument(
+        "--input", required=True, help="Tournament details Parquet (city, fed)"
+    )
+    parser.add_argument(
+        "--geonames-dir",
+        required=True,
+        help=f"Directory with {DEFAULT_CITIES_FILE} and {COUNTRY_INFO_FILE}",
+    )
+    parser.add_argument(
+        "--cities-file",
+        default=DEFAULT_CITIES_FILE,
+        help=f"GeoNames cities file name (default: {DEFAULT_CITIES_FILE})",
+    )
+    parser.add_argument("--output", required=True, help="Output Parquet path")
+    args = parser.parse_args()
+    redact.install()
+
+    try:
+        geocoder = Geocoder.from_dir(args.geonames_dir, args.cities_file)
+        details = pd.read_parquet(args.input, columns=["tournament_id", "city", "fed"])
+    except (OSError, ValueError) as e:
+        logger.error("%s", e)
+        return 1
+
+    geo = geocode_details(details, geocoder)
+    counts = geo["geo_match"].value_counts(dropna=False)
+    logger.info(
+        "Geocoded %d tournaments: %d city, %d country fallback, "
+        "%d capital missing, %d unknown federation",
+        len(geo),
+        int(counts.get(MATCH_CITY, 0)),
+        int(counts.get(MATCH_COUNTRY, 0)),
+        int(counts.get(MATCH_CAPITAL_MISSING, 0)),
+        int(geo["geo_match"].isna().sum()),
+    )
+    out = Path(args.output)
+    out.parent.mkdir(parents=True, exist_ok=True)
+    provenance = build_provenance(geocoded_with=f"GeoNames {args.cities_file}")
+    out.write_bytes(dataframe_to_parquet_bytes(geo, provenance))
+    logger.info("Saved %s", out)
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -19,6 +19,7 @@ from typing import List, Dict, Optional
 import requests
 from bs4 import BeautifulSoup
 
+import redact
 from s3_io import (
     build_federations_data_uri,
     build_local_path_for_run,
@@ -379,6 +380,7 @@ def main() -> int:
     )
 
     args = parser.parse_args()
+    redact.install()
 
     if args.output is not None and is_s3_path(args.output):
         bucket, _ = parse_s3_uri(args.output)
@@ -16,7 +16,6 @@ import signal
 import sys
 import tempfile
 from collections import Counter
-import datetime
 import json
 import random
 import time
@@ -29,6 +28,8 @@ from typing import Any
 import pandas as pd
 import requests
 
+import redact
+from bulk_download import download_file
 from s3_io import (
     build_player_lists_data_uri,
     build_player_lists_raw_uri,
@@ -48,6 +49,8 @@ from s3_io import (
     PLAYER_LISTS_REPORTS_PREFIX,
     PLAYER_LISTS_SAMPLE_PREFIX,
 )
+from provenance import build_provenance, dataframe_to_parquet_bytes
+from timestamps import utc_now
 
 logging.basicConfig(
     level=logging.INFO,
@@ -60,6 +63,8 @@ _shutdown_state = {}
 
 # Combined list STD, BLZ, RPD - XML format
 DOWNLOAD_URL = "https://ratings.fide.com/download/players_list_xml.zip"
+# ~45 MB today; anything far larger is not the rating list
+MAX_DOWNLOAD_BYTES = 500 * 1024 * 1024
 
 # Title normalization: single-letter -> full code
 TITLE_MAP = {
@@ -99,7 +104,7 @@ def _sanitize_byear(value: int | None) -> int | None:
     """Return byear if in valid range (1900..current_year), else None."""
     if value is None:
         return None
-    current_year = datetime.datetime.now().year
+    current_year = utc_now().year
     if 1900 <= value < current_year:
         return value
     return None
@@ -126,7 +131,7 @@ def parse_xml_content(xml_bytes: bytes) -> tuple[list[dict[str, Any]], dict[str,
 
     Uses iterparse (streaming) to avoid loading the full DOM into memory.
     """
-    current_year = datetime.datetime.now().year
+    current_year = utc_now().year
     rows: list[dict[str, Any]] = []
     xml_fields: set[str] = set()
     skipped_no_id = 0
@@ -198,9 +203,7 @@ def parse_xml_content(xml_bytes: bytes) -> tuple[list[dict[str, Any]], dict[str,
         elif title_normalized in WOMEN_TITLES:
             output_w_title = title_normalized
 
-        fed = _elem_text(children.get("country"))
-        if fed:
-            fed = fed.upper()
+        fed = _elem_text(children.get("country")).upper() or None
 
         rows.append(
             {
@@ -259,25 +262,32 @@ def download_player_list(
     max_retries: int = 3,
     retry_delay: float = 2.0,
     session: requests.Session | None = None,
+    download_dir: str | Path | None = None,
 ) -> bytes:
     """
     Download the FIDE players_list_xml.zip with retry logic.
 
+    Streams to disk with Range resume and a zip CRC check (bulk_download). With
+    download_dir, the partial file is kept there so a rerun resumes it; otherwise
+    a temporary directory is used and removed.
+
     Returns:
         Raw bytes of the zip file.
     """
-    sess = session or requests.Session()
-    for attempt in range(max_retries):
-        try:
-            resp = sess.get(DOWNLOAD_URL, timeout=120)
-            resp.raise_for_status()
-            return resp.content
-        except requests.RequestException as e:
-            if attempt < max_retries - 1:
-                time.sleep(retry_delay * (attempt + 1))
-                continue
-            raise e
-    raise RuntimeError("Download failed")  # unreachable
+    with tempfile.TemporaryDirectory() as tmp:
+        dest = Path(download_dir or tmp) / Path(DOWNLOAD_URL).name
+        download_file(
+            DOWNLOAD_URL,
+            dest,
+            session=session,
+            max_retries=max_retries,
+            retry_delay=retry_delay,
+            max_bytes=MAX_DOWNLOAD_BYTES,
+        )
+        zip_bytes = dest.read_bytes()
+        if download_dir:
+            dest.unlink()
+        return zip_bytes
 
 
 def process_zip(zip_bytes: bytes) -> list[dict[str, Any]]:
@@ -343,7 +353,7 @@ def build_report(
 
     Returns a dict suitable for JSON serialization.
     """
-    current_year = datetime.datetime.now().year
+    current_year = utc_now().year
     valid_feds = load_federations(federations_path) if federations_path else frozenset()
 
     SAMPLE_SIZE = 10
@@ -585,14 +595,16 @@ def _save_results(
         logger.warning("No players to save")
         return
     df = df[["byear", "id", "fed", "name", "sex", "title", "w_title"]]
-    buf = BytesIO()
-    df.to_parquet(buf, index=False)
-    write_output(buf.getvalue(), str(parquet_path))
+    provenance = build_provenance()
+    write_output(dataframe_to_parquet_bytes(df, provenance), str(parquet_path))
     compressed = _compress_xml_gzip(xml_content)
     write_output(compressed, str(xml_path))
     sample = players[:100]
     write_output(json.dumps(sample, indent=2, default=str), str(json_sample_path))
-    report = build_report(players, parse_stats, federations_path)
+    report = {
+        "provenance": provenance,
+        **build_report(players, parse_stats, federations_path),
+    }
     write_output(json.dumps(report, indent=2, default=str), str(report_path))
     logger.info("Saved parquet: %s", parquet_path)
     logger.info("Saved XML (gzip): %s", xml_path)
@@ -606,6 +618,7 @@ def run_shared(
     override: bool = False,
     quiet: bool = False,
     federations_uri: str | None = None,
+    download_dir: str | Path | None = None,
 ) -> str:
     """
     Download FIDE player list and write to shared path (player_lists/data/player_list_{timestamp}.parquet).
@@ -617,6 +630,8 @@ def run_shared(
         override: If True, skip list check and always fetch + write.
         quiet: Reduce log output.
         federations_uri: S3 URI or path for federations (for report). If None, resolves latest.
+        download_dir: Keep an interrupted download here so a rerun resumes it
+            (see download_player_list). Default: a temporary directory.
 
     Returns:
         URI or path str of the parquet file used.
@@ -724,7 +739,7 @@ def run_shared(
     start = time.time()
 
     try:
-        zip_bytes = download_player_list()
+        zip_bytes = download_player_list(download_dir=download_dir)
         players, parse_stats, xml_content = _process_zip_internal(zip_bytes)
     except Exception as e:
         logger.error("Error: %s", e)
@@ -751,6 +766,7 @@ def run(
     override: bool = False,
     quiet: bool = False,
     federations_s3_uri: str | None = None,
+    download_dir: str | Path | None = None,
 ) -> int:
     """
     Download FIDE player list and write to S3.
@@ -763,6 +779,7 @@ def run(
         quiet: If True, reduce log output.
         federations_s3_uri: Optional S3 URI for federations.csv (for report's fed check).
             Defaults to {base}/data/federations.csv when None.
+        download_dir: As for run_shared.
 
     Returns:
         0 on success, 1 on failure.
@@ -834,7 +851,7 @@ def run(
     start = time.time()
 
     try:
-        zip_bytes = download_player_list()
+        zip_bytes = download_player_list(download_dir=download_dir)
         players, parse_stats, xml_content = _process_zip_internal(zip_bytes)
     except Exception as e:
         logger.error("Error: %s", e)
@@ -923,7 +940,15 @@ def main() -> int:
         default="",
         help="Path for report JSON (default: players_list_report.json in output dir)",
     )
+    parser.add_argument(
+        "--download-dir",
+        type=str,
+        default=None,
+        help="Keep an interrupted download here so a rerun resumes it "
+        "(default: a temporary directory)",
+    )
     args = parser.parse_args()
+    redact.install()
 
     if args.quiet:
         logging.getLogger().setLevel(logging.WARNING)
@@ -953,6 +978,7 @@ def main() -> int:
             override=args.override,
             quiet=args.quiet,
             federations_s3_uri=fed_uri,
+            download_dir=args.download_dir,
         )
 
     # Local output: run structure uses shared path; legacy uses old structure
@@ -971,6 +997,7 @@ def main() -> int:
             override=args.override,
             quiet=args.quiet,
             federations_uri=str(fed_path) if fed_path else None,
+            download_dir=args.download_dir,
         )
         return 0
 
@@ -1020,7 +1047,7 @@ def main() -> int:
     start = time.time()
 
     try:
-        zip_bytes = download_player_list()
+        zip_bytes = download_player_list(download_dir=args.download_dir)
         players, parse_stats, xml_content = _process_zip_internal(zip_bytes)
     except Exception as e:
         logger.error("Error: %s", e)
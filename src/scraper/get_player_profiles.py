@@ -0,0 +1,329 @@
+#!/usr/bin/env python3
+"""
+Scrape FIDE profiles for players who appear in games but not in the player list.
+
+FIDE's bulk player list leaves out some players who appear in crosstables. This
+command gathers every distinct player ID in the given games or reports Parquet
+files, plus the IDs in the profile queue that validation fills (profile_queue.py).
+It drops IDs already in the player list and IDs already in --output, then fetches
+the profile page of each remaining ID and nothing else:
+
+  https://ratings.fide.com/profile/{id}
+
+Output rows use the player list columns (id, name, fed, sex, byear, title,
+w_title) plus fetched_at, so they can be appended to the player table. A rerun
+only fetches IDs that are not in --output yet. Found IDs are removed from the
+queue. IDs without a profile are logged and stay queued.
+
+--output and --queue may be S3 URIs. The shared locations,
+s3://{bucket}/player_lists/profiles/player_profiles.parquet and
+s3://{bucket}/player_lists/queue/missing_player_ids.txt, are the ones the
+validate Lambda reads: IDs in the profiles file are not queued again.
+
+Federations are shown on the profile by name. Pass --federations (the CSV from
+get_federations.py) to map them to codes; without it, fed is null unless the page
+shows a code.
+
+Usage:
+  uv run src/scraper/get_player_profiles.py \\
+    --games data/prod/*/data/tournament_reports_games.parquet \\
+    --player-list data/player_lists/data/player_list_20250101-000000.parquet \\
+    --queue data/player_lists/queue/missing_player_ids.txt \\
+    --federations data/federations/data/federations_20250101-000000.csv \\
+    --output data/player_lists/profiles/player_profiles.parquet
+"""
+
+import argparse
+import csv
+import io
+import logging
+import re
+import sys
+import time
+from pathlib import Path
+from typing import Dict, Iterable, List, Optional, Tuple
+
+import pandas as pd
+import requests
+from bs4 import BeautifulSoup
+
+import http_timeouts
+import redact
+import user_agents
+from profile_queue import read_queue, update_queue, valid_ids
+from provenance import build_provenance, dataframe_to_parquet_bytes
+from rate_limiter import RateLimiter
+from run_summary import exit_code_for
+from s3_io import read_versioned, write_output
+from timestamps import utc_now
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+PROFILE_URL = "https://ratings.fide.com/profile/{player_id}"
+OUTPUT_COLUMNS = [
+    "id",
+    "name",
+    "fed",
+    "sex",
+    "byear",
+    "title",
+    "w_title",
+    "fetched_at",
+]
+ID_COLUMNS = ("white_player_id", "black_player_id", "player_id")
+MAX_ATTEMPTS = 3
+
+TITLES = {
+    "grandmaster": "GM",
+    "international master": "IM",
+    "fide master": "FM",
+    "candidate master": "CM",
+}
+W_TITLES = {
+    "woman grandmaster": "WGM",
+    "woman international master": "WIM",
+    "woman fide master": "WFM",
+    "woman candidate master": "WCM",
+}
+SEX = {"male": "M", "female": "F"}
+
+# Profile labels (lowercased, colon and spaces stripped) -> field
+LABELS = {
+    "federation": "fed",
+    "b-year": "byear",
+    "birthyear": "byear",
+    "yearofbirth": "byear",
+    "sex": "sex",
+    "gender": "sex",
+    "fidetitle": "title",
+    "title": "title",
+}
+
+
+def load_federation_codes(path: str | Path) -> Dict[str, str]:
+    """Lowercased federation name -> code from a get_federations.py CSV."""
+    codes = {}
+    with open(path, "r", encoding="utf-8") as f:
+        for row in csv.DictReader(f):
+            code = (row.get("code") or "").strip().upper()
+            name = (row.get("name") or "").strip().lower()
+            if code and name:
+                codes[name] = code
+    return codes
+
+
+def _label_key(text: str) -> str:
+    return re.sub(r"[\s:]+", "", text).lower()
+
+
+def _profile_fields(soup: BeautifulSoup) -> Dict[str, str]:
+    """Label/value pairs from the profile info blocks (header div + data div)."""
+    fields = {}
+    for header in soup.select("[class*='__row__header']"):
+        data = header.find_next_sibling(class_=re.compile("__row__data"))
+        field = LABELS.get(_label_key(header.get_text(" ", strip=True)))
+        if field and data is not None and field not in fields:
+            fields[field] = data.get_text(" ", strip=True)
+    return fields
+
+
+def parse_titles(text: str) -> Tuple[Optional[str], Optional[str]]:
+    """(title, w_title) from the profile's title text, e.g. "Grandmaster"."""
+    lowered = text.lower()
+    title = w_title = None
+    for name, code in W_TITLES.items():
+        if name in lowered:
+            w_title = code
+            lowered = lowered.replace(name, "")
+    for name, code in TITLES.items():
+        if name in lowered:
+            title = code
+            break
+    return title, w_title
+
+
+def parse_profile(
+    content: bytes | str, fed_codes: Optional[Dict[str, str]] = None
+) -> Optional[Dict]:
+    """Player list fields from a profile page, or None if it shows no player."""
+    soup = BeautifulSoup(content, "html.parser")
+    name_el = soup.select_one(".profile-top-title")
+    name = name_el.get_text(" ", strip=True) if name_el else ""
+    if not name:
+        return None
+    fields = _profile_fields(soup)
+
+    fed_text = fields.get("fed", "").strip()
+    if re.fullmatch(r"[A-Z]{3}", fed_text):
+        fed = fed_text
+    else:
+        fed = (fed_codes or {}).get(fed_text.lower())
+    byear_match = re.search(r"\b(1[89]\d\d|20\d\d)\b", fields.get("byear", ""))
+    title, w_title = parse_titles(fields.get("title", ""))
+    return {
+        "name": name,
+        "fed": fed,
+        "sex": SEX.get(fields.get("sex", "").strip().lower()),
+        "byear": int(byear_match.group(1)) if byear_match else None,
+        "title": title,
+        "w_title": w_title,
+    }
+
+
+def game_player_ids(paths: Iterable[str | Path]) -> set:
+    """Distinct player IDs in games (white/black) or reports players Parquet files."""
+    ids = set()
+    for path in paths:
+        df = pd.read_parquet(path)
+        for col in ID_COLUMNS:
+            if col in df.columns:
+                ids |= valid_ids(df[col].dropna().unique())
+    return ids
+
+
+def ids_to_fetch(
+    candidates: Iterable[str], known: Iterable, done: Iterable
+) -> List[str]:
+    """Candidates not in the player list or output yet, in numeric order."""
+    todo = valid_ids(candidates) - valid_ids(known) - valid_ids(done)
+    return sorted(todo, key=int)
+
+
+def fetch_profile(
+    player_id: str,
+    session: requests.Session,
+    fed_codes: Optional[Dict[str, str]] = None,
+) -> Tuple[Optional[Dict], Optional[str]]:
+    """(profile fields, None) or (None, error); "no profile" for unknown IDs."""
+    url = PROFILE_URL.format(player_id=player_id)
+    error = None
+    for attempt in range(MAX_ATTEMPTS):
+        if attempt:
+            time.sleep(2**attempt)
+        try:
+            response = http_timeouts.get(
+                session, url, headers=user_agents.browser_headers()
+            )
+        except requests.exceptions.Timeout as e:
+            error = f"timeout: {e}"
+            continue
+        except requests.exceptions.RequestException as e:
+            error = f"network error: {e}"
+            continue
+        if response.status_code == 404:
+            return None, "no profile"
+        if response.status_code != 200:
+            error = f"HTTP {response.status_code}"
+            continue
+        profile = parse_profile(response.content, fed_codes)
+        return (profile, None) if profile else (None, "no profile")
+    return None, error
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="Scrape FIDE profiles for player IDs missing from the player list",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    parser.add_argument(
+        "--games",
+        nargs="*",
+        default=[],
+        help="Games or reports players Parquet files to take player IDs from",
+    )
+    parser.add_argument("--queue", help="Profile queue file (also updated)")
+    parser.add_argument(
+        "--player-list", required=True, help="Player list Parquet (IDs to skip)"
+    )
+    parser.add_argument("--federations", help="Federations CSV (code,name)")
+    parser.add_argument("--output", required=True, help="Profiles Parquet")
+    parser.add_argument(
+        "--rate-limit",
+        type=float,
+        default=0.5,
+        help="Requests per second (default: 0.5)",
+    )
+    parser.add_argument("--limit", type=int, default=0, help="Fetch at most N")
+    http_timeouts.add_arguments(parser)
+    args = parser.parse_args()
+    redact.install()
+
+    if not args.games and not args.queue:
+        logger.error("Nothing to do: pass --games and/or --queue")
+        return 1
+    try:
+        http_timeouts.configure_from_args(args)
+        fed_codes = load_federation_codes(args.federations) if args.federations else {}
+        candidates = game_player_ids(args.games)
+        if args.queue:
+            candidates |= set(read_queue(args.queue))
+        known = pd.read_parquet(args.player_list, columns=["id"])["id"]
+        content, _ = read_versioned(args.output)
+        existing = (
+            pd.read_parquet(io.BytesIO(content))
+            if content is not None
+            else pd.DataFrame(columns=OUTPUT_COLUMNS)
+        )
+    except (OSError, ValueError, KeyError) as e:
+        logger.error("%s", e)
+        return 1
+
+    todo = ids_to_fetch(candidates, known, existing["id"])
+    if args.limit > 0:
+        todo = todo[: args.limit]
+    logger.info(
+        "%d player IDs in games/queue; %d to fetch (%d in player list, %d done)",
+        len(candidates),
+        len(todo),
+        len(candidates & valid_ids(known)),
+        len(existing),
+    )
+
+    session = requests.Session()
+    rate_limiter = RateLimiter(args.rate_limit)
+    rows, failed = [], {}
+    for i, player_id in enumerate(todo, 1):
+        rate_limiter.wait()
+        profile, error = fetch_profile(player_id, session, fed_codes)
+        if profile is None:
+            failed[player_id] = error
+            logger.warning("Profile %s: %s", player_id, error)
+        else:
+            rows.append({"id": int(player_id), **profile, "fetched_at": utc_now()})
+        if i % 50 == 0:
+            logger.info("Progress: %d/%d | %s", i, len(todo), rate_limiter.describe())
+
+    if rows:
+        new = pd.DataFrame(rows, columns=OUTPUT_COLUMNS)
+        profiles = (
+            pd.concat([existing, new], ignore_index=True) if len(existing) else new
+        )
+        provenance = build_provenance(
+            source_url="https://ratings.fide.com/profile/", profiles=len(profiles)
+        )
+        write_output(dataframe_to_parquet_bytes(profiles, provenance), args.output)
+        logger.info(
+            "Saved %d new profiles (%d total) to %s",
+            len(rows),
+            len(profiles),
+            args.output,
+        )
+    if args.queue:
+        # Found IDs leave the queue; IDs from --games without a profile join it
+        stats = update_queue(args.queue, failed, [r["id"] for r in rows])
+        logger.info("Queue %s: %d IDs left", args.queue, stats["queued"])
+
+    logger.info(
+        "Done: %d fetched, %d without a profile or failed", len(rows), len(failed)
+    )
+    return exit_code_for(len(rows), len(failed))
+
+
+if __name__ == "__main__":
+    sys.exit(main())
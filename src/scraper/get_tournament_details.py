@@ -13,11 +13,13 @@ import json
 import logging
 import os
 import random
+import re
 import signal
 import sys
 import tempfile
 import time
 from collections import Counter
+from contextlib import nullcontext
 from datetime import datetime
 from pathlib import Path
 from typing import Dict, List, Optional, Tuple
@@ -28,6 +30,38 @@ import requests
 from bs4 import BeautifulSoup
 from tqdm import tqdm
 
+import anomaly
+import circuit_breaker
+import disk_guard
+import http_timeouts
+import live_config
+import redact
+import tracing
+import user_agents
+from checkpoints import (
+    DEFAULT_KEEP,
+    CheckpointSchedule,
+    load_latest,
+    parse_duration,
+    write_rotated,
+)
+from provenance import build_provenance, dataframe_to_parquet_bytes
+from rate_limiter import RateLimiter
+from report_links import parse_report_link
+from run_summary import (
+    EXIT_FATAL,
+    EXIT_SUCCESS,
+    build_summary,
+    exit_code_for,
+    format_failure_groups,
+    group_failures,
+    latest_results,
+    summary_path,
+    use_color,
+    write_summary,
+)
+from timestamps import fide_date
+
 # Configure logging
 logging.basicConfig(
     level=logging.INFO,
@@ -36,29 +70,13 @@ logging.basicConfig(
 )
 logger = logging.getLogger(__name__)
 
+# --input value that reads IDs from stdin, e.g. `duckdb ... | ... --input -`
+STDIN_INPUT = "-"
+
 # State for graceful shutdown
 _shutdown_state = {}
 
 
-class RateLimiter:
-    """Enforces minimum spacing between requests (no bursting)."""
-
-    def __init__(self, requests_per_second: float):
-        self.min_interval = 1.0 / requests_per_second
-        self.last_request = 0.0
-
-    def wait(self):
-        """Wait until enough time has passed since the last request."""
-        now = time.perf_counter()
-        elapsed = now - self.last_request
-        if elapsed < self.min_interval:
-            time.sleep(self.min_interval - elapsed)
-        self.last_request = time.perf_counter()
-
-    def get_rate(self) -> float:
-        return 1.0 / self.min_interval
-
-
 # Optional S3 support (used by run() when paths are S3 URIs)
 def _is_s3(path: str) -> bool:
     try:
@@ -94,22 +112,26 @@ def _write_to_path(path: str, content: bytes | str) -> None:
         write_output(content, path)
     else:
         p = Path(path)
-        p.parent.mkdir(parents=True, exist_ok=True)
-        if isinstance(content, str):
-            p.write_text(content, encoding="utf-8")
-        else:
-            p.write_bytes(content)
 
+        def _write() -> None:
+            p.parent.mkdir(parents=True, exist_ok=True)
+            if isinstance(content, str):
+                p.write_text(content, encoding="utf-8")
+            else:
+                p.write_bytes(content)
+
+        disk_guard.guarded_write(_write, p)
 
-def _read_ids_from_path(path: str) -> List[str]:
-    """Read tournament IDs from a file (local or S3)."""
+
+def _read_ids_from_path(path: str, limit: int = 0) -> List[str]:
+    """Read tournament IDs from a file (local or S3), or stdin for "-"."""
     if _is_s3(path):
         from s3_io import download_to_file
 
         local_path = Path(tempfile.gettempdir()) / "tournament_ids.txt"
         download_to_file(path, local_path)
         path = str(local_path)
-    return read_tournament_ids(path)
+    return read_tournament_ids(path, limit=limit)
 
 
 def format_duration(seconds: float) -> str:
@@ -126,14 +148,24 @@ def format_duration(seconds: float) -> str:
         return f"{hours}h {minutes}m"
 
 
-def read_tournament_ids(file_path: str) -> List[str]:
-    """Read tournament IDs from a file."""
+def read_tournament_ids(file_path: str, limit: int = 0) -> List[str]:
+    """
+    Read all tournament IDs from a file, or from stdin when file_path is "-". With
+    limit > 0 reading stops after limit IDs, so the rest of a stream is not drained.
+    """
     ids = []
-    with open(file_path, "r", encoding="utf-8") as f:
+    source = (
+        nullcontext(sys.stdin)
+        if file_path == STDIN_INPUT
+        else open(file_path, "r", encoding="utf-8")
+    )
+    with source as f:
         for line in f:
             tid = line.strip()
             if tid:
                 ids.append(tid)
+                if limit > 0 and len(ids) >= limit:
+                    break
     return ids
 
 
@@ -162,6 +194,33 @@ def extract_text_from_cell(cell) -> str:
     return " ".join(parts)
 
 
+_PROFILE_ID_RE = re.compile(r"/profile/(\d+)")
+_FED_SUFFIX_RE = re.compile(r"\s*\([A-Z]{3}\)$")
+
+
+def extract_people(cell) -> List[Dict[str, Optional[str]]]:
+    """
+    People in an arbiter/organizer cell: {"name", "fide_id"} per /profile/ link,
+    e.g. "Marghetis, Aris (CAN)" -> name "Marghetis, Aris", fide_id "2611058".
+    Text outside links (an organizer without a profile) is one more name with
+    fide_id None.
+    """
+    people = []
+    for link in cell.find_all("a"):
+        name = _FED_SUFFIX_RE.sub("", link.get_text(" ", strip=True))
+        if not name:
+            continue
+        m = _PROFILE_ID_RE.search(link.get("href") or "")
+        people.append({"name": name, "fide_id": m.group(1) if m else None})
+    cell_copy = cell.__copy__()
+    for link in cell_copy.find_all("a"):
+        link.decompose()
+    remaining = " ".join(cell_copy.get_text(" ", strip=True).split())
+    if remaining:
+        people.append({"name": remaining, "fide_id": None})
+    return people
+
+
 def extract_links_from_cell(cell) -> List[str]:
     """Extract link texts from a table cell."""
     links = []
@@ -213,18 +272,30 @@ def parse_n_players(raw: str) -> tuple[Optional[int], bool]:
         return None, False
 
 
+def parse_n_rounds(raw: str) -> Optional[int]:
+    """Parse n_rounds to a positive int, None if missing or invalid."""
+    try:
+        n = int(str(raw or "").strip())
+    except ValueError:
+        return None
+    return n if n > 0 else None
+
+
 def parse_date(raw: str) -> Optional[datetime]:
-    """Parse date string to datetime. Returns None if unparseable."""
+    """
+    Parse date string to midnight UTC of that calendar day (see
+    timestamps.fide_date). Returns None if unparseable.
+    """
     if not raw or not str(raw).strip():
         return None
     s = str(raw).strip()
     for fmt in ("%Y-%m-%d", "%d.%m.%Y", "%d/%m/%Y", "%Y/%m/%d"):
         try:
-            return datetime.strptime(s, fmt)
+            return fide_date(datetime.strptime(s, fmt))
         except ValueError:
             continue
     try:
-        return pd.to_datetime(s)
+        return fide_date(pd.to_datetime(s))
     except Exception:
         return None
 
@@ -234,6 +305,65 @@ def parse_nat_championship(raw: str) -> bool:
     return bool(raw and str(raw).strip())
 
 
+# Details-table labels -> JSON field names. Keys are normalize_label() forms;
+# variants cover re-worded/re-cased labels seen on older or localized pages.
+_LABEL_FIELDS = {
+    "id": ["Event code", "Tournament code"],
+    "name": ["Tournament Name", "Event name"],
+    "city": ["City", "Place", "Location"],
+    "fed": ["Country", "Federation"],
+    "n_players": ["Number of players", "No. of players"],
+    "n_rounds": ["No. of rounds", "Rounds"],
+    "system": ["System", "Pairing system"],
+    "hybrid": ["Hybrid"],
+    "category": ["Category"],
+    "start_date": ["Start Date", "Date start", "Starting date"],
+    "end_date": ["End Date", "Date end", "Ending date"],
+    "date_received": ["Date received"],
+    "date_registered": ["Date registered", "Registration date"],
+    "type": ["Type", "Tournament type"],
+    "time_control": ["Time Control", "Rate of play"],
+    "zone": ["Zone"],
+    "nat_championship": ["Nat. Championship", "National Championship"],
+    "view_report": ["View Report"],
+    "chief_arbiter": ["Chief Arbiter"],
+    "deputy_chief_arbiter": ["Deputy Chief Arbiter", "Deputy Chief Arbiters"],
+    "arbiter": ["Arbiter", "Arbiters"],
+    "assistant_arbiter": ["Assistant Arbiter", "Assistant Arbiters"],
+    "chief_organizer": ["Chief Organizer", "Chief Organiser"],
+    "organizer": ["Organizer", "Organiser", "Organizers"],
+}
+
+# Fields whose cells list people (see extract_people); flattened to the list
+# columns {field}_names and {field}_fide_ids
+PEOPLE_FIELDS = (
+    "chief_arbiter",
+    "deputy_chief_arbiter",
+    "arbiter",
+    "assistant_arbiter",
+    "chief_organizer",
+    "organizer",
+)
+
+
+def normalize_label(label: str) -> str:
+    """Lowercase, drop a trailing colon and collapse whitespace (incl. nbsp)."""
+    s = " ".join(str(label or "").replace("\xa0", " ").split()).lower()
+    return s.rstrip(":").rstrip()
+
+
+LABEL_ALIASES: Dict[str, str] = {
+    normalize_label(label): field
+    for field, labels in _LABEL_FIELDS.items()
+    for label in labels
+}
+
+
+def field_for_label(label: str) -> Optional[str]:
+    """JSON field name for a details-table label, or None if unknown."""
+    return LABEL_ALIASES.get(normalize_label(label))
+
+
 def fetch_tournament_details(
     tournament_id: str,
     session: requests.Session,
@@ -267,17 +397,12 @@ def fetch_tournament_details(
             time.sleep(delay)
 
         try:
-            headers = {
-                "User-Agent": "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
-                "Accept": "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8",
-                "Accept-Language": "en-US,en;q=0.9",
-                "Cache-Control": "max-age=0",
-            }
+            headers = user_agents.browser_headers({"Cache-Control": "max-age=0"})
 
             t0 = time.perf_counter()
             try:
-                # Connect 15s, read 45s (match reports; fail connect fast for all-or-nothing)
-                response = session.get(url, headers=headers, timeout=(15, 45))
+                # Connect 15s, read 45s by default (see http_timeouts)
+                response = http_timeouts.get(session, url, headers=headers)
             finally:
                 elapsed = time.perf_counter() - t0
                 attempt_times.append(elapsed)
@@ -313,6 +438,7 @@ def fetch_tournament_details(
 
             details_table = soup.find("table", class_="details_table")
             if not details_table:
+                anomaly.observe("details", response.content, 0, tournament_id)
                 return None, "no data found", len(attempt_times), None
 
             details = {}
@@ -328,29 +454,15 @@ def fetch_tournament_details(
                 label = label_cell.get_text(strip=True)
                 value = extract_text_from_cell(value_cell)
 
-                # Map labels to JSON field names
-                field_map = {
-                    "Event code": "id",
-                    "Tournament Name": "name",
-                    "City": "city",
-                    "Country": "fed",
-                    "Number of players": "n_players",
-                    "System": "system",
-                    "Hybrid": "hybrid",
-                    "Category": "category",
-                    "Start Date": "start_date",
-                    "End Date": "end_date",
-                    "Date received": "date_received",
-                    "Date registered": "date_registered",
-                    "Type": "type",
-                    "Time Control": "time_control",
-                    "Zone": "zone",
-                    "Nat. Championship": "nat_championship",
-                }
-
-                if label in field_map:
-                    details[field_map[label]] = value
+                field = field_for_label(label)
+                if field in PEOPLE_FIELDS:
+                    details[field] = extract_people(value_cell)
+                elif field:
+                    details[field] = value
+                if field == "view_report":
+                    details["view_report_href"] = extract_link_href(value_cell)
 
+            anomaly.observe("details", response.content, len(details), tournament_id)
             # Remove empty fields
             return (
                 {k: v for k, v in details.items() if v},
@@ -478,12 +590,22 @@ def fetch_tournament_details(
     return None, f"max retries exceeded: {last_error}", len(attempt_times), None
 
 
+def optional_str(value) -> Optional[str]:
+    """value as a string, or None when missing or blank (null, not "", in Parquet)."""
+    if value is None or not str(value).strip():
+        return None
+    return str(value)
+
+
 def flatten_result(result: Dict) -> Dict:
-    """Flatten a result dictionary for Parquet storage with processed fields."""
+    """
+    Flatten a result dictionary for Parquet storage with processed fields.
+    Missing or blank fields are None (null), never "".
+    """
     flattened = {
         "tournament_id": result.get("tournament_id", ""),
         "success": result.get("success", False),
-        "error": result.get("error", ""),
+        "error": optional_str(result.get("error")),
     }
 
     details = result.get("details", {})
@@ -500,12 +622,16 @@ def flatten_result(result: Dict) -> Dict:
             "type",
             "zone",
         ]:
-            flattened[field] = details.get(field, "")
+            flattened[field] = optional_str(details.get(field))
 
         # n_players: int, None if invalid
         n_players_val, _ = parse_n_players(details.get("n_players", ""))
         flattened["n_players"] = n_players_val
 
+        # n_rounds: int, None if not on the page (current pages have no rounds row;
+        # the reports players file has the crosstable's n_rounds)
+        flattened["n_rounds"] = parse_n_rounds(details.get("n_rounds", ""))
+
         # time_control: S/R/B from first word
         tc_raw = details.get("time_control", "")
         tc_code, _ = parse_time_control(tc_raw)
@@ -522,6 +648,20 @@ def flatten_result(result: Dict) -> Dict:
             details.get("nat_championship", "")
         )
 
+        # View Report link: which report it is (see report_links)
+        link = parse_report_link(
+            details.get("view_report_href"), details.get("view_report", "")
+        )
+        flattened["report_kind"] = link.kind if link else None
+        flattened["report_system"] = optional_str(link.system) if link else None
+        flattened["report_event"] = optional_str(link.event) if link else None
+
+        # Arbiters and organizers: parallel lists of names and FIDE IDs
+        for field in PEOPLE_FIELDS:
+            people = details.get(field) or []
+            flattened[f"{field}_names"] = [p["name"] for p in people]
+            flattened[f"{field}_fide_ids"] = [p.get("fide_id") for p in people]
+
     return flattened
 
 
@@ -542,6 +682,9 @@ def build_report(results: List[Dict]) -> Dict:
     report: Dict = {
         "tournaments_total": len(results),
         "tournaments_success": len(successful),
+        "failure_groups": group_failures(
+            latest_results(results, "tournament_id"), "tournament_id"
+        ),
     }
 
     if df_success.empty:
@@ -630,20 +773,33 @@ def save_time_control_unique_values(results: List[Dict], output_path: str):
 
 
 # Columns that can be int or None across chunks; use float64 for consistent Parquet schema
-_NULLABLE_NUMERIC_COLS = ("n_players",)
+_NULLABLE_NUMERIC_COLS = ("n_players", "n_rounds")
+
+
+def results_to_parquet_bytes(
+    results: List[Dict], prior: Optional[pd.DataFrame] = None
+) -> bytes:
+    """
+    Serialize flattened results to Parquet bytes (with provenance metadata). prior
+    rows (already flattened, e.g. from --resume) come first.
+    """
+    df = results_to_dataframe(results)
+    if prior is not None and len(prior):
+        df = pd.concat([prior, df], ignore_index=True)
+    for col in _NULLABLE_NUMERIC_COLS:
+        if col in df.columns:
+            df[col] = df[col].astype("float64")
+    return dataframe_to_parquet_bytes(df)
 
 
-def save_results_parquet(results: List[Dict], parquet_path: str) -> None:
-    """Save results as Parquet file (local or S3)."""
+def save_results_parquet(
+    results: List[Dict], parquet_path: str, prior: Optional[pd.DataFrame] = None
+) -> None:
+    """Save results (after any prior rows) as Parquet file (local or S3)."""
     try:
-        df = results_to_dataframe(results)
-        for col in _NULLABLE_NUMERIC_COLS:
-            if col in df.columns:
-                df[col] = df[col].astype("float64")
-        buf = io.BytesIO()
-        df.to_parquet(buf, index=False, engine="pyarrow")
-        _write_to_path(parquet_path, buf.getvalue())
-        logger.info(f"Saved {len(results)} records to {parquet_path}")
+        _write_to_path(parquet_path, results_to_parquet_bytes(results, prior))
+        n_prior = len(prior) if prior is not None else 0
+        logger.info(f"Saved {len(results) + n_prior} records to {parquet_path}")
     except Exception as e:
         logger.error(f"Parquet save failed: {e}")
 
@@ -706,6 +862,7 @@ def build_and_save_report(
         "city",
         "fed",
         "n_players",
+        "n_rounds",
         "system",
         "hybrid",
         "category",
@@ -776,6 +933,7 @@ def build_and_save_report(
     }
 
     report = {
+        "provenance": build_provenance(),
         "tournaments_count": len(successful),
         "nulls_by_column": nulls_by_column,
         "distributions": distributions,
@@ -829,12 +987,28 @@ def run(
     output_sample_path: str | None = None,
     output_reports_base: str | None = None,
     save_raw: bool = True,
+    checkpoint_interval: float = 0.0,
+    checkpoint_keep: int = DEFAULT_KEEP,
+    summary_file: str | None = None,
+    resume: bool = False,
+    breaker: Optional[circuit_breaker.CircuitBreaker] = None,
+    control_file: str | None = None,
+    control_poll: float = 0.0,
+    abort_after_connect_timeouts: int = 0,
+    verbose: bool = False,
+    show_time: bool = False,
+    verbose_errors: bool = False,
+    handle_signals: bool = False,
 ) -> int:
     """
     Scrape tournament details for IDs from input_path, write to output_path.
 
+    Shared by main() and the Lambda handler, so both get the same outputs, summary
+    JSON and exit codes.
+
     Args:
-        input_path: Path to tournament IDs file (one ID per line). Local or S3 URI.
+        input_path: Path to tournament IDs file (one ID per line). Local, S3 URI,
+            or - for stdin.
         output_path: Parquet output path (local or S3).
         rate_limit: Requests per second.
         max_retries: Retry passes for failed fetches.
@@ -842,13 +1016,30 @@ def run(
         quiet: Reduce log output.
         limit: Process only first N IDs (0 = all).
         output_sample_path: Optional path for JSON sample. Default: {output_path}_sample.json.
-        output_reports_base: Optional base for report, failures, time_control files.
-            Default: output_path base. Used for _report.json, _failures.json,
-            _time_control_unique_values.txt.
+        output_reports_base: Optional base for report, failures, time_control and
+            summary files. Default: output_path base. Used for _report.json,
+            _failures.json, _time_control_unique_values.txt, _summary.json.
         save_raw: If True, save raw HTML per tournament to raw/details/{chunk}/{id}.html.gz.
+        checkpoint_interval: Also save a checkpoint every this many seconds
+            (0 = disabled). Checkpoints include failed results.
+        checkpoint_keep: Number of rotated local checkpoints to keep.
+        summary_file: Path for the summary JSON. Default: {reports base}_summary.json.
+        resume: Skip tournaments that already succeeded in the output or the newest
+            checkpoint, and keep their rows in the output.
+        breaker: Circuit breaker around the fetches (None = no breaker).
+        control_file: Settings file re-read on SIGHUP (see live_config).
+        control_poll: Instead of waiting for SIGHUP, re-read control_file (local
+            or S3) every this many seconds if it changed (0 = SIGHUP only).
+        abort_after_connect_timeouts: Raise RuntimeError after this many connect
+            timeouts in a row (0 = never), so a Step Function can retry the chunk
+            from a fresh Lambda.
+        verbose: Print one line per tournament instead of the progress bar.
+        show_time: Log timing info for each tournament.
+        verbose_errors: Log failed HTTP attempts and a retry analysis at the end.
+        handle_signals: Save partial results on SIGINT/SIGTERM and exit.
 
     Returns:
-        0 on success, 1 on failure.
+        EXIT_SUCCESS, EXIT_PARTIAL or EXIT_FATAL (see run_summary).
     """
     if quiet:
         logging.getLogger().setLevel(logging.WARNING)
@@ -863,20 +1054,54 @@ def run(
         output_sample_path if output_sample_path is not None else base + "_sample.json"
     )
     reports_base = output_reports_base if output_reports_base is not None else base
+    summary_file = summary_file or summary_path(reports_base)
+    outputs = {
+        "parquet": parquet_path,
+        "json_sample": json_path,
+        "report": reports_base + "_report.json",
+        "failures": reports_base + "_failures.json",
+    }
+
+    def _fatal(message: str) -> int:
+        logger.error(message)
+        write_summary(
+            build_summary("tournament_details", EXIT_FATAL, error=message),
+            summary_file,
+        )
+        return EXIT_FATAL
 
     try:
-        tournament_ids = _read_ids_from_path(input_path)
+        tournament_ids = _read_ids_from_path(input_path, limit)
     except Exception as e:
-        logger.error("Error reading IDs from %s: %s", input_path, e)
-        return 1
+        return _fatal(f"Error reading IDs from {input_path}: {e}")
 
     if not tournament_ids:
-        logger.error("No tournament IDs found in %s", input_path)
-        return 1
+        return _fatal(f"No tournament IDs found in {input_path}")
 
     if limit > 0:
-        tournament_ids = tournament_ids[:limit]
-        logger.info("Limited to first %d tournaments", limit)
+        logger.info("Limited to first %d tournaments", len(tournament_ids))
+
+    resumed = None
+    if resume:
+        try:
+            resumed = load_resume_rows(parquet_path, checkpoint_keep)
+        except Exception as e:
+            return _fatal(f"Error reading earlier output for --resume: {e}")
+        done = set(resumed["tournament_id"]) if len(resumed) else set()
+        remaining = [tid for tid in tournament_ids if tid not in done]
+        logger.info(
+            "Resuming: %d of %d tournaments already succeeded, %d to fetch",
+            len(tournament_ids) - len(remaining),
+            len(tournament_ids),
+            len(remaining),
+        )
+        if not remaining:
+            save_results_parquet([], parquet_path, resumed)
+            summary = build_summary("tournament_details", EXIT_SUCCESS, outputs=outputs)
+            summary["resumed"] = len(resumed)
+            write_summary(summary, summary_file)
+            return EXIT_SUCCESS
+        tournament_ids = remaining
 
     logger.info(
         "Processing %d tournaments from %s -> %s",
@@ -884,14 +1109,27 @@ def run(
         input_path,
         parquet_path,
     )
+    logger.info(
+        f"Settings: {rate_limit:.2f} req/s initial rate, checkpoint every {checkpoint}"
+    )
 
+    # Create HTTP session with connection reuse disabled
     session = requests.Session()
     adapter = requests.adapters.HTTPAdapter(
         pool_connections=1, pool_maxsize=1, max_retries=0
     )
     session.mount("http://", adapter)
     session.mount("https://", adapter)
+
     rate_limiter = RateLimiter(rate_limit)
+    control_poller: Optional[live_config.ControlFilePoller] = None
+    if control_file and control_poll > 0:
+        control_poller = live_config.ControlFilePoller(
+            control_file, rate_limiter, control_poll
+        )
+    elif control_file:
+        live_config.install(control_file, rate_limiter)
+    circuit_gave_up = False
 
     raw_base: Optional[str] = (
         _raw_base_from_output_path(output_path) if save_raw else None
@@ -901,11 +1139,40 @@ def run(
     all_results: List[Dict] = []
     success_count = 0
     error_count = 0
+    # Total number of tournaments that have been retried at least once
     total_retries = 0
+    attempt_log: List[Dict] = []  # Shared across all fetches (--verbose-errors)
+    attempt_counts: List[Tuple[str, int]] = []  # (tid, n) in order
+    consecutive_connect_timeouts = 0
+
+    def _save_outputs() -> None:
+        # All results as Parquet (after rows kept by --resume), a random sample of
+        # 100 successful results as JSON, the report and the failures
+        save_results_parquet(all_results, parquet_path, resumed)
+        save_results_json_sample(all_results, json_path, sample_size=100)
+        if success_count > 0:
+            build_and_save_report(all_results, parquet_path, report_base=reports_base)
+        save_failures_json(all_results, reports_base)
+
+    def _graceful_shutdown(signum, frame):
+        logger.warning("\nReceived interrupt, initiating graceful shutdown...")
+        if all_results:
+            try:
+                _save_outputs()
+                logger.info("Saved %d results to %s", len(all_results), parquet_path)
+            except Exception as e:
+                logger.error("Error saving partial results: %s", e)
+        sys.exit(130 if signum == 2 else 0)
+
+    if handle_signals:
+        signal.signal(signal.SIGINT, _graceful_shutdown)
+        signal.signal(signal.SIGTERM, _graceful_shutdown)
+
     current_tournaments = tournament_ids
 
+    # Progress bar (unless quiet or verbose)
     pbar = None
-    if not quiet:
+    if not quiet and not verbose:
         pbar = tqdm(
             total=len(tournament_ids),
             desc="Processing",
@@ -914,54 +1181,70 @@ def run(
         )
 
     start_time = time.time()
-    consecutive_connect_timeouts = 0
-    CONSECUTIVE_CONNECT_TIMEOUT_THRESHOLD = 2
+    checkpoints = CheckpointSchedule(checkpoint, checkpoint_interval)
 
     for pass_num in range(max_retries + 1):
         if not current_tournaments:
             break
+
         if pass_num > 0:
-            delay = 3 * (2 ** (pass_num - 1))
+            delay = 3 * (2 ** (pass_num - 1))  # Exponential backoff: 3s, 6s, 12s
             logger.info(
-                "Retry pass %d: waiting %s before retrying %d tournaments",
-                pass_num,
-                format_duration(delay),
-                len(current_tournaments),
+                f"Retry pass {pass_num}: waiting {format_duration(delay)} before retrying {len(current_tournaments)} tournaments"
             )
             time.sleep(delay)
+            # Count tournaments being retried in this pass
             total_retries += len(current_tournaments)
             consecutive_connect_timeouts = 0  # reset on new pass
 
         pass_failed = []
-        for tournament_id in current_tournaments:
+        # Tournaments that fail while the circuit breaker is open are appended
+        # and fetched again in this pass
+        pass_queue = list(current_tournaments)
+
+        for queue_index, tournament_id in enumerate(pass_queue):
+            if breaker:
+                try:
+                    breaker.before_request()
+                except circuit_breaker.CircuitOpenError as e:
+                    logger.error(f"{e}; stopping")
+                    circuit_gave_up = True
+                    break
+            if control_poller:
+                control_poller.poll()
             rate_limiter.wait()
-            details, error, _, raw_content = fetch_tournament_details(
-                tournament_id, session, return_raw=save_raw
-            )
 
-            result = {"tournament_id": tournament_id}
-            if details is None:
-                logger.warning(
-                    "Details fetch failed: tournament_id=%s error=%s",
+            with tracing.span(
+                "fetch_details", tournament_id=tournament_id, retry_pass=pass_num
+            ) as fetch_span:
+                details, error, num_attempts, raw_content = fetch_tournament_details(
                     tournament_id,
-                    error or "unknown",
+                    session,
+                    return_raw=raw_base is not None,
+                    _attempt_log=attempt_log if verbose_errors else None,
                 )
-                error_count += 1
-                result["success"] = False
-                result["error"] = error or "fetch failed"
-                error_lower = (error or "").lower()
+                tracing.set_attributes(fetch_span, attempts=num_attempts, error=error)
+            if verbose_errors:
+                attempt_counts.append((tournament_id, num_attempts))
+
+            result = {"tournament_id": tournament_id}
+
+            if details is None:
+                # Check if it's a rate limit/network error
+                error_lower = error.lower() if error else ""
                 is_connect_timeout = (
                     "connect" in error_lower and "timeout" in error_lower
                 ) or "connecttimeout" in error_lower
                 if is_connect_timeout:
                     consecutive_connect_timeouts += 1
                     if (
-                        consecutive_connect_timeouts
-                        >= CONSECUTIVE_CONNECT_TIMEOUT_THRESHOLD
+                        abort_after_connect_timeouts
+                        and consecutive_connect_timeouts >= abort_after_connect_timeouts
                     ):
                         raise RuntimeError(
-                            f"Details fetch: {CONSECUTIVE_CONNECT_TIMEOUT_THRESHOLD} consecutive "
-                            "connect timeouts; aborting chunk so Step Function can retry with fresh Lambda"
+                            f"Details fetch: {consecutive_connect_timeouts} "
+                            "consecutive connect timeouts; aborting chunk so Step "
+                            "Function can retry with fresh Lambda"
                         ) from None
                 else:
                     consecutive_connect_timeouts = 0
@@ -973,27 +1256,159 @@ def run(
                     "remote end closed",
                     "broken pipe",
                 ]
-                is_network_error = any(p in error_lower for p in network_error_patterns)
-                if (
-                    error
-                    and (is_network_error or "timeout" in error_lower)
-                    and pass_num < max_retries
-                ):
-                    pass_failed.append(tournament_id)
+                is_network_error = any(
+                    pattern in error_lower for pattern in network_error_patterns
+                )
+                retryable = bool(error) and (
+                    is_network_error or "timeout" in error_lower
+                )
+                if breaker and breaker.record(not retryable) and retryable:
+                    # Failed during an outage: try again once the breaker closes
+                    pass_queue.append(tournament_id)
+                    continue
+
+                error_count += 1
+                result["success"] = False
+                result["error"] = error or "fetch failed"
+
+                # Retry on network errors and timeouts
+                if retryable:
+                    if pass_num < max_retries:
+                        pass_failed.append(tournament_id)
             else:
+                if breaker:
+                    breaker.record(True)
                 consecutive_connect_timeouts = 0
                 success_count += 1
                 result["success"] = True
                 result["details"] = details
                 if raw_base and raw_content:
                     raw_accumulator.append((tournament_id, raw_content))
-                if checkpoint > 0 and success_count % checkpoint == 0:
-                    save_checkpoint(parquet_path, all_results, base + ".checkpoint")
-
             all_results.append(result)
-            if pbar:
-                pbar.update(1)
-                pbar.set_postfix({"✓": success_count, "✗": error_count})
+
+            # Checkpoint (failed results included)
+            if checkpoints.due(success_count):
+                logger.info(
+                    f"Saving checkpoint at {success_count} successful, "
+                    f"{error_count} failed..."
+                )
+                save_checkpoint(
+                    parquet_path,
+                    all_results,
+                    parquet_path + ".checkpoint",
+                    checkpoint_keep,
+                    prior=resumed,
+                )
+                checkpoints.mark(success_count)
+
+            total_processed = success_count + error_count
+            elapsed = time.time() - start_time
+
+            if total_processed > 0:
+                avg_time = elapsed / total_processed
+                remaining = len(tournament_ids) - total_processed
+                est_remaining = avg_time * remaining
+            else:
+                est_remaining = 0
+
+            # Verbose stdout mode
+            if verbose:
+                rate = rate_limiter.get_rate()
+                actual_rate = rate_limiter.get_actual_rate()
+
+                if result["success"]:
+                    name = result.get("details", {}).get("name", "unknown")
+                    retry_info = f" [Retry pass {pass_num + 1}]" if pass_num > 0 else ""
+                    http_retries = (
+                        f" [{num_attempts} HTTP attempts]" if num_attempts > 1 else ""
+                    )
+                    print(
+                        f"[{total_processed}/{len(tournament_ids)}] ✓ {tournament_id}: {name}{retry_info}{http_retries} | "
+                        f"Rate: {rate:.2f}/s (actual: {actual_rate:.2f}/s) | "
+                        f"Elapsed: {format_duration(elapsed)} | Est: {format_duration(est_remaining)} | "
+                        f"Success: {success_count} | Errors: {error_count} | Retries: {total_retries}",
+                        file=sys.stderr,
+                    )
+                else:
+                    error_msg = result.get("error", "unknown")
+                    will_retry = tournament_id in pass_failed
+                    retry_info = f" [Retry pass {pass_num + 1}]" if pass_num > 0 else ""
+                    http_retries = (
+                        f" [{num_attempts} HTTP attempts]" if num_attempts > 1 else ""
+                    )
+                    retry_status = " [WILL RETRY]" if will_retry else " [FINAL FAILURE]"
+
+                    print(
+                        f"[{total_processed}/{len(tournament_ids)}] ✗ {tournament_id}: {error_msg}{retry_info}{http_retries}{retry_status} | "
+                        f"Rate: {rate:.2f}/s (actual: {actual_rate:.2f}/s) | "
+                        f"Elapsed: {format_duration(elapsed)} | Est: {format_duration(est_remaining)} | "
+                        f"Success: {success_count} | Errors: {error_count} | Retries: {total_retries}",
+                        file=sys.stderr,
+                    )
+            else:
+                # Progress bar mode
+                # Build postfix with retry info
+                postfix_dict = {
+                    "✓": success_count,
+                    "✗": error_count,
+                    "rate": f"{rate_limiter.get_actual_rate():.2f}/s",
+                }
+
+                # Add retry information
+                if total_retries > 0 or pass_num > 0:
+                    postfix_dict["retries"] = total_retries
+                if pass_num > 0:
+                    postfix_dict["pass"] = f"{pass_num + 1}/{max_retries + 1}"
+                if len(pass_failed) > 0:
+                    postfix_dict["pending"] = len(pass_failed)
+
+                postfix_dict["est"] = (
+                    format_duration(est_remaining) if est_remaining > 0 else "?"
+                )
+
+                # Update progress bar
+                if pbar:
+                    pbar.update(1)
+                    pbar.set_postfix(postfix_dict)
+
+                if show_time:
+                    rate_info = rate_limiter.describe()
+                    if result["success"]:
+                        name = result.get("details", {}).get("name", "unknown")
+                        logger.info(
+                            f"[{total_processed}/{len(tournament_ids)}] ✓ {tournament_id}: {name} | "
+                            f"{rate_info} | Est: {format_duration(est_remaining)}"
+                        )
+                    else:
+                        logger.info(
+                            f"[{total_processed}/{len(tournament_ids)}] ✗ {tournament_id}: {result.get('error', 'unknown')} | "
+                            f"{rate_info}"
+                        )
+
+            # Periodic progress update (only in non-verbose mode or at milestones)
+            if not verbose and (
+                total_processed % 50 == 0 or total_processed == len(tournament_ids)
+            ):
+                avg_rate = total_processed / elapsed if elapsed > 0 else 0
+                logger.info(
+                    f"Progress: {total_processed}/{len(tournament_ids)} "
+                    f"({success_count}✓ {error_count}✗) | "
+                    f"{rate_limiter.describe()} | Average: {avg_rate:.2f}/s | "
+                    f"Elapsed: {format_duration(elapsed)} | Est: {format_duration(est_remaining)}"
+                )
+
+        if circuit_gave_up:
+            # Record the rest as failed so retry_failed.py picks them up
+            for tournament_id in dict.fromkeys(pass_queue[queue_index:]):
+                error_count += 1
+                all_results.append(
+                    {
+                        "tournament_id": tournament_id,
+                        "success": False,
+                        "error": "circuit open: FIDE unavailable",
+                    }
+                )
+            break
 
         current_tournaments = pass_failed
 
@@ -1011,54 +1426,147 @@ def run(
             raw_path,
         )
 
-    save_results_parquet(all_results, parquet_path)
-    save_results_json_sample(all_results, json_path, sample_size=100)
-    if success_count > 0:
-        build_and_save_report(all_results, parquet_path, report_base=reports_base)
-    save_failures_json(all_results, reports_base)
+    _save_outputs()
 
-    elapsed = time.time() - start_time
+    total_time = time.time() - start_time
+    final_rate = (success_count + error_count) / total_time if total_time > 0 else 0
+
+    final = latest_results(all_results, "tournament_id")
+    logger.info("\nFinal Summary:")
+    logger.info(f"  Total: {len(tournament_ids)}")
     logger.info(
-        "Done: %d success, %d errors in %s",
-        success_count,
-        error_count,
-        format_duration(elapsed),
+        f"  Success: {success_count} ({100.0 * success_count / len(tournament_ids):.1f}%)"
+    )
+    logger.info(f"  Errors: {error_count}")
+    failure_groups = group_failures(final, "tournament_id")
+    if failure_groups:
+        logger.info("  Failed tournaments by class:")
+        for line in format_failure_groups(failure_groups, use_color(sys.stderr)):
+            logger.info(line)
+    if total_retries > 0:
+        logger.info(f"  Retries: {total_retries}")
+    logger.info(f"  Time: {format_duration(total_time)}")
+    logger.info(f"  Average rate: {final_rate:.2f} tournaments/sec")
+    logger.info(f"  Recent {rate_limiter.describe()}")
+    logger.info(f"  Parquet output: {parquet_path}")
+    logger.info(f"  JSON sample: {json_path}")
+
+    n_final_success = sum(1 for r in final if r.get("success"))
+    n_resumed = len(resumed) if resumed is not None else 0
+    exit_code = exit_code_for(
+        n_final_success + n_resumed, len(final) - n_final_success
     )
-    return 0
+    summary = build_summary(
+        "tournament_details", exit_code, final, outputs=outputs, key="tournament_id"
+    )
+    summary["rate"] = rate_limiter.stats()
+    if resumed is not None:
+        summary["resumed"] = n_resumed
+    write_summary(summary, summary_file)
+    logger.info(f"  Summary: {summary_file}")
+
+    # Verbose error analysis (attempt distribution, retry tournaments, error breakdown)
+    if verbose_errors and attempt_counts:
+        dist = Counter(n for _, n in attempt_counts)
+        retried = [(tid, n) for tid, n in attempt_counts if n > 1]
+        error_counts = Counter(e.get("error", "unknown") for e in attempt_log)
+        logger.info("\nVerbose Error Analysis:")
+        logger.info("  Attempt distribution: %s", dict(sorted(dist.items())))
+        if retried:
+            tids = [tid for tid, _ in retried]
+            max_show = 30
+            if len(tids) <= max_show:
+                logger.info("  Tournaments needing retries (in order): %s", tids)
+            else:
+                logger.info(
+                    "  Tournaments needing retries (first %d): %s ... and %d more",
+                    max_show,
+                    tids[:max_show],
+                    len(tids) - max_show,
+                )
+        if error_counts:
+            logger.info("  Error breakdown: %s", dict(error_counts))
+
+    return exit_code
+
+
+def checkpoint_parquet_path(checkpoint_path: str) -> str:
+    """Parquet checkpoint path save_checkpoint() writes for checkpoint_path."""
+    # Convert .json checkpoint path to .parquet
+    if checkpoint_path.endswith(".checkpoint"):
+        return checkpoint_path.replace(".checkpoint", ".parquet.checkpoint")
+    elif checkpoint_path.endswith(".json.checkpoint"):
+        return checkpoint_path.replace(".json.checkpoint", ".parquet.checkpoint")
+    return checkpoint_path + ".parquet"
 
 
 def save_checkpoint(
-    output_path: str, results: List[Dict], checkpoint_path: Optional[str] = None
+    output_path: str,
+    results: List[Dict],
+    checkpoint_path: Optional[str] = None,
+    keep: int = DEFAULT_KEEP,
+    prior: Optional[pd.DataFrame] = None,
 ):
-    """Save checkpoint file as Parquet."""
+    """
+    Save checkpoint file as Parquet. Local checkpoints are rotated and gzipped
+    ({checkpoint}.1.gz newest ... {checkpoint}.{keep}.gz); S3 ones are overwritten.
+    """
     if not output_path or not checkpoint_path:
         return
 
     try:
-        # Convert .json checkpoint path to .parquet
-        if checkpoint_path.endswith(".checkpoint"):
-            parquet_checkpoint = checkpoint_path.replace(
-                ".checkpoint", ".parquet.checkpoint"
-            )
-        elif checkpoint_path.endswith(".json.checkpoint"):
-            parquet_checkpoint = checkpoint_path.replace(
-                ".json.checkpoint", ".parquet.checkpoint"
-            )
+        parquet_checkpoint = checkpoint_parquet_path(checkpoint_path)
+        if _is_s3(parquet_checkpoint):
+            save_results_parquet(results, parquet_checkpoint, prior)
         else:
-            parquet_checkpoint = checkpoint_path + ".parquet"
-
-        save_results_parquet(results, parquet_checkpoint)
+            content = results_to_parquet_bytes(results, prior)
+            path = disk_guard.guarded_write(
+                lambda: write_rotated(parquet_checkpoint, content, keep),
+                parquet_checkpoint,
+            )
+            logger.info(f"Saved checkpoint ({len(results)} records) to {path}")
     except Exception as e:
         logger.error(f"Checkpoint save failed: {e}")
 
 
+def load_resume_rows(parquet_path: str, keep: int = DEFAULT_KEEP) -> pd.DataFrame:
+    """
+    Success rows left by an earlier run into parquet_path (local or S3): the final
+    output if it exists, plus the newest readable checkpoint (S3 keeps only one).
+    One row per tournament_id, empty if there is neither.
+    """
+    frames = []
+    checkpoint_path = checkpoint_parquet_path(parquet_path + ".checkpoint")
+    if _is_s3(parquet_path):
+        from s3_io import read_versioned
+
+        contents = [read_versioned(p)[0] for p in (parquet_path, checkpoint_path)]
+    else:
+        if os.path.exists(parquet_path):
+            frames.append(pd.read_parquet(parquet_path))
+        contents = [load_latest(checkpoint_path, keep)]
+    for content in contents:
+        if content is not None:
+            frames.append(pd.read_parquet(io.BytesIO(content)))
+    frames = [f for f in frames if len(f)]
+    if not frames:
+        return pd.DataFrame()
+    df = pd.concat(frames, ignore_index=True)
+    df = df[df["success"].fillna(False).astype(bool)]
+    df = df.assign(tournament_id=df["tournament_id"].astype(str))
+    return df.drop_duplicates("tournament_id").reset_index(drop=True)
+
+
 def main():
     parser = argparse.ArgumentParser(
         description="Scrape FIDE tournament details",
         formatter_class=argparse.RawDescriptionHelpFormatter,
     )
     parser.add_argument(
-        "--input", type=str, default="", help="Path to tournament IDs file"
+        "--input",
+        type=str,
+        default="",
+        help="Path to tournament IDs file, or - for stdin (needs --output)",
     )
     parser.add_argument("--year", type=int, default=0, help="Year to process")
     parser.add_argument("--month", type=int, default=0, help="Month to process")
@@ -1103,6 +1611,72 @@ def main():
         default=100,
         help="Save every N tournaments (default: 100)",
     )
+    parser.add_argument(
+        "--checkpoint-interval",
+        type=parse_duration,
+        default=0.0,
+        metavar="DURATION",
+        help="Also save a checkpoint every DURATION (e.g. 2m, 90s), even when "
+        "requests are failing (default: off)",
+    )
+    parser.add_argument(
+        "--checkpoint-keep",
+        type=int,
+        default=DEFAULT_KEEP,
+        metavar="K",
+        help="Keep the last K checkpoints, rotated and gzipped "
+        f"(default: {DEFAULT_KEEP})",
+    )
+    parser.add_argument(
+        "--alert-command",
+        default=None,
+        help="Command run with an alert message as its last argument when the "
+        "disk fills up (scraping pauses) and when writes resume",
+    )
+    parser.add_argument(
+        "--min-free-mb",
+        type=int,
+        default=disk_guard.DEFAULT_MIN_FREE_BYTES // (1024 * 1024),
+        help="After a disk-full error, resume once this many MiB are free "
+        "(default: 512)",
+    )
+    parser.add_argument(
+        "--user-agents",
+        default=None,
+        metavar="FILE",
+        help="JSON list of browser identities (User-Agent plus matching headers) "
+        "to pick from instead of the built-in pool",
+    )
+    parser.add_argument(
+        "--worker-id",
+        type=int,
+        default=0,
+        help="Worker id; selects this process's identity from the pool, so "
+        "parallel workers present different user agents (default: 0)",
+    )
+    http_timeouts.add_arguments(parser)
+    circuit_breaker.add_arguments(parser)
+    live_config.add_arguments(parser)
+    tracing.add_arguments(parser)
+    parser.add_argument(
+        "--anomaly-pause",
+        type=parse_duration,
+        default=anomaly.DEFAULT_PAUSE_SECONDS,
+        metavar="DURATION",
+        help="After a run of empty/shrunken responses (e.g. stub pages), alert, "
+        "save the last response and pause this long (default: 10m)",
+    )
+    parser.add_argument(
+        "--anomaly-dir",
+        default=str(anomaly.DEFAULT_SNAPSHOT_DIR),
+        help="Directory for anomalous response snapshots "
+        f"(default: {anomaly.DEFAULT_SNAPSHOT_DIR})",
+    )
+    parser.add_argument(
+        "--no-anomaly-check",
+        action="store_true",
+        help="Disable response size/structure anomaly detection",
+    )
     parser.add_argument(
         "--show-time", action="store_true", help="Show timing info for each tournament"
     )
@@ -1122,13 +1696,50 @@ def main():
         action="store_true",
         help="Overwrite existing output if it exists",
     )
+    parser.add_argument(
+        "--resume",
+        action="store_true",
+        help="Skip tournaments that already succeeded in the output or newest "
+        "checkpoint, fetch the rest and keep both in the output",
+    )
     parser.add_argument(
         "--verbose-errors",
         action="store_true",
         help="Log failed HTTP attempt details and print retry analysis at end",
     )
+    parser.add_argument(
+        "--summary",
+        default=None,
+        help="Path for the final summary JSON (default: {output base}_summary.json)",
+    )
 
     args = parser.parse_args()
+    redact.install()
+
+    if args.checkpoint_keep < 1:
+        logger.error("Error: --checkpoint-keep must be >= 1")
+        sys.exit(EXIT_FATAL)
+    disk_guard.configure(
+        alert_command=args.alert_command, min_free_bytes=args.min_free_mb * 1024 * 1024
+    )
+    try:
+        user_agents.configure(
+            user_agents.load_identities(args.user_agents) if args.user_agents else None,
+            worker=args.worker_id,
+        )
+    except (OSError, ValueError) as e:
+        logger.error("Error loading --user-agents: %s", e)
+        sys.exit(EXIT_FATAL)
+    try:
+        http_timeouts.configure_from_args(args)
+    except ValueError as e:
+        logger.error("Error: %s", e)
+        sys.exit(EXIT_FATAL)
+    tracing.configure_from_args(args, "tournament_details")
+    if not args.no_anomaly_check:
+        anomaly.configure(
+            "details", pause_seconds=args.anomaly_pause, snapshot_dir=args.anomaly_dir
+        )
 
     # Determine input path
     if args.input:
@@ -1136,7 +1747,7 @@ def main():
     elif args.run_type and (args.run_name or (args.year > 0 and args.month > 0)):
         if args.month < 1 or args.month > 12:
             logger.error("Error: month must be 1-12")
-            sys.exit(1)
+            sys.exit(EXIT_FATAL)
         run_name = args.run_name or f"{args.year}-{args.month:02d}"
         from s3_io import build_local_path_for_run
 
@@ -1148,22 +1759,22 @@ def main():
     elif args.year > 0 and args.month > 0:
         if args.month < 1 or args.month > 12:
             logger.error("Error: month must be 1-12")
-            sys.exit(1)
+            sys.exit(EXIT_FATAL)
         input_path = os.path.join(
             args.data_dir, "tournament_ids", f"{args.year}_{args.month:02d}"
         )
     else:
         logger.error("Error: specify --input or --year and --month (or --run-type)")
-        sys.exit(1)
+        sys.exit(EXIT_FATAL)
 
     from s3_io import output_exists as _path_exists
 
-    if not _path_exists(input_path):
+    if input_path != STDIN_INPUT and not _path_exists(input_path):
         logger.error(
             "Tournament IDs file not found: %s. Run get_tournaments first.",
             input_path,
         )
-        sys.exit(1)
+        sys.exit(EXIT_FATAL)
 
     # Determine output paths
     parquet_path = None
@@ -1220,319 +1831,39 @@ def main():
         json_path = base_path + "_sample.json"
         report_base = None
 
-    if not args.override and os.path.exists(parquet_path):
+    if parquet_path is None:
+        logger.error("Error: --input needs --output (or --year and --month)")
+        sys.exit(EXIT_FATAL)
+
+    if not args.override and not args.resume and os.path.exists(parquet_path):
         logger.info(
             "Output %s already exists. Use --override to replace.", parquet_path
         )
         sys.exit(0)
 
-    # Read tournament IDs
-    try:
-        tournament_ids = read_tournament_ids(input_path)
-    except Exception as e:
-        logger.error(f"Error reading IDs: {e}")
-        sys.exit(1)
-
-    if not tournament_ids:
-        logger.error("No tournament IDs found")
-        sys.exit(1)
-
-    if args.limit > 0:
-        tournament_ids = tournament_ids[: args.limit]
-        logger.info(f"Limited to first {len(tournament_ids)} tournaments")
-
-    logger.info(f"Processing {len(tournament_ids)} tournaments")
-    logger.info(
-        f"Settings: {args.rate_limit:.2f} req/s initial rate, checkpoint every {args.checkpoint}"
-    )
-
-    start_time = time.time()
-
-    # Create HTTP session with connection reuse disabled
-    session = requests.Session()
-    adapter = requests.adapters.HTTPAdapter(
-        pool_connections=1, pool_maxsize=1, max_retries=0
-    )
-    session.mount("http://", adapter)
-    session.mount("https://", adapter)
-
-    rate_limiter = RateLimiter(args.rate_limit)
-
-    all_results = []
-    success_count = 0
-    error_count = 0
-
-    def _graceful_shutdown(signum, frame):
-        logger.warning("\nReceived interrupt, initiating graceful shutdown...")
-        if all_results and parquet_path:
-            try:
-                save_results_parquet(all_results, parquet_path)
-                if json_path:
-                    save_results_json_sample(all_results, json_path, sample_size=100)
-                build_and_save_report(
-                    all_results, parquet_path, report_base=report_base
-                )
-                save_failures_json(
-                    all_results,
-                    (
-                        report_base
-                        if report_base
-                        else parquet_path.replace(".parquet", "")
-                    ),
-                )
-                logger.info("Saved %d results to %s", len(all_results), parquet_path)
-            except Exception as e:
-                logger.error("Error saving partial results: %s", e)
-        sys.exit(130 if signum == 2 else 0)
-
-    signal.signal(signal.SIGINT, _graceful_shutdown)
-    signal.signal(signal.SIGTERM, _graceful_shutdown)
-    total_retries = (
-        0  # Total number of tournaments that have been retried at least once
-    )
-    attempt_log: List[Dict] = (
-        [] if args.verbose_errors else []
-    )  # Shared across all fetches
-    attempt_counts: List[Tuple[str, int]] = (
-        [] if args.verbose_errors else []
-    )  # (tid, n) in order
-
-    current_tournaments = tournament_ids
-
-    # Progress bar (only if not verbose)
-    pbar = None
-    if not args.verbose:
-        pbar = tqdm(
-            total=len(tournament_ids),
-            desc="Processing",
-            unit="tournament",
-            bar_format="{l_bar}{bar}| {n_fmt}/{total_fmt} [{elapsed}<{remaining}, {rate_fmt}]",
-        )
-
-    for pass_num in range(args.max_retries + 1):
-        if not current_tournaments:
-            break
-
-        if pass_num > 0:
-            delay = 3 * (2 ** (pass_num - 1))  # Exponential backoff: 3s, 6s, 12s
-            logger.info(
-                f"Retry pass {pass_num}: waiting {format_duration(delay)} before retrying {len(current_tournaments)} tournaments"
-            )
-            time.sleep(delay)
-            # Count tournaments being retried in this pass
-            total_retries += len(current_tournaments)
-
-        pass_failed = []
-
-        for tournament_id in current_tournaments:
-            rate_limiter.wait()
-
-            details, error, num_attempts, _ = fetch_tournament_details(
-                tournament_id,
-                session,
-                _attempt_log=attempt_log if args.verbose_errors else None,
-            )
-            if args.verbose_errors:
-                attempt_counts.append((tournament_id, num_attempts))
-
-            result = {"tournament_id": tournament_id}
-
-            if details is None:
-                error_count += 1
-                result["success"] = False
-                result["error"] = error or "fetch failed"
-
-                # Check if it's a rate limit/network error
-                error_lower = error.lower() if error else ""
-                network_error_patterns = [
-                    "eof",
-                    "connection reset",
-                    "connection aborted",
-                    "remotedisconnected",
-                    "remote end closed",
-                    "broken pipe",
-                ]
-                is_network_error = any(
-                    pattern in error_lower for pattern in network_error_patterns
-                )
-
-                # Retry on network errors and timeouts
-                if error and (is_network_error or "timeout" in error_lower):
-                    if pass_num < args.max_retries:
-                        pass_failed.append(tournament_id)
-            else:
-                success_count += 1
-                result["success"] = True
-                result["details"] = details
-
-                # Checkpoint
-                if args.checkpoint > 0 and success_count % args.checkpoint == 0:
-                    checkpoint_path = (
-                        parquet_path + ".checkpoint" if parquet_path else None
-                    )
-                    logger.info(f"Saving checkpoint at {success_count} successful...")
-                    save_checkpoint(parquet_path, all_results, checkpoint_path)
-            all_results.append(result)
-
-            total_processed = success_count + error_count
-            elapsed = time.time() - start_time
-
-            if total_processed > 0:
-                avg_time = elapsed / total_processed
-                remaining = len(tournament_ids) - total_processed
-                est_remaining = avg_time * remaining
-            else:
-                est_remaining = 0
-
-            # Verbose stdout mode
-            if args.verbose:
-                rate = rate_limiter.get_rate()
-                actual_rate = total_processed / elapsed if elapsed > 0 else 0
-
-                if result["success"]:
-                    name = result.get("details", {}).get("name", "unknown")
-                    retry_info = f" [Retry pass {pass_num + 1}]" if pass_num > 0 else ""
-                    http_retries = (
-                        f" [{num_attempts} HTTP attempts]" if num_attempts > 1 else ""
-                    )
-                    print(
-                        f"[{total_processed}/{len(tournament_ids)}] ✓ {tournament_id}: {name}{retry_info}{http_retries} | "
-                        f"Rate: {rate:.2f}/s (actual: {actual_rate:.2f}/s) | "
-                        f"Elapsed: {format_duration(elapsed)} | Est: {format_duration(est_remaining)} | "
-                        f"Success: {success_count} | Errors: {error_count} | Retries: {total_retries}"
-                    )
-                else:
-                    error_msg = result.get("error", "unknown")
-                    will_retry = tournament_id in pass_failed
-                    retry_info = f" [Retry pass {pass_num + 1}]" if pass_num > 0 else ""
-                    http_retries = (
-                        f" [{num_attempts} HTTP attempts]" if num_attempts > 1 else ""
-                    )
-                    retry_status = " [WILL RETRY]" if will_retry else " [FINAL FAILURE]"
-
-                    print(
-                        f"[{total_processed}/{len(tournament_ids)}] ✗ {tournament_id}: {error_msg}{retry_info}{http_retries}{retry_status} | "
-                        f"Rate: {rate:.2f}/s (actual: {actual_rate:.2f}/s) | "
-                        f"Elapsed: {format_duration(elapsed)} | Est: {format_duration(est_remaining)} | "
-                        f"Success: {success_count} | Errors: {error_count} | Retries: {total_retries}"
-                    )
-            else:
-                # Progress bar mode
-                # Build postfix with retry info
-                postfix_dict = {
-                    "✓": success_count,
-                    "✗": error_count,
-                    "rate": f"{rate_limiter.get_rate():.2f}/s",
-                }
-
-                # Add retry information
-                if total_retries > 0 or pass_num > 0:
-                    postfix_dict["retries"] = total_retries
-                if pass_num > 0:
-                    postfix_dict["pass"] = f"{pass_num + 1}/{args.max_retries + 1}"
-                if len(pass_failed) > 0:
-                    postfix_dict["pending"] = len(pass_failed)
-
-                postfix_dict["est"] = (
-                    format_duration(est_remaining) if est_remaining > 0 else "?"
-                )
-
-                # Update progress bar
-                if pbar:
-                    pbar.update(1)
-                    pbar.set_postfix(postfix_dict)
-
-                if args.show_time:
-                    rate = rate_limiter.get_rate()
-                    if result["success"]:
-                        name = result.get("details", {}).get("name", "unknown")
-                        logger.info(
-                            f"[{total_processed}/{len(tournament_ids)}] ✓ {tournament_id}: {name} | "
-                            f"Rate: {rate:.2f}/s | Est: {format_duration(est_remaining)}"
-                        )
-                    else:
-                        logger.info(
-                            f"[{total_processed}/{len(tournament_ids)}] ✗ {tournament_id}: {result.get('error', 'unknown')} | "
-                            f"Rate: {rate:.2f}/s"
-                        )
-
-            # Periodic progress update (only in non-verbose mode or at milestones)
-            if not args.verbose and (
-                total_processed % 50 == 0 or total_processed == len(tournament_ids)
-            ):
-                actual_rate = total_processed / elapsed if elapsed > 0 else 0
-                target_rate = rate_limiter.get_rate()
-                logger.info(
-                    f"Progress: {total_processed}/{len(tournament_ids)} "
-                    f"({success_count}✓ {error_count}✗) | "
-                    f"Actual: {actual_rate:.2f}/s | Target: {target_rate:.2f}/s | "
-                    f"Elapsed: {format_duration(elapsed)} | Est: {format_duration(est_remaining)}"
-                )
-
-        current_tournaments = pass_failed
-
-    if pbar:
-        pbar.close()
-
-    # Save final results
-    if parquet_path:
-        # Save all results as Parquet
-        save_results_parquet(all_results, parquet_path)
-
-        # Save random sample of 100 successful results as JSON
-        if json_path:
-            save_results_json_sample(all_results, json_path, sample_size=100)
-
-        # Build and save report (distributions, nulls, time_control unique values)
-        build_and_save_report(all_results, parquet_path, report_base=report_base)
-        # Save failures for investigation
-        save_failures_json(
-            all_results,
-            report_base if report_base else parquet_path.replace(".parquet", ""),
+    sys.exit(
+        run(
+            input_path,
+            parquet_path,
+            rate_limit=args.rate_limit,
+            max_retries=args.max_retries,
+            checkpoint=args.checkpoint,
+            limit=args.limit,
+            output_sample_path=json_path,
+            output_reports_base=report_base,
+            save_raw=False,
+            checkpoint_interval=args.checkpoint_interval,
+            checkpoint_keep=args.checkpoint_keep,
+            summary_file=args.summary,
+            resume=args.resume,
+            breaker=circuit_breaker.from_args("details", args),
+            control_file=args.control_file,
+            verbose=args.verbose,
+            show_time=args.show_time,
+            verbose_errors=args.verbose_errors,
+            handle_signals=True,
         )
-    else:
-        # If no output path specified, dump to stdout as JSON (for backwards compatibility)
-        json.dump(all_results, sys.stdout, indent=2, ensure_ascii=False)
-
-    total_time = time.time() - start_time
-    final_rate = (success_count + error_count) / total_time if total_time > 0 else 0
-
-    logger.info("\nFinal Summary:")
-    logger.info(f"  Total: {len(tournament_ids)}")
-    logger.info(
-        f"  Success: {success_count} ({100.0 * success_count / len(tournament_ids):.1f}%)"
     )
-    logger.info(f"  Errors: {error_count}")
-    if total_retries > 0:
-        logger.info(f"  Retries: {total_retries}")
-    logger.info(f"  Time: {format_duration(total_time)}")
-    logger.info(f"  Average rate: {final_rate:.2f} tournaments/sec")
-    if parquet_path:
-        logger.info(f"  Parquet output: {parquet_path}")
-    if json_path:
-        logger.info(f"  JSON sample: {json_path}")
-
-    # Verbose error analysis (attempt distribution, retry tournaments, error breakdown)
-    if args.verbose_errors and attempt_counts:
-        dist = Counter(n for _, n in attempt_counts)
-        retried = [(tid, n) for tid, n in attempt_counts if n > 1]
-        error_counts = Counter(e.get("error", "unknown") for e in attempt_log)
-        logger.info("\nVerbose Error Analysis:")
-        logger.info("  Attempt distribution: %s", dict(sorted(dist.items())))
-        if retried:
-            tids = [tid for tid, _ in retried]
-            max_show = 30
-            if len(tids) <= max_show:
-                logger.info("  Tournaments needing retries (in order): %s", tids)
-            else:
-                logger.info(
-                    "  Tournaments needing retries (first %d): %s ... and %d more",
-                    max_show,
-                    tids[:max_show],
-                    len(tids) - max_show,
-                )
-        if error_counts:
-            logger.info("  Error breakdown: %s", dict(error_counts))
 
 
 if __name__ == "__main__":
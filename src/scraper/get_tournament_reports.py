@@ -21,15 +21,51 @@ import sys
 import tempfile
 import time
 from collections import Counter
+from contextlib import nullcontext
 from datetime import datetime
 from pathlib import Path
-from typing import Dict, List, Optional, Tuple
+from typing import Dict, Iterator, List, Optional, Tuple
 
 import pandas as pd
 import requests
 from bs4 import BeautifulSoup
 from tqdm import tqdm
 
+import anomaly
+import circuit_breaker
+import disk_guard
+import http_timeouts
+import live_config
+import redact
+import tracing
+import user_agents
+from checkpoints import (
+    DEFAULT_KEEP,
+    CheckpointSchedule,
+    parse_duration,
+    write_rotated,
+)
+from provenance import dataframe_to_parquet_bytes
+from rate_limiter import RateLimiter
+from report_links import (
+    PAIRING_PROGRAM,
+    SOURCE_REPORT_URL,
+    ReportLink,
+    report_url,
+)
+from run_summary import (
+    EXIT_FATAL,
+    build_summary,
+    exit_code_for,
+    format_failure_groups,
+    group_failures,
+    latest_results,
+    summary_path,
+    use_color,
+    write_summary,
+)
+from timestamps import fide_date, utc_now
+
 # Configure logging
 logging.basicConfig(
     level=logging.INFO,
@@ -38,22 +74,8 @@ logging.basicConfig(
 )
 logger = logging.getLogger(__name__)
 
-
-class RateLimiter:
-    """Enforces minimum spacing between requests."""
-
-    def __init__(self, requests_per_second: float):
-        self.min_interval = 1.0 / requests_per_second if requests_per_second > 0 else 0
-        self.last_request = 0.0
-
-    def wait(self):
-        if self.min_interval <= 0:
-            return
-        now = time.perf_counter()
-        elapsed = now - self.last_request
-        if elapsed < self.min_interval:
-            time.sleep(self.min_interval - elapsed)
-        self.last_request = time.perf_counter()
+# --input value that reads IDs from stdin, e.g. `duckdb ... | ... --input -`
+STDIN_INPUT = "-"
 
 
 def format_duration(seconds: float) -> str:
@@ -105,32 +127,46 @@ def _write_to_path(path: str, content: bytes | str) -> None:
         write_output(content, path)
     else:
         p = Path(path)
-        p.parent.mkdir(parents=True, exist_ok=True)
-        if isinstance(content, str):
-            p.write_text(content, encoding="utf-8")
-        else:
-            p.write_bytes(content)
+
+        def _write() -> None:
+            p.parent.mkdir(parents=True, exist_ok=True)
+            if isinstance(content, str):
+                p.write_text(content, encoding="utf-8")
+            else:
+                p.write_bytes(content)
+
+        disk_guard.guarded_write(_write, p)
 
 
-def _read_codes_from_path(path: str) -> List[str]:
-    """Read tournament codes from a file (local or S3)."""
+def _read_codes_from_path(path: str, limit: int = 0) -> List[str]:
+    """Read tournament codes from a file (local or S3), or stdin for "-"."""
     if _is_s3(path):
         from s3_io import download_to_file
 
         local_path = Path(tempfile.gettempdir()) / "tournament_codes.txt"
         download_to_file(path, local_path)
         path = str(local_path)
-    return read_tournament_codes(path)
+    return read_tournament_codes(path, limit=limit)
 
 
-def read_tournament_codes(file_path: str) -> List[str]:
-    """Read tournament codes from a file."""
+def read_tournament_codes(file_path: str, limit: int = 0) -> List[str]:
+    """
+    Read tournament codes from a file, or from stdin when file_path is "-"
+    (stopping after limit codes when limit > 0; see read_tournament_ids).
+    """
     codes = []
-    with open(file_path, "r", encoding="utf-8") as f:
+    source = (
+        nullcontext(sys.stdin)
+        if file_path == STDIN_INPUT
+        else open(file_path, "r", encoding="utf-8")
+    )
+    with source as f:
         for line in f:
             code = line.strip()
             if code:
                 codes.append(code)
+                if limit > 0 and len(codes) >= limit:
+                    break
     return codes
 
 
@@ -255,7 +291,7 @@ def _parse_round_date_with_format(date_str: str, fmt: str) -> Optional[str]:
 
 def _is_valid_parsed_year(year: int) -> bool:
     """Rule 1: Years should be in 2002..current_year (FIDE round dates)."""
-    current_year = datetime.now().year
+    current_year = utc_now().year
     return 2002 <= year <= current_year + 1
 
 
@@ -346,11 +382,14 @@ def parse_date_to_iso(date_str: str, date_format: Optional[str] = None) -> str:
 
 
 def parse_iso_to_datetime(iso_str: str):
-    """Convert ISO date string (YYYY-MM-DD) to datetime. Returns None if invalid."""
+    """
+    Convert ISO date string (YYYY-MM-DD) to midnight UTC (see timestamps.fide_date).
+    Returns None if invalid.
+    """
     if not iso_str or not str(iso_str).strip():
         return None
     try:
-        return datetime.strptime(str(iso_str).strip()[:10], "%Y-%m-%d")
+        return fide_date(datetime.strptime(str(iso_str).strip()[:10], "%Y-%m-%d"))
     except ValueError:
         return None
 
@@ -378,6 +417,74 @@ def parse_round_date(round_text: str) -> Tuple[Optional[int], Optional[str]]:
     return None, None
 
 
+def game_number_in_round(rounds: List[Dict], round_num, opp_id: str) -> int:
+    """
+    1-based game number for a new row against opp_id in round_num.
+
+    Matches and some team events list several games per round number against the
+    same opponent; both players list them in the same order, so the count of
+    earlier rows with the same (round, opponent) pairs up the two sides.
+    """
+    return 1 + sum(
+        1 for r in rounds if r.get("round") == round_num and r.get("opp_id") == opp_id
+    )
+
+
+# Reasons recorded in report["skipped_rows"] when a crosstable row is dropped
+SKIP_PARSE_ERROR = "parse error"
+SKIP_UNKNOWN_OPPONENT = "unknown opponent"
+
+
+class RoundRowSkipped(Exception):
+    """A round row that cannot be turned into a game; reason is a SKIP_* value."""
+
+    def __init__(self, reason: str, detail: str = ""):
+        super().__init__(detail or reason)
+        self.reason = reason
+        self.detail = detail
+
+
+def parse_round_row(
+    round_cells, anchor_to_id: Dict[str, str], rounds: List[Dict]
+) -> Optional[Dict]:
+    """
+    Parse one round row of a player's crosstable block.
+
+    Returns the round dict, or None when the row has no opponent link (byes,
+    unpaired rounds). Raises RoundRowSkipped when the row names an opponent that
+    cannot be resolved, so the caller can record it instead of losing the game
+    silently.
+    """
+    round_num, round_date = parse_round_date(round_cells[0].get_text(strip=True))
+    score_text = round_cells[6].get_text(strip=True)
+
+    color = extract_color_from_cell(round_cells[1])
+    anchor = extract_href_anchor_from_cell(round_cells[1])
+    opp_id = anchor_to_id.get(anchor, "") if anchor else ""
+    if anchor and not opp_id:
+        raise RoundRowSkipped(
+            SKIP_UNKNOWN_OPPONENT, f"no player row for anchor #{anchor}"
+        )
+    score = parse_score(score_text)
+    forfeit = extract_forfeit_indicator(score_text)
+    # Forfeit can also appear in Opp. Fed. column (cells[2]) when score cell is empty
+    if not forfeit:
+        forfeit = extract_forfeit_indicator(round_cells[2].get_text(strip=True))
+
+    if not opp_id:
+        return None
+    # Add round only when we have an opponent (can form a game)
+    return {
+        "round": round_num,
+        "game": game_number_in_round(rounds, round_num, opp_id),
+        "date": round_date,
+        "opp_id": opp_id,
+        "color": color,
+        "score": score,
+        "forfeit": forfeit,
+    }
+
+
 def extract_href_anchor_from_cell(cell) -> str:
     """
     Extract the href fragment from the first link in a cell.
@@ -434,22 +541,22 @@ def fetch_tournament_report(
     tournament_code: str,
     session: requests.Session,
     *,
+    url: Optional[str] = None,
     _attempt_log: Optional[List[Dict]] = None,
     return_raw: bool = False,
 ) -> Tuple[Optional[Dict], Optional[str], int, Optional[bytes]]:
     """
-    Fetch tournament report from FIDE website.
+    Fetch tournament report from FIDE website: url (from report_links.report_url)
+    or the original report for tournament_code.
 
     Returns:
         Tuple of (report_dict, error_string, num_attempts, raw_content).
         If successful, report_dict is not None.
         raw_content is response bytes when return_raw=True, else None.
     """
-    url = f"https://ratings.fide.com/tournament_src_report.phtml?code={tournament_code}"
+    url = url or SOURCE_REPORT_URL.format(code=tournament_code)
 
     max_retries = 2
-    # Fail fast on connect: 15s to establish, 45s to read (large report pages)
-    connect_timeout, read_timeout = 15, 45
     last_error = None
     attempt_times: List[float] = []
 
@@ -466,19 +573,14 @@ def fetch_tournament_report(
             time.sleep(delay)
 
         try:
-            headers = {
-                "User-Agent": "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
-                "Accept": "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8",
-                "Accept-Language": "en-US,en;q=0.9",
-                "Connection": "close",
-                "Cache-Control": "max-age=0",
-            }
+            headers = user_agents.browser_headers(
+                {"Connection": "close", "Cache-Control": "max-age=0"}
+            )
 
             t0 = time.perf_counter()
             try:
-                response = session.get(
-                    url, headers=headers, timeout=(connect_timeout, read_timeout)
-                )
+                # Connect 15s, read 45s by default (see http_timeouts)
+                response = http_timeouts.get(session, url, headers=headers)
             finally:
                 elapsed = time.perf_counter() - t0
                 attempt_times.append(elapsed)
@@ -538,6 +640,7 @@ def fetch_tournament_report(
             raw_content = response.content if return_raw else None
             table = soup.find("table", class_="calc_table")
             if not table:
+                anomaly.observe("reports", response.content, 0, tournament_code)
                 return None, "no data found", len(attempt_times), raw_content
 
             rows = table.find_all("tr")
@@ -559,6 +662,7 @@ def fetch_tournament_report(
                                 break
 
             players = []
+            skipped_rows: List[Dict] = []
             i = 0
             while i < len(rows):
                 row = rows[i]
@@ -575,13 +679,14 @@ def fetch_tournament_report(
                         player_country = cells[2].get_text(strip=True)
                         player_total = cells[6].get_text(strip=True)
 
-                        # Total score (no longer collecting rating - use profile chart if needed)
+                        # Total score (no longer collecting rating - use profile chart
+                        # if needed); None when blank or not a number, never 0.0
                         try:
                             player_total_float = (
-                                float(player_total) if player_total else 0.0
+                                float(player_total) if player_total else None
                             )
                         except ValueError:
-                            player_total_float = 0.0
+                            player_total_float = None
 
                         # Rank = 1-based order on page (correlates with tournament rank/tiebreaks)
                         rank = len(players) + 1
@@ -616,41 +721,32 @@ def fetch_tournament_report(
                                 round_first_text = round_cells[0].get_text(strip=True)
                                 # Check if this is a round data row (starts with digit)
                                 if round_first_text and round_first_text[0].isdigit():
-                                    round_num, round_date = parse_round_date(
-                                        round_first_text
-                                    )
-                                    opp_name = extract_text_from_cell(round_cells[1])
-                                    score_text = round_cells[6].get_text(strip=True)
-
-                                    color = extract_color_from_cell(round_cells[1])
-                                    anchor = extract_href_anchor_from_cell(
-                                        round_cells[1]
-                                    )
-                                    opp_id = (
-                                        anchor_to_id.get(anchor, "") if anchor else ""
-                                    )
-                                    score = parse_score(score_text)
-                                    forfeit = extract_forfeit_indicator(score_text)
-                                    # Forfeit can also appear in Opp. Fed. column (cells[2]) when score cell is empty
-                                    if not forfeit and len(round_cells) >= 3:
-                                        opp_fed_text = round_cells[2].get_text(
-                                            strip=True
+                                    try:
+                                        round_data = parse_round_row(
+                                            round_cells, anchor_to_id, player["rounds"]
                                         )
-                                        forfeit = extract_forfeit_indicator(
-                                            opp_fed_text
+                                    except RoundRowSkipped as e:
+                                        skipped_rows.append(
+                                            {
+                                                "player_id": player_id,
+                                                "row": round_first_text,
+                                                "reason": e.reason,
+                                                "detail": e.detail,
+                                            }
                                         )
-
-                                    has_opponent = bool(opp_id)
-                                    if has_opponent:
-                                        # Add round only when we have an opponent (can form a game)
-                                        round_data = {
-                                            "round": round_num,
-                                            "date": round_date,
-                                            "opp_id": opp_id,
-                                            "color": color,
-                                            "score": score,
-                                            "forfeit": forfeit,
-                                        }
+                                        round_data = None
+                                    except Exception as e:
+                                        # Malformed row: keep the rest of the table
+                                        skipped_rows.append(
+                                            {
+                                                "player_id": player_id,
+                                                "row": round_first_text,
+                                                "reason": SKIP_PARSE_ERROR,
+                                                "detail": f"{type(e).__name__}: {e}",
+                                            }
+                                        )
+                                        round_data = None
+                                    if round_data is not None:
                                         player["rounds"].append(round_data)
                                     i += 1
                                 else:
@@ -665,6 +761,9 @@ def fetch_tournament_report(
 
                 i += 1
 
+            anomaly.observe(
+                "reports", response.content, len(players), tournament_code
+            )
             if not players:
                 return None, "no players found", len(attempt_times), raw_content
 
@@ -674,6 +773,13 @@ def fetch_tournament_report(
             }
             if report_start_iso:
                 report_dict["report_start"] = report_start_iso
+            if skipped_rows:
+                report_dict["skipped_rows"] = skipped_rows
+                logger.warning(
+                    "Partial parse: tournament_code=%s skipped %d row(s)",
+                    tournament_code,
+                    len(skipped_rows),
+                )
             return (report_dict, None, len(attempt_times), raw_content)
 
         except requests.exceptions.Timeout as e:
@@ -833,7 +939,7 @@ def flatten_result(result: Dict) -> List[Dict]:
         pid = player.get("id", "")
         pname = player.get("name", "")
         pcountry = player.get("country", "")
-        ptotal = player.get("total", 0.0)
+        ptotal = player.get("total")
         rounds = player.get("rounds", [])
         if not rounds:
             flattened.append(
@@ -869,6 +975,7 @@ def flatten_result(result: Dict) -> List[Dict]:
                         "player_country": pcountry,
                         "player_total": ptotal,
                         "round": rd.get("round"),
+                        "game": rd.get("game", 1),
                         "round_date": rd.get("date", ""),
                         "opp_name": rd.get("opp_name", ""),
                         "opp_id": rd.get("opp_id", ""),
@@ -890,7 +997,8 @@ def flatten_to_games(
 ) -> List[Dict]:
     """
     Convert player-round rows to games (legacy format for tests).
-    Returns list of dicts with white_id, black_id, white_score, forfeit (bool), round, date.
+    Returns list of dicts with white_id, black_id, white_score, forfeit (bool), round,
+    game (1-based within round vs the same opponent), date.
     """
     date_strs = list(
         {
@@ -916,6 +1024,7 @@ def flatten_to_games(
             continue
         tc = row.get("tournament_code", tournament_code)
         rnd = row["round"]
+        game = row.get("game", 1)
         color = (row.get("color") or "").strip().lower()
         forfeit = (row.get("forfeit") or "").strip()
 
@@ -926,7 +1035,7 @@ def flatten_to_games(
         else:
             continue
 
-        key = (tc, rnd, white_id, black_id)
+        key = (tc, rnd, game, white_id, black_id)
         if key in seen:
             continue
         seen.add(key)
@@ -951,6 +1060,7 @@ def flatten_to_games(
             {
                 "tournament_code": tc,
                 "round": rnd,
+                "game": game,
                 "date": date_iso,
                 "white_id": white_id,
                 "black_id": black_id,
@@ -977,6 +1087,7 @@ def _flatten_rounds_for_games(result: Dict) -> List[Dict]:
                     "tournament_code": tc,
                     "player_id": pid,
                     "round": rd.get("round"),
+                    "game": rd.get("game", 1),
                     "round_date": rd.get("date", ""),
                     "opp_id": rd.get("opp_id", ""),
                     "color": (rd.get("color") or "").strip().lower(),
@@ -1045,8 +1156,8 @@ def validate_pairings(result: Dict) -> None:
         return
     tc = result.get("tournament_code", "")
     players_by_id = {str(p.get("id", "")): p for p in result.get("players", [])}
-    # Build (player_id, round) -> {opp_id, score, forfeit, color}
-    rounds_map: Dict[Tuple[str, int], Dict] = {}
+    # Build (player_id, round, game) -> {opp_id, score, forfeit, color}
+    rounds_map: Dict[Tuple[str, int, int], Dict] = {}
     for player in result.get("players", []):
         pid = str(player.get("id", ""))
         for rd in player.get("rounds", []):
@@ -1056,7 +1167,7 @@ def validate_pairings(result: Dict) -> None:
             rnd = rd.get("round")
             if rnd is None:
                 continue
-            key = (pid, rnd)
+            key = (pid, rnd, rd.get("game", 1))
             rounds_map[key] = {
                 "opp_id": str(opp_id),
                 "score": rd.get("score"),
@@ -1064,13 +1175,13 @@ def validate_pairings(result: Dict) -> None:
                 "color": (rd.get("color") or "").strip().lower(),
             }
     seen_pairs: set = set()
-    for (pid, rnd), data in rounds_map.items():
+    for (pid, rnd, game), data in rounds_map.items():
         opp_id = data["opp_id"]
-        pair = tuple(sorted([pid, opp_id])) + (rnd,)
+        pair = tuple(sorted([pid, opp_id])) + (rnd, game)
         if pair in seen_pairs:
             continue
         seen_pairs.add(pair)
-        rev_key = (opp_id, rnd)
+        rev_key = (opp_id, rnd, game)
         rev = rounds_map.get(rev_key)
         if not rev:
             logger.warning(
@@ -1137,22 +1248,27 @@ def validate_pairings(result: Dict) -> None:
 def results_to_players_dataframe(results: List[Dict]) -> pd.DataFrame:
     """
     Build players DataFrame. PK: (player_id, tournament_id).
-    Columns: player_id, tournament_id, player_name, player_country, player_total, rank.
+    Columns: player_id, tournament_id, player_name, player_country, player_total, rank,
+    n_rounds. A missing player_id, total or rank is None (null), not "" or 0.
+    n_rounds is the tournament's highest round in the crosstable (details pages have
+    no rounds row), the same on each of its rows.
     """
     rows = []
     for result in results:
         if not result.get("success"):
             continue
         tc = result.get("tournament_code", "")
+        n_rounds = crosstable_n_rounds(result)
         for player in result.get("players", []):
             rows.append(
                 {
-                    "player_id": str(player.get("id", "")),
+                    "player_id": str(player["id"]) if player.get("id") else None,
                     "tournament_id": str(tc),
-                    "player_name": player.get("name", ""),
-                    "player_country": player.get("country", ""),
-                    "player_total": player.get("total", 0.0),
-                    "rank": player.get("rank", 0),
+                    "player_name": player.get("name") or None,
+                    "player_country": player.get("country") or None,
+                    "player_total": player.get("total"),
+                    "rank": player.get("rank"),
+                    "n_rounds": n_rounds,
                 }
             )
     if not rows:
@@ -1164,9 +1280,30 @@ def results_to_players_dataframe(results: List[Dict]) -> pd.DataFrame:
                 "player_country",
                 "player_total",
                 "rank",
+                "n_rounds",
             ]
         )
-    return pd.DataFrame(rows)
+    df = pd.DataFrame(rows)
+    # Nullable types keep one Parquet type per column whether or not values are missing
+    df["player_total"] = df["player_total"].astype("float64")
+    df["rank"] = df["rank"].astype("Int64")
+    df["n_rounds"] = df["n_rounds"].astype("Int64")
+    return df
+
+
+def crosstable_n_rounds(result: Dict) -> Optional[int]:
+    """
+    Highest round with a game in a report's crosstable, or None if it has none.
+    Byes and unplayed rounds are not parsed, so this can fall short of the
+    scheduled count when nobody played the last rounds.
+    """
+    rounds = [
+        rd["round"]
+        for player in result.get("players", [])
+        for rd in player.get("rounds", [])
+        if rd.get("round")
+    ]
+    return max(rounds) if rounds else None
 
 
 def results_to_games_dataframe(
@@ -1174,8 +1311,11 @@ def results_to_games_dataframe(
     details_map: Optional[Dict[str, Tuple[Optional[str], Optional[str]]]] = None,
 ) -> pd.DataFrame:
     """
-    Build games DataFrame. PK: (white_player_id, tournament_id, round_number).
-    Columns: white_player_id, black_player_id, tournament_id, round_number, round_date, score, forfeit.
+    Build games DataFrame.
+    PK: (white_player_id, tournament_id, round_number, game_number).
+    Columns: white_player_id, black_player_id, tournament_id, round_number, game_number,
+    round_date, score, forfeit. game_number is 1 unless the pair played several games
+    under one round number (matches, double-game rounds).
     score = white's score (0, 0.5, 1). forfeit = from white's perspective ("+", "-", or "").
     """
     all_games = []
@@ -1199,7 +1339,7 @@ def results_to_games_dataframe(
             report_start_iso=report_start_iso,
         )
 
-        seen: set = set()  # (white_id, tc, round)
+        seen: set = set()  # (white_id, tc, round, game)
         for row in flattened:
             if (
                 row.get("round") is None
@@ -1215,7 +1355,8 @@ def results_to_games_dataframe(
             else:
                 continue
 
-            key = (white_id, tc, row["round"])
+            game = row.get("game", 1)
+            key = (white_id, tc, row["round"], game)
             if key in seen:
                 continue
             seen.add(key)
@@ -1253,6 +1394,7 @@ def results_to_games_dataframe(
                     "black_player_id": black_id,
                     "tournament_id": tc,
                     "round_number": row["round"],
+                    "game_number": game,
                     "round_date": round_dt,
                     "score": white_score,
                     "forfeit": white_forfeit,
@@ -1266,6 +1408,7 @@ def results_to_games_dataframe(
                 "black_player_id",
                 "tournament_id",
                 "round_number",
+                "game_number",
                 "round_date",
                 "score",
                 "forfeit",
@@ -1274,36 +1417,80 @@ def results_to_games_dataframe(
     return pd.DataFrame(all_games)
 
 
-def _write_parquet_to_path(df: pd.DataFrame, path: str) -> None:
-    """Write DataFrame to Parquet (local or S3)."""
-    buf = io.BytesIO()
-    df.to_parquet(buf, index=False, engine="pyarrow")
-    _write_to_path(path, buf.getvalue())
-
-
 ERROR_REPORT_UPDATED_OR_REPLACED = "report_updated_or_replaced"
+# View Report links to a pairing program's site, which no parser reads yet, and
+# FIDE's original report for the code has no crosstable either
+ERROR_PAIRING_PROGRAM_REPORT = "pairing_program_report"
 # Permanent structural failures — tournament page exists but has no usable data
 SKIPPABLE_ERRORS = frozenset(
-    [ERROR_REPORT_UPDATED_OR_REPLACED, "no data found", "no players found"]
+    [
+        ERROR_REPORT_UPDATED_OR_REPLACED,
+        ERROR_PAIRING_PROGRAM_REPORT,
+        "no data found",
+        "no players found",
+    ]
 )
 
 
 def save_skipped_json(skipped: List[Dict], base_path: str) -> None:
-    """Save tournaments skipped (no original report) to JSON for reporting."""
+    """Save tournaments skipped (no usable report) to JSON for reporting."""
     if not skipped:
         return
     path = base_path.rstrip("/") + "_skipped.json"
     content = json.dumps(skipped, indent=2, ensure_ascii=False)
     _write_to_path(path, content)
-    logger.info("Saved %d skipped (no original report) to %s", len(skipped), path)
+    logger.info("Saved %d skipped (no usable report) to %s", len(skipped), path)
+
+
+def partial_parse_entries(results: List[Dict]) -> List[Dict]:
+    """Tournaments parsed with skipped crosstable rows: code plus skipped_rows."""
+    return [
+        {
+            "tournament_code": r.get("tournament_code", ""),
+            "skipped_rows": r["skipped_rows"],
+        }
+        for r in results
+        if r.get("success") and r.get("skipped_rows")
+    ]
+
+
+def save_partial_json(partial: List[Dict], base_path: str) -> None:
+    """Save partially parsed tournaments (see partial_parse_entries) to JSON."""
+    if not partial:
+        return
+    path = base_path.rstrip("/") + "_partial.json"
+    content = json.dumps(partial, indent=2, ensure_ascii=False)
+    _write_to_path(path, content)
+    n_rows = sum(len(p["skipped_rows"]) for p in partial)
+    logger.info(
+        "Saved %d partially parsed tournaments (%d skipped rows) to %s",
+        len(partial),
+        n_rows,
+        path,
+    )
+
+
+def players_parquet_bytes(results: List[Dict]) -> Tuple[bytes, int]:
+    """Players Parquet bytes (with provenance metadata) and row count."""
+    df = results_to_players_dataframe(results)
+    return dataframe_to_parquet_bytes(df), len(df)
+
+
+def games_parquet_bytes(
+    results: List[Dict],
+    details_map: Optional[Dict[str, Tuple[Optional[str], Optional[str]]]] = None,
+) -> Tuple[bytes, int]:
+    """Games Parquet bytes (with provenance metadata) and row count."""
+    df = results_to_games_dataframe(results, details_map=details_map)
+    return dataframe_to_parquet_bytes(df), len(df)
 
 
 def save_players_parquet(results: List[Dict], parquet_path: str):
     """Save players Parquet. PK: (player_id, tournament_id)."""
     try:
-        df = results_to_players_dataframe(results)
-        _write_parquet_to_path(df, parquet_path)
-        logger.info(f"Saved {len(df)} player rows to {parquet_path}")
+        content, n_rows = players_parquet_bytes(results)
+        _write_to_path(parquet_path, content)
+        logger.info(f"Saved {n_rows} player rows to {parquet_path}")
     except Exception as e:
         logger.error(f"Players Parquet save failed: {e}")
 
@@ -1315,10 +1502,10 @@ def save_games_parquet(
 ):
     """Save games as Parquet file (one row per game, main output format)."""
     try:
-        df = results_to_games_dataframe(results, details_map=details_map)
-        _write_parquet_to_path(df, parquet_path)
+        content, n_games = games_parquet_bytes(results, details_map)
+        _write_to_path(parquet_path, content)
         logger.info(f"Saved {len(results)} tournament(s) to {parquet_path}")
-        logger.info(f"  Total games: {len(df)}")
+        logger.info(f"  Total games: {n_games}")
     except Exception as e:
         logger.error(f"Games Parquet save failed: {e}")
 
@@ -1419,16 +1606,116 @@ def save_checkpoint(
     results: List[Dict],
     checkpoint_path: Optional[str] = None,
     details_map: Optional[Dict[str, Tuple[Optional[str], Optional[str]]]] = None,
+    keep: int = DEFAULT_KEEP,
 ):
-    """Save checkpoint (games parquet) to checkpoint path."""
+    """
+    Save checkpoint (games parquet) to checkpoint path. Failed codes and errors go
+    to {checkpoint_path}.failures.json so they survive a crash too.
+
+    Local games checkpoints are rotated and gzipped ({checkpoint_path}.1.gz newest
+    ... {checkpoint_path}.{keep}.gz); S3 ones are overwritten.
+    """
     if not checkpoint_path:
         return
     try:
-        save_games_parquet(results, checkpoint_path, details_map=details_map)
+        if _is_s3(checkpoint_path):
+            save_games_parquet(results, checkpoint_path, details_map=details_map)
+        else:
+            df = results_to_games_dataframe(results, details_map=details_map)
+            content = dataframe_to_parquet_bytes(df)
+            path = disk_guard.guarded_write(
+                lambda: write_rotated(checkpoint_path, content, keep), checkpoint_path
+            )
+            logger.info(f"Saved checkpoint ({len(df)} games) to {path}")
+        failures = [
+            {"tournament_code": r["tournament_code"], "error": r.get("error", "")}
+            for r in results
+            if not r.get("success", False)
+        ]
+        if failures:
+            _write_to_path(
+                checkpoint_path + ".failures.json",
+                json.dumps(failures, indent=2, ensure_ascii=False),
+            )
     except Exception as e:
         logger.error(f"Checkpoint save failed: {e}")
 
 
+def _read_details(details_path: str) -> Optional[pd.DataFrame]:
+    """
+    Successful rows of a details Parquet (local or S3), or None if a local file
+    is missing.
+    """
+    if _is_s3(details_path):
+        from s3_io import download_to_file
+
+        local_path = Path(tempfile.gettempdir()) / "details_chunk.parquet"
+        download_to_file(details_path, local_path)
+        df = pd.read_parquet(local_path)
+    elif os.path.exists(details_path):
+        df = pd.read_parquet(details_path)
+    else:
+        return None
+    if "success" in df.columns:
+        df = df[df["success"] == True]  # noqa: E712
+    return df
+
+
+def _details_codes(df: pd.DataFrame) -> Iterator[Tuple[str, pd.Series]]:
+    """(event_code, row) for each details row with a code."""
+    ec_col = "event_code" if "event_code" in df.columns else "id"
+    for _, row in df.iterrows():
+        ec = row.get(ec_col)
+        if pd.notna(ec) and str(ec):
+            yield str(ec), row
+
+
+def details_map_from_frame(
+    df: pd.DataFrame,
+) -> Dict[str, Tuple[Optional[str], Optional[str]]]:
+    """event_code -> (start ISO, end ISO) for round date inference."""
+    details_map: Dict[str, Tuple[Optional[str], Optional[str]]] = {}
+    for ec, row in _details_codes(df):
+        sd = parse_details_date_to_iso(str(row.get("start_date", "")))
+        ed = parse_details_date_to_iso(str(row.get("end_date", "")))
+        details_map[ec] = (sd, ed)
+    return details_map
+
+
+def report_links_from_frame(df: pd.DataFrame) -> Dict[str, ReportLink]:
+    """
+    event_code -> ReportLink from the report_kind, report_system and report_event
+    columns. Rows without a report_kind (or details written before those columns)
+    are left out and fetched by event code.
+    """
+    if "report_kind" not in df.columns:
+        return {}
+
+    def _value(row: pd.Series, column: str) -> Optional[str]:
+        value = row.get(column)
+        return str(value) if value is not None and pd.notna(value) else None
+
+    links: Dict[str, ReportLink] = {}
+    for ec, row in _details_codes(df):
+        kind = _value(row, "report_kind")
+        if kind:
+            links[ec] = ReportLink(
+                kind, _value(row, "report_system"), _value(row, "report_event"), ""
+            )
+    return links
+
+
+def load_details_map(
+    details_path: str,
+) -> Dict[str, Tuple[Optional[str], Optional[str]]]:
+    """
+    event_code -> (start ISO, end ISO) from the successful rows of a details
+    Parquet (local or S3), for round date inference; {} if a local file is missing.
+    """
+    df = _read_details(details_path)
+    return {} if df is None else details_map_from_frame(df)
+
+
 def run(
     input_path: str,
     output_path: str,
@@ -1440,25 +1727,66 @@ def run(
     output_sample_json: Optional[str] = None,
     output_sample_csv: Optional[str] = None,
     output_reports_base: Optional[str] = None,
+    max_retries: int = 3,
+    checkpoint: int = 0,
+    checkpoint_interval: float = 0.0,
+    checkpoint_keep: int = DEFAULT_KEEP,
+    summary_file: Optional[str] = None,
+    players_file: Optional[str] = None,
+    validate: bool = False,
+    breaker: Optional[circuit_breaker.CircuitBreaker] = None,
+    control_file: Optional[str] = None,
+    control_poll: float = 0.0,
+    abort_after_connect_timeouts: int = 0,
+    verbose: bool = False,
+    show_time: bool = False,
+    verbose_errors: bool = False,
+    handle_signals: bool = False,
 ) -> int:
     """
     Scrape tournament reports for codes from input_path, write to output_path.
 
+    Shared by main() and the Lambda handler, so both get the same outputs, summary
+    JSON and exit codes.
+
     Args:
-        input_path: Path to tournament codes file (one per line). Local or S3 URI.
+        input_path: Path to tournament codes file (one per line). Local, S3 URI, or
+            - for stdin.
         output_path: Base path for outputs. Writes {output_path}_players.parquet and
             {output_path}_games.parquet.
-        details_path: Optional path to tournament_details parquet for date inference.
+        details_path: Optional path to tournament_details parquet for date inference
+            and View Report links (pairing program links are skipped, not fetched).
         rate_limit: Requests per second (0 = no limit, natural throughput).
         quiet: Reduce log output.
         limit: Process only first N codes (0 = all).
         save_raw: If True, save concatenated raw HTML to raw/reports/reports_chunk_{i}.html.gz.
         output_sample_json: Optional path for verbose JSON sample (tournaments with players/rounds).
         output_sample_csv: Optional path for CSV sample from games parquet.
-        output_reports_base: Optional base path for reports (e.g. skipped tournaments JSON).
+        output_reports_base: Optional base path for reports (skipped and partially
+            parsed tournaments, summary). Default: output_path.
+        max_retries: Retry passes for network errors and timeouts.
+        checkpoint: Save checkpoint every N successful (0 = disabled).
+        checkpoint_interval: Also save a checkpoint every this many seconds
+            (0 = disabled).
+        checkpoint_keep: Number of rotated local checkpoints to keep.
+        summary_file: Path for the summary JSON. Default: {reports base}_summary.json.
+        players_file: Players list Parquet to validate names and countries against.
+        validate: Run pairing checks (and the players_file checks) on each report.
+        breaker: Circuit breaker around the fetches (None = no breaker).
+        control_file: Settings file re-read on SIGHUP (see live_config).
+        control_poll: Instead of waiting for SIGHUP, re-read control_file (local
+            or S3) every this many seconds if it changed (0 = SIGHUP only).
+        abort_after_connect_timeouts: Raise RuntimeError after this many connect
+            timeouts in a row (0 = never), so a Step Function can retry the chunk
+            from a fresh Lambda.
+        verbose: Print one line per tournament instead of the progress bar.
+        show_time: Log timing info for each tournament.
+        verbose_errors: Log failed HTTP attempts and a retry analysis at the end.
+        handle_signals: Save partial results on SIGINT/SIGTERM and exit.
 
     Returns:
-        0 on success, 1 on failure.
+        EXIT_SUCCESS, EXIT_PARTIAL or EXIT_FATAL (see run_summary). Tournaments
+        skipped for SKIPPABLE_ERRORS (no usable report) do not count as failures.
     """
     if quiet:
         logging.getLogger().setLevel(logging.WARNING)
@@ -1466,46 +1794,68 @@ def run(
     base = output_path.rstrip("/")
     players_path = base + "_players.parquet"
     games_path = base + "_games.parquet"
+    reports_base = output_reports_base or base
+    summary_file = summary_file or summary_path(reports_base)
+    outputs = {
+        "players": players_path,
+        "games": games_path,
+        "json_sample": output_sample_json,
+        "csv_sample": output_sample_csv,
+    }
+
+    def _fatal(message: str) -> int:
+        logger.error(message)
+        write_summary(
+            build_summary("tournament_reports", EXIT_FATAL, error=message),
+            summary_file,
+        )
+        return EXIT_FATAL
 
     try:
-        codes = _read_codes_from_path(input_path)
+        codes = _read_codes_from_path(input_path, limit)
     except Exception as e:
-        logger.error("Error reading codes from %s: %s", input_path, e)
-        return 1
+        return _fatal(f"Error reading codes from {input_path}: {e}")
 
     if not codes:
-        logger.error("No tournament codes found in %s", input_path)
-        return 1
+        return _fatal(f"No tournament codes found in {input_path}")
 
     if limit > 0:
-        codes = codes[:limit]
-        logger.info("Limited to first %d tournaments", limit)
+        logger.info("Limited to first %d tournaments", len(codes))
 
     details_map: Dict[str, Tuple[Optional[str], Optional[str]]] = {}
+    report_links: Dict[str, ReportLink] = {}
     if details_path:
         try:
-            if _is_s3(details_path):
-                from s3_io import download_to_file
+            details_df = _read_details(details_path)
+            if details_df is not None:
+                details_map = details_map_from_frame(details_df)
+                report_links = report_links_from_frame(details_df)
+            if details_map:
+                logger.info(
+                    "Loaded date bounds for %d tournaments from %s",
+                    len(details_map),
+                    details_path,
+                )
+        except Exception as e:
+            logger.warning("Could not load details for dates and report links: %s", e)
 
-                local_path = Path(tempfile.gettempdir()) / "details_chunk.parquet"
-                download_to_file(details_path, local_path)
-                df = pd.read_parquet(local_path)
-            else:
-                df = pd.read_parquet(details_path)
-            ec_col = "event_code" if "event_code" in df.columns else "id"
-            for _, row in df.iterrows():
-                ec = row.get(ec_col)
-                if pd.notna(ec) and str(ec):
-                    sd = parse_details_date_to_iso(str(row.get("start_date", "")))
-                    ed = parse_details_date_to_iso(str(row.get("end_date", "")))
-                    details_map[str(ec)] = (sd, ed)
+    # Load players file for validation (name/country, pairing checks)
+    players_df: Optional[pd.DataFrame] = None
+    if validate and players_file:
+        if os.path.exists(players_file):
+            try:
+                players_df = pd.read_parquet(players_file)
+                logger.info(
+                    "Loaded players file for validation: %s (%d rows)",
+                    players_file,
+                    len(players_df),
+                )
+            except Exception as e:
+                logger.warning("Could not load players file for validation: %s", e)
+        else:
             logger.info(
-                "Loaded date bounds for %d tournaments from %s",
-                len(details_map),
-                details_path,
+                "Players file not found at %s; skipping validation", players_file
             )
-        except Exception as e:
-            logger.warning("Could not load details for date inference: %s", e)
 
     logger.info(
         "Processing %d tournaments from %s -> %s",
@@ -1521,20 +1871,67 @@ def run(
     session.mount("http://", adapter)
     session.mount("https://", adapter)
 
+    # Also created at rate_limit 0: wait() then only tracks the achieved rate
+    rate_limiter = RateLimiter(rate_limit)
+    control_poller: Optional[live_config.ControlFilePoller] = None
+    if control_file and control_poll > 0:
+        control_poller = live_config.ControlFilePoller(
+            control_file, rate_limiter, control_poll
+        )
+    elif control_file:
+        live_config.install(control_file, rate_limiter)
+    circuit_gave_up = False
+
     raw_base: Optional[str] = (
         _raw_base_from_output_path(output_path) if save_raw else None
     )
     raw_accumulator: List[Tuple[str, bytes]] = []  # (code, html)
 
     all_results: List[Dict] = []
-    skipped_reports: List[Dict] = (
-        []
-    )  # Tournaments with no original report (updated/replaced)
+    # Tournaments with no usable report (SKIPPABLE_ERRORS)
+    skipped_reports: List[Dict] = []
     success_count = 0
-    current_codes = codes
+    error_count = 0
+    total_retries = 0
+    attempt_log: List[Dict] = []
+    attempt_counts: List[Tuple[str, int]] = []
+    consecutive_connect_timeouts = 0
+
+    def _save_outputs() -> None:
+        save_players_parquet(all_results, players_path)
+        save_games_parquet(all_results, games_path, details_map=details_map)
+        save_skipped_json(skipped_reports, reports_base)
+        save_partial_json(partial_parse_entries(all_results), reports_base)
+        if output_sample_csv:
+            save_csv_sample_from_parquet(
+                games_path, output_sample_csv, sample_size=100
+            )
+        if output_sample_json:
+            save_verbose_json_sample(
+                all_results,
+                output_sample_json,
+                sample_size=100,
+                details_map=details_map,
+            )
+
+    def _graceful_shutdown(signum, frame):
+        logger.warning("\nReceived interrupt, initiating graceful shutdown...")
+        if all_results:
+            try:
+                _save_outputs()
+                logger.info("Saved %d results to %s", len(all_results), games_path)
+            except Exception as e:
+                logger.error("Error saving partial results: %s", e)
+        sys.exit(130 if signum == 2 else 0)
+
+    if handle_signals:
+        signal.signal(signal.SIGINT, _graceful_shutdown)
+        signal.signal(signal.SIGTERM, _graceful_shutdown)
+
+    current_tournaments = codes
 
     pbar = None
-    if not quiet:
+    if not quiet and not verbose:
         pbar = tqdm(
             total=len(codes),
             desc="Processing",
@@ -1542,77 +1939,347 @@ def run(
             bar_format="{l_bar}{bar}| {n_fmt}/{total_fmt} [{elapsed}<{remaining}, {rate_fmt}]",
         )
 
-    rate_limiter = RateLimiter(rate_limit) if rate_limit > 0 else None
-
     start_time = time.time()
+    checkpoints = CheckpointSchedule(checkpoint, checkpoint_interval)
+
+    for pass_num in range(max_retries + 1):
+        if not current_tournaments:
+            break
+        if pass_num > 0:
+            delay = 3 * (2 ** (pass_num - 1))  # Exponential backoff: 3s, 6s, 12s
+            logger.info(
+                f"Retry pass {pass_num}: waiting {format_duration(delay)} before retrying {len(current_tournaments)} tournaments"
+            )
+            time.sleep(delay)
+            total_retries += len(current_tournaments)
+            consecutive_connect_timeouts = 0  # reset on new pass
 
-    for code in current_codes:
-        if rate_limiter:
+        pass_failed = []
+        # Tournaments that fail while the circuit breaker is open are appended
+        # and fetched again in this pass
+        pass_queue = list(current_tournaments)
+
+        for queue_index, tournament_code in enumerate(pass_queue):
+            link = report_links.get(tournament_code)
+            if breaker:
+                try:
+                    breaker.before_request()
+                except circuit_breaker.CircuitOpenError as e:
+                    logger.error(f"{e}; stopping")
+                    circuit_gave_up = True
+                    break
+            if control_poller:
+                control_poller.poll()
             rate_limiter.wait()
-        report, error, _, raw_content = fetch_tournament_report(
-            code, session, return_raw=save_raw
-        )
+            with tracing.span(
+                "fetch_report", tournament_code=tournament_code, retry_pass=pass_num
+            ) as fetch_span:
+                # Pairing program links have no report_url: FIDE's original
+                # report for the code is tried instead
+                report, error, num_attempts, raw_content = fetch_tournament_report(
+                    tournament_code,
+                    session,
+                    url=report_url(link),
+                    return_raw=raw_base is not None,
+                    _attempt_log=attempt_log if verbose_errors else None,
+                )
+                tracing.set_attributes(fetch_span, attempts=num_attempts, error=error)
+            if (
+                report is None
+                and error in SKIPPABLE_ERRORS
+                and link is not None
+                and link.kind == PAIRING_PROGRAM
+            ):
+                # No crosstable on FIDE either: the report is only on the
+                # pairing program's site
+                error = ERROR_PAIRING_PROGRAM_REPORT
+            if verbose_errors:
+                attempt_counts.append((tournament_code, num_attempts))
+
+            result = {"tournament_code": tournament_code}
 
-        result = {"tournament_code": code}
-        if report is None:
-            if error in SKIPPABLE_ERRORS:
-                # No usable report data — skip and record for audit
+            if report is None and error in SKIPPABLE_ERRORS:
+                # No usable report data: skip and record for audit
+                if breaker:
+                    breaker.record(True)
+                consecutive_connect_timeouts = 0
                 result["success"] = False
+                result["skipped"] = True
                 result["error"] = error
-                skipped_reports.append({"tournament_code": code, "error": error})
-                all_results.append(result)
-                if pbar:
-                    pbar.update(1)
-                continue
-            raise RuntimeError(
-                f"Report fetch failed for tournament {code}: {error or 'unknown'}; "
-                "aborting chunk so Step Function can retry with fresh Lambda"
-            ) from None
-
-        success_count += 1
-        result["success"] = True
-        result.update(report)
-        if raw_base and raw_content:
-            raw_accumulator.append((code, raw_content))
-
-        all_results.append(result)
-        if pbar:
-            pbar.update(1)
-            pbar.set_postfix({"✓": success_count})
+                skipped = {"tournament_code": tournament_code, "error": error}
+                if error == ERROR_PAIRING_PROGRAM_REPORT:
+                    skipped["report_system"] = link.system
+                    skipped["report_event"] = link.event
+                skipped_reports.append(skipped)
+            elif report is None:
+                error_lower = error.lower() if error else ""
+                is_connect_timeout = (
+                    "connect" in error_lower and "timeout" in error_lower
+                ) or "connecttimeout" in error_lower
+                if is_connect_timeout:
+                    consecutive_connect_timeouts += 1
+                    if (
+                        abort_after_connect_timeouts
+                        and consecutive_connect_timeouts >= abort_after_connect_timeouts
+                    ):
+                        raise RuntimeError(
+                            f"Report fetch: {consecutive_connect_timeouts} "
+                            "consecutive connect timeouts; aborting chunk so Step "
+                            "Function can retry with fresh Lambda"
+                        ) from None
+                else:
+                    consecutive_connect_timeouts = 0
+                network_error_patterns = [
+                    "eof",
+                    "connection reset",
+                    "connection aborted",
+                    "remotedisconnected",
+                    "remote end closed",
+                    "broken pipe",
+                ]
+                is_network_error = any(p in error_lower for p in network_error_patterns)
+                retryable = bool(error) and (
+                    is_network_error or "timeout" in error_lower
+                )
+                if breaker and breaker.record(not retryable) and retryable:
+                    # Failed during an outage: try again once the breaker closes
+                    pass_queue.append(tournament_code)
+                    continue
 
-    if pbar:
-        pbar.close()
+                error_count += 1
+                result["success"] = False
+                result["error"] = error or "fetch failed"
+                if retryable:
+                    if pass_num < max_retries:
+                        pass_failed.append(tournament_code)
+            else:
+                if breaker:
+                    breaker.record(True)
+                consecutive_connect_timeouts = 0
+                success_count += 1
+                result["success"] = True
+                result.update(report)
+                if raw_base and raw_content:
+                    raw_accumulator.append((tournament_code, raw_content))
+                if validate:
+                    validate_pairings(result)
+                    if players_df is not None:
+                        validate_against_players_file(result, players_df)
 
-    if raw_base and raw_accumulator:
-        from raw_utils import build_concatenated_gzip
+            all_results.append(result)
+            if checkpoints.due(success_count):
+                logger.info(
+                    f"Saving checkpoint at {success_count} successful, "
+                    f"{error_count} failed..."
+                )
+                save_checkpoint(
+                    games_path,
+                    all_results,
+                    games_path + ".checkpoint",
+                    details_map=details_map,
+                    keep=checkpoint_keep,
+                )
+                checkpoints.mark(success_count)
 
-        raw_path = raw_base + ".html.gz"
-        _write_to_path(raw_path, build_concatenated_gzip(raw_accumulator))
-        logger.info(
-            "Saved concatenated raw HTML (%d tournaments) to %s",
-            len(raw_accumulator),
-            raw_path,
-        )
+            total_processed = success_count + error_count + len(skipped_reports)
+            elapsed = time.time() - start_time
 
-    save_players_parquet(all_results, players_path)
-    save_games_parquet(all_results, games_path, details_map=details_map)
+            if total_processed > 0:
+                avg_time = elapsed / total_processed
+                remaining = len(codes) - total_processed
+                est_remaining = avg_time * remaining
+            else:
+                est_remaining = 0
 
-    if output_reports_base and skipped_reports:
-        save_skipped_json(skipped_reports, output_reports_base)
+            if verbose:
+                actual_rate = total_processed / elapsed if elapsed > 0 else 0
+                if result["success"]:
+                    num_players = len(result.get("players", []))
+                    retry_info = f" [Retry pass {pass_num + 1}]" if pass_num > 0 else ""
+                    http_retries = (
+                        f" [{num_attempts} HTTP attempts]" if num_attempts > 1 else ""
+                    )
+                    print(
+                        f"[{total_processed}/{len(codes)}] ✓ {tournament_code}: {num_players} players{retry_info}{http_retries} | "
+                        f"Actual: {actual_rate:.2f}/s | "
+                        f"Elapsed: {format_duration(elapsed)} | Est: {format_duration(est_remaining)} | "
+                        f"Success: {success_count} | Errors: {error_count} | Retries: {total_retries}",
+                        file=sys.stderr,
+                    )
+                else:
+                    error_msg = result.get("error", "unknown")
+                    will_retry = tournament_code in pass_failed
+                    retry_info = f" [Retry pass {pass_num + 1}]" if pass_num > 0 else ""
+                    http_retries = (
+                        f" [{num_attempts} HTTP attempts]" if num_attempts > 1 else ""
+                    )
+                    if result.get("skipped"):
+                        retry_status = " [SKIPPED]"
+                    elif will_retry:
+                        retry_status = " [WILL RETRY]"
+                    else:
+                        retry_status = " [FINAL FAILURE]"
+                    print(
+                        f"[{total_processed}/{len(codes)}] ✗ {tournament_code}: {error_msg}{retry_info}{http_retries}{retry_status} | "
+                        f"Actual: {actual_rate:.2f}/s | "
+                        f"Elapsed: {format_duration(elapsed)} | Est: {format_duration(est_remaining)} | "
+                        f"Success: {success_count} | Errors: {error_count} | Retries: {total_retries}",
+                        file=sys.stderr,
+                    )
+            else:
+                postfix_dict = {
+                    "✓": success_count,
+                    "✗": error_count,
+                    "rate": f"{total_processed / elapsed if elapsed > 0 else 0:.2f}/s",
+                }
+                if skipped_reports:
+                    postfix_dict["skipped"] = len(skipped_reports)
+                if total_retries > 0 or pass_num > 0:
+                    postfix_dict["retries"] = total_retries
+                if pass_num > 0:
+                    postfix_dict["pass"] = f"{pass_num + 1}/{max_retries + 1}"
+                if len(pass_failed) > 0:
+                    postfix_dict["pending"] = len(pass_failed)
+                postfix_dict["est"] = (
+                    format_duration(est_remaining) if est_remaining > 0 else "?"
+                )
+                if pbar:
+                    pbar.update(1)
+                    pbar.set_postfix(postfix_dict)
 
-    if output_sample_json:
-        save_verbose_json_sample(
-            all_results,
-            output_sample_json,
-            sample_size=100,
-            details_map=details_map,
-        )
-    if output_sample_csv:
-        save_csv_sample_from_parquet(games_path, output_sample_csv, sample_size=100)
+                if show_time:
+                    actual_rate = total_processed / elapsed if elapsed > 0 else 0
+                    if result["success"]:
+                        num_players = len(result.get("players", []))
+                        logger.info(
+                            f"[{total_processed}/{len(codes)}] ✓ {tournament_code}: {num_players} players | "
+                            f"Rate: {actual_rate:.2f}/s | Est: {format_duration(est_remaining)}"
+                        )
+                    else:
+                        logger.info(
+                            f"[{total_processed}/{len(codes)}] ✗ {tournament_code}: {result.get('error', 'unknown')} | "
+                            f"Rate: {actual_rate:.2f}/s"
+                        )
 
-    elapsed = time.time() - start_time
-    logger.info("Done: %d tournaments in %s", success_count, format_duration(elapsed))
-    return 0
+            if not verbose and (
+                total_processed % 50 == 0 or total_processed == len(codes)
+            ):
+                actual_rate = total_processed / elapsed if elapsed > 0 else 0
+                logger.info(
+                    f"Progress: {total_processed}/{len(codes)} "
+                    f"({success_count}✓ {error_count}✗ "
+                    f"{len(skipped_reports)} skipped) | "
+                    f"Actual: {actual_rate:.2f}/s | "
+                    f"Elapsed: {format_duration(elapsed)} | Est: {format_duration(est_remaining)}"
+                )
+
+        if circuit_gave_up:
+            # Record the rest as failed so retry_failed.py picks them up
+            for tournament_code in dict.fromkeys(pass_queue[queue_index:]):
+                error_count += 1
+                all_results.append(
+                    {
+                        "tournament_code": tournament_code,
+                        "success": False,
+                        "error": "circuit open: FIDE unavailable",
+                    }
+                )
+            break
+
+        current_tournaments = pass_failed
+
+    if pbar:
+        pbar.close()
+
+    if raw_base and raw_accumulator:
+        from raw_utils import build_concatenated_gzip
+
+        raw_path = raw_base + ".html.gz"
+        _write_to_path(raw_path, build_concatenated_gzip(raw_accumulator))
+        logger.info(
+            "Saved concatenated raw HTML (%d tournaments) to %s",
+            len(raw_accumulator),
+            raw_path,
+        )
+
+    _save_outputs()
+
+    total_time = time.time() - start_time
+    n_processed = success_count + error_count + len(skipped_reports)
+    final_rate = n_processed / total_time if total_time > 0 else 0
+
+    logger.info("\nFinal Summary:")
+    logger.info(f"  Total: {len(codes)}")
+    logger.info(
+        f"  Success: {success_count} ({100.0 * success_count / len(codes):.1f}%)"
+    )
+    logger.info(f"  Skipped (no usable report): {len(skipped_reports)}")
+    logger.info(f"  Errors: {error_count}")
+    final = latest_results(all_results, "tournament_code")
+    failure_groups = group_failures(final, "tournament_code")
+    if failure_groups:
+        logger.info("  Tournaments with no successful report, by class:")
+        for line in format_failure_groups(failure_groups, use_color(sys.stderr)):
+            logger.info(line)
+    partial = partial_parse_entries(all_results)
+    if partial:
+        n_skipped = sum(len(p["skipped_rows"]) for p in partial)
+        logger.info(
+            f"  Partial parses: {len(partial)} tournaments ({n_skipped} rows skipped)"
+        )
+    if total_retries > 0:
+        logger.info(f"  Retries: {total_retries}")
+    logger.info(f"  Time: {format_duration(total_time)}")
+    logger.info(f"  Average rate: {final_rate:.2f} tournaments/sec")
+    logger.info(f"  Players Parquet: {players_path}")
+    logger.info(f"  Games Parquet: {games_path}")
+    if output_sample_json:
+        logger.info(f"  JSON sample: {output_sample_json}")
+    if output_sample_csv:
+        logger.info(f"  CSV sample: {output_sample_csv}")
+
+    n_final_success = sum(1 for r in final if r.get("success"))
+    n_final_failed = sum(
+        1 for r in final if not r.get("success") and not r.get("skipped")
+    )
+    exit_code = exit_code_for(n_final_success, n_final_failed)
+    write_summary(
+        build_summary(
+            "tournament_reports",
+            exit_code,
+            final,
+            outputs=outputs,
+            key="tournament_code",
+        ),
+        summary_file,
+    )
+    logger.info(f"  Summary: {summary_file}")
+
+    # Verbose error analysis
+    if verbose_errors and attempt_counts:
+        dist = Counter(n for _, n in attempt_counts)
+        retried = [(code, n) for code, n in attempt_counts if n > 1]
+        error_counts = Counter(e.get("error", "unknown") for e in attempt_log)
+        logger.info("\nVerbose Error Analysis:")
+        logger.info("  Attempt distribution: %s", dict(sorted(dist.items())))
+        if retried:
+            retried_codes = [c for c, _ in retried]
+            max_show = 30
+            if len(retried_codes) <= max_show:
+                logger.info(
+                    "  Tournaments needing retries (in order): %s", retried_codes
+                )
+            else:
+                logger.info(
+                    "  Tournaments needing retries (first %d): %s ... and %d more",
+                    max_show,
+                    retried_codes[:max_show],
+                    len(retried_codes) - max_show,
+                )
+        if error_counts:
+            logger.info("  Error breakdown: %s", dict(error_counts))
+
+    return exit_code
 
 
 def main():
@@ -1621,7 +2288,10 @@ def main():
         formatter_class=argparse.RawDescriptionHelpFormatter,
     )
     parser.add_argument(
-        "--input", type=str, default="", help="Path to tournament codes file"
+        "--input",
+        type=str,
+        default="",
+        help="Path to tournament codes file, or - for stdin (needs --output)",
     )
     parser.add_argument("--year", type=int, default=0, help="Year to process")
     parser.add_argument("--month", type=int, default=0, help="Month to process")
@@ -1660,6 +2330,72 @@ def main():
         default=50,
         help="Save every N tournaments (default: 50)",
     )
+    parser.add_argument(
+        "--checkpoint-interval",
+        type=parse_duration,
+        default=0.0,
+        metavar="DURATION",
+        help="Also save a checkpoint every DURATION (e.g. 2m, 90s), even when "
+        "requests are failing (default: off)",
+    )
+    parser.add_argument(
+        "--checkpoint-keep",
+        type=int,
+        default=DEFAULT_KEEP,
+        metavar="K",
+        help="Keep the last K checkpoints, rotated and gzipped "
+        f"(default: {DEFAULT_KEEP})",
+    )
+    parser.add_argument(
+        "--alert-command",
+        default=None,
+        help="Command run with an alert message as its last argument when the "
+        "disk fills up (scraping pauses) and when writes resume",
+    )
+    parser.add_argument(
+        "--min-free-mb",
+        type=int,
+        default=disk_guard.DEFAULT_MIN_FREE_BYTES // (1024 * 1024),
+        help="After a disk-full error, resume once this many MiB are free "
+        "(default: 512)",
+    )
+    parser.add_argument(
+        "--user-agents",
+        default=None,
+        metavar="FILE",
+        help="JSON list of browser identities (User-Agent plus matching headers) "
+        "to pick from instead of the built-in pool",
+    )
+    parser.add_argument(
+        "--worker-id",
+        type=int,
+        default=0,
+        help="Worker id; selects this process's identity from the pool, so "
+        "parallel workers present different user agents (default: 0)",
+    )
+    http_timeouts.add_arguments(parser)
+    circuit_breaker.add_arguments(parser)
+    live_config.add_arguments(parser)
+    tracing.add_arguments(parser)
+    parser.add_argument(
+        "--anomaly-pause",
+        type=parse_duration,
+        default=anomaly.DEFAULT_PAUSE_SECONDS,
+        metavar="DURATION",
+        help="After a run of empty/shrunken responses (e.g. stub pages), alert, "
+        "save the last response and pause this long (default: 10m)",
+    )
+    parser.add_argument(
+        "--anomaly-dir",
+        default=str(anomaly.DEFAULT_SNAPSHOT_DIR),
+        help="Directory for anomalous response snapshots "
+        f"(default: {anomaly.DEFAULT_SNAPSHOT_DIR})",
+    )
+    parser.add_argument(
+        "--no-anomaly-check",
+        action="store_true",
+        help="Disable response size/structure anomaly detection",
+    )
     parser.add_argument(
         "--show-time", action="store_true", help="Show timing info for each tournament"
     )
@@ -1672,7 +2408,7 @@ def main():
         "--details-path",
         type=str,
         default="",
-        help="Path to tournament_details parquet (for date inference; optional, improves round date format accuracy)",
+        help="Path to tournament_details parquet (for date inference and report links; optional, improves round date format accuracy)",
     )
     parser.add_argument(
         "--no-samples",
@@ -1706,57 +2442,65 @@ def main():
         action="store_true",
         help="Overwrite existing output if it exists",
     )
+    parser.add_argument(
+        "--summary",
+        default=None,
+        help="Path for the final summary JSON (default: {output base}_summary.json)",
+    )
 
     args = parser.parse_args()
+    redact.install()
+
+    if args.checkpoint_keep < 1:
+        logger.error("Error: --checkpoint-keep must be >= 1")
+        sys.exit(EXIT_FATAL)
+    disk_guard.configure(
+        alert_command=args.alert_command, min_free_bytes=args.min_free_mb * 1024 * 1024
+    )
+    try:
+        user_agents.configure(
+            user_agents.load_identities(args.user_agents) if args.user_agents else None,
+            worker=args.worker_id,
+        )
+    except (OSError, ValueError) as e:
+        logger.error("Error loading --user-agents: %s", e)
+        sys.exit(EXIT_FATAL)
+    try:
+        http_timeouts.configure_from_args(args)
+    except ValueError as e:
+        logger.error("Error: %s", e)
+        sys.exit(EXIT_FATAL)
+    tracing.configure_from_args(args, "tournament_reports")
+    if not args.no_anomaly_check:
+        anomaly.configure(
+            "reports", pause_seconds=args.anomaly_pause, snapshot_dir=args.anomaly_dir
+        )
 
     # Repo root for default paths
     _script_dir = Path(__file__).resolve().parent
     repo_root = _script_dir.parent.parent
 
-    # Determine input path and read tournament codes; build details_map for date inference
-    tournament_codes = []
-    details_map: Dict[str, Tuple[Optional[str], Optional[str]]] = {}
-
+    # Determine input path and the details Parquet used for date inference
+    details_path = args.details_path or None
     if args.input:
         input_path = args.input
         from s3_io import output_exists as _path_exists
 
-        if not _path_exists(input_path):
+        if input_path != STDIN_INPUT and not _path_exists(input_path):
             logger.error(
                 "Tournament codes file not found: %s. Run get_tournament_details first.",
                 input_path,
             )
-            sys.exit(1)
-        try:
-            tournament_codes = read_tournament_codes(input_path)
-        except Exception as e:
-            logger.error(f"Error reading codes: {e}")
-            sys.exit(1)
-        # Optionally load details for date inference
-        if args.details_path and os.path.exists(args.details_path):
-            try:
-                df = pd.read_parquet(args.details_path)
-                ec_col = "event_code" if "event_code" in df.columns else "id"
-                for _, row in df.iterrows():
-                    ec = row.get(ec_col)
-                    if pd.notna(ec) and str(ec):
-                        sd = parse_details_date_to_iso(str(row.get("start_date", "")))
-                        ed = parse_details_date_to_iso(str(row.get("end_date", "")))
-                        details_map[str(ec)] = (sd, ed)
-                logger.info(
-                    f"Loaded date bounds for {len(details_map)} tournaments from {args.details_path}"
-                )
-            except Exception as e:
-                logger.warning(f"Could not load details for date inference: {e}")
+            sys.exit(EXIT_FATAL)
     elif args.year > 0 and args.month > 0:
         if args.month < 1 or args.month > 12:
             logger.error("Error: month must be 1-12")
-            sys.exit(1)
+            sys.exit(EXIT_FATAL)
         run_name = args.run_name or f"{args.year}-{args.month:02d}"
         if args.run_type:
             from s3_io import build_local_path_for_run
 
-            ids_path = str(
+            input_path = str(
                 build_local_path_for_run(
                     args.local_root,
                     args.run_type,
@@ -1765,92 +2509,49 @@ def main():
                     "tournament_ids.txt",
                 )
             )
-            details_path = (
-                args.details_path
-                if args.details_path
-                else str(
-                    build_local_path_for_run(
-                        args.local_root,
-                        args.run_type,
-                        run_name,
-                        "data",
-                        "tournament_details.parquet",
-                    )
+            details_path = details_path or str(
+                build_local_path_for_run(
+                    args.local_root,
+                    args.run_type,
+                    run_name,
+                    "data",
+                    "tournament_details.parquet",
                 )
             )
         else:
-            ids_path = os.path.join(
+            input_path = os.path.join(
                 args.data_dir, "tournament_ids", f"{args.year}_{args.month:02d}"
             )
-            details_path = (
-                args.details_path
-                if args.details_path
-                else os.path.join(
-                    args.data_dir,
-                    "tournament_details",
-                    f"{args.year}_{args.month:02d}.parquet",
-                )
+            details_path = details_path or os.path.join(
+                args.data_dir,
+                "tournament_details",
+                f"{args.year}_{args.month:02d}.parquet",
             )
-        if not os.path.exists(ids_path):
-            logger.error(f"Error: tournament IDs file not found: {ids_path}")
+        if not os.path.exists(input_path):
+            logger.error(f"Error: tournament IDs file not found: {input_path}")
             logger.error("Run get_tournaments.py first for --year/--month")
             logger.error("Alternatively use --input with a codes file")
-            sys.exit(1)
-        try:
-            tournament_codes = read_tournament_codes(ids_path)
-            logger.info(
-                f"Loaded {len(tournament_codes)} tournament codes from {ids_path}"
-            )
-        except Exception as e:
-            logger.error(f"Error reading tournament IDs: {e}")
-            sys.exit(1)
-        # Optionally load tournament_details for date inference (start/end) when available
-        if os.path.exists(details_path):
-            try:
-                df = pd.read_parquet(details_path)
-                ec_col = "event_code" if "event_code" in df.columns else "id"
-                success_df = df[df["success"] == True]
-                for _, row in success_df.iterrows():
-                    ec = row.get(ec_col)
-                    if pd.notna(ec) and str(ec):
-                        sd = parse_details_date_to_iso(str(row.get("start_date", "")))
-                        ed = parse_details_date_to_iso(str(row.get("end_date", "")))
-                        details_map[str(ec)] = (sd, ed)
-                if details_map:
-                    logger.info(
-                        f"Loaded date bounds for {len(details_map)} tournaments from {details_path} (for date format inference)"
-                    )
-            except Exception as e:
-                logger.warning(f"Could not load details for date inference: {e}")
+            sys.exit(EXIT_FATAL)
     else:
         logger.error("Error: specify --input or --year and --month")
-        sys.exit(1)
-
-    if not tournament_codes:
-        logger.error("No tournament codes found")
-        sys.exit(1)
-
-    if args.limit > 0:
-        tournament_codes = tournament_codes[: args.limit]
-        logger.info(f"Limited to first {len(tournament_codes)} tournaments")
+        sys.exit(EXIT_FATAL)
 
     # Determine output paths: players and games (2 files)
-    players_path = None
-    games_path = None
+    output_base = None
     json_path = None
     csv_path = None
+    summary_base = None
     if args.output:
-        base = args.output.replace(".json", "").replace(".parquet", "")
-        players_path = base + "_players.parquet"
-        games_path = base + "_games.parquet"
-        json_path = base + "_sample.json"
-        csv_path = base + "_sample.csv"
+        output_base = args.output.replace(".json", "").replace(".parquet", "")
+        summary_base = output_base
+        json_path = output_base + "_sample.json"
+        csv_path = output_base + "_sample.csv"
     elif args.year > 0 and args.month > 0:
         if args.run_type:
             from s3_io import build_local_path_for_run
 
             run_name = args.run_name or f"{args.year}-{args.month:02d}"
-            data_base = str(
+            output_base = str(
                 build_local_path_for_run(
                     args.local_root,
                     args.run_type,
@@ -1868,328 +2569,79 @@ def main():
                     "tournament_reports",
                 )
             )
-            players_path = data_base + "_players.parquet"
-            games_path = data_base + "_games.parquet"
+            summary_base = str(
+                build_local_path_for_run(
+                    args.local_root,
+                    args.run_type,
+                    run_name,
+                    "reports",
+                    "tournament_reports",
+                )
+            )
             json_path = sample_base + "_verbose_sample.json"
             csv_path = sample_base + "_games_sample.csv"
         else:
-            base_path = os.path.join(
+            output_base = os.path.join(
                 args.data_dir, "tournament_reports", f"{args.year}_{args.month:02d}"
             )
-            players_path = base_path + "_players.parquet"
-            games_path = base_path + "_games.parquet"
-            json_path = base_path + "_sample.json"
-            csv_path = base_path + "_sample.csv"
+            summary_base = output_base
+            json_path = output_base + "_sample.json"
+            csv_path = output_base + "_sample.csv"
 
-    if games_path and not args.override and os.path.exists(games_path):
+    if output_base is None:
+        logger.error("Error: --input needs --output (or --year and --month)")
+        sys.exit(EXIT_FATAL)
+
+    games_path = output_base + "_games.parquet"
+    if not args.override and os.path.exists(games_path):
         logger.info("Output %s already exists. Use --override to replace.", games_path)
         sys.exit(0)
 
-    # Load players file for validation (name/country, pairing checks)
-    players_df: Optional[pd.DataFrame] = None
-    if not args.no_validation:
-        if args.players_file:
-            players_file_path = args.players_file
-        elif args.run_type and args.year > 0 and args.month > 0:
-            from s3_io import resolve_latest_players_list_local
-
-            latest = resolve_latest_players_list_local(repo_root / args.local_root)
-            players_file_path = (
-                str(latest)
-                if latest
-                else str(repo_root / "src" / "data" / "players_list.parquet")
-            )
-        else:
-            players_file_path = str(repo_root / "src" / "data" / "players_list.parquet")
-        if os.path.exists(players_file_path):
-            try:
-                players_df = pd.read_parquet(players_file_path)
-                logger.info(
-                    "Loaded players file for validation: %s (%d rows)",
-                    players_file_path,
-                    len(players_df),
-                )
-            except Exception as e:
-                logger.warning("Could not load players file for validation: %s", e)
-        else:
-            logger.info(
-                "Players file not found at %s; skipping validation",
-                players_file_path,
-            )
-
-    logger.info(f"Processing {len(tournament_codes)} tournaments")
-    logger.info(
-        f"Settings: checkpoint every {args.checkpoint} (no rate limit - natural throughput)"
-    )
-
-    start_time = time.time()
-
-    session = requests.Session()
-    adapter = requests.adapters.HTTPAdapter(
-        pool_connections=1, pool_maxsize=1, max_retries=0
-    )
-    session.mount("http://", adapter)
-    session.mount("https://", adapter)
-
-    all_results: List[Dict] = []
-    success_count = 0
-    error_count = 0
-    total_retries = 0
-    attempt_log: List[Dict] = [] if args.verbose_errors else []
-
-    def _graceful_shutdown(signum, frame):
-        logger.warning("\nReceived interrupt, initiating graceful shutdown...")
-        if all_results and games_path:
-            try:
-                if players_path:
-                    save_players_parquet(all_results, players_path)
-                save_games_parquet(all_results, games_path, details_map=details_map)
-                if not args.no_samples and json_path:
-                    save_verbose_json_sample(
-                        all_results, json_path, sample_size=100, details_map=details_map
-                    )
-                if not args.no_samples and csv_path:
-                    save_csv_sample_from_parquet(games_path, csv_path, sample_size=100)
-                logger.info("Saved %d results to %s", len(all_results), games_path)
-            except Exception as e:
-                logger.error("Error saving partial results: %s", e)
-        sys.exit(130 if signum == 2 else 0)
-
-    signal.signal(signal.SIGINT, _graceful_shutdown)
-    signal.signal(signal.SIGTERM, _graceful_shutdown)
-    attempt_counts: List[Tuple[str, int]] = [] if args.verbose_errors else []
-    current_tournaments = tournament_codes
-
-    pbar = None
-    if not args.verbose:
-        pbar = tqdm(
-            total=len(tournament_codes),
-            desc="Processing",
-            unit="tournament",
-            bar_format="{l_bar}{bar}| {n_fmt}/{total_fmt} [{elapsed}<{remaining}, {rate_fmt}]",
+    # Players file for validation (name/country, pairing checks)
+    if args.players_file:
+        players_file_path = args.players_file
+    elif args.run_type and args.year > 0 and args.month > 0:
+        from s3_io import resolve_latest_players_list_local
+
+        latest = resolve_latest_players_list_local(repo_root / args.local_root)
+        players_file_path = (
+            str(latest)
+            if latest
+            else str(repo_root / "src" / "data" / "players_list.parquet")
         )
-
-    for pass_num in range(args.max_retries + 1):
-        if not current_tournaments:
-            break
-        if pass_num > 0:
-            delay = 3 * (2 ** (pass_num - 1))  # Exponential backoff: 3s, 6s, 12s
-            logger.info(
-                f"Retry pass {pass_num}: waiting {format_duration(delay)} before retrying {len(current_tournaments)} tournaments"
-            )
-            time.sleep(delay)
-            total_retries += len(current_tournaments)
-
-        pass_failed = []
-
-        for tournament_code in current_tournaments:
-            report, error, num_attempts, _ = fetch_tournament_report(
-                tournament_code,
-                session,
-                _attempt_log=attempt_log if args.verbose_errors else None,
-            )
-            if args.verbose_errors:
-                attempt_counts.append((tournament_code, num_attempts))
-
-            result = {"tournament_code": tournament_code}
-
-            if report is None:
-                error_count += 1
-                result["success"] = False
-                result["error"] = error or "fetch failed"
-                error_lower = error.lower() if error else ""
-                network_error_patterns = [
-                    "eof",
-                    "connection reset",
-                    "connection aborted",
-                    "remotedisconnected",
-                    "remote end closed",
-                    "broken pipe",
-                ]
-                is_network_error = any(p in error_lower for p in network_error_patterns)
-                if error and (is_network_error or "timeout" in error_lower):
-                    if pass_num < args.max_retries:
-                        pass_failed.append(tournament_code)
-            else:
-                success_count += 1
-                result["success"] = True
-                result.update(report)
-                if not args.no_validation:
-                    validate_pairings(result)
-                    if players_df is not None:
-                        validate_against_players_file(result, players_df)
-                if args.checkpoint > 0 and success_count % args.checkpoint == 0:
-                    checkpoint_path = games_path + ".checkpoint" if games_path else None
-                    logger.info(f"Saving checkpoint at {success_count} successful...")
-                    save_checkpoint(
-                        games_path,
-                        all_results,
-                        checkpoint_path,
-                        details_map=details_map,
-                    )
-
-            all_results.append(result)
-
-            total_processed = success_count + error_count
-            elapsed = time.time() - start_time
-
-            if total_processed > 0:
-                avg_time = elapsed / total_processed
-                remaining = len(tournament_codes) - total_processed
-                est_remaining = avg_time * remaining
-            else:
-                est_remaining = 0
-
-            if args.verbose:
-                actual_rate = total_processed / elapsed if elapsed > 0 else 0
-                if result["success"]:
-                    num_players = len(result.get("players", []))
-                    retry_info = f" [Retry pass {pass_num + 1}]" if pass_num > 0 else ""
-                    http_retries = (
-                        f" [{num_attempts} HTTP attempts]" if num_attempts > 1 else ""
-                    )
-                    print(
-                        f"[{total_processed}/{len(tournament_codes)}] ✓ {tournament_code}: {num_players} players{retry_info}{http_retries} | "
-                        f"Actual: {actual_rate:.2f}/s | "
-                        f"Elapsed: {format_duration(elapsed)} | Est: {format_duration(est_remaining)} | "
-                        f"Success: {success_count} | Errors: {error_count} | Retries: {total_retries}"
-                    )
-                else:
-                    error_msg = result.get("error", "unknown")
-                    will_retry = tournament_code in pass_failed
-                    retry_info = f" [Retry pass {pass_num + 1}]" if pass_num > 0 else ""
-                    http_retries = (
-                        f" [{num_attempts} HTTP attempts]" if num_attempts > 1 else ""
-                    )
-                    retry_status = " [WILL RETRY]" if will_retry else " [FINAL FAILURE]"
-                    print(
-                        f"[{total_processed}/{len(tournament_codes)}] ✗ {tournament_code}: {error_msg}{retry_info}{http_retries}{retry_status} | "
-                        f"Actual: {actual_rate:.2f}/s | "
-                        f"Elapsed: {format_duration(elapsed)} | Est: {format_duration(est_remaining)} | "
-                        f"Success: {success_count} | Errors: {error_count} | Retries: {total_retries}"
-                    )
-            else:
-                postfix_dict = {
-                    "✓": success_count,
-                    "✗": error_count,
-                    "rate": f"{total_processed / elapsed if elapsed > 0 else 0:.2f}/s",
-                }
-                if total_retries > 0 or pass_num > 0:
-                    postfix_dict["retries"] = total_retries
-                if pass_num > 0:
-                    postfix_dict["pass"] = f"{pass_num + 1}/{args.max_retries + 1}"
-                if len(pass_failed) > 0:
-                    postfix_dict["pending"] = len(pass_failed)
-                postfix_dict["est"] = (
-                    format_duration(est_remaining) if est_remaining > 0 else "?"
-                )
-                if pbar:
-                    pbar.update(1)
-                    pbar.set_postfix(postfix_dict)
-
-                if args.show_time:
-                    actual_rate = total_processed / elapsed if elapsed > 0 else 0
-                    if result["success"]:
-                        num_players = len(result.get("players", []))
-                        logger.info(
-                            f"[{total_processed}/{len(tournament_codes)}] ✓ {tournament_code}: {num_players} players | "
-                            f"Rate: {actual_rate:.2f}/s | Est: {format_duration(est_remaining)}"
-                        )
-                    else:
-                        logger.info(
-                            f"[{total_processed}/{len(tournament_codes)}] ✗ {tournament_code}: {result.get('error', 'unknown')} | "
-                            f"Rate: {actual_rate:.2f}/s"
-                        )
-
-            if not args.verbose and (
-                total_processed % 50 == 0 or total_processed == len(tournament_codes)
-            ):
-                actual_rate = total_processed / elapsed if elapsed > 0 else 0
-                logger.info(
-                    f"Progress: {total_processed}/{len(tournament_codes)} "
-                    f"({success_count}✓ {error_count}✗) | "
-                    f"Actual: {actual_rate:.2f}/s | "
-                    f"Elapsed: {format_duration(elapsed)} | Est: {format_duration(est_remaining)}"
-                )
-
-        current_tournaments = pass_failed
-
-    if pbar:
-        pbar.close()
-
-    # Save final results
-    if players_path and games_path:
-        save_players_parquet(all_results, players_path)
-        save_games_parquet(all_results, games_path, details_map=details_map)
-
-        if not args.no_samples:
-            if csv_path:
-                save_csv_sample_from_parquet(games_path, csv_path, sample_size=100)
-            if json_path:
-                save_verbose_json_sample(
-                    all_results, json_path, sample_size=100, details_map=details_map
-                )
     else:
-        # If no output path specified, dump to stdout as JSON (for backwards compatibility)
-        json.dump(all_results, sys.stdout, indent=2, ensure_ascii=False)
-
-    total_time = time.time() - start_time
-    final_rate = (success_count + error_count) / total_time if total_time > 0 else 0
+        players_file_path = str(repo_root / "src" / "data" / "players_list.parquet")
 
-    logger.info("\nFinal Summary:")
-    logger.info(f"  Total: {len(tournament_codes)}")
     logger.info(
-        f"  Success: {success_count} ({100.0 * success_count / len(tournament_codes):.1f}%)"
+        f"Settings: checkpoint every {args.checkpoint} (no rate limit - natural throughput)"
     )
-    logger.info(f"  Errors: {error_count}")
-    if total_retries > 0:
-        logger.info(f"  Retries: {total_retries}")
-    logger.info(f"  Time: {format_duration(total_time)}")
-    logger.info(f"  Average rate: {final_rate:.2f} tournaments/sec")
-
-    # Report tournaments with no successful fetch (orphaned / failed)
-    failed_codes = [
-        r.get("tournament_code", "") for r in all_results if not r.get("success", False)
-    ]
-    if failed_codes:
-        max_show = 20
-        sample = failed_codes[:max_show]
-        logger.info(
-            "  Tournaments with no successful report: %d (%s%s)",
-            len(failed_codes),
-            ", ".join(sample),
-            " ..." if len(failed_codes) > max_show else "",
+    sys.exit(
+        run(
+            input_path,
+            output_base,
+            details_path=details_path,
+            # No limit unless set through --control-file
+            rate_limit=0,
+            limit=args.limit,
+            save_raw=False,
+            output_sample_json=None if args.no_samples else json_path,
+            output_sample_csv=None if args.no_samples else csv_path,
+            output_reports_base=summary_base,
+            max_retries=args.max_retries,
+            checkpoint=args.checkpoint,
+            checkpoint_interval=args.checkpoint_interval,
+            checkpoint_keep=args.checkpoint_keep,
+            summary_file=args.summary,
+            players_file=players_file_path,
+            validate=not args.no_validation,
+            breaker=circuit_breaker.from_args("reports", args),
+            control_file=args.control_file,
+            verbose=args.verbose,
+            show_time=args.show_time,
+            verbose_errors=args.verbose_errors,
+            handle_signals=True,
         )
-    if players_path:
-        logger.info(f"  Players Parquet: {players_path}")
-    if games_path:
-        logger.info(f"  Games Parquet: {games_path}")
-    if not args.no_samples and json_path:
-        logger.info(f"  JSON sample: {json_path}")
-    if not args.no_samples and csv_path:
-        logger.info(f"  CSV sample: {csv_path}")
-
-    # Verbose error analysis
-    if args.verbose_errors and attempt_counts:
-        dist = Counter(n for _, n in attempt_counts)
-        retried = [(code, n) for code, n in attempt_counts if n > 1]
-        error_counts = Counter(e.get("error", "unknown") for e in attempt_log)
-        logger.info("\nVerbose Error Analysis:")
-        logger.info("  Attempt distribution: %s", dict(sorted(dist.items())))
-        if retried:
-            codes = [c for c, _ in retried]
-            max_show = 30
-            if len(codes) <= max_show:
-                logger.info("  Tournaments needing retries (in order): %s", codes)
-            else:
-                logger.info(
-                    "  Tournaments needing retries (first %d): %s ... and %d more",
-                    max_show,
-                    codes[:max_show],
-                    len(codes) - max_show,
-                )
-        if error_counts:
-            logger.info("  Error breakdown: %s", dict(error_counts))
+    )
 
 
 if __name__ == "__main__":
@@ -23,6 +23,8 @@ from typing import Any, List, Optional, Tuple, Union
 
 import aiohttp
 
+import redact
+import user_agents
 from raw_utils import build_concatenated_gzip
 from s3_io import (
     build_local_path_for_run,
@@ -79,6 +81,11 @@ class Tournament:
     federation: str
 
 
+def _eprint(*args: Any) -> None:
+    """Print a human-readable summary line to stderr; stdout is left for data."""
+    print(*args, file=sys.stderr)
+
+
 def format_time(seconds: float) -> str:
     """
     Format time in seconds to a human-readable string.
@@ -236,11 +243,13 @@ async def fetch_federation_tournaments(
     period = f"{year}-{month:02d}-01"
     url = f"{TOURNAMENTS_URL}?country={code}&period={period}"
 
-    # Simple headers that work with curl - no need for Referer or Origin
-    headers = {
-        "X-Requested-With": "XMLHttpRequest",
-        "Accept": "application/json, text/javascript, */*; q=0.01",
-    }
+    # The worker's browser identity plus the XHR headers; no Referer or Origin
+    headers = user_agents.browser_headers(
+        {
+            "X-Requested-With": "XMLHttpRequest",
+            "Accept": "application/json, text/javascript, */*; q=0.01",
+        }
+    )
 
     for attempt in range(max_retries):
         if _shutdown_requested:
@@ -329,10 +338,7 @@ async def fetch_available_periods(
         List of period dictionaries with 'num1', 'frl_publish', 'txt2' keys.
     """
     url = f"{PERIODS_URL}?country={code}&periods_tab=1"
-    headers = {
-        "X-Requested-With": "XMLHttpRequest",
-        "User-Agent": "Mozilla/5.0 (compatible; FIDE-Scraper/1.0)",
-    }
+    headers = user_agents.browser_headers({"X-Requested-With": "XMLHttpRequest"})
 
     try:
         async with session.get(
@@ -433,20 +439,20 @@ def graceful_shutdown(signum: int, frame) -> None:
 
     # Print summary
     elapsed_time = time.time() - processing_start_time
-    print("\n" + "=" * 80)
-    print("Graceful Shutdown Summary:")
-    print(f"  Federations processed: {processed_count}/{total_federations}")
-    print(f"  Tournament IDs collected: {len(all_tournaments)}")
-    print(f"  Unique tournament IDs: {len(unique_tournaments)}")
-    print(f"  Time elapsed: {format_time(elapsed_time)}")
+    _eprint("\n" + "=" * 80)
+    _eprint("Graceful Shutdown Summary:")
+    _eprint(f"  Federations processed: {processed_count}/{total_federations}")
+    _eprint(f"  Tournament IDs collected: {len(all_tournaments)}")
+    _eprint(f"  Unique tournament IDs: {len(unique_tournaments)}")
+    _eprint(f"  Time elapsed: {format_time(elapsed_time)}")
     if output_path:
         json_uri_shutdown = _shutdown_state.get("json_uri")
         if is_s3_path(str(output_path)):
             json_uri_disp = json_uri_shutdown or _json_uri_from_ids_uri(
                 str(output_path)
             )
-            print(f"  IDs file: {output_path}")
-            print(f"  JSON file: {json_uri_disp}")
+            _eprint(f"  IDs file: {output_path}")
+            _eprint(f"  JSON file: {json_uri_disp}")
         else:
             ids_path = Path(output_path)
             if json_uri_shutdown:
@@ -457,11 +463,11 @@ def graceful_shutdown(signum: int, frame) -> None:
                 )
                 if json_path.suffix != ".json":
                     json_path = json_path.with_suffix(".json")
-            print(f"  IDs file: {ids_path}")
-            print(f"  JSON file: {json_path}")
+            _eprint(f"  IDs file: {ids_path}")
+            _eprint(f"  JSON file: {json_path}")
     if log_entries and log_path:
-        print(f"  Log saved to: {log_path}")
-    print("=" * 80)
+        _eprint(f"  Log saved to: {log_path}")
+    _eprint("=" * 80)
 
     sys.exit(0)
 
@@ -752,20 +758,20 @@ async def scrape_month(
         tc_counts[t.time_control] = tc_counts.get(t.time_control, 0) + 1
 
     # Summary
-    print("\n" + "=" * 80)
-    print("Summary:")
-    print(
+    _eprint("\n" + "=" * 80)
+    _eprint("Summary:")
+    _eprint(
         f"  Federations processed: {len(federations) - len(errors)}/{len(federations)}"
     )
-    print(f"  Errors: {len(errors)}")
-    print(f"  Total tournaments: {len(all_tournaments)}")
-    print(f"  Unique tournaments: {len(unique_tournaments)}")
-    print(f"  Time taken: {format_time(elapsed)}")
-    print(f"  IDs file: {ids_path}")
-    print(f"  JSON file: {json_path}")
+    _eprint(f"  Errors: {len(errors)}")
+    _eprint(f"  Total tournaments: {len(all_tournaments)}")
+    _eprint(f"  Unique tournaments: {len(unique_tournaments)}")
+    _eprint(f"  Time taken: {format_time(elapsed)}")
+    _eprint(f"  IDs file: {ids_path}")
+    _eprint(f"  JSON file: {json_path}")
     if tc_counts:
-        print(f"  By time control: {tc_counts}")
-    print("=" * 80)
+        _eprint(f"  By time control: {tc_counts}")
+    _eprint("=" * 80)
 
     rem_end = _lambda_remaining_ms(lambda_context)
     logger.info(
@@ -984,6 +990,7 @@ def main() -> int:
     )
 
     args = parser.parse_args()
+    redact.install()
 
     # Validate month
     if args.month < 1 or args.month > 12:
@@ -0,0 +1,115 @@
+"""
+Timeouts for FIDE page requests, shared by the details and reports scrapers.
+
+Three limits, set once per process with configure() (CLI flags from
+add_arguments(), Lambda event keys via from_mapping()):
+
+  connect   seconds to open the connection (default 15)
+  read      seconds to wait for the response headers, and then between body
+            bytes (default 45)
+  total     seconds for the whole request including the body (default: off)
+
+The short connect timeout fails fast when FIDE is unreachable (the scrapers abort
+a chunk after repeated connect timeouts); raise read or total on slow FIDE days
+instead of letting pages that are merely slow fail. total catches responses that
+trickle in without ever pausing longer than the read timeout.
+"""
+
+import argparse
+import time
+from typing import Mapping, Optional, Tuple
+
+import requests
+
+DEFAULT_CONNECT = 15.0
+DEFAULT_READ = 45.0
+_CHUNK_SIZE = 64 * 1024
+
+_config = {"connect": DEFAULT_CONNECT, "read": DEFAULT_READ, "total": None}
+
+
+def configure(
+    connect: Optional[float] = None,
+    read: Optional[float] = None,
+    total: Optional[float] = None,
+) -> None:
+    """Set the limits in seconds (None: default; total None: no total limit)."""
+    for name, value in (("connect", connect), ("read", read), ("total", total)):
+        if value is not None and value <= 0:
+            raise ValueError(f"{name} timeout must be > 0, got {value}")
+    _config["connect"] = DEFAULT_CONNECT if connect is None else float(connect)
+    _config["read"] = DEFAULT_READ if read is None else float(read)
+    _config["total"] = None if total is None else float(total)
+
+
+def requests_timeout() -> Tuple[float, float]:
+    """(connect, read) tuple for requests' timeout argument."""
+    return _config["connect"], _config["read"]
+
+
+def add_arguments(parser: argparse.ArgumentParser) -> None:
+    """Add --connect-timeout, --read-timeout and --total-timeout to parser."""
+    parser.add_argument(
+        "--connect-timeout",
+        type=float,
+        default=None,
+        help=f"Seconds to connect to FIDE (default: {DEFAULT_CONNECT:g})",
+    )
+    parser.add_argument(
+        "--read-timeout",
+        type=float,
+        default=None,
+        help="Seconds to wait for response headers and between body bytes "
+        f"(default: {DEFAULT_READ:g})",
+    )
+    parser.add_argument(
+        "--total-timeout",
+        type=float,
+        default=None,
+        help="Seconds for a whole request including the body (default: no limit)",
+    )
+
+
+def configure_from_args(args: argparse.Namespace) -> None:
+    """configure() from the add_arguments() flags."""
+    configure(args.connect_timeout, args.read_timeout, args.total_timeout)
+
+
+def from_mapping(event: Mapping) -> None:
+    """configure() from connect_timeout / read_timeout / total_timeout keys."""
+
+    def seconds(key: str) -> Optional[float]:
+        value = event.get(key)
+        if value is None:
+            return None
+        try:
+            return float(value)
+        except (TypeError, ValueError):
+            raise ValueError(f"{key} must be a number, got {value!r}") from None
+
+    configure(
+        seconds("connect_timeout"), seconds("read_timeout"), seconds("total_timeout")
+    )
+
+
+def get(session: requests.Session, url: str, **kwargs) -> requests.Response:
+    """
+    session.get(url) with the configured timeouts. With a total limit the body is
+    streamed and requests.Timeout is raised once the limit passes.
+    """
+    total = _config["total"]
+    if total is None:
+        return session.get(url, timeout=requests_timeout(), **kwargs)
+    deadline = time.monotonic() + total
+    response = session.get(url, timeout=requests_timeout(), stream=True, **kwargs)
+    chunks = []
+    try:
+        for chunk in response.iter_content(_CHUNK_SIZE):
+            if time.monotonic() > deadline:
+                raise requests.Timeout(f"total timeout: {url} took over {total:g}s")
+            chunks.append(chunk)
+    finally:
+        response.close()
+    # Same cache that response.content fills when the body is read in one go
+    response._content = b"".join(chunks)
+    return response
@@ -0,0 +1,212 @@
+#!/usr/bin/env python3
+"""
+Late-reported games audit: how many games per month arrive after the fact.
+
+FIDE often receives a tournament report weeks or months after the event ends.
+Under a played-month rating policy (rating_input.py --period played) those games
+land in periods that may already be rated, so the engine has to recompute them.
+This audit joins games to their tournament's details and, per month played (month
+of round_date, else the tournament's end date), writes:
+
+  month, games, late_games, late_share, tournaments, late_tournaments,
+  median_delay_days, max_delay_days, months_late_1, months_late_2, months_late_3+
+
+A game is late when its report was received more than --late-days (default 30,
+as in boundary_audit.py) after the tournament's end date and in a later month
+than the game was played. months_late_N counts late games by how many calendar
+months after the played month the report arrived. Delays are days from end date
+to date received, over tournaments with both dates. Games whose tournament has no
+details or no date_received are counted in games only.
+
+Output is CSV, or Parquet if --output ends in .parquet. --summary writes the
+totals as JSON.
+
+Usage:
+  uv run src/scraper/late_reports.py --local-root data \\
+    --output data/stats/late_reports.csv --summary data/stats/late_reports.json
+"""
+
+import argparse
+import json
+import logging
+import sys
+from pathlib import Path
+from typing import Dict, List
+
+import pandas as pd
+
+import redact
+from boundary_audit import DEFAULT_LATE_DAYS, find_details_files
+from player_activity import find_games_files
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+_GAME_COLUMNS = ["tournament_id", "round_date"]
+_LAG_BUCKETS = ["months_late_1", "months_late_2", "months_late_3+"]
+
+
+def _month_index(dates: pd.Series) -> pd.Series:
+    return dates.dt.year * 12 + dates.dt.month
+
+
+def game_delays(games: pd.DataFrame, details: pd.DataFrame) -> pd.DataFrame:
+    """
+    Per game: tournament_id, month (played), delay_days, months_late. delay_days
+    and months_late are NaN when the tournament's dates are unknown.
+    """
+    ec_col = "event_code" if "event_code" in details.columns else "id"
+    ok = details
+    if "success" in details.columns:
+        ok = details[details["success"].fillna(False).astype(bool)]
+    dates = pd.DataFrame(
+        {
+            "end_date": pd.to_datetime(ok["end_date"], errors="coerce", utc=True),
+            "date_received": pd.to_datetime(
+                ok["date_received"], errors="coerce", utc=True
+            ),
+        }
+    )
+    dates.index = ok[ec_col].astype(str)
+    dates = dates[~dates.index.duplicated()]
+
+    codes = games["tournament_id"].astype(str)
+    end = codes.map(dates["end_date"])
+    received = codes.map(dates["date_received"])
+    played = pd.to_datetime(games["round_date"], errors="coerce", utc=True)
+    played = played.where(played.notna(), end)
+
+    out = pd.DataFrame({"tournament_id": codes})
+    out["month"] = played.dt.strftime("%Y-%m")
+    out["delay_days"] = (received - end).dt.days
+    out["months_late"] = _month_index(received) - _month_index(played)
+    return out
+
+
+def audit(delays: pd.DataFrame, late_days: int = DEFAULT_LATE_DAYS) -> pd.DataFrame:
+    """One row per month played with late-report counts (see module docstring)."""
+    delays = delays.dropna(subset=["month"]).copy()
+    delays["late"] = (delays["delay_days"] > late_days) & (delays["months_late"] > 0)
+    rows = []
+    for month, group in delays.groupby("month"):
+        late = group[group["late"]]
+        per_tournament = group.drop_duplicates("tournament_id")
+        known = per_tournament["delay_days"].dropna()
+        lags = late["months_late"]
+        rows.append(
+            {
+                "month": month,
+                "games": len(group),
+                "late_games": len(late),
+                "late_share": round(len(late) / len(group), 4),
+                "tournaments": len(per_tournament),
+                "late_tournaments": late["tournament_id"].nunique(),
+                "median_delay_days": known.median() if len(known) else None,
+                "max_delay_days": known.max() if len(known) else None,
+                "months_late_1": int((lags == 1).sum()),
+                "months_late_2": int((lags == 2).sum()),
+                "months_late_3+": int((lags >= 3).sum()),
+            }
+        )
+    return pd.DataFrame(rows)
+
+
+def summarize(by_month: pd.DataFrame, late_days: int) -> Dict:
+    """Totals over all months."""
+    games = int(by_month["games"].sum()) if len(by_month) else 0
+    late = int(by_month["late_games"].sum()) if len(by_month) else 0
+    return {
+        "late_days": late_days,
+        "months": len(by_month),
+        "games": games,
+        "late_games": late,
+        "late_share": round(late / games, 4) if games else 0.0,
+        "by_months_late": {
+            b: int(by_month[b].sum()) if len(by_month) else 0 for b in _LAG_BUCKETS
+        },
+    }
+
+
+def _read_all(paths: List[Path], columns=None) -> pd.DataFrame:
+    return pd.concat(
+        [pd.read_parquet(p, columns=columns) for p in paths], ignore_index=True
+    )
+
+
+def _write(df: pd.DataFrame, path: str) -> None:
+    out = Path(path)
+    out.parent.mkdir(parents=True, exist_ok=True)
+    if out.suffix == ".parquet":
+        from provenance import dataframe_to_parquet_bytes
+
+        out.write_bytes(dataframe_to_parquet_bytes(df))
+    else:
+        df.to_csv(out, index=False)
+    logger.info("Saved %d months to %s", len(df), out)
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="Count late-reported games per month played",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    parser.add_argument("--local-root", help="Local bucket root (reads prod months)")
+    parser.add_argument("--games", nargs="+", help="Explicit games Parquet files")
+    parser.add_argument("--details", nargs="+", help="Explicit details Parquet files")
+    parser.add_argument("--output", required=True, help="CSV or .parquet output")
+    parser.add_argument("--summary", help="Optional JSON summary path")
+    parser.add_argument(
+        "--late-days",
+        type=int,
+        default=DEFAULT_LATE_DAYS,
+        help="Late when received more than this many days after the end date "
+        f"(default: {DEFAULT_LATE_DAYS})",
+    )
+    args = parser.parse_args()
+    redact.install()
+
+    if not args.local_root and not (args.games and args.details):
+        parser.error("pass --local-root, or both --games and --details")
+    games_paths = (
+        [Path(p) for p in args.games]
+        if args.games
+        else find_games_files(args.local_root)
+    )
+    details_paths = (
+        [Path(p) for p in args.details]
+        if args.details
+        else find_details_files(args.local_root)
+    )
+    if not games_paths or not details_paths:
+        logger.error("No games or details files found")
+        return 1
+    try:
+        games = _read_all(games_paths, _GAME_COLUMNS)
+        details = _read_all(details_paths)
+    except (OSError, ValueError) as e:
+        logger.error("%s", e)
+        return 1
+    logger.info(
+        "Loaded %d games and %d tournaments from %d + %d files",
+        len(games),
+        len(details),
+        len(games_paths),
+        len(details_paths),
+    )
+
+    by_month = audit(game_delays(games, details), late_days=args.late_days)
+    _write(by_month, args.output)
+    summary = summarize(by_month, args.late_days)
+    logger.info("Summary: %s", json.dumps(summary))
+    if args.summary:
+        Path(args.summary).write_text(json.dumps(summary, indent=2), encoding="utf-8")
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
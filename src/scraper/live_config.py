@@ -0,0 +1,149 @@
+"""
+Change the request rate of a running details or reports scrape.
+
+Start the scraper with --control-file PATH. To throttle it later without losing
+progress, write the new settings to that file and send SIGHUP:
+
+  echo '{"rate_limit": 0.2}' > control.json
+  kill -HUP <pid>
+
+The file is JSON; "rate_limit" is requests per second (0 = no limit). The file is
+only read on SIGHUP, so --rate-limit still sets the starting rate. An unreadable
+or invalid file is logged and the current settings are kept. The scrapers fetch
+one tournament at a time, so there is no concurrency setting to change; run more
+or fewer processes (--worker-id) for that.
+
+A Lambda cannot be sent SIGHUP, so the chunk handlers use a ControlFilePoller
+instead: run() calls poll() before each request, and every poll_seconds it
+re-reads the file (a local path or an S3 URI) and applies it if it changed. A
+missing file means "no changes", so the handlers can poll a default location.
+"""
+
+import argparse
+import json
+import logging
+import signal
+import time
+from pathlib import Path
+from typing import Callable, Dict, Optional
+
+from rate_limiter import RateLimiter
+from s3_io import read_versioned
+
+logger = logging.getLogger(__name__)
+
+DEFAULT_POLL_SECONDS = 30.0
+
+
+def parse_settings(text: str | bytes, path: str | Path) -> Dict:
+    """Parse and check control file content; raises ValueError if it is invalid."""
+    try:
+        settings = json.loads(text)
+    except (json.JSONDecodeError, UnicodeDecodeError) as e:
+        raise ValueError(f"{path} is not valid JSON: {e}") from None
+    if not isinstance(settings, dict):
+        raise ValueError(f"{path} must hold a JSON object")
+    unknown = set(settings) - {"rate_limit"}
+    if unknown:
+        raise ValueError(f"unknown settings in {path}: {', '.join(sorted(unknown))}")
+    rate = settings.get("rate_limit")
+    if rate is not None and (
+        isinstance(rate, bool) or not isinstance(rate, (int, float)) or rate < 0
+    ):
+        raise ValueError(f"rate_limit must be a number >= 0, got {rate!r}")
+    return settings
+
+
+def read_settings(path: str | Path) -> Dict:
+    """Read and check the control file; raises ValueError if it is invalid."""
+    try:
+        text = Path(path).read_text(encoding="utf-8")
+    except OSError as e:
+        raise ValueError(f"cannot read {path}: {e}") from None
+    return parse_settings(text, path)
+
+
+def apply(path: str | Path, rate_limiter: RateLimiter) -> bool:
+    """Apply the control file to a running scrape. Returns False if it is invalid."""
+    try:
+        settings = read_settings(path)
+    except ValueError as e:
+        logger.error("Control file ignored: %s", e)
+        return False
+    _apply_settings(path, settings, rate_limiter)
+    return True
+
+
+def _apply_settings(
+    path: str | Path, settings: Dict, rate_limiter: RateLimiter
+) -> None:
+    if "rate_limit" in settings:
+        old = rate_limiter.get_rate()
+        rate_limiter.set_rate(settings["rate_limit"])
+        logger.warning(
+            "Control file %s: rate limit %.2f/s -> %.2f/s (0 = no limit)",
+            path,
+            old,
+            rate_limiter.get_rate(),
+        )
+
+
+class ControlFilePoller:
+    """Re-read a local or S3 control file every poll_seconds; see the docstring."""
+
+    def __init__(
+        self,
+        path: str,
+        rate_limiter: RateLimiter,
+        poll_seconds: float = DEFAULT_POLL_SECONDS,
+        clock: Callable[[], float] = time.monotonic,
+    ):
+        self.path = path
+        self.rate_limiter = rate_limiter
+        self.poll_seconds = poll_seconds
+        self._clock = clock
+        self._next_poll = 0.0
+        self._version: Optional[str] = None
+
+    def poll(self) -> bool:
+        """Apply the file if it is due and changed. Returns True if applied."""
+        now = self._clock()
+        if now < self._next_poll:
+            return False
+        self._next_poll = now + self.poll_seconds
+        try:
+            content, version = read_versioned(self.path)
+        except Exception as e:
+            logger.error("Control file %s not read: %s", self.path, e)
+            return False
+        if content is None or version == self._version:
+            return False
+        self._version = version
+        try:
+            settings = parse_settings(content, self.path)
+        except ValueError as e:
+            logger.error("Control file ignored: %s", e)
+            return False
+        _apply_settings(self.path, settings, self.rate_limiter)
+        return True
+
+
+def add_arguments(parser: argparse.ArgumentParser) -> None:
+    """Add --control-file."""
+    parser.add_argument(
+        "--control-file",
+        default=None,
+        metavar="PATH",
+        help='JSON settings re-read on SIGHUP, e.g. {"rate_limit": 0.2}, to '
+        "throttle a running scrape (see live_config.py)",
+    )
+
+
+def install(path: str | Path, rate_limiter: RateLimiter) -> bool:
+    """Re-read path on SIGHUP. Returns False where SIGHUP does not exist."""
+    if not hasattr(signal, "SIGHUP"):
+        logger.warning("SIGHUP not available; --control-file has no effect")
+        return False
+    signal.signal(signal.SIGHUP, lambda signum, frame: apply(path, rate_limiter))
+    logger.info("Send SIGHUP to re-read %s", path)
+    return True
@@ -7,9 +7,15 @@ concatenates, and writes to {base}/data/:
   - tournament_details.parquet
   - tournament_reports_players.parquet
   - tournament_reports_games.parquet
+
+The chunks' run summaries ({base}/reports/tournament_*_chunks/*_summary.json) are
+combined into {base}/reports/tournament_details_summary.json and
+tournament_reports_summary.json, as a CLI run of the month would write them
+(scripts/publish_release.py checks those).
 """
 
 import io
+import json
 import logging
 import re
 
@@ -35,6 +41,12 @@ def _reports_games_re(n: int | None) -> re.Pattern:
     return re.compile(r"reports_chunk_(\d+)_games\.parquet$")
 
 
+def _summary_re(stage: str, n: int | None) -> re.Pattern:
+    if n is not None:
+        return re.compile(rf"{stage}_chunk_(\d+)_of_{n}_summary\.json$")
+    return re.compile(rf"{stage}_chunk_(\d+)_summary\.json$")
+
+
 def _parse_chunk_index(key: str, pattern: re.Pattern) -> int | None:
     """Extract chunk index from key like 'prod/2024-01/data/tournament_details_chunks/details_chunk_3.parquet'."""
     parts = key.split("/")
@@ -56,6 +68,51 @@ def _sorted_chunk_keys(keys: list[str], pattern: re.Pattern) -> list[str]:
     return [k for _, k in indexed]
 
 
+def merge_run_summaries(
+    bucket: str,
+    base: str,
+    chunk_count: int | None,
+    outputs: dict[str, dict[str, str]],
+) -> dict[str, str]:
+    """
+    Combine each stage's chunk summaries into
+    {base}/reports/tournament_{stage}_summary.json. outputs maps the stage
+    ("details", "reports") to its merged output URIs. Returns stage -> summary URI.
+    """
+    import boto3
+
+    from run_summary import merge_summaries, write_summary
+    from s3_io import list_s3_objects
+
+    s3 = boto3.client("s3")
+    written = {}
+    for stage in ("details", "reports"):
+        prefix = f"{base}/reports/tournament_{stage}_chunks/"
+        keys = [k for k, _ in list_s3_objects(bucket, prefix)]
+        chunk_keys = _sorted_chunk_keys(keys, _summary_re(stage, chunk_count))
+        summaries = [
+            json.loads(s3.get_object(Bucket=bucket, Key=k)["Body"].read())
+            for k in chunk_keys
+        ]
+        summary = merge_summaries(
+            summaries,
+            f"tournament_{stage}",
+            expected=chunk_count or 0,
+            outputs=outputs.get(stage),
+        )
+        uri = f"s3://{bucket}/{base}/reports/tournament_{stage}_summary.json"
+        write_summary(summary, uri)
+        logger.info(
+            "Merged %d %s chunk summaries (%s) -> %s",
+            len(summaries),
+            stage,
+            summary["status"],
+            uri,
+        )
+        written[stage] = uri
+    return written
+
+
 def run(
     bucket: str,
     run_type: str,
@@ -126,6 +183,11 @@ def run(
         bucket, run_type, run_name, "data", "tournament_reports_games.parquet"
     )
 
+    summary_outputs = {
+        "details": {"parquet": details_uri},
+        "reports": {"players": players_uri, "games": games_uri},
+    }
+
     if not override:
         if (
             output_exists(details_uri)
@@ -136,6 +198,7 @@ def run(
                 "Merged files already exist (override=false), skipping: %s",
                 details_uri,
             )
+            merge_run_summaries(bucket, base, chunk_count, summary_outputs)
             return {
                 "details_uri": details_uri,
                 "reports_players_uri": players_uri,
@@ -148,6 +211,13 @@ def run(
     import pyarrow as pa
     import pyarrow.parquet as pq
 
+    from provenance import (
+        build_provenance,
+        provenance_from_schema,
+        utc_timestamp,
+        with_provenance,
+    )
+
     s3 = boto3.client("s3")
 
     def _read_parquet(key: str) -> pa.Table:
@@ -167,7 +237,16 @@ def run(
             dfs = [t.to_pandas() for t in tables]
             return pa.Table.from_pandas(pd.concat(dfs, ignore_index=True))
 
-    def _write_parquet(table: pa.Table, uri: str) -> None:
+    def _write_parquet(table: pa.Table, uri: str, chunks: list[pa.Table]) -> None:
+        """Write merged table, keeping the earliest chunk scrape time."""
+        scraped = [provenance_from_schema(t.schema).get("scraped_at") for t in chunks]
+        scraped = [s for s in scraped if s]
+        provenance = build_provenance(
+            scraped_at=min(scraped) if scraped else None,
+            merged_at=utc_timestamp(),
+            chunks=len(chunks),
+        )
+        table = with_provenance(table, provenance)
         buf = io.BytesIO()
         pq.write_table(table, buf)
         buf.seek(0)
@@ -178,7 +257,7 @@ def run(
     logger.info("Merging %d details chunks -> %s", len(details_keys), details_uri)
     details_tables = [_read_parquet(k) for k in details_keys]
     details_merged = _concat_tables_unified(details_tables)
-    _write_parquet(details_merged, details_uri)
+    _write_parquet(details_merged, details_uri, details_tables)
     del details_tables, details_merged
 
     # Merge reports players
@@ -187,14 +266,16 @@ def run(
     )
     players_tables = [_read_parquet(k) for k in players_keys]
     players_merged = _concat_tables_unified(players_tables)
-    _write_parquet(players_merged, players_uri)
+    _write_parquet(players_merged, players_uri, players_tables)
     del players_tables, players_merged
 
     # Merge reports games
     logger.info("Merging %d reports games chunks -> %s", len(games_keys), games_uri)
     games_tables = [_read_parquet(k) for k in games_keys]
     games_merged = _concat_tables_unified(games_tables)
-    _write_parquet(games_merged, games_uri)
+    _write_parquet(games_merged, games_uri, games_tables)
+
+    summary_uris = merge_run_summaries(bucket, base, chunk_count, summary_outputs)
 
     base_uri = f"s3://{bucket}/{base}"
     write_run_metadata(
@@ -220,4 +301,6 @@ def run(
         "reports_games_uri": games_uri,
         "details_chunks": len(details_keys),
         "reports_chunks": len(players_keys),
+        "details_summary_uri": summary_uris["details"],
+        "reports_summary_uri": summary_uris["reports"],
     }
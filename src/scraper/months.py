@@ -0,0 +1,36 @@
+"""
+Month arguments for the scripts that take a month or a range of months
+(run_prod_backfill, run_full_pipeline, package_release, publish_release).
+"""
+
+import argparse
+
+
+def parse_month(s: str) -> tuple[int, int]:
+    """Parse YYYY-MM to (year, month); for argparse type=."""
+    try:
+        year_s, month_s = s.split("-")
+        year, month = int(year_s), int(month_s)
+    except ValueError as e:
+        raise argparse.ArgumentTypeError(
+            f"Invalid month '{s}': expected YYYY-MM (e.g. 2024-01)"
+        ) from e
+    if not (1 <= month <= 12):
+        raise argparse.ArgumentTypeError(f"Invalid month '{s}': month must be 1-12")
+    return year, month
+
+
+def month_range(
+    start: tuple[int, int], end: tuple[int, int]
+) -> list[tuple[int, int]]:
+    """Return (year, month) for each month from start to end (inclusive)."""
+    if start > end:
+        raise ValueError("start month must be <= end month")
+    months = []
+    y, m = start
+    while (y, m) <= end:
+        months.append((y, m))
+        m += 1
+        if m > 12:
+            y, m = y + 1, 1
+    return months
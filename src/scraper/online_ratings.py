@@ -0,0 +1,236 @@
+#!/usr/bin/env python3
+"""
+Import public Lichess and Chess.com ratings for players with a known online account.
+
+Takes a mapping CSV of FIDE ID to online username and pulls each account's public
+ratings, keyed by fide_id so they can be joined to the FIDE player lists (and to
+FIDE ratings once the pipeline produces them) for OTB vs online comparisons:
+
+  mapping CSV    fide_id, site, username     site = lichess or chesscom
+  output         fide_id, site, username, time_control, rating, rd, games,
+                 provisional, fetched_at
+
+Time controls are the sites' own (Lichess: bullet, blitz, rapid, classical,
+correspondence; Chess.com: bullet, blitz, rapid, daily) and are not comparable
+across sites without calibration. Both sites use Glicko (Lichess Glicko-2), so rd
+is reported where available. Unknown or closed accounts are logged and skipped.
+
+Requests are sequential with --delay seconds between them and identify this tool
+(with --contact, as Chess.com asks); on HTTP 429 the importer waits a minute and
+retries once, per Lichess's API guidelines.
+
+Usage:
+  uv run src/scraper/online_ratings.py --mapping data/online_accounts.csv \\
+    --output data/stats/online_ratings.parquet --contact you@example.com
+"""
+
+import argparse
+import logging
+import sys
+import time
+from pathlib import Path
+from typing import Dict, List, Optional
+
+import pandas as pd
+import requests
+
+import redact
+from provenance import build_provenance, dataframe_to_parquet_bytes
+from timestamps import utc_now
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+LICHESS_URL = "https://lichess.org/api/user/{username}"
+CHESSCOM_URL = "https://api.chess.com/pub/player/{username}/stats"
+LICHESS_PERFS = ("bullet", "blitz", "rapid", "classical", "correspondence")
+CHESSCOM_PERFS = {
+    "chess_bullet": "bullet",
+    "chess_blitz": "blitz",
+    "chess_rapid": "rapid",
+    "chess_daily": "daily",
+}
+SITES = ("lichess", "chesscom")
+OUTPUT_COLUMNS = [
+    "fide_id",
+    "site",
+    "username",
+    "time_control",
+    "rating",
+    "rd",
+    "games",
+    "provisional",
+    "fetched_at",
+]
+RATE_LIMIT_WAIT = 60
+
+
+def parse_lichess(data: dict) -> List[Dict]:
+    """Rating rows (time_control, rating, rd, games, provisional) from /api/user."""
+    rows = []
+    for perf in LICHESS_PERFS:
+        p = (data.get("perfs") or {}).get(perf)
+        if not p or not p.get("games"):
+            continue
+        rows.append(
+            {
+                "time_control": perf,
+                "rating": p.get("rating"),
+                "rd": p.get("rd"),
+                "games": p.get("games"),
+                "provisional": bool(p.get("prov", False)),
+            }
+        )
+    return rows
+
+
+def parse_chesscom(data: dict) -> List[Dict]:
+    """Rating rows from /pub/player/{username}/stats (current "last" ratings)."""
+    rows = []
+    for key, perf in CHESSCOM_PERFS.items():
+        p = data.get(key)
+        if not p or "last" not in p:
+            continue
+        record = p.get("record") or {}
+        games = sum(record.get(k, 0) for k in ("win", "loss", "draw"))
+        rd = p["last"].get("rd")
+        rows.append(
+            {
+                "time_control": perf,
+                "rating": p["last"].get("rating"),
+                "rd": rd,
+                "games": games,
+                # No provisional flag in the API; treat RD > 100 as provisional
+                "provisional": rd is not None and rd > 100,
+            }
+        )
+    return rows
+
+
+def read_mapping(path: str | Path) -> pd.DataFrame:
+    """Mapping rows with fide_id, site, username as strings; bad sites dropped."""
+    mapping = pd.read_csv(path, dtype=str).fillna("")
+    missing = {"fide_id", "site", "username"} - set(mapping.columns)
+    if missing:
+        raise ValueError(f"Mapping is missing columns: {sorted(missing)}")
+    mapping = mapping.apply(lambda col: col.str.strip())
+    mapping["site"] = mapping["site"].str.lower().str.replace(".", "", regex=False)
+    bad = ~mapping["site"].isin(SITES)
+    if bad.any():
+        logger.warning("Skipping %d rows with unknown site", int(bad.sum()))
+    mapping = mapping[~bad & (mapping["fide_id"] != "") & (mapping["username"] != "")]
+    return mapping.drop_duplicates().reset_index(drop=True)
+
+
+def fetch_json(session: requests.Session, url: str) -> Optional[dict]:
+    """GET url as JSON; None for unknown accounts. Retries once after HTTP 429."""
+    for attempt in range(2):
+        response = session.get(url, timeout=30)
+        if response.status_code == 429 and attempt == 0:
+            logger.warning("Rate limited; waiting %ds", RATE_LIMIT_WAIT)
+            time.sleep(RATE_LIMIT_WAIT)
+            continue
+        if response.status_code in (404, 410):
+            return None
+        response.raise_for_status()
+        return response.json()
+    return None
+
+
+def fetch_ratings(
+    mapping: pd.DataFrame, session: requests.Session, delay: float = 1.0
+) -> pd.DataFrame:
+    """Rating rows (OUTPUT_COLUMNS) for every account in mapping."""
+    rows = []
+    for i, m in enumerate(mapping.itertuples(index=False)):
+        if i:
+            time.sleep(delay)
+        if m.site == "lichess":
+            url, parse = LICHESS_URL.format(username=m.username), parse_lichess
+        else:
+            url, parse = CHESSCOM_URL.format(username=m.username), parse_chesscom
+        try:
+            data = fetch_json(session, url)
+        except (requests.RequestException, ValueError) as e:
+            logger.warning("%s %s: %s", m.site, m.username, e)
+            continue
+        if data is None or data.get("disabled") or data.get("closed"):
+            logger.warning("%s %s: account not found or closed", m.site, m.username)
+            continue
+        fetched_at = utc_now()
+        for r in parse(data):
+            rows.append(
+                {
+                    "fide_id": m.fide_id,
+                    "site": m.site,
+                    "username": m.username,
+                    **r,
+                    "fetched_at": fetched_at,
+                }
+            )
+    return pd.DataFrame(rows, columns=OUTPUT_COLUMNS)
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="Import Lichess/Chess.com ratings for mapped FIDE players",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    parser.add_argument(
+        "--mapping", required=True, help="CSV with fide_id, site, username"
+    )
+    parser.add_argument("--output", required=True, help="Output Parquet path")
+    parser.add_argument(
+        "--delay",
+        type=float,
+        default=1.0,
+        help="Seconds between requests (default: 1)",
+    )
+    parser.add_argument(
+        "--contact", help="Contact (email or URL) sent in the User-Agent"
+    )
+    args = parser.parse_args()
+    redact.install()
+
+    try:
+        mapping = read_mapping(args.mapping)
+    except (OSError, ValueError) as e:
+        logger.error("Cannot read mapping %s: %s", args.mapping, e)
+        return 1
+    if mapping.empty:
+        logger.error("No usable rows in %s", args.mapping)
+        return 1
+
+    session = requests.Session()
+    agent = "fide-glicko online ratings importer"
+    session.headers["User-Agent"] = (
+        f"{agent} ({args.contact})" if args.contact else agent
+    )
+    ratings = fetch_ratings(mapping, session, delay=args.delay)
+
+    out = Path(args.output)
+    out.parent.mkdir(parents=True, exist_ok=True)
+    provenance = build_provenance(
+        source="lichess.org, chess.com",
+        source_url="https://lichess.org/api, https://api.chess.com/pub",
+        mapping=str(args.mapping),
+        accounts=len(mapping),
+    )
+    out.write_bytes(dataframe_to_parquet_bytes(ratings, provenance))
+    logger.info(
+        "Saved %d ratings for %d of %d accounts to %s",
+        len(ratings),
+        ratings[["site", "username"]].drop_duplicates().shape[0],
+        len(mapping),
+        out,
+    )
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -0,0 +1,198 @@
+#!/usr/bin/env python3
+"""
+Export per-player activity statistics from scraped games.
+
+Reads tournament_reports_games.parquet for every prod month under the local root
+(or explicit --games files) and writes:
+
+  player_activity.parquet          one row per player
+    player_id, first_game, last_game, active_span_days, games, events,
+    active_years, longest_gap_days, longest_gap_start, longest_gap_end
+  player_activity_by_year.parquet  one row per (player_id, year), with --by-year
+    player_id, year, games, events, opponents, opponent_entropy
+
+Games without a round date are counted in games/events but ignored for dates and
+gaps. Gaps are measured between consecutive game dates.
+
+opponent_entropy is the Shannon entropy (bits) of the player's games over their
+opponents that year: 0 when every game was against one opponent, log2(games) when
+no opponent was met twice. A low value next to many games points at a narrow
+schedule. The pipeline computes no ratings yet, so there is no average opponent
+rating or RD.
+
+Usage:
+  uv run src/scraper/player_activity.py --local-root data \\
+    --output data/stats/player_activity.parquet \\
+    --by-year data/stats/player_activity_by_year.parquet
+"""
+
+import argparse
+import logging
+import math
+import sys
+from pathlib import Path
+from typing import List
+
+import pandas as pd
+
+import redact
+from provenance import build_provenance, dataframe_to_parquet_bytes
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+GAMES_FILENAME = "tournament_reports_games.parquet"
+_GAME_COLUMNS = ["white_player_id", "black_player_id", "tournament_id", "round_date"]
+
+
+def find_games_files(local_root: str | Path, run_type: str = "prod") -> List[Path]:
+    """Monthly games files under {local_root}/{run_type}/*/data/, oldest first."""
+    return sorted(Path(local_root).glob(f"{run_type}/*/data/{GAMES_FILENAME}"))
+
+
+def player_game_rows(games: pd.DataFrame) -> pd.DataFrame:
+    """One row per (player, game): player_id, opponent_id, tournament_id, date."""
+    sides = []
+    for col, opp in (
+        ("white_player_id", "black_player_id"),
+        ("black_player_id", "white_player_id"),
+    ):
+        side = games[[col, opp, "tournament_id", "round_date"]].rename(
+            columns={col: "player_id", opp: "opponent_id", "round_date": "date"}
+        )
+        sides.append(side)
+    rows = pd.concat(sides, ignore_index=True)
+    rows["player_id"] = rows["player_id"].fillna("").astype(str)
+    rows["opponent_id"] = rows["opponent_id"].fillna("").astype(str)
+    rows["date"] = pd.to_datetime(rows["date"], errors="coerce")
+    return rows[rows["player_id"].str.strip() != ""]
+
+
+def activity_summary(rows: pd.DataFrame) -> pd.DataFrame:
+    """Per-player totals, active span and longest inactivity gap."""
+    grouped = rows.groupby("player_id")
+    summary = pd.DataFrame(
+        {
+            "first_game": grouped["date"].min(),
+            "last_game": grouped["date"].max(),
+            "games": grouped.size(),
+            "events": grouped["tournament_id"].nunique(),
+            "active_years": grouped["date"].agg(lambda d: d.dt.year.nunique()),
+        }
+    )
+    summary["active_span_days"] = (
+        summary["last_game"] - summary["first_game"]
+    ).dt.days
+
+    dated = rows.dropna(subset=["date"])[["player_id", "date"]].drop_duplicates()
+    dated = dated.sort_values(["player_id", "date"])
+    dated["prev"] = dated.groupby("player_id")["date"].shift()
+    dated["gap_days"] = (dated["date"] - dated["prev"]).dt.days
+    gaps = dated.dropna(subset=["gap_days"])
+    longest = gaps.loc[gaps.groupby("player_id")["gap_days"].idxmax()].set_index(
+        "player_id"
+    )
+    summary["longest_gap_days"] = longest["gap_days"]
+    summary["longest_gap_start"] = longest["prev"]
+    summary["longest_gap_end"] = longest["date"]
+    summary["longest_gap_days"] = summary["longest_gap_days"].fillna(0).astype(int)
+
+    columns = [
+        "first_game",
+        "last_game",
+        "active_span_days",
+        "games",
+        "events",
+        "active_years",
+        "longest_gap_days",
+        "longest_gap_start",
+        "longest_gap_end",
+    ]
+    return summary[columns].reset_index()
+
+
+def schedule_entropy(opponents: pd.Series) -> float:
+    """Shannon entropy (bits) of games over opponents; unknown opponents ignored."""
+    counts = opponents[opponents.str.strip() != ""].value_counts()
+    total = counts.sum()
+    if total == 0:
+        return 0.0
+    return -sum(c / total * math.log2(c / total) for c in counts) + 0.0
+
+
+def activity_by_year(rows: pd.DataFrame) -> pd.DataFrame:
+    """Games, events and opponent spread per (player_id, year) for dated games."""
+    dated = rows.dropna(subset=["date"]).assign(year=lambda r: r["date"].dt.year)
+    grouped = dated.groupby(["player_id", "year"])
+    known = dated[dated["opponent_id"].str.strip() != ""]
+    return (
+        pd.DataFrame(
+            {
+                "games": grouped.size(),
+                "events": grouped["tournament_id"].nunique(),
+                "opponents": known.groupby(["player_id", "year"])[
+                    "opponent_id"
+                ].nunique(),
+                "opponent_entropy": grouped["opponent_id"].agg(schedule_entropy),
+            }
+        )
+        .fillna({"opponents": 0})
+        .astype({"opponents": int})
+        .reset_index()
+    )
+
+
+def _write(df: pd.DataFrame, path: str, n_files: int) -> None:
+    out = Path(path)
+    out.parent.mkdir(parents=True, exist_ok=True)
+    provenance = build_provenance(games_files=n_files)
+    out.write_bytes(dataframe_to_parquet_bytes(df, provenance))
+    logger.info("Saved %d rows to %s", len(df), out)
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="Export per-player activity statistics from scraped games",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    src = parser.add_mutually_exclusive_group(required=True)
+    src.add_argument("--local-root", help="Local bucket root (reads prod months)")
+    src.add_argument("--games", nargs="+", help="Explicit games Parquet files")
+    parser.add_argument("--output", required=True, help="Per-player summary Parquet")
+    parser.add_argument("--by-year", help="Optional per-player-per-year Parquet")
+    args = parser.parse_args()
+    redact.install()
+
+    paths = (
+        [Path(p) for p in args.games]
+        if args.games
+        else find_games_files(args.local_root)
+    )
+    if not paths:
+        logger.error("No %s files found", GAMES_FILENAME)
+        return 1
+
+    try:
+        games = pd.concat(
+            [pd.read_parquet(p, columns=_GAME_COLUMNS) for p in paths],
+            ignore_index=True,
+        )
+    except (OSError, ValueError) as e:
+        logger.error("%s", e)
+        return 1
+    logger.info("Loaded %d games from %d files", len(games), len(paths))
+
+    rows = player_game_rows(games)
+    _write(activity_summary(rows), args.output, len(paths))
+    if args.by_year:
+        _write(activity_by_year(rows), args.by_year, len(paths))
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -0,0 +1,255 @@
+#!/usr/bin/env python3
+"""
+Write one compact JSON bundle per player for static player pages.
+
+Each bundle holds everything a player page needs, so the frontend serves it with
+one fetch:
+
+  id, name, fed, title, w_title, byear   profile from --player-list (when given)
+  games, events, first_game, last_game   totals over all scraped games
+  recent_events   last --recent tournaments: tournament_id, event, end, games,
+                  score (newest first; event name from --details)
+  head_to_head    top --opponents opponents by games: id, name, games, wins,
+                  draws, losses
+
+Ratings and rating history are not included: the pipeline does not compute
+ratings yet. Bundles go to {output-dir}/{shard}/{id}.json, where shard is the
+first two hex digits of sha1(id), so no directory holds more than ~1/256 of the
+players; the frontend computes the same path. Dates are YYYY-MM-DD; games
+without a round date count towards totals only.
+
+Usage:
+  uv run src/scraper/player_bundles.py --local-root data \\
+    --player-list data/player_lists/data/player_list_20250101-000000.parquet \\
+    --details data/prod/2025-*/data/tournament_details.parquet \\
+    --output-dir data/site/players
+"""
+
+import argparse
+import hashlib
+import json
+import logging
+import sys
+from pathlib import Path
+from typing import Dict, List, Optional
+
+import pandas as pd
+
+import redact
+from player_activity import find_games_files
+from player_pgn import event_info
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+PROFILE_COLUMNS = ("name", "fed", "title", "w_title", "byear")
+_GAME_COLUMNS = [
+    "white_player_id",
+    "black_player_id",
+    "tournament_id",
+    "round_date",
+    "score",
+]
+
+
+def bundle_path(output_dir: str | Path, player_id: str) -> Path:
+    """{output_dir}/{first two hex digits of sha1(player_id)}/{player_id}.json."""
+    shard = hashlib.sha1(player_id.encode("utf-8")).hexdigest()[:2]
+    return Path(output_dir) / shard / f"{player_id}.json"
+
+
+def player_results(games: pd.DataFrame) -> pd.DataFrame:
+    """Per (player, game): player_id, opponent_id, tournament_id, date, score."""
+    white = games["white_player_id"].fillna("").astype(str).str.strip()
+    black = games["black_player_id"].fillna("").astype(str).str.strip()
+    score = pd.to_numeric(games["score"], errors="coerce")
+    date = pd.to_datetime(games["round_date"], errors="coerce")
+    sides = [
+        pd.DataFrame({"player_id": white, "opponent_id": black, "score": score}),
+        pd.DataFrame({"player_id": black, "opponent_id": white, "score": 1 - score}),
+    ]
+    for side in sides:
+        side["tournament_id"] = games["tournament_id"].astype(str)
+        side["date"] = date
+    rows = pd.concat(sides, ignore_index=True)
+    played = (rows["player_id"] != "") & (rows["opponent_id"] != "")
+    return rows[played & rows["score"].notna()].reset_index(drop=True)
+
+
+def _day(value) -> Optional[str]:
+    return None if pd.isna(value) else value.strftime("%Y-%m-%d")
+
+
+def _clean(value):
+    """JSON-safe scalar: NaN/NA to None, numpy numbers to Python numbers."""
+    if value is None or (not isinstance(value, str) and pd.isna(value)):
+        return None
+    return value.item() if hasattr(value, "item") else value
+
+
+def recent_events(
+    rows: pd.DataFrame, events: Dict[str, Dict[str, str]], limit: int
+) -> List[Dict]:
+    """A player's last `limit` tournaments, newest first (undated last)."""
+    grouped = rows.groupby("tournament_id")
+    table = pd.DataFrame(
+        {
+            "end": grouped["date"].max(),
+            "games": grouped.size(),
+            "score": grouped["score"].sum(),
+        }
+    ).reset_index()
+    table = table.sort_values(
+        ["end", "tournament_id"], ascending=[False, True], na_position="last"
+    ).head(limit)
+    return [
+        {
+            "tournament_id": t.tournament_id,
+            "event": events.get(t.tournament_id, {}).get("event") or None,
+            "end": _day(t.end),
+            "games": int(t.games),
+            "score": float(t.score),
+        }
+        for t in table.itertuples(index=False)
+    ]
+
+
+def head_to_head(rows: pd.DataFrame, names: Dict[str, str], limit: int) -> List[Dict]:
+    """A player's `limit` most frequent opponents with win/draw/loss counts."""
+    grouped = rows.groupby("opponent_id")["score"]
+    table = pd.DataFrame(
+        {
+            "games": grouped.size(),
+            "wins": grouped.agg(lambda s: int((s == 1).sum())),
+            "draws": grouped.agg(lambda s: int((s == 0.5).sum())),
+            "losses": grouped.agg(lambda s: int((s == 0).sum())),
+        }
+    ).reset_index()
+    table = table.sort_values(["games", "opponent_id"], ascending=[False, True])
+    table = table.head(limit)
+    return [
+        {
+            "id": o.opponent_id,
+            "name": names.get(o.opponent_id),
+            "games": int(o.games),
+            "wins": int(o.wins),
+            "draws": int(o.draws),
+            "losses": int(o.losses),
+        }
+        for o in table.itertuples(index=False)
+    ]
+
+
+def build_bundle(
+    player_id: str,
+    rows: pd.DataFrame,
+    profile: Optional[Dict] = None,
+    names: Optional[Dict[str, str]] = None,
+    events: Optional[Dict[str, Dict[str, str]]] = None,
+    recent: int = 10,
+    opponents: int = 20,
+) -> Dict:
+    """Bundle dict for one player from their player_results rows."""
+    profile = profile or {}
+    bundle = {"id": player_id}
+    bundle.update({k: _clean(profile.get(k)) for k in PROFILE_COLUMNS})
+    bundle.update(
+        {
+            "games": len(rows),
+            "events": int(rows["tournament_id"].nunique()),
+            "first_game": _day(rows["date"].min()),
+            "last_game": _day(rows["date"].max()),
+            "recent_events": recent_events(rows, events or {}, recent),
+            "head_to_head": head_to_head(rows, names or {}, opponents),
+        }
+    )
+    return bundle
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="Write one JSON bundle per player for static player pages",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    src = parser.add_mutually_exclusive_group(required=True)
+    src.add_argument("--local-root", help="Local bucket root (reads prod months)")
+    src.add_argument("--games", nargs="+", help="Explicit games Parquet files")
+    parser.add_argument("--output-dir", required=True, help="Bundle root directory")
+    parser.add_argument("--player-list", help="Player list Parquet for profiles/names")
+    parser.add_argument("--details", nargs="+", help="Details Parquet for event names")
+    parser.add_argument(
+        "--recent", type=int, default=10, help="Recent events per player (default: 10)"
+    )
+    parser.add_argument(
+        "--opponents",
+        type=int,
+        default=20,
+        help="Head-to-head opponents per player (default: 20)",
+    )
+    args = parser.parse_args()
+    redact.install()
+
+    paths = (
+        [Path(p) for p in args.games]
+        if args.games
+        else find_games_files(args.local_root)
+    )
+    if not paths:
+        logger.error("No games files found")
+        return 1
+
+    try:
+        games = pd.concat(
+            [pd.read_parquet(p, columns=_GAME_COLUMNS) for p in paths],
+            ignore_index=True,
+        )
+        profiles: Dict[str, Dict] = {}
+        if args.player_list:
+            players = pd.read_parquet(args.player_list)
+            players["id"] = players["id"].astype(str)
+            cols = [c for c in PROFILE_COLUMNS if c in players.columns]
+            profiles = players.set_index("id")[cols].to_dict("index")
+        events = {}
+        for p in args.details or []:
+            events.update(event_info(pd.read_parquet(p)))
+    except (OSError, ValueError) as e:
+        logger.error("%s", e)
+        return 1
+    names = {pid: p["name"] for pid, p in profiles.items() if p.get("name")}
+
+    results = player_results(games)
+    logger.info(
+        "Loaded %d games from %d files; %d players",
+        len(games),
+        len(paths),
+        results["player_id"].nunique(),
+    )
+    n = 0
+    for player_id, rows in results.groupby("player_id"):
+        bundle = build_bundle(
+            player_id,
+            rows,
+            profiles.get(player_id),
+            names,
+            events,
+            recent=args.recent,
+            opponents=args.opponents,
+        )
+        path = bundle_path(args.output_dir, player_id)
+        path.parent.mkdir(parents=True, exist_ok=True)
+        path.write_text(
+            json.dumps(bundle, ensure_ascii=False, separators=(",", ":")),
+            encoding="utf-8",
+        )
+        n += 1
+    logger.info("Wrote %d player bundles under %s", n, args.output_dir)
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -0,0 +1,275 @@
+#!/usr/bin/env python3
+"""
+Delta encoding for player list snapshots.
+
+Consecutive player lists are mostly identical, so the history is stored as a full
+snapshot every N lists plus per-list diffs against the previous list:
+
+  player_list_{timestamp}.full.parquet   # complete list (byear, id, fed, ...)
+  player_list_{timestamp}.delta.parquet  # op + player columns
+    op = "U": player added or changed (row holds the new values)
+    op = "D": player removed (only id is meaningful)
+
+load_snapshot() rebuilds any list from the nearest full snapshot plus deltas.
+
+Usage:
+  uv run src/scraper/player_list_delta.py encode \\
+    --input-dir data/player_lists/data --output-dir data/player_lists/delta
+  uv run src/scraper/player_list_delta.py decode \\
+    --delta-dir data/player_lists/delta --timestamp 20250101-000000 \\
+    --output player_list.parquet
+"""
+
+import argparse
+import logging
+import re
+import sys
+from datetime import datetime, timezone
+from pathlib import Path
+from typing import List, Tuple
+
+import pandas as pd
+
+import redact
+from provenance import (
+    build_provenance,
+    dataframe_to_parquet_bytes,
+    read_provenance,
+    utc_timestamp,
+)
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+# Same column order as get_player_list._save_results
+PLAYER_COLUMNS = ["byear", "id", "fed", "name", "sex", "title", "w_title"]
+OP_UPSERT = "U"
+OP_DELETE = "D"
+DEFAULT_FULL_EVERY = 12
+
+_SNAPSHOT_RE = re.compile(r"^player_list_(\d{8}-\d{6})\.parquet$")
+_ENCODED_RE = re.compile(r"^player_list_(\d{8}-\d{6})\.(full|delta)\.parquet$")
+
+
+def _normalize(df: pd.DataFrame) -> pd.DataFrame:
+    """
+    Keep player columns in canonical order, indexed by id (int64, as
+    get_player_list writes it).
+    """
+    out = df.reindex(columns=PLAYER_COLUMNS).copy()
+    out["id"] = out["id"].astype("int64")
+    return out.drop_duplicates(subset="id", keep="last").set_index("id", drop=False)
+
+
+def diff_player_lists(prev: pd.DataFrame, curr: pd.DataFrame) -> pd.DataFrame:
+    """
+    Build a delta that turns prev into curr.
+
+    Returns DataFrame with columns ["op"] + PLAYER_COLUMNS. Rows for new or changed
+    players carry op "U"; players missing from curr carry op "D".
+    """
+    prev_n = _normalize(prev)
+    curr_n = _normalize(curr)
+
+    common = curr_n.index.intersection(prev_n.index)
+    new_ids = curr_n.index.difference(prev_n.index)
+    removed_ids = prev_n.index.difference(curr_n.index)
+
+    a = curr_n.loc[common, PLAYER_COLUMNS].astype(object)
+    b = prev_n.loc[common, PLAYER_COLUMNS].astype(object)
+    # NaN/None compare unequal to themselves; treat both-missing as unchanged
+    changed_mask = ~((a == b) | (a.isna() & b.isna())).all(axis=1)
+    changed_ids = common[changed_mask.to_numpy()]
+
+    upserts = curr_n.loc[new_ids.append(changed_ids), PLAYER_COLUMNS].copy()
+    upserts.insert(0, "op", OP_UPSERT)
+    deletes = pd.DataFrame(
+        {"op": OP_DELETE, "id": removed_ids.to_numpy(dtype="int64")}
+    )
+    delta = pd.concat([upserts, deletes.reindex(columns=["op"] + PLAYER_COLUMNS)])
+    return delta.reset_index(drop=True)
+
+
+def apply_player_list_delta(base: pd.DataFrame, delta: pd.DataFrame) -> pd.DataFrame:
+    """Apply a delta from diff_player_lists() to base. Returns a new DataFrame."""
+    out = _normalize(base)
+    if delta.empty:
+        return out.reset_index(drop=True)
+    ids = delta["id"].astype("int64")
+    deleted = set(ids[delta["op"] == OP_DELETE])
+    upserts = _normalize(delta[delta["op"] == OP_UPSERT])
+    out = out[~out.index.isin(deleted) & ~out.index.isin(upserts.index)]
+    out = pd.concat([out, upserts])
+    return out.reset_index(drop=True)
+
+
+def _snapshot_provenance(ts: str, path: Path) -> dict:
+    """Provenance of a source snapshot; scraped_at falls back to its filename."""
+    scraped = datetime.strptime(ts, "%Y%m%d-%H%M%S").replace(tzinfo=timezone.utc)
+    return build_provenance(
+        **{"scraped_at": utc_timestamp(scraped), **read_provenance(path)}
+    )
+
+
+def list_snapshots(input_dir: str | Path) -> List[Tuple[str, Path]]:
+    """Return [(timestamp, path)] for player_list_{timestamp}.parquet, oldest first."""
+    found = []
+    for p in Path(input_dir).iterdir():
+        m = _SNAPSHOT_RE.match(p.name)
+        if m:
+            found.append((m.group(1), p))
+    return sorted(found)
+
+
+def list_encoded(delta_dir: str | Path) -> List[Tuple[str, str, Path]]:
+    """Return [(timestamp, kind, path)] for encoded files, oldest first."""
+    found = []
+    for p in Path(delta_dir).iterdir():
+        m = _ENCODED_RE.match(p.name)
+        if m:
+            found.append((m.group(1), m.group(2), p))
+    return sorted(found)
+
+
+def encode_snapshots(
+    input_dir: str | Path,
+    output_dir: str | Path,
+    full_every: int = DEFAULT_FULL_EVERY,
+) -> dict:
+    """
+    Encode every snapshot in input_dir as full or delta files in output_dir.
+
+    Snapshot i (0-based, oldest first) is stored full when i % full_every == 0.
+    Returns summary with counts and bytes in/out.
+    """
+    if full_every < 1:
+        raise ValueError("full_every must be >= 1")
+    out_dir = Path(output_dir)
+    out_dir.mkdir(parents=True, exist_ok=True)
+
+    summary = {"snapshots": 0, "full": 0, "delta": 0, "bytes_in": 0, "bytes_out": 0}
+    prev = None
+    for i, (ts, path) in enumerate(list_snapshots(input_dir)):
+        curr = pd.read_parquet(path)
+        summary["snapshots"] += 1
+        summary["bytes_in"] += path.stat().st_size
+        provenance = _snapshot_provenance(ts, path)
+        if prev is None or i % full_every == 0:
+            out_path = out_dir / f"player_list_{ts}.full.parquet"
+            full = _normalize(curr).reset_index(drop=True)
+            out_path.write_bytes(dataframe_to_parquet_bytes(full, provenance))
+            summary["full"] += 1
+        else:
+            out_path = out_dir / f"player_list_{ts}.delta.parquet"
+            delta = diff_player_lists(prev, curr)
+            out_path.write_bytes(dataframe_to_parquet_bytes(delta, provenance))
+            summary["delta"] += 1
+            logger.info("%s: %d changed/removed players", ts, len(delta))
+        summary["bytes_out"] += out_path.stat().st_size
+        prev = curr
+    return summary
+
+
+def load_snapshot(delta_dir: str | Path, timestamp: str) -> pd.DataFrame:
+    """
+    Rebuild the player list for timestamp from delta_dir.
+
+    Raises:
+        FileNotFoundError: If timestamp is missing or has no full snapshot before it.
+    """
+    chain: List[Tuple[str, Path]] = []
+    found = False
+    for ts, kind, path in list_encoded(delta_dir):
+        if ts > timestamp:
+            break
+        if kind == "full":
+            chain = []
+        chain.append((kind, path))
+        if ts == timestamp:
+            found = True
+            break
+    if not found or chain[0][0] != "full":
+        raise FileNotFoundError(
+            f"No encoded snapshot for {timestamp} (or no full snapshot before it) "
+            f"in {delta_dir}"
+        )
+    df = pd.read_parquet(chain[0][1])
+    for _, path in chain[1:]:
+        df = apply_player_list_delta(df, pd.read_parquet(path))
+    df = df[PLAYER_COLUMNS]
+    df.attrs["provenance"] = read_provenance(chain[-1][1])
+    return df
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="Delta-encode player list snapshots or rebuild one from deltas",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    sub = parser.add_subparsers(dest="command", required=True)
+
+    enc = sub.add_parser("encode", help="Encode snapshots as full + delta files")
+    enc.add_argument(
+        "--input-dir",
+        required=True,
+        help="Directory with player_list_{timestamp}.parquet files",
+    )
+    enc.add_argument("--output-dir", required=True, help="Directory for encoded files")
+    enc.add_argument(
+        "--full-every",
+        type=int,
+        default=DEFAULT_FULL_EVERY,
+        help=f"Store a full snapshot every N lists (default: {DEFAULT_FULL_EVERY})",
+    )
+
+    dec = sub.add_parser("decode", help="Rebuild one snapshot from encoded files")
+    dec.add_argument("--delta-dir", required=True, help="Directory with encoded files")
+    dec.add_argument(
+        "--timestamp", required=True, help="Snapshot timestamp (YYYYMMDD-HHMMSS)"
+    )
+    dec.add_argument("--output", required=True, help="Output Parquet path")
+
+    args = parser.parse_args()
+    redact.install()
+
+    if args.command == "encode":
+        try:
+            summary = encode_snapshots(args.input_dir, args.output_dir, args.full_every)
+        except (OSError, ValueError) as e:
+            logger.error("Encode failed: %s", e)
+            return 1
+        if not summary["snapshots"]:
+            logger.error("No player_list_*.parquet files in %s", args.input_dir)
+            return 1
+        ratio = summary["bytes_out"] / summary["bytes_in"] if summary["bytes_in"] else 0
+        logger.info(
+            "Encoded %d snapshots (%d full, %d delta): %d -> %d bytes (%.1f%%)",
+            summary["snapshots"],
+            summary["full"],
+            summary["delta"],
+            summary["bytes_in"],
+            summary["bytes_out"],
+            100.0 * ratio,
+        )
+        return 0
+
+    try:
+        df = load_snapshot(args.delta_dir, args.timestamp)
+    except (OSError, FileNotFoundError) as e:
+        logger.error("%s", e)
+        return 1
+    Path(args.output).parent.mkdir(parents=True, exist_ok=True)
+    Path(args.output).write_bytes(
+        dataframe_to_parquet_bytes(df, df.attrs.get("provenance"))
+    )
+    logger.info("Rebuilt %d players to %s", len(df), args.output)
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -0,0 +1,211 @@
+#!/usr/bin/env python3
+"""
+Player name transliteration, matching keys and alternate spellings.
+
+The same player is spelled differently across FIDE tournament pages, the rating
+list and outside sources (chess-results, federation sites): Cyrillic vs Latin
+script, different romanizations ("Aleksei"/"Alexey", "Khalifman"/"Halifman"),
+surname order ("Ding, Liren"/"Liren Ding") and pinyin given names written joined,
+split or hyphenated ("Liren"/"Li Ren"/"Li-Ren", tone marks, ü as u or v).
+
+  transliterate(name)   Cyrillic (Russian, Ukrainian, Belarusian) to Latin
+  match_keys(name)      order-insensitive keys; two spellings match if keys overlap
+  names_match(a, b)     match_keys(a) & match_keys(b)
+
+Keys fold common romanization differences (kh/h, ks/x, y/j/i, w/v, doubled
+letters, -iy/-yi endings, pinyin v for ü). They are for matching only, never for
+display. Chinese characters and Wade-Giles romanizations (Hsieh/Xie) are not
+converted, and irregular spellings (Nepomniachtchi) still need an alias.
+
+The CLI stores every spelling seen per FIDE ID (player list and report players)
+with its source, how often it was seen and its primary matching key:
+
+Usage:
+  uv run src/scraper/player_names.py \\
+    --player-list data/player_lists/data/player_list_20250101-000000.parquet \\
+    --reports data/prod/*/data/tournament_reports_players.parquet \\
+    --output data/stats/player_name_variants.parquet
+"""
+
+import argparse
+import logging
+import re
+import sys
+import unicodedata
+from pathlib import Path
+from typing import List, Set
+
+import pandas as pd
+
+import redact
+from provenance import dataframe_to_parquet_bytes
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+# BGN/PCGN-style romanization, the one FIDE lists mostly follow
+_CYRILLIC = {
+    "а": "a", "б": "b", "в": "v", "г": "g", "д": "d", "е": "e", "ё": "e",
+    "ж": "zh", "з": "z", "и": "i", "й": "y", "к": "k", "л": "l", "м": "m",
+    "н": "n", "о": "o", "п": "p", "р": "r", "с": "s", "т": "t", "у": "u",
+    "ф": "f", "х": "kh", "ц": "ts", "ч": "ch", "ш": "sh", "щ": "shch", "ъ": "",
+    "ы": "y", "ь": "", "э": "e", "ю": "yu", "я": "ya",
+    # Ukrainian and Belarusian
+    "і": "i", "ї": "yi", "є": "ye", "ґ": "g", "ў": "u",
+}  # fmt: skip
+
+# Applied in order to lowercase, accent-free Latin text
+_FOLDS = [
+    (re.compile(r"\b([ln])v"), r"\1u"),  # pinyin ü typed as v (Lv, Nv)
+    (re.compile(r"(?:iy|yi|ij|ii|yy)\b"), "i"),
+    (re.compile(r"kh"), "h"),
+    (re.compile(r"ph"), "f"),
+    (re.compile(r"ks"), "x"),
+    (re.compile(r"[yj]"), "i"),
+    (re.compile(r"w"), "v"),
+    (re.compile(r"(.)\1+"), r"\1"),
+]
+
+# Given names of three or more parts are rare; don't try every join beyond this
+_MAX_TOKENS = 5
+
+
+def transliterate(name: str) -> str:
+    """Latin spelling of a Cyrillic name (other characters are kept)."""
+    out = []
+    for c in name or "":
+        latin = _CYRILLIC.get(c.lower())
+        if latin is None:
+            out.append(c)
+        else:
+            out.append(latin.capitalize() if c.isupper() else latin)
+    result = "".join(out)
+    # All caps stays all caps: "ЩЕРБАКОВ" -> "SHCHERBAKOV", not "ShchERBAKOV"
+    return result.upper() if name and name.isupper() else result
+
+
+def _tokens(name: str) -> List[str]:
+    """Folded word tokens: transliterated, accents and punctuation removed."""
+    s = unicodedata.normalize("NFKD", transliterate(name))
+    s = "".join(c for c in s if not unicodedata.combining(c)).casefold()
+    s = s.replace("ü", "u")
+    s = re.sub(r"[^\w\s]|_|\d", " ", s)
+    tokens = []
+    for tok in s.split():
+        for pattern, repl in _FOLDS:
+            tok = pattern.sub(repl, tok)
+        tokens.append(tok)
+    return tokens
+
+
+def match_keys(name: str) -> Set[str]:
+    """
+    Keys for name: its folded tokens in sorted order, plus the same with any run of
+    adjacent tokens joined (pinyin given names written split or hyphenated).
+    """
+    tokens = _tokens(name)
+    if not tokens:
+        return set()
+    keys = {" ".join(sorted(tokens))}
+    if len(tokens) > _MAX_TOKENS:
+        return keys
+    for i in range(len(tokens)):
+        for j in range(i + 2, len(tokens) + 1):
+            joined = tokens[:i] + ["".join(tokens[i:j])] + tokens[j:]
+            if len(joined) > 1:
+                keys.add(" ".join(sorted(joined)))
+    return keys
+
+
+def primary_key(name: str) -> str:
+    """The main matching key (sorted folded tokens), "" for an empty name."""
+    return " ".join(sorted(_tokens(name)))
+
+
+def names_match(a: str, b: str) -> bool:
+    """True if two spellings plausibly name the same player."""
+    return bool(match_keys(a) & match_keys(b))
+
+
+def spelling_table(sources: List[pd.DataFrame]) -> pd.DataFrame:
+    """
+    Every distinct spelling per player: frames with player_id, name and source
+    columns -> player_id, name, source (comma-joined), n_seen, match_key.
+    """
+    columns = ["player_id", "name", "source", "n_seen", "match_key"]
+    frames = [f[["player_id", "name", "source"]] for f in sources if not f.empty]
+    if not frames:
+        return pd.DataFrame(columns=columns)
+    df = pd.concat(frames, ignore_index=True)
+    df["player_id"] = df["player_id"].astype(str)
+    df = df[(df["player_id"] != "") & df["name"].notna() & (df["name"] != "")]
+    table = (
+        df.groupby(["player_id", "name"])
+        .agg(
+            source=("source", lambda s: ",".join(sorted(set(s)))),
+            n_seen=("source", "size"),
+        )
+        .reset_index()
+    )
+    table["match_key"] = table["name"].map(primary_key)
+    return table[columns].sort_values(["player_id", "name"]).reset_index(drop=True)
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="Store alternate spellings of player names with matching keys",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    parser.add_argument("--player-list", help="Player list Parquet (id, name)")
+    parser.add_argument(
+        "--reports",
+        nargs="*",
+        default=[],
+        help="tournament_reports_players Parquet files (player_id, player_name)",
+    )
+    parser.add_argument("--output", required=True, help="Output Parquet path")
+    args = parser.parse_args()
+    redact.install()
+
+    if not args.player_list and not args.reports:
+        logger.error("Give --player-list and/or --reports")
+        return 1
+
+    sources = []
+    try:
+        if args.player_list:
+            pl = pd.read_parquet(args.player_list, columns=["id", "name"])
+            sources.append(
+                pl.rename(columns={"id": "player_id"}).assign(source="player_list")
+            )
+        for path in args.reports:
+            rp = pd.read_parquet(path, columns=["player_id", "player_name"])
+            sources.append(
+                rp.rename(columns={"player_name": "name"}).assign(source="reports")
+            )
+    except (OSError, ValueError) as e:
+        logger.error("%s", e)
+        return 1
+
+    table = spelling_table(sources)
+    multi = table.groupby("player_id")["name"].nunique()
+    logger.info(
+        "%d spellings for %d players (%d with more than one)",
+        len(table),
+        table["player_id"].nunique(),
+        int((multi > 1).sum()),
+    )
+    out = Path(args.output)
+    out.parent.mkdir(parents=True, exist_ok=True)
+    out.write_bytes(dataframe_to_parquet_bytes(table))
+    logger.info("Saved %s", out)
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
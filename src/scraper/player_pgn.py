@@ -0,0 +1,178 @@
+#!/usr/bin/env python3
+"""
+Export all of a player's scraped games as a PGN file.
+
+Each game from the games Parquet files becomes one PGN game with the Seven Tag
+Roster plus WhiteFideId / BlackFideId:
+
+  Event, Site    tournament name and "city fed" from --details (else the code)
+  Date           round_date as YYYY.MM.DD ("????.??.??" when unknown)
+  Round          round_number, "round.game" for multi-game rounds
+  White, Black   names from --players ("?" when unknown)
+  Result         from score: 1-0, 1/2-1/2, 0-1
+
+The scrapers do not download game scores (the details parser skips the PGN file
+row, and crosstables have results only), so games are headers only: the movetext
+is the result, with a {Forfeit} comment for forfeited games. Standard chess tools
+(ChessBase, SCID, lichess studies) open such files as game lists. Games are in
+date, tournament and round order.
+
+Usage:
+  uv run src/scraper/player_pgn.py --player-id 1503014 \\
+    --games data/prod/2025-*/data/tournament_reports_games.parquet \\
+    --players data/prod/2025-*/data/tournament_reports_players.parquet \\
+    --details data/prod/2025-*/data/tournament_details.parquet \\
+    --output data/pgn/1503014.pgn
+"""
+
+import argparse
+import logging
+import sys
+from pathlib import Path
+from typing import Dict, List, Optional
+
+import pandas as pd
+
+import redact
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+RESULTS = {1.0: "1-0", 0.5: "1/2-1/2", 0.0: "0-1"}
+UNKNOWN_DATE = "????.??.??"
+
+
+def player_games(games: pd.DataFrame, player_id: str) -> pd.DataFrame:
+    """Games where player_id played either colour, in date/tournament/round order."""
+    white = games["white_player_id"].astype(str)
+    black = games["black_player_id"].astype(str)
+    mine = games[(white == player_id) | (black == player_id)]
+    return mine.sort_values(
+        ["round_date", "tournament_id", "round_number", "game_number"],
+        na_position="last",
+        kind="stable",
+    ).reset_index(drop=True)
+
+
+def player_names(players: pd.DataFrame) -> Dict[str, str]:
+    """player_id -> player_name (last non-empty spelling seen)."""
+    named = players[players["player_name"].fillna("").astype(str).str.strip() != ""]
+    return dict(zip(named["player_id"].astype(str), named["player_name"].astype(str)))
+
+
+def event_info(details: pd.DataFrame) -> Dict[str, Dict[str, str]]:
+    """event_code -> {event, site} from successful details rows."""
+    ec_col = "event_code" if "event_code" in details.columns else "id"
+    out = {}
+    for _, row in details[details["success"] == True].iterrows():  # noqa: E712
+        code = row.get(ec_col)
+        if pd.isna(code) or not str(code):
+            continue
+        site = " ".join(
+            str(row[c]).strip()
+            for c in ("city", "fed")
+            if pd.notna(row.get(c)) and str(row[c]).strip()
+        )
+        name = row.get("name")
+        out[str(code)] = {
+            "event": str(name) if pd.notna(name) and str(name) else "",
+            "site": site,
+        }
+    return out
+
+
+def _tag(name: str, value) -> str:
+    value = str(value).replace("\\", "\\\\").replace('"', '\\"')
+    return f'[{name} "{value}"]'
+
+
+def pgn_game(game, names: Dict[str, str], events: Dict[str, Dict[str, str]]) -> str:
+    """One header-only PGN game for a games row (namedtuple or Series)."""
+    white, black = str(game.white_player_id), str(game.black_player_id)
+    code = str(game.tournament_id)
+    event = events.get(code, {})
+    date = pd.to_datetime(game.round_date, errors="coerce")
+    round_label = str(int(game.round_number))
+    if pd.notna(game.game_number) and int(game.game_number) > 1:
+        round_label += f".{int(game.game_number)}"
+    result = RESULTS.get(float(game.score), "*") if pd.notna(game.score) else "*"
+
+    tags = [
+        _tag("Event", event.get("event") or code),
+        _tag("Site", event.get("site") or "?"),
+        _tag("Date", UNKNOWN_DATE if pd.isna(date) else date.strftime("%Y.%m.%d")),
+        _tag("Round", round_label),
+        _tag("White", names.get(white, "?")),
+        _tag("Black", names.get(black, "?")),
+        _tag("Result", result),
+        _tag("WhiteFideId", white),
+        _tag("BlackFideId", black),
+    ]
+    forfeit = getattr(game, "forfeit", "")
+    movetext = f"{{Forfeit}} {result}" if forfeit else result
+    return "\n".join(tags) + "\n\n" + movetext + "\n"
+
+
+def to_pgn(
+    games: pd.DataFrame,
+    names: Optional[Dict[str, str]] = None,
+    events: Optional[Dict[str, Dict[str, str]]] = None,
+) -> str:
+    """PGN text for games, one blank line between games."""
+    return "\n".join(
+        pgn_game(g, names or {}, events or {}) for g in games.itertuples(index=False)
+    )
+
+
+def _read_all(paths: List[str], columns: Optional[List[str]] = None) -> pd.DataFrame:
+    return pd.concat(
+        [pd.read_parquet(p, columns=columns) for p in paths], ignore_index=True
+    )
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="Export a player's scraped games as PGN",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    parser.add_argument("--player-id", required=True, help="FIDE ID of the player")
+    parser.add_argument("--games", nargs="+", required=True, help="Games Parquet files")
+    parser.add_argument(
+        "--players", nargs="+", help="Players Parquet files for player names"
+    )
+    parser.add_argument(
+        "--details", nargs="+", help="Details Parquet files for event name and site"
+    )
+    parser.add_argument("--output", required=True, help="Output PGN path")
+    args = parser.parse_args()
+    redact.install()
+
+    try:
+        games = player_games(_read_all(args.games), args.player_id.strip())
+        names = (
+            player_names(_read_all(args.players, ["player_id", "player_name"]))
+            if args.players
+            else {}
+        )
+        events = event_info(_read_all(args.details)) if args.details else {}
+    except (OSError, ValueError) as e:
+        logger.error("%s", e)
+        return 1
+    if games.empty:
+        logger.error("No games for player %s", args.player_id)
+        return 1
+
+    out = Path(args.output)
+    out.parent.mkdir(parents=True, exist_ok=True)
+    out.write_text(to_pgn(games, names, events), encoding="utf-8")
+    logger.info("Wrote %d games for %s to %s", len(games), args.player_id, out)
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -0,0 +1,106 @@
+"""
+Queue of player IDs that appear in games but not in the player list.
+
+FIDE's bulk player list leaves out some players who appear in crosstables (newly
+registered or unrated players, for example). Games against them are kept as
+scraped. Their IDs go into a queue file so that the next update cycle can scrape
+their profiles, instead of leaving the player table incomplete without notice.
+
+The queue is a text file with one FIDE ID per line, in numeric order. By default
+it lives at player_lists/queue/missing_player_ids.txt, shared by all runs. Each
+validation run adds the IDs that are newly missing. It also drops the IDs that
+are now in the player list or in the scraped profiles
+(player_lists/profiles/player_profiles.parquet), which means the next list
+download or profile scrape has resolved them.
+
+Validation and get_player_profiles.py both update the queue. update_queue()
+reads it with its version (the S3 ETag) and writes it back only if it is
+unchanged (a conditional PUT); if the other writer got there first, it reads the
+new queue and applies its change again, so neither update is lost.
+"""
+
+import logging
+from typing import Dict, Iterable, List, Optional, Tuple
+
+from s3_io import (
+    iter_lines,
+    output_exists,
+    read_versioned,
+    write_if_unchanged,
+    write_output,
+)
+
+logger = logging.getLogger(__name__)
+
+# Read-modify-write attempts before giving up on a queue that keeps changing
+MAX_UPDATE_ATTEMPTS = 5
+
+
+def _sort_key(player_id: str):
+    return (0, int(player_id)) if player_id.isdigit() else (1, player_id)
+
+
+def read_queue(path: str) -> List[str]:
+    """IDs in the queue file (empty if it does not exist yet)."""
+    if not output_exists(path):
+        return []
+    return [line.strip() for line, _ in iter_lines(path) if line.strip()]
+
+
+def _queue_text(player_ids: Iterable[str]) -> str:
+    ids = sorted(set(player_ids), key=_sort_key)
+    return "".join(f"{pid}\n" for pid in ids)
+
+
+def read_queue_versioned(path: str) -> Tuple[List[str], Optional[str]]:
+    """IDs in the queue file and its version (None if it does not exist yet)."""
+    content, version = read_versioned(path)
+    if content is None:
+        return [], None
+    lines = content.decode("utf-8").splitlines()
+    return [line.strip() for line in lines if line.strip()], version
+
+
+def write_queue(path: str, player_ids: Iterable[str]) -> None:
+    """Write IDs one per line in numeric order."""
+    write_output(_queue_text(player_ids), path)
+
+
+def valid_ids(player_ids: Iterable) -> set:
+    """Non-empty numeric IDs as strings (drops blanks, NaN and "None")."""
+    out = set()
+    for pid in player_ids:
+        text = str(pid).strip()
+        if text.endswith(".0"):
+            text = text[:-2]
+        if text.isdigit() and int(text) > 0:
+            out.add(text)
+    return out
+
+
+def update_queue(path: str, missing: Iterable, known: Iterable) -> Dict:
+    """
+    Add missing IDs to the queue and drop queued IDs that are now known.
+
+    Returns {"uri", "queued", "added", "resolved"} counts for reports. The write
+    is conditional on the queue not having changed since it was read; on a
+    conflict the update is redone on the new queue. RuntimeError after
+    MAX_UPDATE_ATTEMPTS conflicts.
+    """
+    known_ids = valid_ids(known)
+    missing_ids = valid_ids(missing)
+    for attempt in range(1, MAX_UPDATE_ATTEMPTS + 1):
+        queued, version = read_queue_versioned(path)
+        old = set(queued)
+        new = (old | missing_ids) - known_ids
+        if write_if_unchanged(_queue_text(new), path, version):
+            return {
+                "uri": path,
+                "queued": len(new),
+                "added": len(new - old),
+                "resolved": len(old - new),
+            }
+        logger.info("Queue %s changed while updating (attempt %d)", path, attempt)
+    raise RuntimeError(
+        f"Queue {path} kept changing; gave up after {MAX_UPDATE_ATTEMPTS} attempts"
+    )
@@ -0,0 +1,85 @@
+"""
+Source attribution and scrape time embedded in exported files.
+
+Parquet outputs carry the provenance as file-level key-value metadata (keys prefixed
+with "fide_glicko."); dict-shaped JSON reports carry it under a "provenance" key.
+Redistributed files keep their origin without a separate notice.
+
+  fide_glicko.source       = "FIDE"
+  fide_glicko.source_url   = "https://ratings.fide.com"
+  fide_glicko.attribution  = "Data (c) FIDE ..."
+  fide_glicko.project_url  = "https://github.com/maxjiang216/fide-glicko"
+  fide_glicko.scraped_at   = "2025-01-01T00:00:00Z"
+"""
+
+import io
+from pathlib import Path
+from typing import Any, Dict, Optional, Union
+
+from timestamps import utc_timestamp
+
+METADATA_PREFIX = "fide_glicko."
+SOURCE = "FIDE"
+SOURCE_URL = "https://ratings.fide.com"
+ATTRIBUTION = (
+    "Data (c) FIDE, scraped from ratings.fide.com. "
+    "Redistributions must credit FIDE as the original source."
+)
+PROJECT_URL = "https://github.com/maxjiang216/fide-glicko"
+
+
+def build_provenance(scraped_at: Optional[str] = None, **extra: Any) -> Dict[str, str]:
+    """
+    Provenance dict for an export. Extra keyword values are stringified and added
+    (e.g. run_type="prod", month="2025-01").
+    """
+    prov = {
+        "source": SOURCE,
+        "source_url": SOURCE_URL,
+        "attribution": ATTRIBUTION,
+        "project_url": PROJECT_URL,
+        "scraped_at": scraped_at or utc_timestamp(),
+    }
+    prov.update({k: str(v) for k, v in extra.items() if v is not None})
+    return prov
+
+
+def provenance_from_schema(schema) -> Dict[str, str]:
+    """Read fide_glicko.* metadata from a pyarrow schema (empty dict if none)."""
+    prov = {}
+    for key, value in (schema.metadata or {}).items():
+        k = key.decode("utf-8")
+        if k.startswith(METADATA_PREFIX):
+            prov[k[len(METADATA_PREFIX) :]] = value.decode("utf-8")
+    return prov
+
+
+def with_provenance(table, provenance: Dict[str, str]):
+    """Return table with provenance merged into its schema metadata."""
+    metadata = dict(table.schema.metadata or {})
+    for k, v in provenance.items():
+        metadata[f"{METADATA_PREFIX}{k}".encode("utf-8")] = str(v).encode("utf-8")
+    return table.replace_schema_metadata(metadata)
+
+
+def dataframe_to_parquet_bytes(
+    df, provenance: Optional[Dict[str, str]] = None
+) -> bytes:
+    """Serialize DataFrame to Parquet bytes with provenance metadata."""
+    import pyarrow as pa
+    import pyarrow.parquet as pq
+
+    table = pa.Table.from_pandas(df, preserve_index=False)
+    table = with_provenance(table, provenance or build_provenance())
+    buf = io.BytesIO()
+    pq.write_table(table, buf)
+    return buf.getvalue()
+
+
+def read_provenance(source: Union[str, Path, bytes]) -> Dict[str, str]:
+    """Read provenance from a Parquet file path or Parquet bytes."""
+    import pyarrow.parquet as pq
+
+    if isinstance(source, bytes):
+        source = io.BytesIO(source)
+    return provenance_from_schema(pq.read_schema(source))
@@ -0,0 +1,282 @@
+#!/usr/bin/env python3
+"""
+Prune data that is no longer needed, so long-running installs stay bounded.
+
+Each policy is opt-in; at least one is required:
+
+  --raw-older-than N     Raw downloads under {run_type}/{run}/raw/ (details and
+                         reports HTML, tournaments JSON, player list XML) for runs
+                         more than N months old. The month comes from the run name
+                         (YYYY-MM) or, for custom names, the file's modification
+                         time. Shared player_lists/raw/ files older than N months go
+                         too, except the newest.
+  --keep-checkpoints K   Rotated local checkpoints ({output}.checkpoint.{n}.gz)
+                         beyond the newest K of each output.
+  --merged-chunks        Per-chunk files under {run}/data/tournament_*_chunks/ once
+                         merge_chunks.py has written all three merged Parquet files
+                         for the run.
+  --converted-json       (local only) Full result JSON files that convert_json.py
+                         has already converted, i.e. whose Parquet outputs exist.
+
+Runs against a local root (--local-root, default data) or an S3 bucket (--bucket).
+Use --dry-run to list what would be deleted. Local directories left empty are
+removed.
+
+Usage:
+  uv run src/scraper/prune.py --raw-older-than 6 --keep-checkpoints 1 \\
+    --merged-chunks --converted-json --dry-run
+  uv run src/scraper/prune.py --bucket fide-glicko --raw-older-than 12
+"""
+
+import argparse
+import json
+import logging
+import re
+import sys
+from datetime import datetime, timezone
+from pathlib import Path
+from typing import Dict, List, Optional
+
+import redact
+from convert_json import classify, find_json_files, output_paths
+from s3_io import PLAYER_LISTS_RAW_PREFIX, VALID_RUN_TYPES, list_s3_objects
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+_RUN = rf"(?P<base>(?:{'|'.join(VALID_RUN_TYPES)})/(?P<run>[^/]+))"
+RUN_RAW_RE = re.compile(rf"^{_RUN}/raw/")
+CHUNK_RE = re.compile(rf"^{_RUN}/data/tournament_(?:id|details|reports)_chunks/")
+CHECKPOINT_RE = re.compile(r"^(?P<output>.+\.checkpoint)\.(?P<n>\d+)\.gz$")
+MONTH_RE = re.compile(r"^\d{4}-\d{2}$")
+MERGED_FILES = (
+    "tournament_details.parquet",
+    "tournament_reports_players.parquet",
+    "tournament_reports_games.parquet",
+)
+S3_DELETE_BATCH = 1000
+
+
+def cutoff_month(now: datetime, months: int) -> str:
+    """YYYY-MM of the month `months` before now's month."""
+    total = now.year * 12 + (now.month - 1) - months
+    return f"{total // 12:04d}-{total % 12 + 1:02d}"
+
+
+def raw_to_prune(
+    objects: Dict[str, datetime], older_than: int, now: datetime
+) -> List[str]:
+    """Raw keys for runs (and shared raw files) more than older_than months old."""
+    cutoff = cutoff_month(now, older_than)
+    prune = []
+    shared = sorted(k for k in objects if k.startswith(PLAYER_LISTS_RAW_PREFIX + "/"))
+    for key in shared[:-1]:
+        if objects[key].strftime("%Y-%m") < cutoff:
+            prune.append(key)
+    for key, modified in objects.items():
+        m = RUN_RAW_RE.match(key)
+        if not m:
+            continue
+        run = m.group("run")
+        month = run if MONTH_RE.match(run) else modified.strftime("%Y-%m")
+        if month < cutoff:
+            prune.append(key)
+    return sorted(prune)
+
+
+def checkpoints_to_prune(objects: Dict[str, datetime], keep: int) -> List[str]:
+    """Rotated checkpoints older than the newest `keep` ({output}.checkpoint.1.gz)."""
+    if keep < 1:
+        raise ValueError("keep must be >= 1")
+    return sorted(
+        key
+        for key in objects
+        if (m := CHECKPOINT_RE.match(key)) and int(m.group("n")) > keep
+    )
+
+
+def merged_chunks_to_prune(objects: Dict[str, datetime]) -> List[str]:
+    """Chunk files of runs whose merged details and reports Parquet files exist."""
+    prune = []
+    for key in objects:
+        m = CHUNK_RE.match(key)
+        if not m:
+            continue
+        base = m.group("base")
+        if all(f"{base}/data/{name}" in objects for name in MERGED_FILES):
+            prune.append(key)
+    return sorted(prune)
+
+
+def converted_json_to_prune(root: Path) -> List[str]:
+    """Full result JSON files under root whose convert_json.py outputs exist."""
+    prune = []
+    for path in find_json_files([root]):
+        try:
+            with open(path, "r", encoding="utf-8") as f:
+                kind = classify(json.load(f))
+        except (OSError, ValueError) as e:
+            logger.warning("Skipping %s: %s", path, e)
+            continue
+        if kind and all(p.exists() for p in output_paths(path, kind).values()):
+            prune.append(path.relative_to(root).as_posix())
+    return sorted(prune)
+
+
+def list_local_objects(root: Path) -> Dict[str, datetime]:
+    """Relative POSIX path -> modification time for every file under root."""
+    return {
+        p.relative_to(root).as_posix(): datetime.fromtimestamp(
+            p.stat().st_mtime, timezone.utc
+        )
+        for p in root.rglob("*")
+        if p.is_file()
+    }
+
+
+def delete_local(root: Path, keys: List[str]) -> int:
+    """Delete files under root and any directories left empty. Returns bytes freed."""
+    freed = 0
+    for key in keys:
+        path = root / key
+        freed += path.stat().st_size
+        path.unlink()
+        parent = path.parent
+        while parent != root and not any(parent.iterdir()):
+            parent.rmdir()
+            parent = parent.parent
+    return freed
+
+
+def delete_s3(bucket: str, keys: List[str]) -> None:
+    """Delete keys from bucket in batches."""
+    import boto3
+
+    s3 = boto3.client("s3")
+    for i in range(0, len(keys), S3_DELETE_BATCH):
+        batch = keys[i : i + S3_DELETE_BATCH]
+        s3.delete_objects(
+            Bucket=bucket,
+            Delete={"Objects": [{"Key": k} for k in batch], "Quiet": True},
+        )
+
+
+def plan(
+    objects: Dict[str, datetime],
+    *,
+    raw_older_than: Optional[int] = None,
+    keep_checkpoints: Optional[int] = None,
+    merged_chunks: bool = False,
+    local_root: Optional[Path] = None,
+    now: Optional[datetime] = None,
+) -> Dict[str, List[str]]:
+    """Keys to delete per policy. converted_json runs only when local_root is set."""
+    policies = {}
+    if raw_older_than is not None:
+        now = now or datetime.now(timezone.utc)
+        policies["raw"] = raw_to_prune(objects, raw_older_than, now)
+    if keep_checkpoints is not None:
+        policies["checkpoints"] = checkpoints_to_prune(objects, keep_checkpoints)
+    if merged_chunks:
+        policies["merged_chunks"] = merged_chunks_to_prune(objects)
+    if local_root is not None:
+        policies["converted_json"] = converted_json_to_prune(local_root)
+    return policies
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="Delete raw downloads, old checkpoints and merged intermediates",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    where = parser.add_mutually_exclusive_group()
+    where.add_argument(
+        "--local-root", default="data", help="Local data root (default: data)"
+    )
+    where.add_argument("--bucket", help="S3 bucket to prune instead of a local root")
+    parser.add_argument(
+        "--raw-older-than",
+        type=int,
+        metavar="MONTHS",
+        help="Delete raw downloads of runs more than MONTHS months old",
+    )
+    parser.add_argument(
+        "--keep-checkpoints",
+        type=int,
+        metavar="K",
+        help="Keep only the newest K rotated checkpoints per output",
+    )
+    parser.add_argument(
+        "--merged-chunks",
+        action="store_true",
+        help="Delete chunk files of runs that have been merged",
+    )
+    parser.add_argument(
+        "--converted-json",
+        action="store_true",
+        help="Delete result JSON files already converted to Parquet (local only)",
+    )
+    parser.add_argument(
+        "--dry-run", action="store_true", help="List files without deleting"
+    )
+    args = parser.parse_args()
+    redact.install()
+
+    if (
+        args.raw_older_than is None
+        and args.keep_checkpoints is None
+        and not args.merged_chunks
+        and not args.converted_json
+    ):
+        parser.error("choose at least one policy")
+    if args.converted_json and args.bucket:
+        parser.error("--converted-json only works with --local-root")
+
+    root = Path(args.local_root)
+    try:
+        if args.bucket:
+            objects = dict(list_s3_objects(args.bucket, ""))
+        else:
+            objects = list_local_objects(root)
+        policies = plan(
+            objects,
+            raw_older_than=args.raw_older_than,
+            keep_checkpoints=args.keep_checkpoints,
+            merged_chunks=args.merged_chunks,
+            local_root=root if args.converted_json else None,
+        )
+    except (OSError, ValueError) as e:
+        logger.error("%s", e)
+        return 1
+
+    keys = sorted({k for policy_keys in policies.values() for k in policy_keys})
+    where_name = f"s3://{args.bucket}" if args.bucket else str(root)
+    for name, policy_keys in policies.items():
+        logger.info("%s: %d files", name, len(policy_keys))
+    if args.dry_run:
+        for key in keys:
+            logger.info("Would delete %s/%s", where_name, key)
+        logger.info("Dry run: %d files would be deleted", len(keys))
+        return 0
+
+    if args.bucket:
+        delete_s3(args.bucket, keys)
+        logger.info("Deleted %d objects from %s", len(keys), where_name)
+    else:
+        freed = delete_local(root, keys)
+        logger.info(
+            "Deleted %d files from %s (%.1f MB freed)",
+            len(keys),
+            where_name,
+            freed / 1e6,
+        )
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -0,0 +1,83 @@
+"""
+Request spacing for the FIDE scrapers, with achieved-rate tracking.
+
+RateLimiter.wait() is called before each request and enforces a minimum interval
+between request starts (no bursting). It also tracks an exponential moving
+average (EWMA) of the interval actually achieved between consecutive requests,
+which includes fetch and parse time, and of how often wait() had to sleep:
+
+  get_rate()          target rate (requests/s; 0 = no limit)
+  get_actual_rate()   EWMA of the achieved rate
+  limiter_bound()     True when most recent requests were delayed by the limiter,
+                      i.e. the limiter, not the server, sets the pace
+
+With alpha = 0.1 the averages follow roughly the last 20 requests.
+"""
+
+import time
+from typing import Dict, Optional
+
+DEFAULT_ALPHA = 0.1
+
+
+class RateLimiter:
+    """Enforces minimum spacing between requests (no bursting); 0 = no limit."""
+
+    def __init__(self, requests_per_second: float, alpha: float = DEFAULT_ALPHA):
+        self.min_interval = 1.0 / requests_per_second if requests_per_second > 0 else 0
+        self.last_request: Optional[float] = None
+        self.alpha = alpha
+        self.interval_ewma: Optional[float] = None
+        self.waited_ewma = 0.0
+
+    def set_rate(self, requests_per_second: float) -> None:
+        """Change the target rate of a running limiter (0 = no limit)."""
+        self.min_interval = 1.0 / requests_per_second if requests_per_second > 0 else 0
+
+    def wait(self):
+        """Wait until enough time has passed since the last request."""
+        waited = False
+        if self.last_request is not None:
+            elapsed = time.perf_counter() - self.last_request
+            if elapsed < self.min_interval:
+                time.sleep(self.min_interval - elapsed)
+                waited = True
+        now = time.perf_counter()
+        if self.last_request is not None:
+            self._observe(now - self.last_request, waited)
+        self.last_request = now
+
+    def _observe(self, interval: float, waited: bool) -> None:
+        if self.interval_ewma is None:
+            self.interval_ewma = interval
+            self.waited_ewma = float(waited)
+            return
+        a = self.alpha
+        self.interval_ewma = a * interval + (1 - a) * self.interval_ewma
+        self.waited_ewma = a * float(waited) + (1 - a) * self.waited_ewma
+
+    def get_rate(self) -> float:
+        return 1.0 / self.min_interval if self.min_interval > 0 else 0.0
+
+    def get_actual_rate(self) -> float:
+        """EWMA of requests/s achieved (0 before the second request)."""
+        if not self.interval_ewma:
+            return 0.0
+        return 1.0 / self.interval_ewma
+
+    def limiter_bound(self) -> bool:
+        return self.min_interval > 0 and self.waited_ewma >= 0.5
+
+    def describe(self) -> str:
+        """Log fragment, e.g. "Rate: 0.33/s target, 0.31/s actual (limiter)"."""
+        target = f"{self.get_rate():.2f}/s" if self.min_interval > 0 else "unlimited"
+        bound = "limiter" if self.limiter_bound() else "server"
+        return f"Rate: {target} target, {self.get_actual_rate():.2f}/s actual ({bound})"
+
+    def stats(self) -> Dict:
+        """Rates for run summaries and metrics."""
+        return {
+            "target_per_s": round(self.get_rate(), 4),
+            "actual_per_s": round(self.get_actual_rate(), 4),
+            "limiter_bound": self.limiter_bound(),
+        }
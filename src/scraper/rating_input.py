@@ -0,0 +1,297 @@
+#!/usr/bin/env python3
+"""
+Apply the rating-input policy to scraped games.
+
+FIDE does not rate forfeits or byes. This module decides which rows of
+tournament_reports_games.parquet feed rating updates and counts what was left out
+per rating period (see --period; "unknown" when the date is missing):
+
+  forfeit   forfeit win/loss (forfeit column "+" or "-"); kept with --include-forfeits
+  unplayed  missing player id, self-pairing or missing score
+
+Byes never reach the games file: the reports scraper only records rounds that have
+an opponent id, so they need no filtering here.
+
+Each rated game gets a rating_period (YYYY-MM) chosen by --period:
+
+  played    month of round_date (default)
+  received  month FIDE received the tournament report (details date_received,
+            needs --details); falls back to the played month when unknown
+
+Games from reports received months after the event change already-rated periods
+under "played" (the engine has to recompute them) but not under "received".
+late_reports.py counts how many games that affects per month.
+
+Experimental game weights (--weights, with --details) add a weight column to the
+output. The weights file maps a details attribute and its value (case-insensitive)
+to a multiplier; a game's weight is the product over attributes, 1.0 by default:
+
+  {"time_control": {"R": 0.5, "B": 0.25}, "hybrid": {"yes": 0.5}}
+
+Attributes: time_control (S/R/B), hybrid, type, system, category. The weights are
+recorded in the output provenance and the report policy. Nothing consumes the
+weight column yet; there is no rating engine or backtester in this repo.
+
+Usage:
+  uv run src/scraper/rating_input.py \\
+    --input data/prod/2025-01/data/tournament_reports_games.parquet \\
+    --output data/prod/2025-01/data/rating_input_games.parquet \\
+    --report data/prod/2025-01/reports/rating_input_report.json
+"""
+
+import argparse
+import json
+import logging
+import sys
+from dataclasses import asdict, dataclass
+from pathlib import Path
+from typing import Dict, Optional, Tuple
+
+import pandas as pd
+
+import redact
+from provenance import build_provenance, dataframe_to_parquet_bytes
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+EXCLUDE_FORFEIT = "forfeit"
+EXCLUDE_UNPLAYED = "unplayed"
+UNKNOWN_PERIOD = "unknown"
+PERIOD_PLAYED = "played"
+PERIOD_RECEIVED = "received"
+WEIGHT_ATTRIBUTES = ("time_control", "hybrid", "type", "system", "category")
+
+
+@dataclass(frozen=True)
+class RatingInputPolicy:
+    """Which games count toward ratings. Defaults match FIDE practice."""
+
+    include_forfeits: bool = False
+    period: str = PERIOD_PLAYED
+    # {details attribute: {value: multiplier}}; None means no weight column
+    weights: Optional[Dict[str, Dict[str, float]]] = None
+
+
+def exclusion_reasons(games: pd.DataFrame, policy: RatingInputPolicy) -> pd.Series:
+    """Reason each game is excluded (EXCLUDE_*), or None when it is rated."""
+    white = games["white_player_id"].fillna("").astype(str).str.strip()
+    black = games["black_player_id"].fillna("").astype(str).str.strip()
+    unplayed = (white == "") | (black == "") | (white == black) | games["score"].isna()
+    forfeit = games["forfeit"].fillna("").astype(str).str.strip() != ""
+
+    reasons = pd.Series(None, index=games.index, dtype=object)
+    if not policy.include_forfeits:
+        reasons[forfeit] = EXCLUDE_FORFEIT
+    reasons[unplayed] = EXCLUDE_UNPLAYED
+    return reasons
+
+
+def _periods(games: pd.DataFrame) -> pd.Series:
+    dates = pd.to_datetime(games["round_date"], errors="coerce")
+    return dates.dt.strftime("%Y-%m").fillna(UNKNOWN_PERIOD)
+
+
+def _details_by_code(details: pd.DataFrame) -> pd.DataFrame:
+    """Successful details rows indexed by event code (games' tournament_id)."""
+    ec_col = "event_code" if "event_code" in details.columns else "id"
+    ok = details[details["success"] == True]  # noqa: E712
+    return (
+        ok.assign(_code=ok[ec_col].astype(str))
+        .drop_duplicates("_code")
+        .set_index("_code")
+    )
+
+
+def rating_periods(
+    games: pd.DataFrame,
+    policy: RatingInputPolicy,
+    details: Optional[pd.DataFrame] = None,
+) -> pd.Series:
+    """Rating period (YYYY-MM, or UNKNOWN_PERIOD) of each game under policy."""
+    played = _periods(games)
+    if policy.period == PERIOD_PLAYED:
+        return played
+    if policy.period != PERIOD_RECEIVED:
+        raise ValueError(f"unknown period policy {policy.period!r}")
+    if details is None or "date_received" not in details.columns:
+        raise ValueError("the received period policy needs details with date_received")
+    received = pd.to_datetime(
+        _details_by_code(details)["date_received"], errors="coerce"
+    ).dt.strftime("%Y-%m")
+    by_game = games["tournament_id"].astype(str).map(received)
+    return by_game.where(by_game.notna(), played)
+
+
+def apply_policy(
+    games: pd.DataFrame,
+    policy: RatingInputPolicy,
+    details: Optional[pd.DataFrame] = None,
+) -> Tuple[pd.DataFrame, Dict[str, Dict[str, int]]]:
+    """
+    Filter games by policy and add their rating_period.
+
+    Returns (rated games, {period: {"games", "rated", "forfeit", "unplayed"}}).
+    """
+    reasons = exclusion_reasons(games, policy)
+    periods = rating_periods(games, policy, details)
+    counts: Dict[str, Dict[str, int]] = {}
+    for period in sorted(periods.unique()):
+        in_period = periods == period
+        period_reasons = reasons[in_period]
+        counts[period] = {
+            "games": int(in_period.sum()),
+            "rated": int(period_reasons.isna().sum()),
+            EXCLUDE_FORFEIT: int((period_reasons == EXCLUDE_FORFEIT).sum()),
+            EXCLUDE_UNPLAYED: int((period_reasons == EXCLUDE_UNPLAYED).sum()),
+        }
+    rated = games.assign(rating_period=periods)[reasons.isna()]
+    return rated.reset_index(drop=True), counts
+
+
+def load_weights(path: str | Path) -> Dict[str, Dict[str, float]]:
+    """
+    Read and check a weights file. Values are lowercased.
+
+    Raises:
+        ValueError: Unknown attribute or a multiplier that is not a number >= 0.
+    """
+    with open(path, "r", encoding="utf-8") as f:
+        raw = json.load(f)
+    if not isinstance(raw, dict):
+        raise ValueError(f"{path}: expected an object of attribute -> value weights")
+    weights = {}
+    for attr, table in raw.items():
+        if attr not in WEIGHT_ATTRIBUTES:
+            raise ValueError(
+                f"{path}: unknown attribute {attr!r} "
+                f"(expected one of {', '.join(WEIGHT_ATTRIBUTES)})"
+            )
+        if not isinstance(table, dict):
+            raise ValueError(f"{path}: {attr} must map values to multipliers")
+        weights[attr] = {}
+        for value, mult in table.items():
+            if isinstance(mult, bool) or not isinstance(mult, (int, float)) or mult < 0:
+                raise ValueError(f"{path}: {attr}.{value} must be a number >= 0")
+            weights[attr][str(value).strip().lower()] = float(mult)
+    return weights
+
+
+def game_weights(
+    games: pd.DataFrame, details: pd.DataFrame, weights: Dict[str, Dict[str, float]]
+) -> pd.Series:
+    """Weight per game: product of the multipliers for its tournament's attributes."""
+    attrs = _details_by_code(details)
+    codes = games["tournament_id"].astype(str)
+    weight = pd.Series(1.0, index=games.index)
+    for attr, table in weights.items():
+        if attr not in attrs.columns:
+            logger.warning("Details have no %s column; its weights are ignored", attr)
+            continue
+        values = codes.map(attrs[attr]).fillna("").astype(str).str.strip().str.lower()
+        weight *= values.map(table).fillna(1.0)
+    return weight
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="Filter scraped games into rating input (forfeit/unplayed policy)",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    parser.add_argument("--input", required=True, help="Games Parquet from reports")
+    parser.add_argument("--output", required=True, help="Rated games Parquet")
+    parser.add_argument("--report", help="Write policy and per-period counts as JSON")
+    parser.add_argument(
+        "--include-forfeits",
+        action="store_true",
+        help="Rate forfeit wins/losses (FIDE excludes them; for experiments)",
+    )
+    parser.add_argument(
+        "--period",
+        choices=[PERIOD_PLAYED, PERIOD_RECEIVED],
+        default=PERIOD_PLAYED,
+        help="Rating period: month played or month the report was received "
+        "(received needs --details; default: played)",
+    )
+    parser.add_argument(
+        "--weights",
+        help="Experimental: JSON of details attribute -> value -> game weight",
+    )
+    parser.add_argument(
+        "--details",
+        nargs="+",
+        help="Details Parquet files (required with --weights or --period received)",
+    )
+    args = parser.parse_args()
+    redact.install()
+
+    if (args.weights or args.period == PERIOD_RECEIVED) and not args.details:
+        parser.error("--weights and --period received need --details")
+    try:
+        weights = load_weights(args.weights) if args.weights else None
+        games = pd.read_parquet(args.input)
+        details = (
+            pd.concat([pd.read_parquet(p) for p in args.details], ignore_index=True)
+            if args.details
+            else None
+        )
+    except (OSError, ValueError) as e:
+        logger.error("%s", e)
+        return 1
+    policy = RatingInputPolicy(
+        include_forfeits=args.include_forfeits, period=args.period, weights=weights
+    )
+
+    try:
+        rated, counts = apply_policy(games, policy, details)
+    except ValueError as e:
+        logger.error("%s", e)
+        return 1
+    if policy.weights:
+        rated["weight"] = game_weights(rated, details, policy.weights)
+        logger.info(
+            "Weighted %d of %d rated games (mean weight %.3f)",
+            int((rated["weight"] != 1.0).sum()),
+            len(rated),
+            rated["weight"].mean() if len(rated) else 1.0,
+        )
+    for period, c in counts.items():
+        logger.info(
+            "%s: %d games, %d rated, %d forfeit excluded, %d unplayed excluded",
+            period,
+            c["games"],
+            c["rated"],
+            c[EXCLUDE_FORFEIT],
+            c[EXCLUDE_UNPLAYED],
+        )
+
+    out = Path(args.output)
+    out.parent.mkdir(parents=True, exist_ok=True)
+    provenance = build_provenance(
+        include_forfeits=policy.include_forfeits,
+        period=policy.period,
+        weights=json.dumps(policy.weights, sort_keys=True) if policy.weights else None,
+    )
+    out.write_bytes(dataframe_to_parquet_bytes(rated, provenance))
+    logger.info("Saved %d of %d games to %s", len(rated), len(games), out)
+
+    if args.report:
+        report = {
+            "provenance": provenance,
+            "policy": asdict(policy),
+            "periods": counts,
+        }
+        report_path = Path(args.report)
+        report_path.parent.mkdir(parents=True, exist_ok=True)
+        report_path.write_text(json.dumps(report, indent=2), encoding="utf-8")
+        logger.info("Saved report to %s", report_path)
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
@@ -11,7 +11,7 @@ import gzip
 import io
 import re
 from pathlib import Path
-from typing import List, Optional, Tuple, Union
+from typing import Iterator, List, Optional, Tuple, Union
 
 DELIMITER_PREFIX = b"\n!!FIDE!!id="
 DELIMITER_SUFFIX = b"!!\n"
@@ -48,6 +48,16 @@ def extract_tournament(
     Returns:
         Raw HTML bytes for that tournament, or None if not found.
     """
+    for id_val, html in iter_tournaments(gz_path_or_bytes):
+        if id_val == tournament_id:
+            return html
+    return None
+
+
+def iter_tournaments(
+    gz_path_or_bytes: Union[str, Path, bytes],
+) -> Iterator[Tuple[str, bytes]]:
+    """Yield (id, html) for every tournament in a concatenated gzip chunk, in order."""
     if isinstance(gz_path_or_bytes, (str, Path)):
         with gzip.open(gz_path_or_bytes, "rb") as f:
             data = f.read()
@@ -56,7 +66,5 @@ def extract_tournament(
     # Match id= followed by any chars until !!
     pattern = rb"\n!!FIDE!!id=([^!]+)!!\n"
     parts = re.split(pattern, data)
-    for i in range(1, len(parts), 2):
-        if i + 1 < len(parts) and parts[i].decode("utf-8") == tournament_id:
-            return parts[i + 1]
-    return None
+    for i in range(1, len(parts) - 1, 2):
+        yield parts[i].decode("utf-8"), parts[i + 1]
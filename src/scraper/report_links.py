@@ -0,0 +1,115 @@
+"""
+Typed View Report links from tournament details pages.
+
+The details table's "View Report" cell links to different kinds of report, and the
+href and text alone do not say which parser can read it. parse_report_link() sorts
+an href into:
+
+  kind             system         href example
+  rating_report    fide           report.phtml?event=368261 (FIDE's rating report)
+  source_report    fide           tournament_src_report.phtml?code=368261 (the
+                                  original report, read by get_tournament_reports)
+  pairing_program  swiss-manager  https://chess-results.com/tnr123456.aspx, or
+                   vega, ...      another pairing program's site (PAIRING_HOSTS)
+  other            host or None   anything else
+
+event is the tournament parameter of the link (event=, code=, tnr=), or None.
+report_url() gives the page the crosstable scraper can parse for a link, or None
+when no parser reads that kind yet.
+"""
+
+import re
+from dataclasses import dataclass
+from typing import Optional
+from urllib.parse import parse_qs, urlparse
+
+RATING_REPORT = "rating_report"
+SOURCE_REPORT = "source_report"
+PAIRING_PROGRAM = "pairing_program"
+OTHER = "other"
+KINDS = (RATING_REPORT, SOURCE_REPORT, PAIRING_PROGRAM, OTHER)
+
+FIDE_HOSTS = ("ratings.fide.com",)
+SOURCE_REPORT_URL = "https://ratings.fide.com/tournament_src_report.phtml?code={code}"
+
+# FIDE pages by file name -> (kind, tournament query parameter)
+_FIDE_PAGES = {
+    "report.phtml": (RATING_REPORT, "event"),
+    "tournament_report.phtml": (RATING_REPORT, "event"),
+    "tournament_src_report.phtml": (SOURCE_REPORT, "code"),
+}
+# Pairing program sites (host or parent domain) -> system
+PAIRING_HOSTS = {
+    "chess-results.com": "swiss-manager",
+    "vegachess.com": "vega",
+    "swisschess.de": "swiss-chess",
+    "chessmanager.com": "chessmanager",
+    "tornelo.com": "tornelo",
+    "info64.org": "info64",
+}
+_EVENT_PARAMS = ("event", "code", "tnr", "id")
+_TNR_PATH_RE = re.compile(r"tnr(\d+)", re.I)
+
+
+@dataclass(frozen=True)
+class ReportLink:
+    kind: str
+    system: Optional[str]
+    event: Optional[str]
+    href: str
+    text: str = ""
+
+
+def _host_system(host: str) -> Optional[str]:
+    for domain, system in PAIRING_HOSTS.items():
+        if host == domain or host.endswith("." + domain):
+            return system
+    return None
+
+
+def _first_param(query: dict, names) -> Optional[str]:
+    for name in names:
+        values = query.get(name)
+        if values and values[0].strip():
+            return values[0].strip()
+    return None
+
+
+def parse_report_link(href: Optional[str], text: str = "") -> Optional[ReportLink]:
+    """ReportLink for a View Report href (relative hrefs are FIDE's), or None."""
+    href = (href or "").strip()
+    if not href:
+        return None
+    text = " ".join((text or "").split())
+    url = urlparse(href)
+    host = (url.hostname or "").lower()
+    if host.startswith("www."):
+        host = host[4:]
+    query = parse_qs(url.query)
+    page = url.path.rsplit("/", 1)[-1].lower()
+
+    if not host or host in FIDE_HOSTS:
+        kind, param = _FIDE_PAGES.get(page, (OTHER, None))
+        event = _first_param(query, (param,) if param else _EVENT_PARAMS)
+        return ReportLink(kind, "fide", event, href, text)
+
+    system = _host_system(host)
+    event = _first_param(query, _EVENT_PARAMS)
+    if event is None:
+        m = _TNR_PATH_RE.search(url.path)
+        event = m.group(1) if m else None
+    if system:
+        return ReportLink(PAIRING_PROGRAM, system, event, href, text)
+    return ReportLink(OTHER, host, event, href, text)
+
+
+def report_url(link: Optional[ReportLink]) -> Optional[str]:
+    """
+    Page the crosstable scraper parses for link: FIDE's original report for both
+    FIDE kinds (same event ID), else None (no parser for pairing program sites).
+    """
+    if link is None or link.system != "fide" or not link.event:
+        return None
+    if link.kind not in (RATING_REPORT, SOURCE_REPORT):
+        return None
+    return SOURCE_REPORT_URL.format(code=link.event)
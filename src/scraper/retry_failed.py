@@ -0,0 +1,349 @@
+#!/usr/bin/env python3
+"""
+Retry only the failed tournaments of a previous details or reports run.
+
+Re-fetches the tournaments that failed in an earlier run, with fresh settings (a
+lower --rate, a --proxy, a longer --read-timeout), and merges the new successes
+into the original output:
+
+  details   failed = tournament_ids with no success row in the details Parquet;
+            their rows are replaced by the new results
+  reports   failed = codes from --failed (a _failures.json / .failures.json file,
+            or one code per line), else codes in --input with no rows in the
+            players Parquet; new players and games rows are appended
+
+Outputs are rewritten atomically (temp file + rename; S3 puts are atomic), so an
+interrupted retry leaves the original output untouched. Tournaments that fail
+again stay failed and can be retried later. Exit codes and the summary JSON
+({output base}_retry_summary.json) follow run_summary: 0 all recovered, 2 some
+recovered, 3 none recovered or bad input.
+
+Usage:
+  uv run src/scraper/retry_failed.py details \\
+    --output data/prod/2025-01/data/tournament_details.parquet --rate 0.5
+  uv run src/scraper/retry_failed.py reports \\
+    --output data/prod/2025-01/data/tournament_reports \\
+    --input data/prod/2025-01/data/tournament_ids.txt \\
+    --details-path data/prod/2025-01/data/tournament_details.parquet \\
+    --proxy http://proxy.example:3128
+"""
+
+import argparse
+import io
+import json
+import logging
+import os
+import sys
+import tempfile
+from pathlib import Path
+from typing import Dict, List, Optional, Set, Tuple
+
+import pandas as pd
+import requests
+
+import http_timeouts
+import redact
+from provenance import dataframe_to_parquet_bytes
+from run_summary import (
+    EXIT_FATAL,
+    build_summary,
+    exit_code_for,
+    summary_path,
+    write_summary,
+)
+from s3_io import is_s3_path, output_exists, write_output
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+
+def read_parquet(path: str) -> pd.DataFrame:
+    """Read a Parquet file from a local path or S3 URI."""
+    if is_s3_path(path):
+        import boto3
+
+        from s3_io import parse_s3_uri
+
+        bucket, key = parse_s3_uri(path)
+        body = boto3.client("s3").get_object(Bucket=bucket, Key=key)["Body"].read()
+        return pd.read_parquet(io.BytesIO(body))
+    return pd.read_parquet(path)
+
+
+def atomic_write(path: str, content: bytes) -> None:
+    """Replace path with content in one step (temp file + rename, or S3 put)."""
+    if is_s3_path(path):
+        write_output(content, path)
+        return
+    target = Path(path)
+    target.parent.mkdir(parents=True, exist_ok=True)
+    fd, tmp = tempfile.mkstemp(dir=target.parent, prefix=target.name + ".")
+    try:
+        with os.fdopen(fd, "wb") as f:
+            f.write(content)
+        os.replace(tmp, target)
+    except BaseException:
+        os.unlink(tmp)
+        raise
+
+
+def failed_details_ids(df: pd.DataFrame) -> List[str]:
+    """tournament_ids without any success row, in file order."""
+    ids = df["tournament_id"].astype(str)
+    succeeded = set(ids[df["success"].astype(bool)])
+    return list(dict.fromkeys(i for i in ids if i not in succeeded))
+
+
+def merge_details(old: pd.DataFrame, new: pd.DataFrame) -> pd.DataFrame:
+    """old without the retried tournament_ids, plus one new row per retried ID."""
+    from get_tournament_details import _NULLABLE_NUMERIC_COLS
+
+    retried = set(new["tournament_id"].astype(str))
+    kept = old[~old["tournament_id"].astype(str).isin(retried)]
+    merged = pd.concat([kept, new], ignore_index=True)
+    for col in _NULLABLE_NUMERIC_COLS:
+        if col in merged.columns:
+            merged[col] = merged[col].astype("float64")
+    return merged
+
+
+def read_failed_codes(path: str) -> List[str]:
+    """Codes from a failures JSON (list of {tournament_code|tournament_id}) or text."""
+    text = Path(path).read_text(encoding="utf-8")
+    if path.endswith(".json"):
+        entries = json.loads(text)
+        codes = [
+            str(e.get("tournament_code") or e.get("tournament_id") or "")
+            for e in entries
+        ]
+    else:
+        codes = [line.strip() for line in text.splitlines()]
+    return list(dict.fromkeys(c for c in codes if c))
+
+
+def missing_report_codes(codes: List[str], players: pd.DataFrame) -> List[str]:
+    """Codes with no rows in the players Parquet."""
+    have = set(players["tournament_id"].astype(str))
+    return [c for c in dict.fromkeys(codes) if c not in have]
+
+
+def merge_reports(old: pd.DataFrame, new: pd.DataFrame, codes: Set[str]):
+    """old without rows for codes (stale partial data), plus the new rows."""
+    kept = old[~old["tournament_id"].astype(str).isin(codes)]
+    frames = [f for f in (kept, new) if not f.empty]
+    if not frames:
+        return old.iloc[0:0]
+    return pd.concat(frames, ignore_index=True)
+
+
+def make_session(proxy: Optional[str]) -> requests.Session:
+    """Single-connection session like the scrapers', optionally via proxy."""
+    session = requests.Session()
+    adapter = requests.adapters.HTTPAdapter(
+        pool_connections=1, pool_maxsize=1, max_retries=0
+    )
+    session.mount("http://", adapter)
+    session.mount("https://", adapter)
+    if proxy:
+        session.proxies = {"http": proxy, "https": proxy}
+    return session
+
+
+def refetch(ids: List[str], fetch, key: str, rate: float, proxy: Optional[str]):
+    """Fetch each ID once at rate/s; results shaped like the scrapers' all_results."""
+    from get_tournament_details import RateLimiter
+
+    limiter = RateLimiter(rate)
+    session = make_session(proxy)
+    results = []
+    for i, item in enumerate(ids, 1):
+        limiter.wait()
+        data, error, _, _ = fetch(item, session)
+        result = {key: item, "success": data is not None}
+        if data is None:
+            result["error"] = error or "fetch failed"
+            logger.info("[%d/%d] ✗ %s: %s", i, len(ids), item, result["error"])
+        else:
+            result["data"] = data
+            logger.info("[%d/%d] ✓ %s", i, len(ids), item)
+        results.append(result)
+    return results
+
+
+def retry_details(args) -> Tuple[int, List[Dict], Dict[str, str]]:
+    import get_tournament_details as details
+
+    old = read_parquet(args.output)
+    ids = failed_details_ids(old)
+    if args.limit > 0:
+        ids = ids[: args.limit]
+    logger.info("Retrying %d failed tournaments from %s", len(ids), args.output)
+    if not ids:
+        return 0, [], {"parquet": args.output}
+
+    results = refetch(
+        ids, details.fetch_tournament_details, "tournament_id", args.rate, args.proxy
+    )
+    for r in results:
+        if r["success"]:
+            r["details"] = r.pop("data")
+    recovered = sum(1 for r in results if r["success"])
+    if recovered:
+        merged = merge_details(old, details.results_to_dataframe(results))
+        atomic_write(args.output, dataframe_to_parquet_bytes(merged))
+        logger.info("Merged %d recovered tournaments into %s", recovered, args.output)
+    return recovered, results, {"parquet": args.output}
+
+
+def load_details_map(path: str) -> Dict[str, Tuple[Optional[str], Optional[str]]]:
+    """event_code -> (start ISO, end ISO) from a details Parquet, for date inference."""
+    from get_tournament_reports import parse_details_date_to_iso
+
+    df = read_parquet(path)
+    ec_col = "event_code" if "event_code" in df.columns else "id"
+    out = {}
+    for _, row in df[df["success"] == True].iterrows():  # noqa: E712
+        ec = row.get(ec_col)
+        if pd.notna(ec) and str(ec):
+            out[str(ec)] = (
+                parse_details_date_to_iso(str(row.get("start_date", ""))),
+                parse_details_date_to_iso(str(row.get("end_date", ""))),
+            )
+    return out
+
+
+def retry_reports(args) -> Tuple[int, List[Dict], Dict[str, str]]:
+    import get_tournament_reports as reports
+
+    players_path = args.output + "_players.parquet"
+    games_path = args.output + "_games.parquet"
+    old_players = read_parquet(players_path)
+    old_games = read_parquet(games_path)
+    if args.failed:
+        codes = read_failed_codes(args.failed)
+    elif args.input:
+        codes = missing_report_codes(
+            reports.read_tournament_codes(args.input), old_players
+        )
+    else:
+        raise ValueError("reports retry needs --failed or --input")
+    if args.limit > 0:
+        codes = codes[: args.limit]
+    outputs = {"players": players_path, "games": games_path}
+    logger.info("Retrying %d failed tournaments for %s", len(codes), args.output)
+    if not codes:
+        return 0, [], outputs
+
+    details_map = load_details_map(args.details_path) if args.details_path else {}
+    results = refetch(
+        codes,
+        reports.fetch_tournament_report,
+        "tournament_code",
+        args.rate,
+        args.proxy,
+    )
+    for r in results:
+        if r["success"]:
+            r.update(r.pop("data"))
+            reports.validate_pairings(r)
+    ok = [r for r in results if r["success"]]
+    if ok:
+        done = {r["tournament_code"] for r in ok}
+        players = merge_reports(
+            old_players, reports.results_to_players_dataframe(ok), done
+        )
+        games = merge_reports(
+            old_games,
+            reports.results_to_games_dataframe(ok, details_map=details_map),
+            done,
+        )
+        # Serialize both before replacing either, so a bad file leaves both intact
+        players_bytes = dataframe_to_parquet_bytes(players)
+        games_bytes = dataframe_to_parquet_bytes(games)
+        atomic_write(players_path, players_bytes)
+        atomic_write(games_path, games_bytes)
+        logger.info("Merged %d recovered tournaments into %s", len(ok), args.output)
+    return len(ok), results, outputs
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="Retry failed tournaments from a previous run and merge them in",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    sub = parser.add_subparsers(dest="command", required=True)
+    for name, output_help in (
+        ("details", "tournament_details Parquet from the previous run"),
+        ("reports", "Output base (BASE_players.parquet, BASE_games.parquet)"),
+    ):
+        p = sub.add_parser(name, help=f"Retry failed tournament {name}")
+        p.add_argument("--output", required=True, help=output_help)
+        p.add_argument(
+            "--rate",
+            type=float,
+            default=0.5,
+            help="Requests per second (default: 0.5, gentler than a full run)",
+        )
+        p.add_argument("--proxy", help="HTTP(S) proxy URL for the retry requests")
+        p.add_argument("--limit", type=int, default=0, help="Retry at most N")
+        http_timeouts.add_arguments(p)
+        p.add_argument(
+            "--summary",
+            help="Summary JSON path (default: {output base}_retry_summary.json)",
+        )
+    reports_parser = sub.choices["reports"]
+    reports_parser.add_argument(
+        "--failed", help="Failures JSON or code list from the previous run"
+    )
+    reports_parser.add_argument(
+        "--input", help="Tournament codes file; retries codes missing from output"
+    )
+    reports_parser.add_argument(
+        "--details-path", help="Details Parquet for round date inference"
+    )
+    args = parser.parse_args()
+    redact.install()
+
+    if args.rate <= 0:
+        logger.error("--rate must be > 0")
+        return EXIT_FATAL
+    try:
+        http_timeouts.configure_from_args(args)
+    except ValueError as e:
+        logger.error("%s", e)
+        return EXIT_FATAL
+    base = args.output.replace(".parquet", "")
+    summary_file = args.summary or summary_path(base + "_retry")
+    command = f"retry_{args.command}"
+
+    try:
+        if args.command == "details":
+            if not output_exists(args.output):
+                raise FileNotFoundError(f"No previous output at {args.output}")
+            recovered, results, outputs = retry_details(args)
+        else:
+            recovered, results, outputs = retry_reports(args)
+    except (OSError, ValueError) as e:
+        logger.error("%s", e)
+        write_summary(build_summary(command, EXIT_FATAL, error=str(e)), summary_file)
+        return EXIT_FATAL
+
+    exit_code = exit_code_for(recovered, len(results) - recovered)
+    key = "tournament_id" if args.command == "details" else "tournament_code"
+    write_summary(
+        build_summary(command, exit_code, results, outputs=outputs, key=key),
+        summary_file,
+    )
+    logger.info(
+        "Recovered %d of %d; summary: %s", recovered, len(results), summary_file
+    )
+    return exit_code
+
+
+if __name__ == "__main__":
+    sys.exit(main())
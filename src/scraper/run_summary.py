@@ -0,0 +1,236 @@
+"""
+Exit codes and the machine-readable summary written when a scrape finishes.
+
+Scrape commands (get_tournament_details, get_tournament_reports) exit with:
+
+  0    success: every item fetched (or skipped, see below)
+  2    partial: output written, but some items failed after all retries
+  3    fatal: bad arguments or input, or nothing fetched at all
+  130  interrupted (SIGINT); partial results are saved as before
+
+Once the output paths are known, every exit also writes {base}_summary.json next
+to the outputs (or --summary), so orchestrators can branch on the outcome without
+parsing logs; skipping an existing output (exit 0) keeps the previous summary:
+
+  {"command": "tournament_details", "status": "partial", "exit_code": 2,
+   "counts": {"total": 120, "success": 117, "skipped": 1, "failed": 2},
+   "failure_classes": {"timeout": 1, "http": 1},
+   "skipped_classes": {"report_replaced": 1},
+   "outputs": {"parquet": "..."}, "error": null, "finished_at": "...",
+   "failure_groups": {"timeout": {"count": 1, "examples": ["368512"]}, ...}}
+
+Skipped items (results with "skipped": true) are ones FIDE answered for but that
+have no usable data, such as reports scraper SKIPPABLE_ERRORS (a replaced report,
+an empty crosstable). They are counted and classed apart from failures, do not
+make a run partial and are not retried.
+
+The scrapers also log the failure groups at the end of a run (colored on a
+terminal; set NO_COLOR to turn that off).
+"""
+
+import json
+import os
+from collections import Counter
+from datetime import datetime, timezone
+from typing import Dict, Iterable, List, Optional, TextIO
+
+import redact
+from s3_io import write_output
+
+EXIT_SUCCESS = 0
+EXIT_PARTIAL = 2
+EXIT_FATAL = 3
+
+STATUS = {EXIT_SUCCESS: "success", EXIT_PARTIAL: "partial", EXIT_FATAL: "fatal"}
+
+# Checked in order against the lowercased error; first match wins
+FAILURE_PATTERNS = [
+    ("report_updated_or_replaced", "report_replaced"),
+    ("pairing_program_report", "pairing_program"),
+    ("circuit open", "circuit_open"),
+    ("timeout", "timeout"),
+    ("network error", "network"),
+    ("connection error", "network"),
+    ("http ", "http"),
+    ("no data", "no_data"),
+    ("no players", "no_data"),
+    ("parse error", "parse"),
+]
+
+
+def failure_class(error: Optional[str]) -> str:
+    """Coarse class of a fetch error string ("other" if unrecognized)."""
+    lower = (error or "").lower()
+    for pattern, cls in FAILURE_PATTERNS:
+        if pattern in lower:
+            return cls
+    return "other"
+
+
+def classify_failures(errors: Iterable[Optional[str]]) -> Dict[str, int]:
+    """Count of failures per class, largest first."""
+    return dict(Counter(failure_class(e) for e in errors).most_common())
+
+
+def group_failures(
+    results: Iterable[dict], key: str, examples: int = 3
+) -> Dict[str, dict]:
+    """
+    Failed results per class, largest first, each with up to `examples` item IDs:
+    {"timeout": {"count": 12, "examples": ["368512", ...]}}.
+    """
+    groups: Dict[str, dict] = {}
+    for r in results:
+        if r.get("success", False) or r.get("skipped", False):
+            continue
+        group = groups.setdefault(
+            failure_class(r.get("error")), {"count": 0, "examples": []}
+        )
+        group["count"] += 1
+        if len(group["examples"]) < examples and r.get(key) is not None:
+            group["examples"].append(str(r.get(key)))
+    return dict(sorted(groups.items(), key=lambda kv: -kv[1]["count"]))
+
+
+# Classes retry_failed.py can usually recover are yellow, the rest red
+_TRANSIENT_CLASSES = {"timeout", "network", "circuit_open"}
+_YELLOW, _RED, _RESET = "\033[33m", "\033[31m", "\033[0m"
+
+
+def use_color(stream: TextIO) -> bool:
+    """Color for terminals, unless NO_COLOR is set."""
+    return "NO_COLOR" not in os.environ and getattr(stream, "isatty", bool)()
+
+
+def format_failure_groups(groups: Dict[str, dict], color: bool = False) -> List[str]:
+    """Log lines for group_failures() output, e.g. "  timeout: 12 (e.g. 1, 2, 3)"."""
+    lines = []
+    for cls, group in groups.items():
+        label = f"{cls}: {group['count']}"
+        if color:
+            code = _YELLOW if cls in _TRANSIENT_CLASSES else _RED
+            label = f"{code}{label}{_RESET}"
+        examples = ", ".join(group["examples"])
+        lines.append(f"  {label} (e.g. {examples})" if examples else f"  {label}")
+    return lines
+
+
+def latest_results(results: Iterable[dict], key: str) -> List[dict]:
+    """Last result per item: retry passes append a new result for each retry."""
+    return list({r.get(key): r for r in results}.values())
+
+
+def exit_code_for(n_success: int, n_failed: int) -> int:
+    """0 if nothing failed, 2 if some items succeeded, 3 if none did."""
+    if n_failed == 0:
+        return EXIT_SUCCESS
+    return EXIT_PARTIAL if n_success > 0 else EXIT_FATAL
+
+
+def build_summary(
+    command: str,
+    exit_code: int,
+    results: Iterable[dict] = (),
+    outputs: Optional[Dict[str, Optional[str]]] = None,
+    error: Optional[str] = None,
+    key: Optional[str] = None,
+) -> dict:
+    """
+    Summary dict for results (each with success and error keys, and skipped for
+    items without usable data). With key (the item ID column) it also lists
+    example IDs per failure class.
+    """
+    results = list(results)
+    skipped = [r.get("error") for r in results if r.get("skipped", False)]
+    failed = [
+        r.get("error")
+        for r in results
+        if not r.get("success", False) and not r.get("skipped", False)
+    ]
+    summary = {
+        "command": command,
+        "status": STATUS.get(exit_code, "interrupted"),
+        "exit_code": exit_code,
+        "counts": {
+            "total": len(results),
+            "success": len(results) - len(skipped) - len(failed),
+            "skipped": len(skipped),
+            "failed": len(failed),
+        },
+        "failure_classes": classify_failures(failed),
+        "skipped_classes": classify_failures(skipped),
+        "outputs": {k: v for k, v in (outputs or {}).items() if v},
+        "error": error,
+        "finished_at": datetime.now(timezone.utc).strftime("%Y-%m-%dT%H:%M:%SZ"),
+    }
+    if key:
+        summary["failure_groups"] = group_failures(results, key)
+    return summary
+
+
+def merge_summaries(
+    summaries: Iterable[dict],
+    command: str,
+    expected: int = 0,
+    outputs: Optional[Dict[str, Optional[str]]] = None,
+    examples: int = 3,
+) -> dict:
+    """
+    One summary for a run scraped in chunks (the Step Function's per-chunk
+    summaries): counts and classes are added up and the exit code is 0 if every
+    chunk succeeded, 3 if every chunk was fatal, else 2. With expected (the
+    chunk count), missing chunk summaries make the run partial.
+    """
+    summaries = list(summaries)
+    counts: Counter = Counter()
+    failure_classes: Counter = Counter()
+    skipped_classes: Counter = Counter()
+    groups: Dict[str, dict] = {}
+    errors = []
+    for s in summaries:
+        counts.update(s.get("counts", {}))
+        failure_classes.update(s.get("failure_classes", {}))
+        skipped_classes.update(s.get("skipped_classes", {}))
+        for cls, group in s.get("failure_groups", {}).items():
+            merged = groups.setdefault(cls, {"count": 0, "examples": []})
+            merged["count"] += group.get("count", 0)
+            room = examples - len(merged["examples"])
+            merged["examples"].extend(group.get("examples", [])[:room])
+        if s.get("error"):
+            errors.append(s["error"])
+    missing = max(expected - len(summaries), 0)
+    if missing:
+        errors.append(f"{missing} of {expected} chunk summaries missing")
+
+    codes = [s.get("exit_code", EXIT_FATAL) for s in summaries]
+    if all(c == EXIT_FATAL for c in codes):
+        exit_code = EXIT_FATAL
+    elif missing or any(c != EXIT_SUCCESS for c in codes):
+        exit_code = EXIT_PARTIAL
+    else:
+        exit_code = EXIT_SUCCESS
+    return {
+        "command": command,
+        "status": STATUS[exit_code],
+        "exit_code": exit_code,
+        "counts": {
+            k: counts.get(k, 0) for k in ("total", "success", "skipped", "failed")
+        },
+        "failure_classes": dict(failure_classes.most_common()),
+        "skipped_classes": dict(skipped_classes.most_common()),
+        "outputs": {k: v for k, v in (outputs or {}).items() if v},
+        "error": "; ".join(errors) or None,
+        "finished_at": datetime.now(timezone.utc).strftime("%Y-%m-%dT%H:%M:%SZ"),
+        "failure_groups": dict(sorted(groups.items(), key=lambda kv: -kv[1]["count"])),
+        "chunks": len(summaries),
+    }
+
+
+def summary_path(base: str) -> str:
+    """Default summary location for an output base (path without extension)."""
+    return base + "_summary.json"
+
+
+def write_summary(summary: dict, path: str) -> None:
+    """Write summary as JSON to a local path or S3 URI, with secrets masked."""
+    write_output(json.dumps(redact.redact_value(summary), indent=2) + "\n", path)
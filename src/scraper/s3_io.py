@@ -7,7 +7,7 @@ Used by scrapers when running in Lambda or with --output s3://...
 import json
 from datetime import datetime, timezone
 from pathlib import Path
-from typing import Optional
+from typing import Iterator, Optional
 
 S3_PREFIX = "s3://"
 
@@ -19,6 +19,12 @@ PLAYER_LISTS_DATA_PREFIX = "player_lists/data"
 PLAYER_LISTS_RAW_PREFIX = "player_lists/raw"
 PLAYER_LISTS_SAMPLE_PREFIX = "player_lists/sample"
 PLAYER_LISTS_REPORTS_PREFIX = "player_lists/reports"
+# IDs seen in games but missing from the player list, for profile scraping
+PLAYER_LISTS_QUEUE_PREFIX = "player_lists/queue"
+PROFILE_QUEUE_FILENAME = "missing_player_ids.txt"
+# Profiles scraped for queued IDs (get_player_profiles.py)
+PLAYER_LISTS_PROFILES_PREFIX = "player_lists/profiles"
+PROFILES_FILENAME = "player_profiles.parquet"
 STALE_DAYS = 14  # Only re-fetch if latest is older than this
 
 
@@ -85,6 +91,126 @@ def write_output(content: bytes | str, output_path: str) -> None:
         path.write_bytes(content)
 
 
+def read_versioned(path: str) -> tuple[Optional[bytes], Optional[str]]:
+    """
+    (content, version) of a local file or S3 object, or (None, None) if it does
+    not exist. The version is the S3 ETag, or the local file's mtime and size;
+    pass it to write_if_unchanged() for a read-modify-write.
+    """
+    if is_s3_path(path):
+        import boto3
+        from botocore.exceptions import ClientError
+
+        bucket, key = parse_s3_uri(path)
+        s3 = boto3.client("s3")
+        try:
+            obj = s3.get_object(Bucket=bucket, Key=key)
+        except ClientError as e:
+            if e.response.get("Error", {}).get("Code") in ("NoSuchKey", "404"):
+                return None, None
+            raise
+        return obj["Body"].read(), obj["ETag"]
+    p = Path(path)
+    if not p.exists():
+        return None, None
+    st = p.stat()
+    return p.read_bytes(), f"{st.st_mtime_ns}:{st.st_size}"
+
+
+def object_version(path: str) -> Optional[str]:
+    """
+    Size and version of a local file or S3 object without reading it ("{size}:"
+    plus the ETag, or the mtime for local files), or None if it does not exist.
+    A changed file gets a different value.
+    """
+    if is_s3_path(path):
+        import boto3
+        from botocore.exceptions import ClientError
+
+        bucket, key = parse_s3_uri(path)
+        s3 = boto3.client("s3")
+        try:
+            head = s3.head_object(Bucket=bucket, Key=key)
+        except ClientError as e:
+            if e.response.get("Error", {}).get("Code") in ("NoSuchKey", "404"):
+                return None
+            raise
+        return f"{head['ContentLength']}:{head['ETag']}"
+    p = Path(path)
+    if not p.exists():
+        return None
+    st = p.stat()
+    return f"{st.st_size}:{st.st_mtime_ns}"
+
+
+def write_if_unchanged(
+    content: bytes | str, output_path: str, version: Optional[str]
+) -> bool:
+    """
+    Write content only if output_path is still at version (from read_versioned;
+    None means it must not exist yet). Returns False, without writing, if another
+    writer got there first. S3 uses a conditional PUT (If-Match / If-None-Match),
+    so the check and the write are atomic; the local check is best effort.
+    """
+    if isinstance(content, str):
+        content = content.encode("utf-8")
+    if is_s3_path(output_path):
+        import boto3
+        from botocore.exceptions import ClientError
+
+        bucket, key = parse_s3_uri(output_path)
+        s3 = boto3.client("s3")
+        condition = {"IfMatch": version} if version else {"IfNoneMatch": "*"}
+        try:
+            s3.put_object(Bucket=bucket, Key=key, Body=content, **condition)
+        except ClientError as e:
+            code = e.response.get("Error", {}).get("Code")
+            if code in ("PreconditionFailed", "ConditionalRequestConflict"):
+                return False
+            raise
+        return True
+    if read_versioned(output_path)[1] != version:
+        return False
+    write_output(content, output_path)
+    return True
+
+
+def _byte_chunks(path: str, start: int, size: int = 1 << 20) -> Iterator[bytes]:
+    """Stream bytes from offset start: ranged GET for S3, seek for local files."""
+    if is_s3_path(path):
+        import boto3
+
+        bucket, key = parse_s3_uri(path)
+        s3 = boto3.client("s3")
+        if start >= s3.head_object(Bucket=bucket, Key=key)["ContentLength"]:
+            return  # a range past the end is a 416, not an empty body
+        obj = s3.get_object(Bucket=bucket, Key=key, Range=f"bytes={start}-")
+        yield from obj["Body"].iter_chunks(chunk_size=size)
+    else:
+        with open(path, "rb") as f:
+            f.seek(start)
+            while chunk := f.read(size):
+                yield chunk
+
+
+def iter_lines(path: str, start: int = 0) -> Iterator[tuple[str, int]]:
+    """
+    Yield (line, byte offset just after it) from a UTF-8 text file (local or S3),
+    starting at byte offset start, without loading the file. A saved offset can be
+    passed back as start to resume after that line.
+    """
+    offset = start
+    pending = b""
+    for chunk in _byte_chunks(path, start):
+        pending += chunk
+        *lines, pending = pending.split(b"\n")
+        for line in lines:
+            offset += len(line) + 1
+            yield line.decode("utf-8").rstrip("\r"), offset
+    if pending:
+        yield pending.decode("utf-8").rstrip("\r"), offset + len(pending)
+
+
 def build_s3_uri(bucket: str, prefix: str, filename: str) -> str:
     """
     Build S3 URI from bucket, prefix (e.g. 'data' or 'runs/dev-123'), and filename.
@@ -215,6 +341,16 @@ def build_player_lists_raw_uri(bucket: str, timestamp: str) -> str:
     )
 
 
+def build_profile_queue_uri(bucket: str) -> str:
+    """Build s3://bucket/player_lists/queue/missing_player_ids.txt."""
+    return f"{S3_PREFIX}{bucket}/{PLAYER_LISTS_QUEUE_PREFIX}/{PROFILE_QUEUE_FILENAME}"
+
+
+def build_profiles_uri(bucket: str) -> str:
+    """Build s3://bucket/player_lists/profiles/player_profiles.parquet."""
+    return f"{S3_PREFIX}{bucket}/{PLAYER_LISTS_PROFILES_PREFIX}/{PROFILES_FILENAME}"
+
+
 def resolve_latest_federations_uri(bucket: str) -> Optional[str]:
     """Return URI of latest federations file, or None if none exist."""
     uri, _ = get_latest_in_s3_prefix(bucket, FEDERATIONS_DATA_PREFIX + "/")
@@ -23,9 +23,11 @@ PLAYER_TOTAL = "player_total"
 # Tournament details
 EVENT_CODE = "id"
 N_PLAYERS = "n_players"
+N_ROUNDS = "n_rounds"
 
 # Reports / games
 ROUND_NUMBER = "round_number"
+GAME_NUMBER = "game_number"  # 1-based within round vs the same opponent
 ROUND_DATE = "round_date"
 SCORE = "score"
 FORFEIT = "forfeit"
@@ -0,0 +1,153 @@
+#!/usr/bin/env python3
+"""
+Sex field consistency across player list snapshots.
+
+Women's lists and categories filter the player list on sex, so a wrong or flapping
+value silently moves a player in or out. This compares consecutive snapshots
+(player_list_{timestamp}.parquet, oldest first) and flags, per FIDE ID:
+
+  changed       M <-> F between two lists
+  cleared       M/F in one list, missing in the next
+  set           missing in one list, M/F in the next (usually a data fix)
+
+and, within the latest list:
+
+  w_title_not_f women's title (WGM, WIM, WFM, WCM) but sex is not F
+  invalid       value other than M, F or missing
+
+Values are compared case-insensitively; players who leave or join the list are
+not flagged.
+
+Usage:
+  uv run src/scraper/sex_consistency.py --input-dir data/player_lists/data \\
+    --output data/stats/sex_consistency.csv
+"""
+
+import argparse
+import logging
+import sys
+from pathlib import Path
+
+import pandas as pd
+
+import redact
+from player_list_delta import list_snapshots
+
+logging.basicConfig(
+    level=logging.INFO,
+    format="%(asctime)s - %(levelname)s - %(message)s",
+    datefmt="%Y-%m-%d %H:%M:%S",
+)
+logger = logging.getLogger(__name__)
+
+VALID_SEX = ("M", "F")
+FLAG_COLUMNS = ["id", "name", "flag", "sex_before", "sex_after", "list", "prev_list"]
+
+
+def normalize_sex(values: pd.Series) -> pd.Series:
+    """Upper-cased M/F; missing and empty become None, other values are kept."""
+    s = values.astype(object).where(values.notna(), None)
+    s = s.map(lambda v: str(v).strip().upper() if v is not None else None)
+    return s.where(s != "", None)
+
+
+def _by_id(df: pd.DataFrame) -> pd.DataFrame:
+    out = df.reindex(columns=["id", "name", "sex", "w_title"]).copy()
+    out["id"] = out["id"].astype(str)
+    out["sex"] = normalize_sex(out["sex"])
+    return out.drop_duplicates(subset="id", keep="last").set_index("id", drop=False)
+
+
+def sex_changes(prev: pd.DataFrame, curr: pd.DataFrame) -> pd.DataFrame:
+    """changed/cleared/set rows for players in both lists (columns id..sex_after)."""
+    a = _by_id(prev)
+    b = _by_id(curr)
+    common = b.index.intersection(a.index)
+    before = a.loc[common, "sex"]
+    after = b.loc[common, "sex"]
+    known_before = before.isin(VALID_SEX)
+    known_after = after.isin(VALID_SEX)
+    flag = pd.Series(None, index=common, dtype=object)
+    flag[known_before & known_after & (before != after)] = "changed"
+    flag[known_before & after.isna()] = "cleared"
+    flag[before.isna() & known_after] = "set"
+    hit = flag.notna()
+    return pd.DataFrame(
+        {
+            "id": common[hit.to_numpy()],
+            "name": b.loc[common, "name"][hit].to_numpy(),
+            "flag": flag[hit].to_numpy(),
+            "sex_before": before[hit].to_numpy(),
+            "sex_after": after[hit].to_numpy(),
+        }
+    )
+
+
+def list_problems(df: pd.DataFrame) -> pd.DataFrame:
+    """w_title_not_f and invalid rows within one list."""
+    p = _by_id(df)
+    women_title = p["w_title"].notna() & (p["w_title"].astype(str) != "")
+    invalid = p["sex"].notna() & ~p["sex"].isin(VALID_SEX)
+    flag = pd.Series(None, index=p.index, dtype=object)
+    flag[women_title & (p["sex"] != "F")] = "w_title_not_f"
+    flag[invalid] = "invalid"
+    hit = flag.notna()
+    return pd.DataFrame(
+        {
+            "id": p["id"][hit].to_numpy(),
+            "name": p["name"][hit].to_numpy(),
+            "flag": flag[hit].to_numpy(),
+            "sex_before": None,
+            "sex_after": p["sex"][hit].to_numpy(),
+        }
+    )
+
+
+def check_snapshots(input_dir: str | Path) -> pd.DataFrame:
+    """Flags across all snapshots in input_dir plus problems in the latest one."""
+    frames = []
+    prev, prev_ts = None, None
+    for ts, path in list_snapshots(input_dir):
+        curr = pd.read_parquet(path, columns=["id", "name", "sex", "w_title"])
+        if prev is not None:
+            changes = sex_changes(prev, curr)
+            if len(changes):
+                logger.info("%s: %d sex changes since %s", ts, len(changes), prev_ts)
+            frames.append(changes.assign(list=ts, prev_list=prev_ts))
+        prev, prev_ts = curr, ts
+    if prev is not None:
+        frames.append(list_problems(prev).assign(list=prev_ts, prev_list=None))
+    if not frames:
+        return pd.DataFrame(columns=FLAG_COLUMNS)
+    return pd.concat(frames, ignore_index=True).reindex(columns=FLAG_COLUMNS)
+
+
+def main() -> int:
+    parser = argparse.ArgumentParser(
+        description="Flag sex changes across player lists and in the latest list",
+        formatter_class=argparse.RawDescriptionHelpFormatter,
+        epilog=__doc__,
+    )
+    parser.add_argument(
+        "--input-dir",
+        required=True,
+        help="Directory with player_list_{timestamp}.parquet files",
+    )
+    parser.add_argument("--output", required=True, help="CSV output path")
+    args = parser.parse_args()
+    redact.install()
+
+    if not list_snapshots(args.input_dir):
+        logger.error("No player_list_*.parquet files in %s", args.input_dir)
+        return 1
+    flags = check_snapshots(args.input_dir)
+    out = Path(args.output)
+    out.parent.mkdir(parents=True, exist_ok=True)
+    flags.to_csv(out, index=False)
+    counts = flags["flag"].value_counts().to_dict() if len(flags) else {}
+    logger.info("Saved %d flags to %s: %s", len(flags), out, counts)
+    return 0
+
+
+if __name__ == "__main__":
+    sys.exit(main())
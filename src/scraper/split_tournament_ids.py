@@ -4,15 +4,26 @@ Split tournament IDs into even chunks for fan-out processing.
 
 Reads IDs from a file (S3 or local), splits into N chunks, writes each chunk.
 Fails fast if the IDs file does not exist.
+
+The file is streamed, never loaded whole, so very large ID lists (e.g. a global
+multi-year list) can be split without preparing them by hand. A first pass counts
+the IDs to size the chunks. After each chunk the next chunk index, byte offset, ID
+count and the file's version (size and ETag, or size and mtime for a local file)
+are recorded in run_metadata.json (split_ids_progress). A rerun on the same,
+unchanged file with the same chunk count skips the count pass and resumes from
+that offset, reading S3 objects with an HTTP range request. A changed file, or
+--override, starts over.
 """
 
 import argparse
 import json
 import logging
 import sys
-import tempfile
 from pathlib import Path
-from typing import List, Optional
+from typing import List, Optional, Tuple
+
+import redact
+from s3_io import iter_lines, object_version, read_run_metadata, write_run_metadata
 
 # Configure logging
 logging.basicConfig(
@@ -22,6 +33,9 @@ logging.basicConfig(
 )
 logger = logging.getLogger(__name__)
 
+# run_metadata.json key recording how far an interrupted split got
+SPLIT_PROGRESS_KEY = "split_ids_progress"
+
 
 def _is_s3(path: str) -> bool:
     try:
@@ -32,23 +46,6 @@ def _is_s3(path: str) -> bool:
         return path.strip().lower().startswith("s3://")
 
 
-def _read_ids(path: str) -> List[str]:
-    """Read tournament IDs from file (local or S3)."""
-    if _is_s3(path):
-        from s3_io import download_to_file
-
-        local = Path(tempfile.gettempdir()) / "tournament_ids_split.txt"
-        download_to_file(path, local)
-        path = str(local)
-    ids = []
-    with open(path, "r", encoding="utf-8") as f:
-        for line in f:
-            tid = line.strip()
-            if tid:
-                ids.append(tid)
-    return ids
-
-
 def _write_chunk(content: str, path: str) -> None:
     """Write chunk content to path (local or S3)."""
     if _is_s3(path):
@@ -79,18 +76,46 @@ def even_split(items: List[str], n: int) -> List[List[str]]:
         raise ValueError("n must be positive")
     if not items:
         return []
-    total = len(items)
-    base_size = total // n
-    remainder = total % n
     chunks = []
     start = 0
-    for i in range(n):
-        size = base_size + (1 if i < remainder else 0)
+    for size in chunk_sizes(len(items), n):
         chunks.append(items[start : start + size])
         start += size
     return chunks
 
 
+def chunk_sizes(total: int, n: int) -> List[int]:
+    """Sizes of n chunks of total items as even as possible (larger chunks first)."""
+    if n <= 0:
+        raise ValueError("n must be positive")
+    base_size, remainder = divmod(total, n)
+    return [base_size + (1 if i < remainder else 0) for i in range(n)]
+
+
+def _chunk_paths(run_base: str, i: int, n_chunks: int) -> Tuple[str, str]:
+    """(chunk IDs file, details output base) for chunk i of n_chunks."""
+    # Structure: tournament_id_chunks/ids_chunk_{i}_of_{n}.txt (n=chunk_count disambiguates runs)
+    if _is_s3(run_base):
+        return (
+            f"{run_base}/data/tournament_id_chunks/ids_chunk_{i}_of_{n_chunks}.txt",
+            f"{run_base}/data/tournament_details_chunks/details_chunk_{i}_of_{n_chunks}",
+        )
+    return (
+        str(
+            Path(run_base)
+            / "data"
+            / "tournament_id_chunks"
+            / f"ids_chunk_{i}_of_{n_chunks}.txt"
+        ),
+        str(
+            Path(run_base)
+            / "data"
+            / "tournament_details_chunks"
+            / f"details_chunk_{i}_of_{n_chunks}"
+        ),
+    )
+
+
 def run(
     ids_path: str,
     chunk_count: Optional[int] = None,
@@ -127,11 +152,6 @@ def run(
             f"Tournament IDs file not found: {ids_path}. Run get_tournaments first."
         )
 
-    ids = _read_ids(ids_path)
-    if not ids:
-        logger.error("No tournament IDs found in %s", ids_path)
-        return []
-
     if chunk_count is not None and chunk_count <= 0:
         logger.error("chunk_count must be positive")
         return []
@@ -139,12 +159,6 @@ def run(
         logger.error("chunk_size must be positive")
         return []
 
-    n_chunks = (
-        chunk_count
-        if chunk_count is not None
-        else max(1, (len(ids) + chunk_size - 1) // chunk_size)
-    )
-
     # Derive run_base from ids_path: .../data/tournament_ids.txt -> ... (run root)
     if _is_s3(ids_path):
         # s3://bucket/prod/2024-01/data/tournament_ids.txt -> s3://bucket/prod/2024-01
@@ -164,52 +178,93 @@ def run(
         else:
             run_base = str(ids_p.parent)
 
-    chunks = even_split(ids, n_chunks)
-    logger.info(
-        "Split %d IDs into %d chunks (sizes %s)",
-        len(ids),
-        len(chunks),
-        [len(c) for c in chunks],
+    # Progress of an earlier split counts only for the same, unchanged file; if
+    # the file changed, its chunks are stale and are rewritten
+    version = object_version(ids_path)
+    progress = (read_run_metadata(run_base) or {}).get(SPLIT_PROGRESS_KEY) or {}
+    rewrite = override or (
+        progress.get("ids_path") == ids_path and progress.get("version") != version
+    )
+    if (
+        override
+        or progress.get("ids_path") != ids_path
+        or progress.get("version") != version
+        or "total" not in progress
+    ):
+        progress = {}
+
+    if progress:
+        total = progress["total"]
+    else:
+        # Streaming count pass: the file is never held in memory
+        total = sum(1 for line, _ in iter_lines(ids_path) if line.strip())
+    if not total:
+        logger.error("No tournament IDs found in %s", ids_path)
+        return []
+
+    n_chunks = (
+        chunk_count
+        if chunk_count is not None
+        else max(1, (total + chunk_size - 1) // chunk_size)
     )
+    sizes = chunk_sizes(total, n_chunks)
+    logger.info("Split %d IDs into %d chunks (sizes %s)", total, n_chunks, sizes)
 
     # With _of_{n} naming, different chunk sizes produce different paths—no force_rewrite needed.
 
-    result = []
-    for i, chunk_ids in enumerate(chunks):
-        chunk_content = "\n".join(chunk_ids) + "\n"
-        # Structure: tournament_id_chunks/ids_chunk_{i}_of_{n}.txt (n=chunk_count disambiguates runs)
-        if _is_s3(run_base):
-            chunk_input_path = (
-                f"{run_base}/data/tournament_id_chunks/ids_chunk_{i}_of_{n_chunks}.txt"
-            )
-            chunk_output_path = f"{run_base}/data/tournament_details_chunks/details_chunk_{i}_of_{n_chunks}"
-        else:
-            chunk_input_path = str(
-                Path(run_base)
-                / "data"
-                / "tournament_id_chunks"
-                / f"ids_chunk_{i}_of_{n_chunks}.txt"
-            )
-            chunk_output_path = str(
-                Path(run_base)
-                / "data"
-                / "tournament_details_chunks"
-                / f"details_chunk_{i}_of_{n_chunks}"
-            )
+    # Resume where an interrupted split stopped: chunks before next_chunk are
+    # written, and the ID file is re-read from their end offset (HTTP range on S3)
+    next_chunk, offset = 0, 0
+    if progress.get("chunk_count") == n_chunks:
+        next_chunk, offset = progress["next_chunk"], progress["offset"]
+        logger.info("Resuming at chunk %d (byte offset %d)", next_chunk, offset)
 
-        if not override and _output_exists(chunk_input_path):
+    def _write(i: int, chunk_ids: List[str], end_offset: int) -> None:
+        chunk_input_path, _ = _chunk_paths(run_base, i, n_chunks)
+        if not rewrite and _output_exists(chunk_input_path):
             logger.info("Chunk %d already exists, skipping write", i)
         else:
-            _write_chunk(chunk_content, chunk_input_path)
+            _write_chunk("\n".join(chunk_ids) + "\n", chunk_input_path)
             logger.info(
                 "Wrote chunk %d (%d IDs) -> %s", i, len(chunk_ids), chunk_input_path
             )
+        write_run_metadata(
+            run_base,
+            {
+                SPLIT_PROGRESS_KEY: {
+                    "ids_path": ids_path,
+                    "version": version,
+                    "total": total,
+                    "chunk_count": n_chunks,
+                    "next_chunk": i + 1,
+                    "offset": end_offset,
+                }
+            },
+        )
 
+    i, chunk_ids = next_chunk, []
+    for line, end_offset in iter_lines(ids_path, start=offset):
+        tid = line.strip()
+        offset = end_offset
+        if not tid or i >= n_chunks:
+            continue
+        chunk_ids.append(tid)
+        if len(chunk_ids) == sizes[i]:
+            _write(i, chunk_ids, offset)
+            i, chunk_ids = i + 1, []
+    # Empty trailing chunks when there are more chunks than IDs
+    while i < n_chunks:
+        _write(i, chunk_ids, offset)
+        i, chunk_ids = i + 1, []
+
+    result = []
+    for i, size in enumerate(sizes):
+        chunk_input_path, chunk_output_path = _chunk_paths(run_base, i, n_chunks)
         result.append(
             {
                 "input_path": chunk_input_path,
                 "output_path": chunk_output_path,
-                "tournament_count": len(chunk_ids),
+                "tournament_count": size,
                 "chunk_index": i,
                 "chunk_count": n_chunks,
             }
@@ -267,6 +322,7 @@ def main() -> int:
         help="Print chunks as JSON to stdout",
     )
     args = parser.parse_args()
+    redact.install()
 
     chunks = run(
         ids_path=args.ids,
@@ -0,0 +1,54 @@
+"""
+Timestamp conventions for everything the pipeline records.
+
+Instants (scrape/merge/fetch times, run metadata) are UTC with an explicit offset:
+ISO 8601 strings end in "Z" (utc_timestamp) and datetimes are timezone-aware.
+
+Dates FIDE publishes (round dates, start/end, received/registered) are calendar
+dates with no time zone; the event's local date is all FIDE shows. We assume
+FIDE_DATE_TZ = UTC: each date is stored as midnight UTC of the published calendar
+day (fide_date). Because the stored value is already UTC, converting it to UTC
+never moves it to the previous day, so month bucketing always uses the date as
+published. Never localize these to another zone before taking .date() or the month.
+"""
+
+from datetime import date, datetime, timezone
+from typing import Optional
+
+FIDE_DATE_TZ = timezone.utc
+
+
+def utc_now() -> datetime:
+    """Current time as a timezone-aware UTC datetime."""
+    return datetime.now(timezone.utc)
+
+
+def utc_timestamp(dt: Optional[datetime] = None) -> str:
+    """ISO 8601 UTC timestamp with Z suffix (now if dt is None)."""
+    dt = dt or utc_now()
+    return dt.astimezone(timezone.utc).strftime("%Y-%m-%dT%H:%M:%SZ")
+
+
+def utc_compact_stamp(dt: Optional[datetime] = None) -> str:
+    """UTC stamp for file names, e.g. 20250101T120000Z (now if dt is None)."""
+    dt = dt or utc_now()
+    return dt.astimezone(timezone.utc).strftime("%Y%m%dT%H%M%SZ")
+
+
+def fide_date(value) -> Optional[datetime]:
+    """
+    A FIDE-published date as midnight in FIDE_DATE_TZ (UTC).
+
+    Accepts date, datetime or pandas Timestamp. Only the calendar day is kept: an
+    aware value keeps its own wall-clock date rather than being converted, so a
+    date is never shifted across a day (or month) boundary. None/NaT -> None.
+    """
+    if value is None or value != value:  # NaT/NaN compare unequal to themselves
+        return None
+    if isinstance(value, datetime):
+        day = value.date()
+    elif isinstance(value, date):
+        day = value
+    else:
+        return None
+    return datetime(day.year, day.month, day.day, tzinfo=FIDE_DATE_TZ)
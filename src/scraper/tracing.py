@@ -0,0 +1,175 @@
+"""
+Optional OpenTelemetry tracing for the scrapers and the pipeline runner.
+
+Tracing is off unless an OTLP endpoint is set (--otlp-endpoint or the standard
+OTEL_EXPORTER_OTLP_ENDPOINT variable) and the OpenTelemetry packages are
+installed (`uv sync --extra tracing`). When off, span() is a no-op, so call sites
+need no checks.
+
+Spans are exported over OTLP/HTTP in batches:
+
+  pipeline                  run_full_pipeline.py, one span per run
+    stage                   one per stage (stage=details, ...)
+      tournament_details    get_tournament_details.py, one span per run
+        fetch_details       one per fetch (tournament_id, retry_pass, attempts,
+                            error)
+      tournament_reports    get_tournament_reports.py
+        fetch_report        (tournament_code, ...)
+
+Subprocesses join the parent's trace through the TRACEPARENT environment
+variable (W3C trace context): child_env() adds it, and configure() picks it up.
+The details and reports chunk Lambdas take both from their event instead
+(otlp_endpoint, traceparent; see handlers/scrape_options.py).
+"""
+
+import argparse
+import atexit
+import logging
+import os
+from contextlib import contextmanager
+from typing import Any, Dict, Iterator, Optional
+
+logger = logging.getLogger(__name__)
+
+ENDPOINT_ENV = "OTEL_EXPORTER_OTLP_ENDPOINT"
+TRACEPARENT_ENV = "TRACEPARENT"
+
+_config: Dict[str, Any] = {
+    "tracer": None,
+    "provider": None,
+    "root": None,
+    "endpoint": None,
+}
+
+
+def _attributes(attributes: Dict[str, Any]) -> Dict[str, Any]:
+    """Drop None values and stringify anything OpenTelemetry cannot store."""
+    return {
+        k: v if isinstance(v, (bool, int, float, str)) else str(v)
+        for k, v in attributes.items()
+        if v is not None
+    }
+
+
+def configure(
+    service_name: str,
+    endpoint: Optional[str] = None,
+    traceparent: Optional[str] = None,
+    **attributes: Any,
+) -> bool:
+    """
+    Set up export to endpoint (else $OTEL_EXPORTER_OTLP_ENDPOINT) and start a root
+    span named service_name, ended at exit or by shutdown(). The span joins the
+    trace in traceparent (else $TRACEPARENT), if any. Returns True if tracing is on.
+    """
+    endpoint = endpoint or os.environ.get(ENDPOINT_ENV)
+    if not endpoint:
+        return False
+    try:
+        from opentelemetry import context, trace
+        from opentelemetry.exporter.otlp.proto.http.trace_exporter import (
+            OTLPSpanExporter,
+        )
+        from opentelemetry.sdk.resources import Resource
+        from opentelemetry.sdk.trace import TracerProvider
+        from opentelemetry.sdk.trace.export import BatchSpanProcessor
+        from opentelemetry.trace.propagation.tracecontext import (
+            TraceContextTextMapPropagator,
+        )
+    except ImportError:
+        logger.warning(
+            "Tracing requested but OpenTelemetry is not installed "
+            "(uv sync --extra tracing); continuing without it"
+        )
+        return False
+
+    provider = TracerProvider(resource=Resource.create({"service.name": service_name}))
+    exporter = OTLPSpanExporter(endpoint=f"{endpoint.rstrip('/')}/v1/traces")
+    provider.add_span_processor(BatchSpanProcessor(exporter))
+    tracer = provider.get_tracer("fide-glicko")
+
+    parent = None
+    traceparent = traceparent or os.environ.get(TRACEPARENT_ENV)
+    if traceparent:
+        parent = TraceContextTextMapPropagator().extract({"traceparent": traceparent})
+    root = tracer.start_span(
+        service_name, context=parent, attributes=_attributes(attributes)
+    )
+    context.attach(trace.set_span_in_context(root))
+    _config.update(tracer=tracer, provider=provider, root=root, endpoint=endpoint)
+    atexit.register(shutdown)
+    logger.info("Tracing to %s as %s", endpoint, service_name)
+    return True
+
+
+def shutdown() -> None:
+    """End the root span and flush pending spans (safe to call twice)."""
+    root, provider = _config["root"], _config["provider"]
+    _config.update(tracer=None, provider=None, root=None, endpoint=None)
+    if root is not None:
+        root.end()
+    if provider is not None:
+        provider.shutdown()
+
+
+def enabled() -> bool:
+    """True once configure() has turned tracing on."""
+    return _config["tracer"] is not None
+
+
+@contextmanager
+def span(name: str, **attributes: Any) -> Iterator[Optional[Any]]:
+    """Child span of the current one, or None when tracing is off."""
+    tracer = _config["tracer"]
+    if tracer is None:
+        yield None
+        return
+    with tracer.start_as_current_span(name, attributes=_attributes(attributes)) as s:
+        yield s
+
+
+def set_attributes(current: Optional[Any], **attributes: Any) -> None:
+    """Add attributes to a span from span(); an error attribute marks it failed."""
+    if current is None:
+        return
+    current.set_attributes(_attributes(attributes))
+    if attributes.get("error"):
+        from opentelemetry.trace import Status, StatusCode
+
+        current.set_status(Status(StatusCode.ERROR, str(attributes["error"])))
+
+
+def child_env(env: Optional[Dict[str, str]] = None) -> Dict[str, str]:
+    """
+    Copy of env (default os.environ) with the endpoint and TRACEPARENT for the
+    current span, so a subprocess that calls configure() joins this trace.
+    """
+    out = dict(os.environ if env is None else env)
+    if enabled():
+        out[ENDPOINT_ENV] = _config["endpoint"]
+        from opentelemetry.trace.propagation.tracecontext import (
+            TraceContextTextMapPropagator,
+        )
+
+        carrier: Dict[str, str] = {}
+        TraceContextTextMapPropagator().inject(carrier)
+        if "traceparent" in carrier:
+            out[TRACEPARENT_ENV] = carrier["traceparent"]
+    return out
+
+
+def add_arguments(parser: argparse.ArgumentParser) -> None:
+    """Add --otlp-endpoint to parser."""
+    parser.add_argument(
+        "--otlp-endpoint",
+        default=None,
+        help=f"Export OpenTelemetry traces to this OTLP/HTTP endpoint "
+        f"(default: ${ENDPOINT_ENV}; off if unset)",
+    )
+
+
+def configure_from_args(
+    args: argparse.Namespace, service_name: str, **attributes: Any
+) -> bool:
+    """configure() from the add_arguments() flag."""
+    return configure(service_name, args.otlp_endpoint, **attributes)
@@ -0,0 +1,104 @@
+"""
+Browser identities (User-Agent plus matching headers) for FIDE requests.
+
+An identity is a dict of headers that a real browser sends together: a Firefox
+User-Agent goes with Firefox's Accept and Accept-Language, never Chrome's. Each
+worker (a CLI process or a Lambda chunk) keeps one identity for its whole run,
+picked by worker id, so a scrape spread over many workers does not present one
+UA string while no single session switches fingerprints mid-run.
+
+The pool defaults to DEFAULT_IDENTITIES; --user-agents FILE replaces it with a
+JSON list of header objects, each with at least "User-Agent":
+
+  [
+    {"User-Agent": "Mozilla/5.0 ...", "Accept": "...", "Accept-Language": "..."},
+    ...
+  ]
+"""
+
+import json
+from pathlib import Path
+from typing import Dict, List, Optional
+
+_CHROME_ACCEPT = (
+    "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8"
+)
+_FIREFOX_ACCEPT = (
+    "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,"
+    "image/webp,*/*;q=0.8"
+)
+_SAFARI_ACCEPT = "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+
+# Worker 0 gets the first identity (the UA the scrapers have always sent)
+DEFAULT_IDENTITIES: List[Dict[str, str]] = [
+    {
+        "User-Agent": "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 "
+        "(KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+        "Accept": _CHROME_ACCEPT,
+        "Accept-Language": "en-US,en;q=0.9",
+    },
+    {
+        "User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 "
+        "(KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+        "Accept": _CHROME_ACCEPT,
+        "Accept-Language": "en-GB,en;q=0.9",
+    },
+    {
+        "User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) "
+        "Gecko/20100101 Firefox/121.0",
+        "Accept": _FIREFOX_ACCEPT,
+        "Accept-Language": "en-US,en;q=0.5",
+    },
+    {
+        "User-Agent": "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) "
+        "AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15",
+        "Accept": _SAFARI_ACCEPT,
+        "Accept-Language": "en-US,en;q=0.9",
+    },
+]
+
+_config = {"identities": DEFAULT_IDENTITIES, "worker": 0}
+
+
+def validate_identities(identities) -> List[Dict[str, str]]:
+    """Check a pool is a non-empty list of header dicts with a User-Agent."""
+    if not isinstance(identities, list) or not identities:
+        raise ValueError("User-agent pool must be a non-empty JSON list")
+    for i, identity in enumerate(identities):
+        if not isinstance(identity, dict) or not identity.get("User-Agent"):
+            raise ValueError(f"User-agent pool entry {i} has no User-Agent")
+        if not all(isinstance(v, str) for v in identity.values()):
+            raise ValueError(f"User-agent pool entry {i} has non-string headers")
+    return identities
+
+
+def load_identities(path: str | Path) -> List[Dict[str, str]]:
+    """Read a JSON identity pool from path."""
+    with open(path, encoding="utf-8") as f:
+        return validate_identities(json.load(f))
+
+
+def configure(
+    identities: Optional[List[Dict[str, str]]] = None, worker: int = 0
+) -> None:
+    """Set the pool (default: DEFAULT_IDENTITIES) and this process's worker id."""
+    _config["identities"] = (
+        validate_identities(identities) if identities else DEFAULT_IDENTITIES
+    )
+    _config["worker"] = worker
+
+
+def identity_for_worker(
+    worker: int, identities: Optional[List[Dict[str, str]]] = None
+) -> Dict[str, str]:
+    """Identity assigned to worker: round-robin over the pool by worker id."""
+    pool = identities or _config["identities"]
+    return pool[worker % len(pool)]
+
+
+def browser_headers(extra: Optional[Dict[str, str]] = None) -> Dict[str, str]:
+    """Headers for the configured worker's identity, plus request-specific extras."""
+    headers = dict(identity_for_worker(_config["worker"]))
+    if extra:
+        headers.update(extra)
+    return headers
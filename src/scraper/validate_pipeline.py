@@ -38,10 +38,16 @@ def validate_player_list_vs_reports(
     reports_path: str | Path,
     *,
     max_sample: int = 20,
+    queue_path: str | None = None,
+    profiles_path: str | Path | None = None,
 ) -> dict:
     """
     Compare player IDs in reports with player list.
     Returns summary dict with errors, missing_count, sample missing IDs.
+    With queue_path, missing IDs are also queued for profile scraping (see
+    profile_queue.py) and the queue counts are returned under "profile_queue".
+    IDs in profiles_path (get_player_profiles.py output) are already scraped:
+    they are counted under "in_profiles" and not queued again.
     """
     players_path = Path(players_path)
     reports_path = Path(reports_path)
@@ -72,12 +78,26 @@ def validate_player_list_vs_reports(
     report_ids = white_ids | black_ids
 
     missing = report_ids - player_ids
-    return {
+    result = {
         "total_in_player_list": len(player_ids),
         "total_in_reports": len(report_ids),
         "missing_in_player_list": len(missing),
         "sample_missing": sorted(missing)[:max_sample],
     }
+    if profiles_path and Path(profiles_path).exists():
+        from profile_queue import valid_ids
+
+        profile_ids = valid_ids(pd.read_parquet(profiles_path, columns=["id"])["id"])
+        result["in_profiles"] = len(missing & profile_ids)
+    else:
+        profile_ids = set()
+    if queue_path:
+        from profile_queue import update_queue
+
+        result["profile_queue"] = update_queue(
+            queue_path, missing, player_ids | profile_ids
+        )
+    return result
 
 
 def validate_details_vs_reports(
@@ -163,6 +183,25 @@ def validate_details_vs_reports(
                 }
             )
 
+    # Crosstable rounds beyond the details page's n_rounds (when the page has one)
+    round_mismatches = []
+    if "n_rounds" in success_details.columns and "round_number" in reports_df.columns:
+        played = (
+            pd.to_numeric(reports_df["round_number"], errors="coerce")
+            .groupby(reports_df[tc_col].astype(str))
+            .max()
+        )
+        for _, row in success_details.dropna(subset=["n_rounds"]).iterrows():
+            tc = row["event_code_str"]
+            if tc in played.index and played[tc] > row["n_rounds"]:
+                round_mismatches.append(
+                    {
+                        "tournament_code": tc,
+                        "details_rounds": int(row["n_rounds"]),
+                        "reports_max_round": int(played[tc]),
+                    }
+                )
+
     date_issues = []
     for tc in list(report_codes & detail_codes)[:500]:
         rep_dates = reports_df[reports_df[tc_col] == tc][date_col].dropna()
@@ -206,6 +245,8 @@ def validate_details_vs_reports(
         "sample_in_details_not_reports": sorted(in_details_not_reports)[:max_sample],
         "player_count_mismatches": len(count_mismatches),
         "sample_count_mismatches": count_mismatches[:max_sample],
+        "round_count_mismatches": len(round_mismatches),
+        "sample_round_mismatches": round_mismatches[:max_sample],
         "date_issues": len(date_issues),
         "sample_date_issues": date_issues[:max_sample],
     }
@@ -242,6 +283,53 @@ def _collect_skipped_tournaments(bucket: str, base: str) -> dict:
     return {"total": total, "by_error": by_error}
 
 
+def _collect_partial_parses(bucket: str, base: str, max_sample: int = 20) -> dict:
+    """
+    Aggregate all *_partial.json files written by reports_chunk into a summary.
+    Returns {"tournaments": N, "skipped_rows": M, "by_reason": {...}, "sample": [...]}
+    """
+    import boto3
+
+    s3 = boto3.client("s3")
+    prefix = f"{base}/reports/tournament_reports_chunks/"
+    tournaments = 0
+    skipped_rows = 0
+    by_reason: dict[str, int] = {}
+    sample: list[dict] = []
+    paginator = s3.get_paginator("list_objects_v2")
+    for page in paginator.paginate(Bucket=bucket, Prefix=prefix):
+        for obj in page.get("Contents", []):
+            key = obj["Key"]
+            if not key.endswith("_partial.json"):
+                continue
+            try:
+                body = s3.get_object(Bucket=bucket, Key=key)["Body"].read()
+                entries = json.loads(body)
+            except Exception:
+                continue
+            for entry in entries:
+                rows = entry.get("skipped_rows", [])
+                tournaments += 1
+                skipped_rows += len(rows)
+                for row in rows:
+                    reason = row.get("reason", "unknown")
+                    by_reason[reason] = by_reason.get(reason, 0) + 1
+                if len(sample) < max_sample:
+                    sample.append(
+                        {
+                            "tournament_code": str(entry.get("tournament_code", "?")),
+                            "skipped_rows": len(rows),
+                        }
+                    )
+
+    return {
+        "tournaments": tournaments,
+        "skipped_rows": skipped_rows,
+        "by_reason": by_reason,
+        "sample": sample,
+    }
+
+
 def run(
     bucket: str,
     run_type: str,
@@ -256,6 +344,9 @@ def run(
     - details: {base}/data/tournament_details.parquet
     - reports: {base}/data/tournament_reports_games.parquet
     - players: latest in {bucket}/player_lists/data/
+    - profile queue: {bucket}/player_lists/queue/missing_player_ids.txt (updated)
+    - profiles: {bucket}/player_lists/profiles/player_profiles.parquet (IDs in it
+      are not queued)
 
     Args:
         bucket: S3 bucket.
@@ -269,7 +360,10 @@ def run(
     import logging
     import tempfile
 
+    from provenance import build_provenance
     from s3_io import (
+        build_profile_queue_uri,
+        build_profiles_uri,
         build_run_base,
         build_s3_uri_for_run,
         download_to_file,
@@ -306,11 +400,23 @@ def run(
         players_path = download_to_file(players_uri, tmp_path / "players.parquet")
         details_path = download_to_file(details_uri, tmp_path / "details.parquet")
         reports_path = download_to_file(reports_uri, tmp_path / "reports.parquet")
+        profiles_uri = build_profiles_uri(bucket)
+        profiles_path = (
+            download_to_file(profiles_uri, tmp_path / "profiles.parquet")
+            if output_exists(profiles_uri)
+            else None
+        )
 
-        pl_result = validate_player_list_vs_reports(players_path, reports_path)
+        pl_result = validate_player_list_vs_reports(
+            players_path,
+            reports_path,
+            queue_path=build_profile_queue_uri(bucket),
+            profiles_path=profiles_path,
+        )
         dt_result = validate_details_vs_reports(details_path, reports_path)
 
     skipped = _collect_skipped_tournaments(bucket, base)
+    partial = _collect_partial_parses(bucket, base)
 
     has_issues = False
     if "error" in pl_result:
@@ -321,15 +427,18 @@ def run(
         has_issues = True
     elif (
         dt_result.get("player_count_mismatches", 0) > 0
+        or dt_result.get("round_count_mismatches", 0) > 0
         or dt_result.get("date_issues", 0) > 0
     ):
         has_issues = True
 
     report_dict = {
+        "provenance": build_provenance(run_type=run_type, run_name=run_name),
         "run_type": run_type,
         "run_name": run_name or "",
         "has_issues": has_issues,
         "skipped_tournaments": skipped,
+        "partial_parses": partial,
         "player_list_vs_reports": pl_result,
         "details_vs_reports": dt_result,
     }
@@ -0,0 +1,66 @@
+"""
+Tests for age_categories.py.
+
+Offline: FIDE calendar-year age rules at the junior and senior boundaries.
+"""
+
+import pandas as pd
+import pytest
+
+from age_categories import (
+    age_in_year,
+    category_mask,
+    eligible_categories,
+    is_eligible,
+    is_junior,
+    is_senior,
+)
+
+
+class TestBoundaries:
+    def test_turning_20_during_list_year_is_still_junior(self):
+        # Born 2004: turns 20 at some point in 2024, junior all year
+        assert is_junior(2004, 2024)
+        assert not is_junior(2004, 2025)
+        assert not is_junior(2003, 2024)
+
+    def test_turning_50_during_list_year_is_already_senior(self):
+        # Born 1974: turns 50 at some point in 2024, senior from January
+        assert is_senior(1974, 2024)
+        assert not is_senior(1975, 2024)
+        assert is_eligible(1959, 2024, "S65")
+        assert not is_eligible(1960, 2024, "S65")
+
+    def test_youth_categories(self):
+        assert eligible_categories(2012, 2024) == ["U12", "U14", "U16", "U18", "U20"]
+        assert eligible_categories(1950, 2024) == ["S50", "S65"]
+        assert eligible_categories(1990, 2024) == []
+
+
+@pytest.mark.parametrize("byear", [None, float("nan"), 0])
+def test_missing_birth_year_is_in_no_category(byear):
+    assert age_in_year(byear, 2024) is None
+    assert eligible_categories(byear, 2024) == []
+
+
+def test_unknown_category():
+    with pytest.raises(ValueError):
+        is_eligible(2000, 2024, "U21")
+
+
+def test_category_mask_matches_scalar_rules():
+    byear = pd.Series([2004.0, 2003.0, None, 1974.0, 0.0])
+    assert category_mask(byear, 2024, "U20").tolist() == [
+        True,
+        False,
+        False,
+        False,
+        False,
+    ]
+    assert category_mask(byear, 2024, "S50").tolist() == [
+        False,
+        False,
+        False,
+        True,
+        False,
+    ]
@@ -0,0 +1,102 @@
+"""Unit tests for response anomaly detection (anomaly.py)."""
+
+from pathlib import Path
+
+import anomaly
+
+FIXTURES = Path(__file__).parent / "fixtures"
+
+
+def _monitor(tmp_path, alerts, sleeps, monkeypatch, **kwargs):
+    monkeypatch.setattr(anomaly.disk_guard, "send_alert", alerts.append)
+    return anomaly.ResponseMonitor(
+        "reports",
+        min_samples=5,
+        consecutive=3,
+        pause_seconds=60,
+        snapshot_dir=tmp_path,
+        sleep=sleeps.append,
+        **kwargs,
+    )
+
+
+def _warm_up(monitor, n=10):
+    for i in range(n):
+        assert not monitor.observe(b"x" * 40_000, 30, key=str(i))
+
+
+class TestResponseMonitor:
+    def test_normal_variation_does_not_alarm(self, tmp_path, monkeypatch):
+        alerts, sleeps = [], []
+        monitor = _monitor(tmp_path, alerts, sleeps, monkeypatch)
+        _warm_up(monitor)
+        for size, rows in [(35_000, 8), (90_000, 200), (20_000, 10)]:
+            assert not monitor.observe(b"x" * size, rows)
+        assert alerts == [] and sleeps == []
+
+    def test_streak_of_stub_pages_alarms_snapshots_and_pauses(
+        self, tmp_path, monkeypatch
+    ):
+        alerts, sleeps = [], []
+        monitor = _monitor(tmp_path, alerts, sleeps, monkeypatch)
+        _warm_up(monitor)
+
+        stub = b"<html>Service temporarily unavailable</html>"
+        results = [monitor.observe(stub, 0, key=f"40{i}") for i in range(3)]
+
+        assert results == [False, False, True]
+        assert sleeps == [60]
+        assert len(alerts) == 1 and "402" in alerts[0]
+        snapshots = list(tmp_path.glob("reports_402_*.html"))
+        assert len(snapshots) == 1
+        assert snapshots[0].read_bytes() == stub
+
+    def test_run_of_small_tournaments_does_not_alarm(self, tmp_path, monkeypatch):
+        alerts, sleeps = [], []
+        monitor = _monitor(tmp_path, alerts, sleeps, monkeypatch)
+        large = (FIXTURES / "world_cup_25_report.html").read_bytes()
+        small = (FIXTURES / "blitz_playoff_418871_report.html").read_bytes()
+        for i in range(10):
+            assert not monitor.observe(large, 206, key=f"wc{i}")
+        # Two-player playoffs: under 1% of the median size and rows, but real pages
+        for i in range(20):
+            assert not monitor.observe(small, 2, key=f"playoff{i}")
+        assert alerts == [] and sleeps == []
+
+    def test_full_size_empty_pages_do_not_alarm(self, tmp_path, monkeypatch):
+        alerts, sleeps = [], []
+        monitor = _monitor(tmp_path, alerts, sleeps, monkeypatch)
+        # "No data found" pages have the full page layout, before and after warm-up
+        for i in range(5):
+            assert not monitor.observe(b"x" * 20_000, 0, key=f"empty{i}")
+        _warm_up(monitor)
+        for i in range(5):
+            assert not monitor.observe(b"x" * 20_000, 0, key=f"empty{i}")
+        assert alerts == [] and sleeps == []
+        assert 0 not in monitor._rows
+
+    def test_normal_response_resets_streak(self, tmp_path, monkeypatch):
+        alerts, sleeps = [], []
+        monitor = _monitor(tmp_path, alerts, sleeps, monkeypatch)
+        _warm_up(monitor)
+        for _ in range(2):
+            monitor.observe(b"tiny", 1)
+        monitor.observe(b"x" * 40_000, 30)
+        for _ in range(2):
+            monitor.observe(b"tiny", 1)
+        assert alerts == []
+
+    def test_shrunken_pages_only_flagged_after_warm_up(self, tmp_path, monkeypatch):
+        alerts, sleeps = [], []
+        monitor = _monitor(tmp_path, alerts, sleeps, monkeypatch)
+        assert not monitor.is_anomalous(100, 1)
+        assert monitor.is_anomalous(100, 0)
+        _warm_up(monitor)
+        assert monitor.is_anomalous(100, 1)
+
+
+class TestModuleMonitors:
+    def test_observe_is_noop_until_configured(self):
+        anomaly.disable("details")
+        for _ in range(20):
+            assert not anomaly.observe("details", b"", 0)
@@ -0,0 +1,87 @@
+"""
+Tests for anonymize_export.py.
+
+Offline: keyed pseudonyms, name stripping per table and the key file.
+"""
+
+import pandas as pd
+
+from anonymize_export import anonymize, load_or_create_key, pseudonym
+
+KEY = bytes(32)
+
+
+def test_pseudonym_is_keyed_and_stable():
+    assert pseudonym(KEY, "1503014") == pseudonym(KEY, 1503014)
+    assert len(pseudonym(KEY, "1503014")) == 16
+    assert pseudonym(KEY, "1503014") != pseudonym(b"other key", "1503014")
+    assert pseudonym(KEY, "") == ""
+
+
+def test_anonymize_games_and_players_join_on_pseudonyms():
+    games = pd.DataFrame(
+        {
+            "white_player_id": ["1503014", "2020009"],
+            "black_player_id": ["2020009", ""],
+            "tournament_id": ["368357", "368357"],
+        }
+    )
+    players = pd.DataFrame(
+        {
+            "player_id": ["1503014"],
+            "player_name": ["Carlsen, Magnus"],
+            "player_country": ["NOR"],
+        }
+    )
+    mapping = {}
+    g = anonymize(games, KEY, mapping)
+    p = anonymize(players, KEY, mapping)
+
+    assert "player_name" not in p.columns
+    assert p["player_country"].tolist() == ["NOR"]
+    assert g["tournament_id"].tolist() == ["368357", "368357"]
+    assert g.loc[0, "white_player_id"] == p.loc[0, "player_id"]
+    assert g.loc[0, "black_player_id"] == g.loc[1, "white_player_id"]
+    assert g.loc[1, "black_player_id"] == ""
+    assert set(mapping) == {"1503014", "2020009"}
+    assert "1503014" not in g.values
+
+
+def test_key_file_created_once(tmp_path):
+    path = tmp_path / "keys" / "pseudonym.key"
+    key = load_or_create_key(path)
+    assert len(key) == 32
+    assert load_or_create_key(path) == key
+    assert path.stat().st_mode & 0o777 == 0o600
+
+
+def test_anonymize_details_keeps_event_id_and_name():
+    details = pd.DataFrame(
+        {
+            "tournament_id": ["368261"],
+            "id": ["368261"],
+            "name": ["FIDE Candidates Tournament 2024"],
+            "chief_arbiter_names": [["Marghetis, Aris"]],
+            "chief_arbiter_fide_ids": [["2611058"]],
+            "organizer_names": [["Chess Club", "Doe, Jane"]],
+            "organizer_fide_ids": [[None, "100"]],
+        }
+    )
+    mapping = {}
+    d = anonymize(details, KEY, mapping)
+
+    assert d["id"].tolist() == ["368261"]
+    assert d["name"].tolist() == ["FIDE Candidates Tournament 2024"]
+    assert "chief_arbiter_names" not in d.columns
+    assert "organizer_names" not in d.columns
+    assert d.loc[0, "chief_arbiter_fide_ids"] == [pseudonym(KEY, "2611058")]
+    assert d.loc[0, "organizer_fide_ids"] == [None, pseudonym(KEY, "100")]
+    assert set(mapping) == {"2611058", "100"}
+
+
+def test_anonymize_player_list_id_and_name():
+    players = pd.DataFrame({"id": [1503014], "name": ["Carlsen, Magnus"]})
+    mapping = {}
+    p = anonymize(players, KEY, mapping)
+    assert "name" not in p.columns
+    assert p["id"].tolist() == [pseudonym(KEY, "1503014")]
@@ -0,0 +1,146 @@
+"""
+Tests for artifact_schemas.py.
+
+Offline: the schema subset validator, file-name inference, and scraper output from
+a real report fixture checked against the published schemas.
+"""
+
+import json
+from pathlib import Path
+from unittest.mock import MagicMock
+
+import pandas as pd
+
+from artifact_schemas import (
+    SCHEMA_DIR,
+    check_value,
+    load_schema,
+    schema_for_path,
+    schema_names,
+    to_json_value,
+    validate_dataframe,
+)
+from get_tournament_details import fetch_tournament_details, results_to_dataframe
+from get_tournament_reports import (
+    fetch_tournament_report,
+    results_to_games_dataframe,
+    results_to_players_dataframe,
+)
+
+
+def test_published_schemas_are_draft_2020_12_objects():
+    assert schema_names() == [
+        "player_list",
+        "tournament_details",
+        "tournament_reports_games",
+        "tournament_reports_players",
+    ]
+    for path in SCHEMA_DIR.glob("*.schema.json"):
+        schema = json.loads(path.read_text(encoding="utf-8"))
+        assert schema["$schema"].endswith("/draft/2020-12/schema")
+        assert schema["additionalProperties"] is False
+        assert set(schema["required"]) <= set(schema["properties"])
+
+
+def test_schema_for_path():
+    assert schema_for_path("x/tournament_reports_games.parquet") == (
+        "tournament_reports_games"
+    )
+    assert schema_for_path("2024_01_players.parquet") == "tournament_reports_players"
+    assert schema_for_path("player_list_20250101-000000.parquet") == "player_list"
+    assert schema_for_path("tournament_details.parquet") == "tournament_details"
+    assert schema_for_path("federations.csv") is None
+
+
+class TestCheckValue:
+    def test_types_and_nulls(self):
+        prop = {"type": ["integer", "null"]}
+        assert check_value(3, prop) is None
+        assert check_value(1985.0, prop) is None
+        assert check_value(None, prop) is None
+        assert check_value(True, prop) is not None
+        assert check_value("3", prop) is not None
+
+    def test_enum_pattern_minimum_format(self):
+        assert check_value(0.5, {"enum": [0, 0.5, 1]}) is None
+        assert check_value(0.25, {"enum": [0, 0.5, 1]}) is not None
+        assert check_value("12a", {"type": "string", "pattern": "^[0-9]+$"})
+        assert check_value(0, {"type": "integer", "minimum": 1})
+        assert check_value("", {"type": ["string", "null"], "minLength": 1})
+        assert check_value(None, {"type": ["string", "null"], "minLength": 1}) is None
+        assert check_value("2024-01-01T00:00:00+00:00", {"format": "date-time"}) is None
+        assert check_value("2024-01-01", {"format": "date-time"})
+
+    def test_array_items(self):
+        prop = {"type": ["array", "null"], "items": {"type": "string", "minLength": 1}}
+        assert check_value(["Howie, Andrew"], prop) is None
+        assert check_value([], prop) is None
+        assert check_value(None, prop) is None
+        assert check_value("Howie, Andrew", prop)
+        assert check_value(["ok", ""], prop).startswith("item 1:")
+
+    def test_to_json_value(self):
+        assert to_json_value(float("nan")) is None
+        assert to_json_value(pd.NaT) is None
+        assert to_json_value(pd.Timestamp("2024-01-01", tz="UTC")) == (
+            "2024-01-01T00:00:00+00:00"
+        )
+
+
+def test_validate_dataframe_reports_missing_extra_and_bad_values():
+    df = pd.DataFrame(
+        {
+            "byear": [1990.0, None],
+            "id": [1503014, 0],
+            "fed": ["NOR", "norway"],
+            "name": ["Carlsen, Magnus", None],
+            "sex": ["M", "X"],
+            "title": ["GM", None],
+            "rating": [2830, 0],
+        }
+    )
+    result = validate_dataframe(df, load_schema("player_list"))
+    assert result["rows"] == 2
+    assert {(e["column"], e["row"]) for e in result["errors"]} == {
+        ("w_title", None),
+        ("rating", None),
+        ("id", 1),
+        ("fed", 1),
+        ("sex", 1),
+    }
+
+
+def test_report_fixture_output_matches_published_schemas():
+    fixtures = Path(__file__).parent / "fixtures"
+    fixture = fixtures / "double_round_robin_900002_report.html"
+    response = MagicMock(status_code=200, content=fixture.read_bytes())
+    session = MagicMock()
+    session.get.return_value = response
+    report, error, _, _ = fetch_tournament_report("900002", session)
+    assert error is None
+    results = [{**report, "success": True}]
+
+    games = results_to_games_dataframe(results)
+    players = results_to_players_dataframe(results)
+    assert len(games) and len(players)
+    for df, name in (
+        (games, "tournament_reports_games"),
+        (players, "tournament_reports_players"),
+    ):
+        assert validate_dataframe(df, load_schema(name))["errors"] == []
+
+
+def test_details_fixture_output_matches_published_schema():
+    fixture = Path(__file__).parent / "fixtures" / "candidates_24_details.html"
+    response = MagicMock(status_code=200, content=fixture.read_bytes())
+    session = MagicMock()
+    session.get.return_value = response
+    details, error, _, _ = fetch_tournament_details("368261", session)
+    assert error is None
+    df = results_to_dataframe(
+        [
+            {"tournament_id": "368261", "success": True, "details": details},
+            {"tournament_id": "368262", "success": False, "error": "HTTP 500"},
+        ]
+    )
+    assert validate_dataframe(df, load_schema("tournament_details"))["errors"] == []
@@ -0,0 +1,82 @@
+"""
+Tests for boundary_audit.py.
+
+Offline: policy periods, ambiguity flags and the summary.
+"""
+
+import pandas as pd
+
+from boundary_audit import audit, listed_period, summarize
+
+
+def _details():
+    return pd.DataFrame(
+        {
+            "id": ["1", "2", "3", "4"],
+            "name": ["Mid-month", "Spans", "Month end", "Late report"],
+            "start_date": pd.to_datetime(
+                ["2024-01-10", "2024-01-28", "2024-01-25", "2024-01-05"], utc=True
+            ),
+            "end_date": pd.to_datetime(
+                ["2024-01-14", "2024-02-03", "2024-01-31", "2024-01-12"], utc=True
+            ),
+            "date_received": pd.to_datetime(
+                ["2024-01-20", "2024-02-05", "2024-02-01", "2024-03-20"], utc=True
+            ),
+            "listed": ["2024-01", "2024-02", "2024-01", "2024-03"],
+        }
+    )
+
+
+def _by_id(df):
+    return df.set_index("tournament_id")
+
+
+class TestAudit:
+    def test_clear_tournament_not_flagged(self):
+        flagged = _by_id(audit(_details()))
+        assert "1" not in flagged.index
+
+    def test_reasons_and_policy_periods(self):
+        flagged = _by_id(audit(_details()))
+
+        spans = flagged.loc["2"]
+        assert set(spans["reasons"].split(";")) == {
+            "spans_months",
+            "near_boundary",
+            "policies_differ",
+        }
+        assert spans["period_start_date"] == "2024-01"
+        assert spans["period_end_date"] == "2024-02"
+
+        month_end = flagged.loc["3"]
+        assert "near_boundary" in month_end["reasons"]
+        assert month_end["period_date_received"] == "2024-02"
+
+        late = flagged.loc["4"]
+        assert "late_received" in late["reasons"]
+        assert late["period_listed"] == "2024-03"
+
+    def test_near_days_threshold(self):
+        assert audit(_details().iloc[[0]]).empty
+        flagged = audit(_details().iloc[[0]], near_days=20)
+        assert "near_boundary" in flagged.loc[0, "reasons"]
+
+
+class TestSummary:
+    def test_counts_disagreements_with_listed(self):
+        flagged = audit(_details())
+        summary = summarize(flagged, total=4)
+        assert summary["tournaments"] == 4
+        assert summary["flagged"] == 3
+        assert summary["by_reason"]["late_received"] == 1
+        # end_date differs from listed for "Late report" only (Spans ends in Feb)
+        assert summary["disagrees_with_listed"]["end_date"] == 1
+        assert summary["disagrees_with_listed"]["start_date"] == 2
+
+
+def test_listed_period_from_prod_path():
+    assert listed_period("data/prod/2024-01/data/tournament_details.parquet") == (
+        "2024-01"
+    )
+    assert listed_period("custom/details.parquet") == ""
@@ -0,0 +1,125 @@
+"""Unit tests for resumable bulk downloads (bulk_download.py)."""
+
+import io
+import zipfile
+
+import pytest
+import requests
+
+import bulk_download
+from bulk_download import DownloadError, VerificationError, download_file
+
+
+def _zip_bytes():
+    buf = io.BytesIO()
+    with zipfile.ZipFile(buf, "w") as zf:
+        zf.writestr("players_list_xml_foa.xml", "<playerslist>" + "x" * 500 + "</")
+    return buf.getvalue()
+
+
+class FakeResponse:
+    def __init__(self, status, body, headers, fail_after=None):
+        self.status_code = status
+        self.headers = headers
+        self._body = body
+        self._fail_after = fail_after
+
+    def __enter__(self):
+        return self
+
+    def __exit__(self, *exc):
+        return False
+
+    def raise_for_status(self):
+        if self.status_code >= 400:
+            raise requests.HTTPError(f"HTTP {self.status_code}")
+
+    def iter_content(self, chunk_size):
+        for i in range(0, len(self._body), 10):
+            if self._fail_after is not None and i >= self._fail_after:
+                raise requests.ConnectionError("connection reset")
+            yield self._body[i : i + 10]
+
+
+class FakeSession:
+    """Serves body with Range support; the first response drops after 40 bytes."""
+
+    def __init__(self, body, honour_range=True, drop_first=True):
+        self.body = body
+        self.honour_range = honour_range
+        self.drop_first = drop_first
+        self.ranges = []
+
+    def get(self, url, headers=None, stream=False, timeout=None):
+        rng = (headers or {}).get("Range")
+        self.ranges.append(rng)
+        fail_after = 40 if self.drop_first and len(self.ranges) == 1 else None
+        if rng and self.honour_range:
+            start = int(rng.split("=")[1].rstrip("-"))
+            if start >= len(self.body):
+                return FakeResponse(416, b"", {})
+            rest = self.body[start:]
+            headers = {
+                "Content-Range": f"bytes {start}-{len(self.body) - 1}/{len(self.body)}",
+                "Content-Length": str(len(rest)),
+            }
+            return FakeResponse(206, rest, headers, fail_after)
+        headers = {"Content-Length": str(len(self.body))}
+        return FakeResponse(200, self.body, headers, fail_after)
+
+
+@pytest.fixture(autouse=True)
+def _plenty_of_disk(monkeypatch):
+    monkeypatch.setattr(bulk_download, "free_bytes", lambda path: 10**12)
+
+
+def test_resumes_with_range_after_dropped_connection(tmp_path):
+    body = _zip_bytes()
+    session = FakeSession(body)
+    dest = download_file("u", tmp_path / "list.zip", session=session, retry_delay=0)
+    assert dest.read_bytes() == body
+    assert session.ranges == [None, "bytes=40-"]
+    assert not (tmp_path / "list.zip.part").exists()
+
+
+def test_restarts_when_server_ignores_range(tmp_path):
+    body = _zip_bytes()
+    session = FakeSession(body, honour_range=False)
+    dest = download_file("u", tmp_path / "list.zip", session=session, retry_delay=0)
+    assert dest.read_bytes() == body
+
+
+def test_rerun_resumes_existing_part(tmp_path):
+    body = _zip_bytes()
+    (tmp_path / "list.zip.part").write_bytes(body[:100])
+    session = FakeSession(body, drop_first=False)
+    download_file("u", tmp_path / "list.zip", session=session, retry_delay=0)
+    assert session.ranges == ["bytes=100-"]
+
+
+def test_corrupt_part_is_downloaded_again(tmp_path):
+    body = _zip_bytes()
+    (tmp_path / "list.zip.part").write_bytes(b"garbage" * 200)
+    session = FakeSession(body, drop_first=False)
+    dest = download_file("u", tmp_path / "list.zip", session=session, retry_delay=0)
+    assert dest.read_bytes() == body
+    assert session.ranges == ["bytes=1400-", None]
+
+
+def test_sha256_mismatch_raises_after_retries(tmp_path):
+    session = FakeSession(_zip_bytes(), drop_first=False)
+    with pytest.raises(VerificationError):
+        download_file(
+            "u", tmp_path / "list.zip", session=session, retry_delay=0, sha256="00"
+        )
+    assert not (tmp_path / "list.zip").exists()
+
+
+def test_max_bytes_and_low_disk_stop_without_retry(tmp_path, monkeypatch):
+    session = FakeSession(_zip_bytes(), drop_first=False)
+    with pytest.raises(DownloadError):
+        download_file("u", tmp_path / "a.zip", session=session, max_bytes=100)
+    monkeypatch.setattr(bulk_download, "free_bytes", lambda path: 1000)
+    with pytest.raises(DownloadError):
+        download_file("u", tmp_path / "b.zip", session=session)
+    assert len(session.ranges) == 2
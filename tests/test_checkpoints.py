@@ -0,0 +1,90 @@
+"""Unit tests for checkpoint scheduling and rotation (checkpoints.py)."""
+
+import argparse
+import gzip
+
+import pytest
+
+from checkpoints import (
+    CheckpointSchedule,
+    load_latest,
+    parse_duration,
+    rotated_path,
+    write_rotated,
+)
+
+
+class TestParseDuration:
+    @pytest.mark.parametrize(
+        "raw,seconds",
+        [("90", 90.0), ("90s", 90.0), ("2m", 120.0), ("1.5h", 5400.0), ("2M", 120.0)],
+    )
+    def test_valid(self, raw, seconds):
+        assert parse_duration(raw) == seconds
+
+    @pytest.mark.parametrize("raw", ["", "m", "2d", "-1m", "two minutes"])
+    def test_invalid(self, raw):
+        with pytest.raises(argparse.ArgumentTypeError):
+            parse_duration(raw)
+
+
+class FakeClock:
+    def __init__(self):
+        self.now = 0.0
+
+    def __call__(self):
+        return self.now
+
+
+class TestCheckpointSchedule:
+    def test_disabled(self):
+        schedule = CheckpointSchedule()
+        assert not schedule.enabled
+        assert not schedule.due(100)
+
+    def test_count_based_fires_once_per_multiple(self):
+        schedule = CheckpointSchedule(every=10)
+        assert not schedule.due(9)
+        assert schedule.due(10)
+        schedule.mark(10)
+        # Failures after the 10th success keep success_count at 10
+        assert not schedule.due(10)
+        assert schedule.due(20)
+
+    def test_time_based_fires_without_successes(self):
+        clock = FakeClock()
+        schedule = CheckpointSchedule(interval=120, clock=clock)
+        clock.now = 119
+        assert not schedule.due(0)
+        clock.now = 120
+        assert schedule.due(0)
+        schedule.mark(0)
+        assert not schedule.due(0)
+        clock.now = 240
+        assert schedule.due(0)
+
+
+class TestRotation:
+    def test_keeps_last_k_newest_first(self, tmp_path):
+        path = tmp_path / "games.parquet.checkpoint"
+        for i in range(4):
+            write_rotated(path, f"v{i}".encode(), keep=3)
+
+        assert not rotated_path(path, 4).exists()
+        contents = [
+            gzip.decompress(rotated_path(path, n).read_bytes()) for n in range(1, 4)
+        ]
+        assert contents == [b"v3", b"v2", b"v1"]
+
+    def test_load_latest_skips_corrupt_newest(self, tmp_path):
+        path = tmp_path / "details.parquet.checkpoint"
+        write_rotated(path, b"good", keep=3)
+        write_rotated(path, b"newer", keep=3)
+        # Simulate a truncated final write
+        newest = rotated_path(path, 1)
+        newest.write_bytes(newest.read_bytes()[:10])
+
+        assert load_latest(path, keep=3) == b"good"
+
+    def test_load_latest_none_when_missing(self, tmp_path):
+        assert load_latest(tmp_path / "missing.checkpoint") is None
@@ -0,0 +1,105 @@
+"""Unit tests for the FIDE circuit breaker (circuit_breaker.py)."""
+
+import argparse
+
+import pytest
+
+import circuit_breaker
+from circuit_breaker import CLOSED, HALF_OPEN, OPEN, CircuitBreaker, CircuitOpenError
+from run_summary import failure_class
+
+
+class FakeClock:
+    def __init__(self):
+        self.now = 0.0
+        self.slept = []
+
+    def __call__(self):
+        return self.now
+
+    def sleep(self, seconds):
+        self.slept.append(seconds)
+        self.now += seconds
+
+
+def make_breaker(**kwargs):
+    clock = FakeClock()
+    kwargs = {"window": 4, "error_ratio": 0.5, "cooldown": 10.0, **kwargs}
+    return CircuitBreaker("details", clock=clock, sleep=clock.sleep, **kwargs), clock
+
+
+def test_opens_at_error_ratio_once_min_requests_seen():
+    breaker, _ = make_breaker()
+    assert breaker.min_requests == 2
+    assert breaker.record(False) is False  # one outcome is not enough
+    breaker.record(True)
+    assert breaker.state == OPEN  # 1 of 2 failed
+    breaker, _ = make_breaker()
+    for ok in (True, True, False):
+        assert breaker.record(ok) is False
+    assert breaker.record(False) is True  # 2 of the last 4
+    assert breaker.state == OPEN
+    assert breaker.trips == 1
+
+
+def test_successes_keep_it_closed():
+    breaker, _ = make_breaker()
+    for ok in (True, True, False, True, True, True, False, True):
+        breaker.record(ok)
+    assert breaker.state == CLOSED
+    assert breaker.allow()
+
+
+def test_open_waits_cooldown_then_probe_success_closes():
+    breaker, clock = make_breaker(min_requests=1)
+    assert breaker.record(False)
+    assert not breaker.allow()
+    breaker.before_request()
+    assert clock.slept == [10.0]
+    assert breaker.state == HALF_OPEN
+    assert breaker.record(True) is False
+    assert breaker.state == CLOSED
+    assert breaker.trips == 0
+    assert breaker.total_trips == 1
+
+
+def test_failed_probe_doubles_cooldown_and_gives_up_after_max_trips():
+    breaker, clock = make_breaker(min_requests=1, max_trips=3)
+    breaker.record(False)
+    breaker.before_request()
+    assert breaker.record(False)  # probe failed
+    breaker.before_request()
+    assert breaker.record(False)
+    assert clock.slept == [10.0, 20.0]
+    assert breaker.trips == 3
+    with pytest.raises(CircuitOpenError):
+        breaker.before_request()
+
+
+def test_cooldown_is_capped():
+    breaker, _ = make_breaker(cooldown=100.0, max_cooldown=250.0)
+    breaker.trips = 5
+    assert breaker.current_cooldown() == 250.0
+
+
+def test_invalid_settings():
+    with pytest.raises(ValueError):
+        CircuitBreaker("details", window=0)
+    with pytest.raises(ValueError):
+        CircuitBreaker("details", error_ratio=0)
+
+
+def test_from_args():
+    parser = argparse.ArgumentParser()
+    circuit_breaker.add_arguments(parser)
+    breaker = circuit_breaker.from_args(
+        "reports", parser.parse_args(["--breaker-window", "10"])
+    )
+    assert breaker.min_requests == 5
+    assert breaker.cooldown == circuit_breaker.DEFAULT_COOLDOWN
+    args = parser.parse_args(["--no-circuit-breaker"])
+    assert circuit_breaker.from_args("reports", args) is None
+
+
+def test_gave_up_error_has_its_own_failure_class():
+    assert failure_class("circuit open: FIDE unavailable") == "circuit_open"
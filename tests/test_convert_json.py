@@ -0,0 +1,139 @@
+"""
+Tests for convert_json.py.
+
+Offline: recognizing result lists, output naming, file discovery, a details
+round trip through Parquet and reports converted as the reports scraper writes them.
+"""
+
+import io
+import json
+
+import pandas as pd
+
+from convert_json import (
+    KIND_DETAILS,
+    KIND_REPORTS,
+    classify,
+    convert_file,
+    find_json_files,
+    output_paths,
+    load_details_maps,
+    summarize,
+    validate_output,
+)
+from get_tournament_details import results_to_parquet_bytes
+from get_tournament_reports import games_parquet_bytes
+
+DETAILS_RECORDS = [
+    {
+        "tournament_id": "368357",
+        "success": True,
+        "error": "",
+        "details": {"id": "368357", "name": "Candidates", "n_players": "8"},
+    },
+    {"tournament_id": "1", "success": False, "error": "no data found"},
+]
+REPORTS_RECORDS = [{"tournament_code": "368357", "success": True, "players": []}]
+
+
+class TestClassify:
+    def test_details_and_reports(self):
+        assert classify(DETAILS_RECORDS) == KIND_DETAILS
+        assert classify(REPORTS_RECORDS) == KIND_REPORTS
+
+    def test_other_json_is_not_a_result_list(self):
+        assert classify({"tournaments": 3}) is None
+        assert classify([]) is None
+        assert classify([{"tournament_id": "1", "error": "timeout"}]) is None
+
+
+def test_output_paths(tmp_path):
+    path = tmp_path / "2024_01.json"
+    assert output_paths(path, KIND_DETAILS) == {"details": tmp_path / "2024_01.parquet"}
+    assert output_paths(path, KIND_REPORTS) == {
+        "players": tmp_path / "2024_01_players.parquet",
+        "games": tmp_path / "2024_01_games.parquet",
+    }
+
+
+def test_find_json_files_skips_scraper_side_outputs(tmp_path):
+    for name in ("2024_01.json", "2024_01_sample.json", "2024_01_failures.json"):
+        (tmp_path / "a" / name).parent.mkdir(exist_ok=True)
+        (tmp_path / "a" / name).write_text("[]")
+    assert find_json_files([tmp_path]) == [tmp_path / "a" / "2024_01.json"]
+
+
+def test_validate_output_reports_blank_keys():
+    df = pd.DataFrame({"player_id": ["1", ""], "tournament_id": ["9", "9"]})
+    assert validate_output(df, "players", expected_rows=3) == [
+        "players: 2 rows, expected 3",
+        "players: 1 rows with empty player_id",
+    ]
+
+
+def test_convert_details_round_trip(tmp_path):
+    path = tmp_path / "2024_01.json"
+    path.write_text(json.dumps(DETAILS_RECORDS))
+
+    entry = convert_file(path)
+    assert entry["status"] == "converted"
+    assert entry["rows"] == {"details": 2}
+    assert entry["issues"] == []
+    df = pd.read_parquet(tmp_path / "2024_01.parquet")
+    assert df.loc[df["success"], "name"].tolist() == ["Candidates"]
+
+    assert convert_file(path)["status"] == "exists"
+    assert summarize([entry, {"status": "skipped"}]) == {
+        "files": 2,
+        "by_status": {"converted": 1, "skipped": 1},
+        "with_issues": 0,
+    }
+
+
+def test_convert_details_matches_scraper_output(tmp_path):
+    path = tmp_path / "2024_01.json"
+    path.write_text(json.dumps(DETAILS_RECORDS))
+    convert_file(path)
+    expected = pd.read_parquet(io.BytesIO(results_to_parquet_bytes(DETAILS_RECORDS)))
+    converted = pd.read_parquet(tmp_path / "2024_01.parquet")
+    pd.testing.assert_frame_equal(converted, expected)
+
+
+def _player(pid, opp, color, score):
+    rounds = [
+        {"round": 1, "date": "05/06/24", "opp_id": opp, "color": color, "score": score}
+    ]
+    return {"id": pid, "name": pid, "country": "NOR", "total": score, "rounds": rounds}
+
+
+def test_convert_reports_uses_details_dates(tmp_path):
+    records = [
+        {
+            "tournament_code": "400001",
+            "success": True,
+            "players": [
+                _player("1503014", "2020009", "white", 1.0),
+                _player("2020009", "1503014", "black", 0.0),
+            ],
+        }
+    ]
+    path = tmp_path / "2024_06.json"
+    path.write_text(json.dumps(records))
+    details = tmp_path / "tournament_details.parquet"
+    pd.DataFrame(
+        {
+            "event_code": ["400001"],
+            "success": [True],
+            "start_date": ["2024-06-05"],
+            "end_date": ["2024-06-05"],
+        }
+    ).to_parquet(details)
+    details_map = load_details_maps([details])
+
+    entry = convert_file(path, details_map=details_map)
+    assert entry["status"] == "converted"
+    games = pd.read_parquet(tmp_path / "2024_06_games.parquet")
+    # 05/06/24 read as dd/mm/yy because of the details dates, as the scraper does
+    assert games["round_date"].dt.strftime("%Y-%m-%d").tolist() == ["2024-06-05"]
+    expected = games_parquet_bytes(records, details_map)[0]
+    pd.testing.assert_frame_equal(games, pd.read_parquet(io.BytesIO(expected)))
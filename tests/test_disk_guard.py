@@ -0,0 +1,57 @@
+"""Unit tests for disk-full pause/resume (disk_guard.py)."""
+
+import errno
+
+import pytest
+
+import disk_guard
+
+
+@pytest.fixture(autouse=True)
+def reset_config():
+    disk_guard.configure()
+    yield
+    disk_guard.configure()
+
+
+def _flaky_write(failures: int, exc: OSError):
+    calls = {"n": 0}
+
+    def write():
+        calls["n"] += 1
+        if calls["n"] <= failures:
+            raise exc
+        return "written"
+
+    return write, calls
+
+
+class TestGuardedWrite:
+    def test_waits_for_space_then_retries(self, monkeypatch, tmp_path):
+        free = iter([0, 0, 10**12])
+        monkeypatch.setattr(disk_guard, "free_bytes", lambda path: next(free))
+        alerts = []
+        monkeypatch.setattr(disk_guard, "send_alert", alerts.append)
+        sleeps = []
+        write, calls = _flaky_write(1, OSError(errno.ENOSPC, "No space left"))
+
+        result = disk_guard.guarded_write(write, tmp_path / "x", sleep=sleeps.append)
+
+        assert result == "written"
+        assert calls["n"] == 2
+        assert len(sleeps) == 2
+        assert "paused" in alerts[0] and "resuming" in alerts[1]
+
+    def test_other_os_errors_propagate(self, tmp_path):
+        write, calls = _flaky_write(1, OSError(errno.EACCES, "Permission denied"))
+        with pytest.raises(OSError):
+            disk_guard.guarded_write(write, tmp_path / "x")
+        assert calls["n"] == 1
+
+
+class TestSendAlert:
+    def test_runs_alert_command_with_message(self, tmp_path):
+        out = tmp_path / "alert.txt"
+        disk_guard.configure(alert_command=f"sh -c 'echo \"$1\" > {out}' alert")
+        disk_guard.send_alert("disk full")
+        assert out.read_text().strip() == "disk full"
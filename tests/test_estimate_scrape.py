@@ -0,0 +1,60 @@
+"""
+Tests for scripts/estimate_scrape.py.
+
+Offline: range parsing and the request/byte/duration arithmetic.
+"""
+
+import argparse
+import sys
+from datetime import datetime, timezone
+from pathlib import Path
+
+import pytest
+
+sys.path.insert(0, str(Path(__file__).parent.parent / "scripts"))
+
+from estimate_scrape import (
+    PageCost,
+    estimate,
+    format_report,
+    parse_id_range,
+    seconds_per_request,
+)
+
+
+class TestParseIdRange:
+    def test_inclusive(self):
+        assert list(parse_id_range("10-12")) == [10, 11, 12]
+
+    @pytest.mark.parametrize("raw", ["10", "a-b", "12-10", "-5-3"])
+    def test_invalid(self, raw):
+        with pytest.raises(argparse.ArgumentTypeError):
+            parse_id_range(raw)
+
+
+class TestEstimate:
+    def test_rate_limit_slower_than_natural_wins(self):
+        assert seconds_per_request(0.5, 1.0) == 2.0
+        assert seconds_per_request(10, 1.0) == 1.0
+        assert seconds_per_request(0, 1.1) == 1.1
+
+    def test_requests_bytes_and_duration(self):
+        est = estimate(
+            1000,
+            hit_rate=0.25,
+            details=PageCost(bytes=40_000, seconds=2.0),
+            reports=PageCost(bytes=100_000, seconds=1.0),
+            workers=5,
+        )
+        assert est.details_requests == 1000
+        assert est.reports_requests == 250
+        assert est.requests == 1250
+        assert est.bytes == 1000 * 40_000 + 250 * 100_000
+        assert est.seconds == (1000 * 2.0 + 250 * 1.0) / 5
+
+    def test_report_shows_completion_time(self):
+        est = estimate(10, 1.0, PageCost(1e6, 3600), PageCost(0, 0))
+        now = datetime(2025, 1, 1, tzinfo=timezone.utc)
+        report = format_report(est, workers=1, now=now)
+        assert "Requests:       20 (10 details + 10 reports)" in report
+        assert "Completes at:   2025-01-01 10:00 UTC" in report
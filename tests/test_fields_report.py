@@ -0,0 +1,86 @@
+"""
+Tests for fields_report.py.
+
+Offline: labels from the Candidates 2024 details fixture, read as a saved page and
+from a raw chunk, with known/unknown flags, ordering and unknown-field examples.
+"""
+
+from pathlib import Path
+
+from fields_report import (
+    build_report,
+    html_pages,
+    label_slug,
+    page_fields,
+    raw_pages,
+    write_examples,
+)
+from raw_utils import build_concatenated_gzip
+
+FIXTURE = Path(__file__).parent / "fixtures" / "candidates_24_details.html"
+
+
+def test_page_fields_reads_the_details_table():
+    fields = page_fields(FIXTURE.read_bytes())
+    assert "Tournament Name" in fields
+    assert "FIDE Candidates Tournament 2024" in fields["Tournament Name"]
+    assert "report.phtml?event=368261" in fields["View Report"]
+
+
+def test_report_from_raw_chunk_and_saved_page(tmp_path):
+    html = FIXTURE.read_bytes()
+    chunk = tmp_path / "0.html.gz"
+    chunk.write_bytes(build_concatenated_gzip([("368261", html), ("1", b"<p>x</p>")]))
+    page = tmp_path / "368261.html"
+    page.write_bytes(html)
+
+    from_raw = build_report(raw_pages([str(chunk)])).to_dict()
+    from_html = build_report(html_pages([str(page)])).to_dict()
+
+    assert from_raw["pages"] == 1
+    assert from_raw["failures"] == {"1": "no details table"}
+    assert from_raw["fields"] == from_html["fields"]
+    name = next(f for f in from_raw["fields"] if f["label"] == "Tournament Name")
+    assert name["known"] and name["field"] == "name" and name["count"] == 1
+
+
+def _page(*rows) -> bytes:
+    cells = "".join(
+        f"<tr><td class=info_table_l>{label}</td><td>{value}</td></tr>"
+        for label, value in rows
+    )
+    return f'<table class="details_table">{cells}</table>'.encode()
+
+
+def test_fields_sorted_by_count_then_label():
+    pages = [
+        ("1", _page(("B", "x"), ("A", "y")), None),
+        ("2", _page(("B", "z")), None),
+        ("3", None, "HTTP 503"),
+    ]
+    report = build_report(pages, samples=1).to_dict()
+    assert [f["label"] for f in report["fields"]] == ["B", "A"]
+    assert report["fields"][0]["sample_values"] == ["x"]
+    assert report["failures"] == {"3": "HTTP 503"}
+    assert report["unknown_fields"] == 2
+
+
+def test_unknown_fields_keep_examples_with_tournament_ids(tmp_path):
+    pages = [
+        ("10", _page(("Tournament Name", "Open"), ("Prize fund:", "<b>500</b>")), None),
+        ("11", _page(("Prize fund:", "<b>500</b>")), None),
+        ("12", _page(("Prize fund:", "")), None),
+        ("13", _page(("Prize fund:", "1000 EUR")), None),
+    ]
+    report = build_report(pages, examples=5).to_dict()
+    fields = {f["label"]: f for f in report["fields"]}
+
+    assert "examples" not in fields["Tournament Name"]
+    assert fields["Prize fund:"]["examples"] == [
+        {"tournament_id": "10", "html": "<b>500</b>"},
+        {"tournament_id": "13", "html": "1000 EUR"},
+    ]
+
+    assert write_examples(report, str(tmp_path)) == 2
+    assert label_slug("Prize fund:") == "prize_fund"
+    assert (tmp_path / "prize_fund" / "10.html").read_text() == "<b>500</b>\n"
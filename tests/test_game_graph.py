@@ -0,0 +1,58 @@
+"""Unit tests for the game graph export (game_graph.py)."""
+
+import xml.etree.ElementTree as ET
+
+import pandas as pd
+
+from game_graph import GRAPHML_NS, UNKNOWN_PERIOD, game_edges, to_graphml
+
+
+def _games():
+    return pd.DataFrame(
+        {
+            "white_player_id": ["2", "1", "1", "3", "", "4"],
+            "black_player_id": ["1", "2", "3", "1", "5", "4"],
+            "score": [1.0, 0.5, 0.0, 1.0, 1.0, 1.0],
+            "round_date": [
+                "2025-01-05",
+                "2025-01-06",
+                "2025-02-01",
+                None,
+                "2025-01-05",
+                "2025-01-05",
+            ],
+        }
+    )
+
+
+def test_edges_are_undirected_weighted_and_scored_from_source():
+    edges = game_edges(_games())
+    jan = edges[edges["period"] == "2025-01"].set_index(["source", "target"])
+    # 2 beat 1 as white, then drew: source 1 scored 0 + 0.5
+    assert jan.loc[("1", "2"), "weight"] == 2
+    assert jan.loc[("1", "2"), "source_score"] == 0.5
+    assert len(jan) == 1  # missing ID and self-pairing skipped
+
+
+def test_edges_split_by_period():
+    edges = game_edges(_games())
+    assert sorted(edges["period"]) == ["2025-01", "2025-02", UNKNOWN_PERIOD]
+    undated = edges[edges["period"] == UNKNOWN_PERIOD].iloc[0]
+    assert (undated["source"], undated["target"]) == ("1", "3")
+    assert undated["source_score"] == 0.0
+
+
+def test_graphml_nodes_edges_and_attributes():
+    edges = game_edges(_games())
+    jan = edges[edges["period"] == "2025-01"]
+    doc = ET.fromstring(to_graphml(jan, {"1": {"fed": "NOR", "title": "GM"}}))
+    ns = {"g": GRAPHML_NS}
+    graph = doc.find("g:graph", ns)
+    assert graph.get("edgedefault") == "undirected"
+    nodes = {n.get("id"): n for n in graph.findall("g:node", ns)}
+    assert set(nodes) == {"1", "2"}
+    data = {d.get("key"): d.text for d in nodes["1"].findall("g:data", ns)}
+    assert data == {"fed": "NOR", "title": "GM"}
+    edge = graph.find("g:edge", ns)
+    edge_data = {d.get("key"): d.text for d in edge.findall("g:data", ns)}
+    assert edge_data == {"weight": "2", "source_score": "0.5"}
@@ -0,0 +1,87 @@
+"""
+Tests for geocode_tournaments.py.
+
+Offline: place-name normalization and lookups against tiny GeoNames-format files.
+"""
+
+import pytest
+
+from geocode_tournaments import (
+    MATCH_CAPITAL_MISSING,
+    MATCH_CITY,
+    MATCH_COUNTRY,
+    Geocoder,
+    normalize_place,
+)
+
+COUNTRY_INFO = (
+    "#ISO\tISO3\tISO-Numeric\tfips\tCountry\tCapital\n"
+    "NL\tNLD\t528\tNL\tNetherlands\tAmsterdam\n"
+    "NO\tNOR\t578\tNO\tNorway\tOslo\n"
+    "IS\tISL\t352\tIC\tIceland\tReykjavik\n"
+)
+
+
+def _city(geonameid, name, alt, lat, lon, country, population):
+    row = [str(geonameid), name, name, alt, str(lat), str(lon), "P", "PPL", country]
+    row += [""] * 5 + [str(population), "", "", "Europe/Amsterdam", "2024-01-01"]
+    return "\t".join(row) + "\n"
+
+
+CITIES = (
+    _city(1, "Amsterdam", "Amsterdam,Amsterdã", 52.37, 4.89, "NL", 741636)
+    + _city(2, "Wijk aan Zee", "", 52.49, 4.59, "NL", 2500)
+    + _city(3, "Oslo", "Christiania", 59.91, 10.75, "NO", 580000)
+    + _city(4, "Stavanger", "", 58.97, 5.73, "NO", 121610)
+)
+
+
+@pytest.fixture
+def geocoder(tmp_path):
+    (tmp_path / "countryInfo.txt").write_text(COUNTRY_INFO, encoding="utf-8")
+    (tmp_path / "cities15000.txt").write_text(CITIES, encoding="utf-8")
+    return Geocoder.from_dir(tmp_path)
+
+
+class TestNormalizePlace:
+    @pytest.mark.parametrize(
+        "raw,expected",
+        [
+            ("Wijk aan Zee (NED)", "wijk aan zee"),
+            ("  STAVANGER ", "stavanger"),
+            ("Málaga, Andalusia", "malaga"),
+            ("", ""),
+        ],
+    )
+    def test_normalize(self, raw, expected):
+        assert normalize_place(raw) == expected
+
+
+class TestGeocoder:
+    def test_fide_code_differs_from_iso3(self, geocoder):
+        assert geocoder.country_code("NED") == "NL"
+        assert geocoder.country_code("NOR") == "NO"
+
+    def test_city_match(self, geocoder):
+        iso2, coords, match = geocoder.geocode("Wijk aan Zee", "NED")
+        assert (iso2, coords, match) == ("NL", (52.49, 4.59), MATCH_CITY)
+
+    def test_alternate_name_match(self, geocoder):
+        assert geocoder.geocode("Christiania", "NOR")[1] == (59.91, 10.75)
+
+    def test_unknown_city_falls_back_to_capital(self, geocoder):
+        assert geocoder.geocode("Hoogeveen", "NED") == (
+            "NL",
+            (52.37, 4.89),
+            MATCH_COUNTRY,
+        )
+
+    def test_capital_missing_from_cities_file(self, geocoder):
+        assert geocoder.geocode("Akureyri", "ISL") == (
+            "IS",
+            None,
+            MATCH_CAPITAL_MISSING,
+        )
+
+    def test_unknown_federation(self, geocoder):
+        assert geocoder.geocode("Anywhere", "FID") == (None, None, None)
@@ -193,7 +193,7 @@ class TestGetPlayerListOnline:
         assert required <= set(p.keys()), f"Missing keys: {required - set(p.keys())}"
         assert isinstance(p["id"], int)
         assert p["name"]
-        assert len(p["fed"]) <= 3
+        assert p["fed"] is None or len(p["fed"]) <= 3
         assert p["sex"] in ("M", "F", None)
 
     @pytest.mark.online
@@ -0,0 +1,86 @@
+"""
+Tests for get_player_profiles.py.
+
+Offline: profile parsing from a trimmed profile page, title mapping, and which
+IDs are fetched.
+"""
+
+from unittest.mock import MagicMock
+
+import pandas as pd
+
+import get_player_profiles
+from get_player_profiles import (
+    fetch_profile,
+    game_player_ids,
+    ids_to_fetch,
+    parse_profile,
+    parse_titles,
+)
+
+PROFILE_HTML = """
+<html><body>
+<div class="profile-top-title">Example, Player</div>
+<div class="profile-top-info__block">
+  <div class="profile-top-info__block__row">
+    <div class="profile-top-info__block__row__header">Federation:</div>
+    <div class="profile-top-info__block__row__data">Norway</div>
+  </div>
+  <div class="profile-top-info__block__row">
+    <div class="profile-top-info__block__row__header">B-Year:</div>
+    <div class="profile-top-info__block__row__data">2009</div>
+  </div>
+  <div class="profile-top-info__block__row">
+    <div class="profile-top-info__block__row__header">Sex:</div>
+    <div class="profile-top-info__block__row__data">Female</div>
+  </div>
+  <div class="profile-top-info__block__row">
+    <div class="profile-top-info__block__row__header">FIDE title:</div>
+    <div class="profile-top-info__block__row__data">Woman FIDE Master</div>
+  </div>
+</div>
+</body></html>
+"""
+
+
+def test_parse_profile():
+    assert parse_profile(PROFILE_HTML, {"norway": "NOR"}) == {
+        "name": "Example, Player",
+        "fed": "NOR",
+        "sex": "F",
+        "byear": 2009,
+        "title": None,
+        "w_title": "WFM",
+    }
+
+
+def test_parse_profile_without_federation_codes_or_player():
+    assert parse_profile(PROFILE_HTML)["fed"] is None
+    assert parse_profile("<html><body>No player</body></html>") is None
+
+
+def test_parse_titles():
+    assert parse_titles("Grandmaster") == ("GM", None)
+    assert parse_titles("Woman Grandmaster") == (None, "WGM")
+    assert parse_titles("International Master, Woman Grandmaster") == ("IM", "WGM")
+    assert parse_titles("None") == (None, None)
+
+
+def test_game_player_ids_and_ids_to_fetch(tmp_path):
+    games = tmp_path / "games.parquet"
+    pd.DataFrame(
+        {"white_player_id": ["10", "11", None], "black_player_id": ["12", "", "13"]}
+    ).to_parquet(games)
+    ids = game_player_ids([games])
+    assert ids == {"10", "11", "12", "13"}
+    assert ids_to_fetch(ids | {"9"}, known=[10, 12], done=[13]) == ["9", "11"]
+
+
+def test_fetch_profile_not_found_and_http_error(monkeypatch):
+    monkeypatch.setattr(get_player_profiles.time, "sleep", lambda s: None)
+    session = MagicMock()
+    session.get.return_value = MagicMock(status_code=404)
+    assert fetch_profile("1", session) == (None, "no profile")
+    session.get.return_value = MagicMock(status_code=503)
+    assert fetch_profile("1", session) == (None, "HTTP 503")
+    assert session.get.call_count == 4
@@ -1,12 +1,25 @@
 """Tests for get_tournament_details scraper."""
 
+import io
+import itertools
+import sys
 from pathlib import Path
 from unittest.mock import MagicMock
 
+import pandas as pd
 import pytest
 import requests
 
-from get_tournament_details import fetch_tournament_details
+from checkpoints import write_rotated
+from get_tournament_details import (
+    fetch_tournament_details,
+    field_for_label,
+    flatten_result,
+    load_resume_rows,
+    parse_n_rounds,
+    read_tournament_ids,
+    results_to_parquet_bytes,
+)
 
 
 class TestFixtureBasedParsing:
@@ -35,6 +48,108 @@ class TestFixtureBasedParsing:
         assert details.get("n_players") == "8"
         assert details.get("start_date") == "2024-04-03"
         assert details.get("end_date") == "2024-04-23"
+        assert details.get("view_report_href") == "report.phtml?event=368261"
+
+        row = flatten_result(
+            {"tournament_id": "368261", "success": True, "details": details}
+        )
+        assert row["report_kind"] == "rating_report"
+        assert (row["report_system"], row["report_event"]) == ("fide", "368261")
+
+    def test_parses_arbiters_and_organizers_from_fixture(self):
+        """Chief/Deputy Chief Arbiter rows link to /profile/; organizers are blank."""
+        fixture_path = Path(__file__).parent / "fixtures" / "candidates_24_details.html"
+        mock_response = MagicMock(status_code=200, content=fixture_path.read_bytes())
+        session = MagicMock()
+        session.get.return_value = mock_response
+
+        details, error, _, _ = fetch_tournament_details("368261", session)
+
+        assert error is None
+        assert details["chief_arbiter"] == [
+            {"name": "Marghetis, Aris", "fide_id": "2611058"}
+        ]
+        assert details["deputy_chief_arbiter"] == [
+            {"name": "Howie, Andrew", "fide_id": "2403986"}
+        ]
+        assert "chief_organizer" not in details and "organizer" not in details
+
+        row = flatten_result(
+            {"tournament_id": "368261", "success": True, "details": details}
+        )
+        assert row["chief_arbiter_names"] == ["Marghetis, Aris"]
+        assert row["chief_arbiter_fide_ids"] == ["2611058"]
+        assert row["deputy_chief_arbiter_names"] == ["Howie, Andrew"]
+        assert row["deputy_chief_arbiter_fide_ids"] == ["2403986"]
+        assert row["chief_organizer_names"] == [] and row["organizer_names"] == []
+
+    def test_people_without_profile_links(self):
+        html = (
+            "<table class=details_table>"
+            "<tr><td class=info_table_l>Organizer</td><td>&nbsp;"
+            "<a href=/profile/100 target=_blank>Doe, Jane (USA) </a>&nbsp;"
+            "Chess Club Springfield</td></tr></table>"
+        )
+        session = MagicMock()
+        session.get.return_value = MagicMock(status_code=200, content=html.encode())
+
+        details, error, _, _ = fetch_tournament_details("1", session)
+
+        assert error is None
+        assert details["organizer"] == [
+            {"name": "Doe, Jane", "fide_id": "100"},
+            {"name": "Chess Club Springfield", "fide_id": None},
+        ]
+
+    def test_arbiter_and_assistant_arbiter_rows(self):
+        html = (
+            "<table class=details_table>"
+            "<tr><td class=info_table_l>Arbiter</td><td>&nbsp;"
+            "<a href=/profile/200 target=_blank>Roe, Ann (CAN) </a></td></tr>"
+            "<tr><td class=info_table_l>Assistant Arbiter</td><td>&nbsp;"
+            "<a href=/profile/300 target=_blank>Poe, Ed (USA) </a></td></tr>"
+            "</table>"
+        )
+        session = MagicMock()
+        session.get.return_value = MagicMock(status_code=200, content=html.encode())
+
+        details, error, _, _ = fetch_tournament_details("1", session)
+
+        assert error is None
+        row = flatten_result(
+            {"tournament_id": "1", "success": True, "details": details}
+        )
+        assert row["arbiter_names"] == ["Roe, Ann"]
+        assert row["arbiter_fide_ids"] == ["200"]
+        assert row["assistant_arbiter_names"] == ["Poe, Ed"]
+        assert row["assistant_arbiter_fide_ids"] == ["300"]
+        assert row["chief_arbiter_names"] == []
+
+    def test_reworded_labels_parse_the_same(self):
+        """Re-cased/re-spaced labels (e.g. "Start date:") map to the same fields."""
+        fixture_path = Path(__file__).parent / "fixtures" / "candidates_24_details.html"
+        html = fixture_path.read_text(encoding="utf-8")
+        for original, variant in [
+            ("Start Date", "Start date:"),
+            ("End Date", "END&nbsp;DATE"),
+            ("Tournament Name", "Tournament  name"),
+            ("Number of players", "No. of players"),
+        ]:
+            html = html.replace(f">{original}<", f">{variant}<")
+
+        mock_response = MagicMock()
+        mock_response.status_code = 200
+        mock_response.content = html.encode("utf-8")
+        session = MagicMock()
+        session.get.return_value = mock_response
+
+        details, error, _, _ = fetch_tournament_details("368261", session)
+
+        assert error is None
+        assert details.get("name") == "FIDE Candidates Tournament 2024"
+        assert details.get("n_players") == "8"
+        assert details.get("start_date") == "2024-04-03"
+        assert details.get("end_date") == "2024-04-23"
 
     @pytest.mark.online
     def test_live_fetch_matches_fixture(self):
@@ -97,3 +212,140 @@ class TestFixtureBasedParsing:
         assert details["name"]
         assert details["city"]
         assert details["fed"]
+
+
+class TestLabelAliases:
+    @pytest.mark.parametrize(
+        "label,field",
+        [
+            ("Start Date", "start_date"),
+            ("Start date", "start_date"),
+            ("start  date:", "start_date"),
+            ("End\xa0Date", "end_date"),
+            ("Time control", "time_control"),
+            ("Rate of play", "time_control"),
+            ("Federation", "fed"),
+            ("No. of players", "n_players"),
+            ("No. of rounds", "n_rounds"),
+            ("Rounds:", "n_rounds"),
+            ("National Championship", "nat_championship"),
+            ("NAT. CHAMPIONSHIP", "nat_championship"),
+            ("View Report", "view_report"),
+            ("Chief Arbiter", "chief_arbiter"),
+            ("Deputy Chief Arbiter", "deputy_chief_arbiter"),
+            ("Arbiter", "arbiter"),
+            ("Assistant Arbiters", "assistant_arbiter"),
+            ("Chief Organiser", "chief_organizer"),
+            ("Organizer", "organizer"),
+        ],
+    )
+    def test_known_variants(self, label, field):
+        assert field_for_label(label) == field
+
+    @pytest.mark.parametrize("label", ["PGN file", "Orig.Report", ""])
+    def test_unknown_labels_ignored(self, label):
+        assert field_for_label(label) is None
+
+
+class TestNRounds:
+    @pytest.mark.parametrize(
+        "raw,expected",
+        [("9", 9), (" 11 ", 11), ("0", None), ("", None), (None, None), ("9a", None)],
+    )
+    def test_parse(self, raw, expected):
+        assert parse_n_rounds(raw) == expected
+
+    def test_flattened_as_int(self):
+        result = {"tournament_id": "1", "success": True, "details": {"n_rounds": "9"}}
+        assert flatten_result(result)["n_rounds"] == 9
+
+
+class TestOptionalFields:
+    def test_blank_and_missing_fields_are_null(self):
+        row = flatten_result(
+            {
+                "tournament_id": "1",
+                "success": True,
+                "details": {"id": "X1", "name": "Open", "city": "  ", "zone": ""},
+            }
+        )
+        assert row["error"] is None
+        assert (row["id"], row["name"]) == ("X1", "Open")
+        assert row["city"] is None and row["zone"] is None and row["fed"] is None
+
+    def test_failure_keeps_error(self):
+        row = flatten_result({"tournament_id": "1", "success": False, "error": "x"})
+        assert row["error"] == "x"
+
+
+class TestReadTournamentIds:
+    def test_reads_file_skipping_blank_lines(self, tmp_path):
+        path = tmp_path / "ids.txt"
+        path.write_text("368261\n\n  397341 \n")
+        assert read_tournament_ids(str(path)) == ["368261", "397341"]
+
+    def test_reads_stdin(self, monkeypatch):
+        monkeypatch.setattr(sys, "stdin", io.StringIO("1\n2\n"))
+        assert read_tournament_ids("-") == ["1", "2"]
+
+    def test_limit_stops_reading_an_endless_stream(self, monkeypatch):
+        endless = (f"{i}\n" for i in itertools.count(1))
+        monkeypatch.setattr(sys, "stdin", endless)
+        assert read_tournament_ids("-", limit=3) == ["1", "2", "3"]
+        assert next(endless) == "4\n"
+
+
+class TestResume:
+    @staticmethod
+    def _result(tid, success=True):
+        if success:
+            return {"tournament_id": tid, "success": True, "details": {"name": tid}}
+        return {"tournament_id": tid, "success": False, "error": "HTTP 503"}
+
+    def test_union_of_output_and_newest_checkpoint(self, tmp_path):
+        parquet = tmp_path / "2024_01.parquet"
+        parquet.write_bytes(
+            results_to_parquet_bytes([self._result("1"), self._result("2", False)])
+        )
+        checkpoint = f"{parquet}.parquet.checkpoint"
+        write_rotated(checkpoint, results_to_parquet_bytes([self._result("3")]))
+        write_rotated(
+            checkpoint,
+            results_to_parquet_bytes([self._result("3"), self._result("1")]),
+        )
+
+        rows = load_resume_rows(str(parquet))
+
+        assert sorted(rows["tournament_id"]) == ["1", "3"]
+        assert rows["success"].all()
+
+    def test_nothing_to_resume(self, tmp_path):
+        assert load_resume_rows(str(tmp_path / "missing.parquet")).empty
+
+    def test_s3_output_and_checkpoint(self, monkeypatch):
+        import s3_io
+
+        output = "s3://fide-glicko/custom/x/data/details.parquet"
+        objects = {
+            output: results_to_parquet_bytes([self._result("1")]),
+            f"{output}.parquet.checkpoint": results_to_parquet_bytes(
+                [self._result("2"), self._result("3", False)]
+            ),
+        }
+        monkeypatch.setattr(
+            s3_io, "read_versioned", lambda path: (objects.get(path), "etag")
+        )
+
+        rows = load_resume_rows(output)
+
+        assert sorted(rows["tournament_id"]) == ["1", "2"]
+        del objects[output]
+        assert sorted(load_resume_rows(output)["tournament_id"]) == ["2"]
+
+    def test_prior_rows_come_first_in_output(self):
+        prior_bytes = results_to_parquet_bytes([self._result("1")])
+        prior = pd.read_parquet(io.BytesIO(prior_bytes))
+        out = pd.read_parquet(
+            io.BytesIO(results_to_parquet_bytes([self._result("2")], prior))
+        )
+        assert list(out["tournament_id"]) == ["1", "2"]
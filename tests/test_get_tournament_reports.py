@@ -6,21 +6,28 @@ from unittest.mock import MagicMock
 import pytest
 import requests
 
+import get_tournament_reports
 from get_tournament_reports import (
     ERROR_REPORT_UPDATED_OR_REPLACED,
+    SKIP_PARSE_ERROR,
+    SKIP_UNKNOWN_OPPONENT,
     extract_forfeit_indicator,
     fetch_tournament_report,
     flatten_result,
     flatten_to_games,
     format_duration,
+    game_number_in_round,
     infer_date_format,
     parse_date_to_iso,
     parse_details_date_to_iso,
     parse_round_date,
     parse_score,
+    partial_parse_entries,
+    report_links_from_frame,
     results_to_games_dataframe,
     results_to_players_dataframe,
 )
+from report_links import PAIRING_PROGRAM, RATING_REPORT, SOURCE_REPORT_URL, ReportLink
 
 
 class TestParseScore:
@@ -286,6 +293,28 @@ class TestFlattenResult:
         assert flattened[0]["round"] is None
 
 
+class TestPlayersDataframe:
+    """Tests for results_to_players_dataframe()."""
+
+    def test_missing_id_total_and_rank_are_null(self):
+        result = {
+            "success": True,
+            "tournament_code": "368261",
+            "players": [
+                {"id": "1503014", "name": "Carlsen, Magnus", "total": 7.5, "rank": 1},
+                {"id": "", "name": "Unknown", "country": "NOR"},
+            ],
+        }
+        df = results_to_players_dataframe([result])
+
+        assert df["player_id"].tolist() == ["1503014", None]
+        assert df["player_total"].tolist()[0] == 7.5
+        assert df["player_total"].isna().tolist() == [False, True]
+        assert str(df["rank"].dtype) == "Int64"
+        assert df["rank"].isna().tolist() == [False, True]
+        assert df["n_rounds"].isna().all()
+
+
 class TestFlattenToGames:
     """Tests for flatten_to_games()."""
 
@@ -402,6 +431,106 @@ class TestFlattenToGames:
         assert games[0]["date"] == "2024-12-25"
 
 
+class TestMultipleGamesPerPair:
+    """Double round robins and matches: several games between the same pair."""
+
+    @staticmethod
+    def _parse_fixture(filename: str, code: str) -> dict:
+        fixture_path = Path(__file__).parent / "fixtures" / filename
+        mock_response = MagicMock()
+        mock_response.status_code = 200
+        mock_response.content = fixture_path.read_bytes()
+        session = MagicMock()
+        session.get.return_value = mock_response
+        report, error, _, _ = fetch_tournament_report(code, session)
+        assert error is None
+        return {**report, "success": True}
+
+    def test_game_number_in_round(self):
+        rounds = [
+            {"round": 1, "opp_id": "101"},
+            {"round": 1, "opp_id": "102"},
+            {"round": 2, "opp_id": "101"},
+        ]
+        assert game_number_in_round([], 1, "101") == 1
+        assert game_number_in_round(rounds, 1, "101") == 2
+        assert game_number_in_round(rounds, 3, "101") == 1
+
+    def test_flatten_to_games_keeps_two_games_in_one_round(self):
+        def row(pid, opp, game, color, score):
+            return {
+                "tournament_code": "TC",
+                "success": True,
+                "player_id": pid,
+                "opp_id": opp,
+                "round": 1,
+                "game": game,
+                "round_date": "24/11/25",
+                "color": color,
+                "score": score,
+                "forfeit": "",
+            }
+
+        flattened = [
+            row("100", "101", 1, "white", 1.0),
+            row("100", "101", 2, "white", 0.5),
+            row("101", "100", 1, "black", 0.0),
+            row("101", "100", 2, "black", 0.5),
+        ]
+        games = flatten_to_games(flattened, tournament_code="TC")
+        assert [(g["round"], g["game"], g["white_score"]) for g in games] == [
+            (1, 1, 1.0),
+            (1, 2, 0.5),
+        ]
+
+    def test_match_fixture_two_games_per_round(self):
+        result = self._parse_fixture(
+            "match_two_games_per_round_900001_report.html", "900001"
+        )
+        carlsen = result["players"][0]
+        assert [(r["round"], r["game"]) for r in carlsen["rounds"]] == [
+            (1, 1),
+            (1, 2),
+            (2, 1),
+            (2, 2),
+        ]
+
+        df = results_to_games_dataframe([result])
+        assert len(df) == 4
+        df = df.sort_values(["round_number", "game_number"])
+        assert list(df["game_number"]) == [1, 2, 1, 2]
+        assert list(df["white_player_id"]) == [
+            "1503014",
+            "2020009",
+            "1503014",
+            "2020009",
+        ]
+        assert list(df["score"]) == [1.0, 0.5, 0.5, 0.5]
+
+    def test_double_round_robin_fixture(self):
+        result = self._parse_fixture("double_round_robin_900002_report.html", "900002")
+        df = results_to_games_dataframe([result])
+
+        # 4 players, 6 rounds, 2 boards per round; each pair meets twice
+        assert len(df) == 12
+        assert set(df["game_number"]) == {1}
+        pairs = df.apply(
+            lambda g: frozenset((g["white_player_id"], g["black_player_id"])), axis=1
+        )
+        assert pairs.value_counts().tolist() == [2] * 6
+        # Colors are reversed in the second meeting
+        for pair in set(pairs):
+            whites = df.loc[pairs == pair, "white_player_id"]
+            assert whites.nunique() == 2
+
+    def test_n_rounds_from_crosstable(self):
+        result = self._parse_fixture("double_round_robin_900002_report.html", "900002")
+        df = results_to_players_dataframe([result])
+
+        assert str(df["n_rounds"].dtype) == "Int64"
+        assert set(df["n_rounds"]) == {6}
+
+
 class TestFixtureBasedParsing:
     """Tests using real FIDE HTML fixture. Validates parser against actual format."""
 
@@ -542,6 +671,39 @@ class TestFixtureBasedParsing:
         assert report is None
         assert error == ERROR_REPORT_UPDATED_OR_REPLACED
 
+    def test_fetches_url_from_report_link(self):
+        session = MagicMock()
+        session.get.return_value = MagicMock(status_code=404)
+
+        url = SOURCE_REPORT_URL.format(code="9")
+        fetch_tournament_report("34330", session, url=url)
+        fetch_tournament_report("34330", session)
+
+        urls = [c.args[0] for c in session.get.call_args_list]
+        assert urls[0] == url
+        assert urls[-1] == SOURCE_REPORT_URL.format(code="34330")
+
+    def test_report_links_from_details(self):
+        import pandas as pd
+
+        details = pd.DataFrame(
+            {
+                "id": ["1", "2", "3", "4"],
+                "success": [True, True, True, False],
+                "report_kind": [RATING_REPORT, PAIRING_PROGRAM, None, RATING_REPORT],
+                "report_system": ["fide", "swiss-manager", None, "fide"],
+                "report_event": ["10", None, None, "40"],
+            }
+        )
+
+        links = report_links_from_frame(details[details["success"]])
+
+        assert links == {
+            "1": ReportLink(RATING_REPORT, "fide", "10", ""),
+            "2": ReportLink(PAIRING_PROGRAM, "swiss-manager", None, ""),
+        }
+        assert report_links_from_frame(details.drop(columns="report_kind")) == {}
+
     @pytest.mark.online
     def test_live_fetch_matches_fixture(self):
         """
@@ -602,3 +764,71 @@ class TestFixtureBasedParsing:
         assert player["id"]
         assert player["name"]
         assert player["total"] is not None
+
+
+class TestPartialSalvage:
+    """Malformed crosstable rows are skipped and recorded, not fatal."""
+
+    @staticmethod
+    def _parse(html: str) -> dict:
+        mock_response = MagicMock()
+        mock_response.status_code = 200
+        mock_response.content = html.encode("utf-8")
+        session = MagicMock()
+        session.get.return_value = mock_response
+        report, error, _, _ = fetch_tournament_report("900001", session)
+        assert error is None
+        return {**report, "success": True}
+
+    @staticmethod
+    def _fixture_html() -> str:
+        path = (
+            Path(__file__).parent
+            / "fixtures"
+            / "match_two_games_per_round_900001_report.html"
+        )
+        return path.read_text(encoding="utf-8")
+
+    def test_clean_report_has_no_skipped_rows(self):
+        report = self._parse(self._fixture_html())
+        assert "skipped_rows" not in report
+        assert partial_parse_entries([report]) == []
+
+    def test_unknown_opponent_anchor_recorded(self):
+        html = self._fixture_html().replace('href="#2"', 'href="#99"', 1)
+        report = self._parse(html)
+
+        carlsen = report["players"][0]
+        assert len(carlsen["rounds"]) == 3
+        assert report["skipped_rows"] == [
+            {
+                "player_id": "1503014",
+                "row": "1   25/03/01",
+                "reason": SKIP_UNKNOWN_OPPONENT,
+                "detail": "no player row for anchor #99",
+            }
+        ]
+
+    def test_malformed_rows_skipped_rest_salvaged(self, monkeypatch):
+        real_parse_round_date = get_tournament_reports.parse_round_date
+
+        def flaky_parse_round_date(text):
+            if text.startswith("2"):
+                raise ValueError("bad round cell")
+            return real_parse_round_date(text)
+
+        monkeypatch.setattr(
+            get_tournament_reports, "parse_round_date", flaky_parse_round_date
+        )
+        report = self._parse(self._fixture_html())
+
+        assert [len(p["rounds"]) for p in report["players"]] == [2, 2]
+        assert len(report["skipped_rows"]) == 4
+        assert {r["reason"] for r in report["skipped_rows"]} == {SKIP_PARSE_ERROR}
+        assert report["skipped_rows"][0]["detail"] == "ValueError: bad round cell"
+
+        games = flatten_to_games(flatten_result(report))
+        assert len(games) == 2
+
+        entries = partial_parse_entries([report, {"success": False}])
+        assert [e["tournament_code"] for e in entries] == ["900001"]
@@ -0,0 +1,80 @@
+"""Unit tests for configurable request timeouts (http_timeouts.py)."""
+
+import argparse
+
+import pytest
+import requests
+
+import http_timeouts
+
+
+@pytest.fixture(autouse=True)
+def reset_config():
+    http_timeouts.configure()
+    yield
+    http_timeouts.configure()
+
+
+class FakeResponse:
+    def __init__(self, chunks):
+        self.chunks = chunks
+        self.closed = False
+
+    def iter_content(self, chunk_size):
+        yield from self.chunks
+
+    def close(self):
+        self.closed = True
+
+
+class FakeSession:
+    def __init__(self, response):
+        self.response = response
+        self.calls = []
+
+    def get(self, url, **kwargs):
+        self.calls.append(kwargs)
+        return self.response
+
+
+def test_defaults_and_cli_flags():
+    assert http_timeouts.requests_timeout() == (15.0, 45.0)
+    parser = argparse.ArgumentParser()
+    http_timeouts.add_arguments(parser)
+    http_timeouts.configure_from_args(parser.parse_args(["--read-timeout", "90"]))
+    assert http_timeouts.requests_timeout() == (15.0, 90.0)
+
+
+def test_from_mapping_validates():
+    http_timeouts.from_mapping({"connect_timeout": "5", "total_timeout": 120})
+    assert http_timeouts.requests_timeout() == (5.0, 45.0)
+    with pytest.raises(ValueError):
+        http_timeouts.from_mapping({"read_timeout": "slow"})
+    with pytest.raises(ValueError):
+        http_timeouts.configure(connect=0)
+
+
+def test_get_without_total_passes_tuple_timeout():
+    session = FakeSession(FakeResponse([]))
+    http_timeouts.get(session, "u", headers={"A": "b"})
+    assert session.calls == [{"timeout": (15.0, 45.0), "headers": {"A": "b"}}]
+
+
+def test_get_with_total_reads_body(monkeypatch):
+    http_timeouts.configure(total=10)
+    response = FakeResponse([b"ab", b"cd"])
+    session = FakeSession(response)
+    result = http_timeouts.get(session, "u")
+    assert result._content == b"abcd"
+    assert session.calls[0]["stream"] is True
+    assert response.closed
+
+
+def test_get_raises_once_total_passes(monkeypatch):
+    http_timeouts.configure(total=10)
+    clock = iter([0.0, 5.0, 11.0])
+    monkeypatch.setattr(http_timeouts.time, "monotonic", lambda: next(clock))
+    response = FakeResponse([b"a", b"b", b"c"])
+    with pytest.raises(requests.Timeout, match="total timeout"):
+        http_timeouts.get(FakeSession(response), "u")
+    assert response.closed
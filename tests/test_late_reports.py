@@ -0,0 +1,67 @@
+"""
+Tests for late_reports.py.
+
+Offline: per-game delays, late flags per month played and totals.
+"""
+
+import pandas as pd
+
+from late_reports import audit, game_delays, summarize
+
+
+def _details():
+    return pd.DataFrame(
+        {
+            "success": [True, True, True],
+            "id": ["100", "200", "300"],
+            "end_date": pd.to_datetime(["2024-01-10", "2024-01-20", "2024-02-05"]),
+            "date_received": pd.to_datetime(["2024-01-15", "2024-04-01", None]),
+        }
+    )
+
+
+def _games():
+    return pd.DataFrame(
+        {
+            "tournament_id": ["100", "100", "200", "200", "300", "999"],
+            "round_date": pd.to_datetime(
+                ["2024-01-09", "2024-01-10", "2024-01-19", None, "2024-02-04", None]
+            ),
+        }
+    )
+
+
+def test_game_delays():
+    delays = game_delays(_games(), _details())
+    assert list(delays["month"].fillna("")) == [
+        "2024-01",
+        "2024-01",
+        "2024-01",
+        "2024-01",
+        "2024-02",
+        "",
+    ]
+    assert delays.loc[2, "delay_days"] == 72
+    assert delays.loc[2, "months_late"] == 3
+    assert pd.isna(delays.loc[4, "delay_days"])
+
+
+def test_audit_counts_late_games_per_month():
+    by_month = audit(game_delays(_games(), _details())).set_index("month")
+    jan = by_month.loc["2024-01"]
+    assert jan["games"] == 4
+    assert jan["late_games"] == 2
+    assert jan["late_tournaments"] == 1
+    assert jan["months_late_3+"] == 2
+    assert jan["median_delay_days"] == (5 + 72) / 2
+    assert by_month.loc["2024-02", "late_games"] == 0
+
+    summary = summarize(by_month.reset_index(), 30)
+    assert summary["games"] == 5
+    assert summary["late_share"] == 0.4
+    assert summary["by_months_late"]["months_late_3+"] == 2
+
+
+def test_late_days_threshold():
+    by_month = audit(game_delays(_games(), _details()), late_days=100)
+    assert by_month["late_games"].sum() == 0
@@ -0,0 +1,81 @@
+"""Unit tests for live rate changes via --control-file and SIGHUP (live_config.py)."""
+
+import argparse
+import os
+import signal
+
+import pytest
+
+import live_config
+from rate_limiter import RateLimiter
+
+
+def test_apply_changes_rate(tmp_path):
+    control = tmp_path / "control.json"
+    control.write_text('{"rate_limit": 0.2}')
+    limiter = RateLimiter(0.5)
+    assert live_config.apply(control, limiter)
+    assert limiter.get_rate() == pytest.approx(0.2)
+    control.write_text('{"rate_limit": 0}')
+    assert live_config.apply(control, limiter)
+    assert limiter.get_rate() == 0.0
+
+
+@pytest.mark.parametrize(
+    "content",
+    ['{"rate_limit": -1}', '{"rate_limit": "fast"}', '{"workers": 2}', "[1]", "{"],
+)
+def test_invalid_file_keeps_current_rate(tmp_path, content):
+    control = tmp_path / "control.json"
+    control.write_text(content)
+    limiter = RateLimiter(0.5)
+    assert not live_config.apply(control, limiter)
+    assert limiter.get_rate() == pytest.approx(0.5)
+
+
+def test_missing_file(tmp_path):
+    with pytest.raises(ValueError, match="cannot read"):
+        live_config.read_settings(tmp_path / "missing.json")
+
+
+def test_add_arguments():
+    parser = argparse.ArgumentParser()
+    live_config.add_arguments(parser)
+    assert parser.parse_args([]).control_file is None
+    assert parser.parse_args(["--control-file", "c.json"]).control_file == "c.json"
+
+
+@pytest.mark.skipif(not hasattr(signal, "SIGHUP"), reason="no SIGHUP")
+def test_sighup_rereads_file(tmp_path):
+    control = tmp_path / "control.json"
+    control.write_text('{"rate_limit": 2}')
+    limiter = RateLimiter(0.5)
+    previous = signal.getsignal(signal.SIGHUP)
+    try:
+        assert live_config.install(control, limiter)
+        os.kill(os.getpid(), signal.SIGHUP)
+        assert limiter.get_rate() == pytest.approx(2.0)
+    finally:
+        signal.signal(signal.SIGHUP, previous)
+
+
+def test_poller_applies_changed_file_when_due(tmp_path):
+    control = tmp_path / "control.json"
+    now = [0.0]
+    limiter = RateLimiter(0.5)
+    poller = live_config.ControlFilePoller(
+        str(control), limiter, poll_seconds=30, clock=lambda: now[0]
+    )
+    assert not poller.poll()  # missing file: nothing to apply
+    control.write_text('{"rate_limit": 0.2}')
+    assert not poller.poll()  # not due yet
+    now[0] = 30.0
+    assert poller.poll()
+    assert limiter.get_rate() == pytest.approx(0.2)
+    now[0] = 60.0
+    assert not poller.poll()  # unchanged
+
+    control.write_text('{"rate_limit": "fast"}')
+    now[0] = 90.0
+    assert not poller.poll()
+    assert limiter.get_rate() == pytest.approx(0.2)
@@ -0,0 +1,31 @@
+"""Unit tests for month arguments (months.py)."""
+
+import argparse
+
+import pytest
+
+from months import month_range, parse_month
+
+
+class TestParseMonth:
+    def test_parse(self):
+        assert parse_month("2024-06") == (2024, 6)
+
+    @pytest.mark.parametrize("bad", ["", "2024", "2024-13", "2024-00", "June-2024"])
+    def test_parse_invalid(self, bad):
+        with pytest.raises(argparse.ArgumentTypeError):
+            parse_month(bad)
+
+
+class TestMonthRange:
+    def test_crosses_year_boundary(self):
+        assert month_range((2023, 11), (2024, 2)) == [
+            (2023, 11),
+            (2023, 12),
+            (2024, 1),
+            (2024, 2),
+        ]
+
+    def test_start_after_end_raises(self):
+        with pytest.raises(ValueError):
+            month_range((2024, 2), (2024, 1))
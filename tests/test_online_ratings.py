@@ -0,0 +1,76 @@
+"""Unit tests for the online ratings importer (online_ratings.py)."""
+
+import pytest
+
+from online_ratings import parse_chesscom, parse_lichess, read_mapping
+
+
+def test_parse_lichess_skips_unplayed_perfs():
+    data = {
+        "perfs": {
+            "blitz": {"games": 120, "rating": 2210, "rd": 48, "prog": 5},
+            "rapid": {"games": 3, "rating": 1900, "rd": 190, "prov": True},
+            "bullet": {"games": 0, "rating": 1500, "rd": 500, "prov": True},
+            "puzzle": {"games": 40, "rating": 2000, "rd": 80},
+        }
+    }
+    assert parse_lichess(data) == [
+        {
+            "time_control": "blitz",
+            "rating": 2210,
+            "rd": 48,
+            "games": 120,
+            "provisional": False,
+        },
+        {
+            "time_control": "rapid",
+            "rating": 1900,
+            "rd": 190,
+            "games": 3,
+            "provisional": True,
+        },
+    ]
+
+
+def test_parse_chesscom_counts_record_and_flags_high_rd():
+    data = {
+        "chess_blitz": {
+            "last": {"rating": 2400, "date": 1700000000, "rd": 45},
+            "record": {"win": 10, "loss": 5, "draw": 2},
+        },
+        "chess_daily": {
+            "last": {"rating": 1800, "date": 1700000000, "rd": 150},
+            "record": {"win": 1, "loss": 0, "draw": 0},
+        },
+        "chess_rapid": {"best": {"rating": 2000}},
+        "tactics": {"highest": {"rating": 2500}},
+    }
+    rows = {r["time_control"]: r for r in parse_chesscom(data)}
+    assert set(rows) == {"blitz", "daily"}
+    assert rows["blitz"]["games"] == 17
+    assert rows["blitz"]["provisional"] is False
+    assert rows["daily"]["provisional"] is True
+
+
+def test_read_mapping_normalizes_sites_and_drops_bad_rows(tmp_path):
+    path = tmp_path / "accounts.csv"
+    path.write_text(
+        "fide_id,site,username\n"
+        "1503014, Lichess ,DrNykterstein\n"
+        "1503014,chess.com,MagnusCarlsen\n"
+        "1503014,chess.com,MagnusCarlsen\n"
+        "2020009,icc,someone\n"
+        ",lichess,nobody\n"
+    )
+    mapping = read_mapping(path)
+    assert mapping.to_dict("records") == [
+        {"fide_id": "1503014", "site": "lichess", "username": "DrNykterstein"},
+        {"fide_id": "1503014", "site": "chesscom", "username": "MagnusCarlsen"},
+    ]
+
+
+def test_read_mapping_requires_columns(tmp_path):
+    path = tmp_path / "accounts.csv"
+    path.write_text("fide_id,username\n1,x\n")
+    with pytest.raises(ValueError, match="site"):
+        read_mapping(path)
@@ -0,0 +1,241 @@
+"""
+Tests for scripts/package_release.py.
+
+Offline: bundle layout, manifest checksums, skipping incomplete months, and the
+SQLite export.
+"""
+
+import hashlib
+import json
+import sqlite3
+import sys
+import tarfile
+from pathlib import Path
+from unittest.mock import MagicMock
+
+import pytest
+
+sys.path.insert(0, str(Path(__file__).parent.parent / "src" / "scraper"))
+sys.path.insert(0, str(Path(__file__).parent.parent / "scripts"))
+
+from package_release import MONTHLY_FILES, SQLITE_INDEXES, package_release
+
+
+def _make_data(root: Path, months: list[str]) -> None:
+    fed_dir = root / "federations" / "data"
+    fed_dir.mkdir(parents=True)
+    (fed_dir / "federations_20250101-000000.csv").write_text("code,name\nUSA,USA\n")
+    players_dir = root / "player_lists" / "data"
+    players_dir.mkdir(parents=True)
+    (players_dir / "player_list_20250101-000000.parquet").write_bytes(b"players")
+    for month in months:
+        d = root / "prod" / month / "data"
+        d.mkdir(parents=True)
+        for name in MONTHLY_FILES:
+            (d / name).write_bytes(f"{month}/{name}".encode())
+
+
+def test_package_release_layout_and_checksums(tmp_path):
+    root = tmp_path / "data"
+    _make_data(root, ["2024-01", "2024-02"])
+    # 2024-03 is incomplete and must be skipped
+    (root / "prod" / "2024-03" / "data").mkdir(parents=True)
+
+    tar_path = package_release(
+        root, ["2024-01", "2024-02", "2024-03"], "2025.01", tmp_path / "out"
+    )
+
+    assert tar_path.name == "fide-glicko-2025.01.tar.gz"
+    sha_line = (tmp_path / "out" / "fide-glicko-2025.01.tar.gz.sha256").read_text()
+    assert sha_line.split()[0] == hashlib.sha256(tar_path.read_bytes()).hexdigest()
+
+    with tarfile.open(tar_path) as tar:
+        names = set(tar.getnames())
+        manifest = json.load(tar.extractfile("fide-glicko-2025.01/manifest.json"))
+        assert "fide-glicko-2025.01/LICENSE" in names
+        assert manifest["version"] == "2025.01"
+        assert manifest["months"] == ["2024-01", "2024-02"]
+        for entry in manifest["files"]:
+            data = tar.extractfile(f"fide-glicko-2025.01/{entry['path']}").read()
+            assert entry["bytes"] == len(data)
+            assert entry["sha256"] == hashlib.sha256(data).hexdigest()
+
+    paths = {e["path"] for e in manifest["files"]}
+    assert "players/player_list_20250101-000000.parquet" in paths
+    assert "months/2024-02/tournament_reports_games.parquet" in paths
+    assert not any(p.startswith("months/2024-03") for p in paths)
+
+
+def test_package_release_refuses_overwrite(tmp_path):
+    root = tmp_path / "data"
+    _make_data(root, ["2024-01"])
+    package_release(root, ["2024-01"], "1", tmp_path / "out")
+    with pytest.raises(FileExistsError):
+        package_release(root, ["2024-01"], "1", tmp_path / "out")
+    package_release(root, ["2024-01"], "1", tmp_path / "out", override=True)
+
+
+def test_package_release_nothing_to_package(tmp_path):
+    with pytest.raises(ValueError):
+        package_release(tmp_path, ["2024-01"], "1", tmp_path / "out")
+
+
+def test_package_release_sqlite(tmp_path):
+    import pandas as pd
+
+    root = tmp_path / "data"
+    _make_data(root, ["2024-01"])
+    players = root / "player_lists" / "data" / "player_list_20250101-000000.parquet"
+    pd.DataFrame({"id": [1503014], "name": ["Carlsen, Magnus"]}).to_parquet(players)
+    month_dir = root / "prod" / "2024-01" / "data"
+    pd.DataFrame(
+        {
+            "tournament_id": ["368512"],
+            "name": ["Open"],
+            "chief_arbiter_names": [["Marghetis, Aris"]],
+        }
+    ).to_parquet(month_dir / "tournament_details.parquet")
+    pd.DataFrame({"player_id": ["1503014"], "tournament_id": ["368512"]}).to_parquet(
+        month_dir / "tournament_reports_players.parquet"
+    )
+    pd.DataFrame(
+        {
+            "white_player_id": ["1503014"],
+            "black_player_id": ["2020009"],
+            "tournament_id": ["368512"],
+            "round_date": pd.to_datetime(["2024-01-05"]),
+            "score": [1.0],
+        }
+    ).to_parquet(month_dir / "tournament_reports_games.parquet")
+
+    package_release(root, ["2024-01"], "2025.01", tmp_path / "out", sqlite=True)
+
+    db_path = tmp_path / "out" / "fide-glicko-2025.01.sqlite"
+    sha_line = (tmp_path / "out" / "fide-glicko-2025.01.sqlite.sha256").read_text()
+    assert sha_line.split()[0] == hashlib.sha256(db_path.read_bytes()).hexdigest()
+    conn = sqlite3.connect(db_path)
+    try:
+        release = dict(conn.execute("SELECT key, value FROM release"))
+        assert release["version"] == "2025.01"
+        assert json.loads(release["months"]) == ["2024-01"]
+        assert conn.execute("SELECT code FROM federations").fetchall() == [("USA",)]
+        assert conn.execute(
+            "SELECT white_player_id, round_date, month FROM games"
+        ).fetchall() == [("1503014", "2024-01-05T00:00:00Z", "2024-01")]
+        (arbiters,) = conn.execute(
+            "SELECT chief_arbiter_names FROM tournaments"
+        ).fetchone()
+        assert json.loads(arbiters) == ["Marghetis, Aris"]
+        indexes = {
+            row[0]
+            for row in conn.execute("SELECT name FROM sqlite_master WHERE type='index'")
+        }
+        assert {"idx_players_id", "idx_games_white_player_id"} <= indexes
+    finally:
+        conn.close()
+
+
+def test_package_release_sqlite_months_with_different_columns(tmp_path):
+    import pandas as pd
+
+    root = tmp_path / "data"
+    _make_data(root, ["2024-01", "2024-02"])
+    players = root / "player_lists" / "data" / "player_list_20250101-000000.parquet"
+    pd.DataFrame({"id": [1503014]}).to_parquet(players)
+    for month, extra in (("2024-01", {}), ("2024-02", {"report_kind": ["other"]})):
+        month_dir = root / "prod" / month / "data"
+        pd.DataFrame(
+            {"tournament_id": [month.replace("-", "")], "n_rounds": [9], **extra}
+        ).to_parquet(month_dir / "tournament_details.parquet")
+        pd.DataFrame({"player_id": ["1503014"], "tournament_id": ["1"]}).to_parquet(
+            month_dir / "tournament_reports_players.parquet"
+        )
+        pd.DataFrame(
+            {
+                "tournament_id": ["1"],
+                "round_date": pd.to_datetime(["2024-01-05 12:30"]).tz_localize(
+                    "Europe/Oslo"
+                ),
+            }
+        ).to_parquet(month_dir / "tournament_reports_games.parquet")
+
+    package_release(
+        root, ["2024-01", "2024-02"], "2025.02", tmp_path / "out", sqlite=True
+    )
+
+    conn = sqlite3.connect(tmp_path / "out" / "fide-glicko-2025.02.sqlite")
+    try:
+        assert conn.execute(
+            "SELECT month, n_rounds, report_kind FROM tournaments ORDER BY month"
+        ).fetchall() == [("2024-01", 9, None), ("2024-02", 9, "other")]
+        assert conn.execute("SELECT DISTINCT round_date FROM games").fetchall() == [
+            ("2024-01-05T11:30:00Z",)
+        ]
+    finally:
+        conn.close()
+
+
+def test_package_release_sqlite_from_scraper_output(tmp_path):
+    """Every SQLITE_INDEXES column exists in the tables built from real output."""
+    import pandas as pd
+    from get_tournament_details import (
+        fetch_tournament_details,
+        results_to_parquet_bytes,
+    )
+    from get_tournament_reports import (
+        fetch_tournament_report,
+        games_parquet_bytes,
+        players_parquet_bytes,
+    )
+
+    fixtures = Path(__file__).parent / "fixtures"
+    session = MagicMock()
+    session.get.return_value = MagicMock(
+        status_code=200,
+        content=(fixtures / "candidates_24_details.html").read_bytes(),
+    )
+    details, error, _, _ = fetch_tournament_details("368261", session)
+    assert error is None
+    session.get.return_value = MagicMock(
+        status_code=200,
+        content=(fixtures / "double_round_robin_900002_report.html").read_bytes(),
+    )
+    report, error, _, _ = fetch_tournament_report("900002", session)
+    assert error is None
+    results = [{**report, "success": True}]
+
+    root = tmp_path / "data"
+    _make_data(root, ["2024-01"])
+    month_dir = root / "prod" / "2024-01" / "data"
+    (month_dir / "tournament_details.parquet").write_bytes(
+        results_to_parquet_bytes(
+            [{"tournament_id": "368261", "success": True, "details": details}]
+        )
+    )
+    (month_dir / "tournament_reports_players.parquet").write_bytes(
+        players_parquet_bytes(results)[0]
+    )
+    (month_dir / "tournament_reports_games.parquet").write_bytes(
+        games_parquet_bytes(results)[0]
+    )
+    players = root / "player_lists" / "data" / "player_list_20250101-000000.parquet"
+    pd.DataFrame({"id": [1503014]}).to_parquet(players)
+
+    package_release(root, ["2024-01"], "2025.01", tmp_path / "out", sqlite=True)
+
+    conn = sqlite3.connect(tmp_path / "out" / "fide-glicko-2025.01.sqlite")
+    try:
+        indexes = {
+            row[0]
+            for row in conn.execute("SELECT name FROM sqlite_master WHERE type='index'")
+        }
+        assert conn.execute("SELECT tournament_id FROM tournaments").fetchall() == [
+            ("368261",)
+        ]
+    finally:
+        conn.close()
+    assert indexes == {
+        f"idx_{table}_{col}"
+        for table, columns in SQLITE_INDEXES.items()
+        for col in columns
+    }
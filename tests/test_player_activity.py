@@ -0,0 +1,85 @@
+"""
+Tests for player_activity.py.
+
+Offline: per-player totals, active span, longest gap, per-year counts and
+schedule entropy.
+"""
+
+import pandas as pd
+import pytest
+
+from player_activity import (
+    activity_by_year,
+    activity_summary,
+    player_game_rows,
+    schedule_entropy,
+)
+
+
+def _games():
+    return pd.DataFrame(
+        {
+            "white_player_id": ["1", "2", "1", "3", ""],
+            "black_player_id": ["2", "1", "3", "1", "2"],
+            "tournament_id": ["100", "100", "200", "300", "300"],
+            "round_date": pd.to_datetime(
+                ["2023-01-01", "2023-01-02", "2023-03-02", "2024-01-01", None]
+            ),
+        }
+    )
+
+
+def _by_player(df):
+    return df.set_index("player_id")
+
+
+class TestActivitySummary:
+    def test_totals_span_and_longest_gap(self):
+        summary = _by_player(activity_summary(player_game_rows(_games())))
+        p1 = summary.loc["1"]
+        assert p1["games"] == 4
+        assert p1["events"] == 3
+        assert p1["active_years"] == 2
+        assert p1["active_span_days"] == 365
+        assert p1["longest_gap_days"] == 305
+        assert p1["longest_gap_start"] == pd.Timestamp("2023-03-02")
+        assert p1["longest_gap_end"] == pd.Timestamp("2024-01-01")
+
+    def test_undated_games_count_but_do_not_shift_dates(self):
+        summary = _by_player(activity_summary(player_game_rows(_games())))
+        p2 = summary.loc["2"]
+        assert p2["games"] == 3
+        assert p2["events"] == 2
+        assert p2["last_game"] == pd.Timestamp("2023-01-02")
+        assert p2["longest_gap_days"] == 1
+
+    def test_single_game_has_no_gap(self):
+        games = _games().iloc[[0]]
+        summary = _by_player(activity_summary(player_game_rows(games)))
+        assert summary.loc["1", "longest_gap_days"] == 0
+        assert summary.loc["1", "active_span_days"] == 0
+
+    def test_missing_player_ids_dropped(self):
+        rows = player_game_rows(_games())
+        assert "" not in set(rows["player_id"])
+
+
+class TestActivityByYear:
+    def test_games_and_events_per_year(self):
+        by_year = activity_by_year(player_game_rows(_games()))
+        p1 = by_year[by_year["player_id"] == "1"].set_index("year")
+        assert p1.loc[2023, "games"] == 3
+        assert p1.loc[2023, "events"] == 2
+        assert p1.loc[2024, "games"] == 1
+
+    def test_opponents_and_schedule_entropy(self):
+        by_year = activity_by_year(player_game_rows(_games()))
+        p1 = by_year[by_year["player_id"] == "1"].set_index("year")
+        # 2023: twice vs 2, once vs 3
+        assert p1.loc[2023, "opponents"] == 2
+        assert p1.loc[2023, "opponent_entropy"] == pytest.approx(0.9183, abs=1e-4)
+        assert p1.loc[2024, "opponent_entropy"] == 0.0
+
+    def test_schedule_entropy_ignores_unknown_opponents(self):
+        assert schedule_entropy(pd.Series(["1", "2", "3", "4"])) == 2.0
+        assert schedule_entropy(pd.Series(["", " "])) == 0.0
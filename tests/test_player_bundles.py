@@ -0,0 +1,72 @@
+"""Unit tests for per-player JSON bundles (player_bundles.py)."""
+
+import json
+
+import pandas as pd
+
+from player_bundles import build_bundle, bundle_path, player_results
+
+
+def _games():
+    return pd.DataFrame(
+        {
+            "white_player_id": ["1", "2", "1", "3", "1"],
+            "black_player_id": ["2", "1", "3", "", "2"],
+            "tournament_id": ["T1", "T1", "T2", "T2", "T3"],
+            "round_date": pd.to_datetime(
+                ["2025-01-01", "2025-01-02", "2025-02-01", "2025-02-01", None]
+            ),
+            "score": [1.0, 0.5, 0.0, 1.0, 0.5],
+        }
+    )
+
+
+def test_bundle_path_is_sharded_by_hash(tmp_path):
+    path = bundle_path(tmp_path, "1503014")
+    assert path.name == "1503014.json"
+    assert len(path.parent.name) == 2
+    assert path == bundle_path(tmp_path, "1503014")
+
+
+def test_player_results_both_sides_skip_missing_opponent():
+    rows = player_results(_games())
+    mine = rows[rows["player_id"] == "2"].sort_values("date", na_position="last")
+    assert list(mine["opponent_id"]) == ["1", "1", "1"]
+    assert list(mine["score"]) == [0.0, 0.5, 0.5]
+    assert len(rows) == 8
+
+
+def test_build_bundle():
+    rows = player_results(_games())
+    bundle = build_bundle(
+        "1",
+        rows[rows["player_id"] == "1"],
+        profile={"name": "Doe, J", "fed": "NED", "title": None, "byear": 1990},
+        names={"2": "Roe, R"},
+        events={"T2": {"event": "Open", "site": ""}},
+        recent=2,
+        opponents=1,
+    )
+    assert bundle["name"] == "Doe, J" and bundle["title"] is None
+    assert (bundle["games"], bundle["events"]) == (4, 3)
+    assert (bundle["first_game"], bundle["last_game"]) == ("2025-01-01", "2025-02-01")
+    assert bundle["recent_events"] == [
+        {
+            "tournament_id": "T2",
+            "event": "Open",
+            "end": "2025-02-01",
+            "games": 1,
+            "score": 0.0,
+        },
+        {
+            "tournament_id": "T1",
+            "event": None,
+            "end": "2025-01-02",
+            "games": 2,
+            "score": 1.5,
+        },
+    ]
+    assert bundle["head_to_head"] == [
+        {"id": "2", "name": "Roe, R", "games": 3, "wins": 1, "draws": 2, "losses": 0}
+    ]
+    json.dumps(bundle)
@@ -0,0 +1,96 @@
+"""Tests for player_list_delta (delta encoding of player list snapshots)."""
+
+import pandas as pd
+import pytest
+
+from player_list_delta import (
+    OP_DELETE,
+    OP_UPSERT,
+    PLAYER_COLUMNS,
+    apply_player_list_delta,
+    diff_player_lists,
+    encode_snapshots,
+    load_snapshot,
+)
+
+
+def _players(rows):
+    # Same dtypes as get_player_list output: int64 id, float64 byear (a player
+    # without a birth year is NaN)
+    return pd.DataFrame(rows, columns=PLAYER_COLUMNS).astype(
+        {"byear": "float64", "id": "int64"}
+    )
+
+
+BASE = _players(
+    [
+        [1990, 100, "USA", "Alpha, A", "M", "GM", None],
+        [None, 200, "NOR", "Beta, B", "F", None, "WFM"],
+        [1985, 300, "FRA", "Gamma, C", "M", None, None],
+    ]
+)
+
+
+def _assert_same_players(actual, expected):
+    assert actual["id"].dtype == "int64"
+    pd.testing.assert_frame_equal(
+        actual[PLAYER_COLUMNS].sort_values("id").reset_index(drop=True),
+        expected[PLAYER_COLUMNS].sort_values("id").reset_index(drop=True),
+    )
+
+
+class TestDiffAndApply:
+    def test_identical_lists_give_empty_delta(self):
+        assert diff_player_lists(BASE, BASE.copy()).empty
+
+    def test_add_change_remove(self):
+        curr = _players(
+            [
+                [1990, 100, "USA", "Alpha, A", "M", "GM", None],
+                [None, 200, "ENG", "Beta, B", "F", None, "WIM"],
+                [2010, 400, "IND", "Delta, D", "M", None, None],
+            ]
+        )
+        delta = diff_player_lists(BASE, curr)
+        ops = dict(zip(delta["id"], delta["op"]))
+        assert ops == {200: OP_UPSERT, 400: OP_UPSERT, 300: OP_DELETE}
+        assert delta["id"].dtype == "int64"
+
+        rebuilt = apply_player_list_delta(BASE, delta)
+        _assert_same_players(rebuilt, curr)
+
+
+class TestEncodeAndLoad:
+    def _write(self, directory, ts, df):
+        df.to_parquet(directory / f"player_list_{ts}.parquet", index=False)
+
+    def test_round_trip(self, tmp_path):
+        src = tmp_path / "data"
+        out = tmp_path / "delta"
+        src.mkdir()
+        lists = {
+            "20250101-000000": BASE,
+            "20250201-000000": BASE.iloc[:2],
+            "20250301-000000": pd.concat(
+                [BASE.iloc[:2], _players([[2012, 500, "CHN", "E", "F", None, None]])]
+            ),
+        }
+        for ts, df in lists.items():
+            self._write(src, ts, df)
+
+        summary = encode_snapshots(src, out, full_every=2)
+        assert summary["snapshots"] == 3
+        assert summary["full"] == 2
+        assert summary["delta"] == 1
+        assert (out / "player_list_20250201-000000.delta.parquet").exists()
+
+        for ts, df in lists.items():
+            _assert_same_players(load_snapshot(out, ts), df)
+
+    def test_load_unknown_timestamp_raises(self, tmp_path):
+        src = tmp_path / "data"
+        src.mkdir()
+        self._write(src, "20250101-000000", BASE)
+        encode_snapshots(src, tmp_path / "delta")
+        with pytest.raises(FileNotFoundError):
+            load_snapshot(tmp_path / "delta", "20250115-000000")
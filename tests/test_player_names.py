@@ -0,0 +1,80 @@
+"""
+Tests for player_names.py.
+
+Offline: Cyrillic transliteration, matching keys across romanizations and name
+orders, and the per-player spelling table.
+"""
+
+import pandas as pd
+import pytest
+
+from player_names import match_keys, names_match, spelling_table, transliterate
+
+
+class TestTransliterate:
+    @pytest.mark.parametrize(
+        "cyrillic,latin",
+        [
+            ("Карякин, Сергей", "Karyakin, Sergey"),
+            ("Щербаков", "Shcherbakov"),
+            ("ЩЕРБАКОВ", "SHCHERBAKOV"),
+            ("Іванчук Василь", "Ivanchuk Vasil"),
+            ("Carlsen, Magnus", "Carlsen, Magnus"),
+        ],
+    )
+    def test_transliterate(self, cyrillic, latin):
+        assert transliterate(cyrillic) == latin
+
+
+class TestNamesMatch:
+    @pytest.mark.parametrize(
+        "a,b",
+        [
+            ("Karjakin, Sergey", "Карякин Сергей"),
+            ("Aleksei Shirov", "Shirov, Alexey"),
+            ("Khalifman, Alexander", "Halifman Alexander"),
+            ("Ding, Liren", "Liren Ding"),
+            ("Ding, Liren", "Ding Li-Ren"),
+            ("Dīng Lìrén", "Ding Liren"),
+            ("Lü Shanglei", "Lv Shanglei"),
+        ],
+    )
+    def test_variants_match(self, a, b):
+        assert names_match(a, b)
+
+    def test_different_players_do_not_match(self):
+        assert not names_match("Carlsen, Magnus", "Caruana, Fabiano")
+        assert not names_match("", "")
+
+    def test_split_given_name_adds_joined_keys(self):
+        assert match_keys("Li-Ren Ding") == {"ding li ren", "ding liren", "li rending"}
+
+
+def test_spelling_table():
+    player_list = pd.DataFrame(
+        {"player_id": [8603677], "name": ["Ding, Liren"], "source": "player_list"}
+    )
+    reports = pd.DataFrame(
+        {
+            "player_id": ["8603677", "8603677", "8603677", ""],
+            "name": ["Ding, Liren", "Ding Liren", "Ding, Liren", "Nobody"],
+            "source": "reports",
+        }
+    )
+    table = spelling_table([player_list, reports])
+    assert table.to_dict("records") == [
+        {
+            "player_id": "8603677",
+            "name": "Ding Liren",
+            "source": "reports",
+            "n_seen": 1,
+            "match_key": "ding liren",
+        },
+        {
+            "player_id": "8603677",
+            "name": "Ding, Liren",
+            "source": "player_list,reports",
+            "n_seen": 3,
+            "match_key": "ding liren",
+        },
+    ]
@@ -0,0 +1,77 @@
+"""Unit tests for PGN export of a player's games (player_pgn.py)."""
+
+import pandas as pd
+
+from player_pgn import event_info, pgn_game, player_games, player_names, to_pgn
+
+
+def _games():
+    return pd.DataFrame(
+        {
+            "white_player_id": ["1", "2", "3", "1"],
+            "black_player_id": ["2", "1", "4", "3"],
+            "tournament_id": ["T1", "T1", "T1", "T2"],
+            "round_number": [2, 1, 1, 1],
+            "game_number": [1, 1, 1, 2],
+            "round_date": pd.to_datetime(
+                ["2025-01-02", "2025-01-01", "2025-01-01", None]
+            ),
+            "score": [0.5, 0.0, 1.0, 1.0],
+            "forfeit": ["", "", "", "+"],
+        }
+    )
+
+
+def test_player_games_filters_and_orders():
+    games = player_games(_games(), "1")
+    assert list(games["round_number"]) == [1, 2, 1]
+    assert list(games["tournament_id"]) == ["T1", "T1", "T2"]
+
+
+def test_player_names_ignores_blank_names():
+    players = pd.DataFrame(
+        {"player_id": ["1", "1", "2"], "player_name": ["Carlsen, M", "", "Doe, J"]}
+    )
+    assert player_names(players) == {"1": "Carlsen, M", "2": "Doe, J"}
+
+
+def test_event_info_uses_successful_rows():
+    details = pd.DataFrame(
+        {
+            "event_code": ["T1", "T2"],
+            "success": [True, False],
+            "name": ["Tata Steel", None],
+            "city": ["Wijk aan Zee", None],
+            "fed": ["NED", None],
+        }
+    )
+    assert event_info(details) == {
+        "T1": {"event": "Tata Steel", "site": "Wijk aan Zee NED"}
+    }
+
+
+def test_pgn_game_headers():
+    game = next(player_games(_games(), "1").itertuples(index=False))
+    text = pgn_game(game, {"1": 'Doe, "JJ"', "2": "Roe, R"}, {})
+    assert text.splitlines() == [
+        '[Event "T1"]',
+        '[Site "?"]',
+        '[Date "2025.01.01"]',
+        '[Round "1"]',
+        '[White "Roe, R"]',
+        '[Black "Doe, \\"JJ\\""]',
+        '[Result "0-1"]',
+        '[WhiteFideId "2"]',
+        '[BlackFideId "1"]',
+        "",
+        "0-1",
+    ]
+
+
+def test_to_pgn_forfeit_and_unknown_date():
+    text = to_pgn(player_games(_games(), "1"))
+    last = text.split("\n\n[")[-1]
+    assert '[Date "????.??.??"]' in last
+    assert '[Round "1.2"]' in last
+    assert last.endswith("{Forfeit} 1-0\n")
+    assert text.count("[Event ") == 3
@@ -0,0 +1,62 @@
+"""Unit tests for the missing-player profile queue (profile_queue.py)."""
+
+import pytest
+
+import profile_queue
+from profile_queue import read_queue, update_queue, valid_ids, write_queue
+
+
+def test_valid_ids_drops_blanks_and_normalizes_floats():
+    assert valid_ids(["1503014", "", "nan", "None", None, 4100018.0, "0"]) == {
+        "1503014",
+        "4100018",
+    }
+
+
+def test_read_missing_queue_is_empty(tmp_path):
+    assert read_queue(str(tmp_path / "queue.txt")) == []
+
+
+def test_write_sorts_numerically(tmp_path):
+    path = str(tmp_path / "queue.txt")
+    write_queue(path, ["900", "10", "10"])
+    assert read_queue(path) == ["10", "900"]
+
+
+def test_update_adds_missing_and_drops_resolved(tmp_path):
+    path = str(tmp_path / "queue" / "missing_player_ids.txt")
+    stats = update_queue(path, {"5", "7", ""}, {"1", "2"})
+    assert stats == {"uri": path, "queued": 2, "added": 2, "resolved": 0}
+
+    # 5 is now in the player list; 9 is newly missing
+    stats = update_queue(path, {"7", "9"}, {"1", "2", "5"})
+    assert read_queue(path) == ["7", "9"]
+    assert stats == {"uri": path, "queued": 2, "added": 1, "resolved": 1}
+
+
+def test_update_redoes_change_when_another_writer_wins(tmp_path, monkeypatch):
+    path = str(tmp_path / "missing_player_ids.txt")
+    write_queue(path, ["5"])
+    real_write = profile_queue.write_if_unchanged
+    calls = []
+
+    def racing_write(content, output_path, version):
+        calls.append(version)
+        if len(calls) == 1:
+            # get_player_profiles resolves 5 and queues 8 between read and write
+            write_queue(output_path, ["8"])
+        return real_write(content, output_path, version)
+
+    monkeypatch.setattr(profile_queue, "write_if_unchanged", racing_write)
+    stats = update_queue(path, {"7"}, {"1"})
+
+    assert len(calls) == 2
+    assert read_queue(path) == ["7", "8"]
+    assert stats["queued"] == 2
+
+
+def test_update_gives_up_on_a_queue_that_keeps_changing(tmp_path, monkeypatch):
+    monkeypatch.setattr(profile_queue, "write_if_unchanged", lambda *a: False)
+    path = str(tmp_path / "missing_player_ids.txt")
+    with pytest.raises(RuntimeError, match="gave up"):
+        update_queue(path, {"7"}, set())
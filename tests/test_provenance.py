@@ -0,0 +1,49 @@
+"""
+Tests for provenance.py.
+
+Offline: provenance dict and Parquet key-value metadata round trip.
+"""
+
+import pandas as pd
+import pyarrow as pa
+
+from provenance import (
+    METADATA_PREFIX,
+    SOURCE,
+    build_provenance,
+    dataframe_to_parquet_bytes,
+    provenance_from_schema,
+    read_provenance,
+    with_provenance,
+)
+
+
+class TestBuildProvenance:
+    def test_defaults(self):
+        prov = build_provenance()
+        assert prov["source"] == SOURCE
+        assert prov["scraped_at"].endswith("Z")
+
+    def test_extra_values_stringified_and_none_dropped(self):
+        prov = build_provenance(scraped_at="2025-01-01T00:00:00Z", chunks=3, x=None)
+        assert prov["scraped_at"] == "2025-01-01T00:00:00Z"
+        assert prov["chunks"] == "3"
+        assert "x" not in prov
+
+
+class TestParquetMetadata:
+    def test_round_trip(self, tmp_path):
+        df = pd.DataFrame({"id": ["1", "2"], "fed": ["USA", "NOR"]})
+        prov = build_provenance(scraped_at="2025-01-01T00:00:00Z")
+        path = tmp_path / "out.parquet"
+        path.write_bytes(dataframe_to_parquet_bytes(df, prov))
+
+        assert read_provenance(path) == prov
+        pd.testing.assert_frame_equal(pd.read_parquet(path), df)
+
+    def test_keeps_existing_schema_metadata(self):
+        table = pa.table({"a": [1]}).replace_schema_metadata({"other": "kept"})
+        table = with_provenance(table, {"source": SOURCE})
+        assert table.schema.metadata[b"other"] == b"kept"
+        assert table.schema.metadata[f"{METADATA_PREFIX}source".encode()] == b"FIDE"
+        assert provenance_from_schema(table.schema) == {"source": SOURCE}
@@ -0,0 +1,98 @@
+"""
+Tests for prune.py.
+
+Offline: each retention policy on a key listing, converted JSON detection, and
+local deletion.
+"""
+
+import json
+from datetime import datetime, timezone
+
+import pytest
+
+from prune import (
+    checkpoints_to_prune,
+    converted_json_to_prune,
+    cutoff_month,
+    delete_local,
+    list_local_objects,
+    merged_chunks_to_prune,
+    raw_to_prune,
+)
+
+NOW = datetime(2025, 6, 15, tzinfo=timezone.utc)
+OLD = datetime(2024, 1, 1, tzinfo=timezone.utc)
+
+
+def test_cutoff_month_crosses_year():
+    assert cutoff_month(NOW, 6) == "2024-12"
+    assert cutoff_month(NOW, 0) == "2025-06"
+
+
+def test_raw_to_prune_uses_run_month_and_keeps_latest_shared():
+    objects = {
+        "prod/2024-11/raw/details/details_chunk_0.html.gz": NOW,
+        "prod/2025-01/raw/reports/reports_chunk_0.html.gz": OLD,
+        "custom/my-run/raw/tournaments.json.gz": OLD,
+        "custom/new-run/raw/tournaments.json.gz": NOW,
+        "prod/2024-11/data/tournament_details.parquet": OLD,
+        "player_lists/raw/player_list_20240101-000000.xml.gz": OLD,
+        "player_lists/raw/player_list_20240201-000000.xml.gz": OLD,
+    }
+    assert raw_to_prune(objects, 6, NOW) == [
+        "custom/my-run/raw/tournaments.json.gz",
+        "player_lists/raw/player_list_20240101-000000.xml.gz",
+        "prod/2024-11/raw/details/details_chunk_0.html.gz",
+    ]
+
+
+def test_checkpoints_to_prune():
+    objects = {
+        f"prod/2025-01/data/out.parquet.checkpoint.{n}.gz": NOW for n in (1, 2, 3)
+    }
+    objects["prod/2025-01/data/out.parquet.checkpoint.failures.json"] = NOW
+    assert checkpoints_to_prune(objects, 1) == [
+        "prod/2025-01/data/out.parquet.checkpoint.2.gz",
+        "prod/2025-01/data/out.parquet.checkpoint.3.gz",
+    ]
+    with pytest.raises(ValueError):
+        checkpoints_to_prune(objects, 0)
+
+
+def test_merged_chunks_to_prune_requires_all_merged_files():
+    chunk = "prod/{m}/data/tournament_details_chunks/details_chunk_0_of_2.parquet"
+    objects = {
+        chunk.format(m="2025-01"): NOW,
+        chunk.format(m="2025-02"): NOW,
+        "prod/2025-01/data/tournament_id_chunks/ids_chunk_0_of_2.txt": NOW,
+        "prod/2025-01/data/tournament_details.parquet": NOW,
+        "prod/2025-01/data/tournament_reports_players.parquet": NOW,
+        "prod/2025-01/data/tournament_reports_games.parquet": NOW,
+        "prod/2025-02/data/tournament_details.parquet": NOW,
+    }
+    assert merged_chunks_to_prune(objects) == [
+        chunk.format(m="2025-01"),
+        "prod/2025-01/data/tournament_id_chunks/ids_chunk_0_of_2.txt",
+    ]
+
+
+def test_converted_json_and_delete_local(tmp_path):
+    d = tmp_path / "tournament_details"
+    d.mkdir()
+    records = [{"tournament_id": "1", "success": True, "details": {}}]
+    (d / "2024_01.json").write_text(json.dumps(records))
+    (d / "2024_01.parquet").write_bytes(b"x")
+    (d / "2024_02.json").write_text(json.dumps(records))
+    (d / "2024_01_sample.json").write_text(json.dumps(records))
+    keys = converted_json_to_prune(tmp_path)
+    assert keys == ["tournament_details/2024_01.json"]
+
+    nested = tmp_path / "prod" / "2024-01" / "raw" / "details"
+    nested.mkdir(parents=True)
+    (nested / "chunk.html.gz").write_bytes(b"1234")
+    assert "prod/2024-01/raw/details/chunk.html.gz" in list_local_objects(tmp_path)
+    freed = delete_local(tmp_path, keys + ["prod/2024-01/raw/details/chunk.html.gz"])
+    assert freed == len(json.dumps(records)) + 4
+    assert not (d / "2024_01.json").exists()
+    assert (d / "2024_02.json").exists()
+    assert not (tmp_path / "prod").exists()
@@ -0,0 +1,179 @@
+"""
+Tests for scripts/publish_release.py.
+
+Offline: refusing unfinished months, and the release and upload calls against a
+mocked GitHub session.
+"""
+
+import json
+import sys
+from pathlib import Path
+from unittest.mock import MagicMock
+
+import pytest
+
+sys.path.insert(0, str(Path(__file__).parent.parent / "src" / "scraper"))
+sys.path.insert(0, str(Path(__file__).parent.parent / "scripts"))
+
+from package_release import MONTHLY_FILES
+from publish_release import (
+    SUMMARY_FILES,
+    PublishError,
+    check_month,
+    publish,
+    read_run_summaries,
+)
+from run_summary import build_summary, exit_code_for, merge_summaries, write_summary
+
+
+def _make_month(root: Path, month: str, exit_code: int = 0) -> None:
+    fed_dir = root / "federations" / "data"
+    fed_dir.mkdir(parents=True)
+    (fed_dir / "federations_20250101-000000.csv").write_text("code,name\nUSA,USA\n")
+    d = root / "prod" / month / "data"
+    d.mkdir(parents=True)
+    for name in MONTHLY_FILES:
+        (d / name).write_bytes(name.encode())
+    reports = root / "prod" / month / "reports"
+    reports.mkdir(parents=True)
+    for name in SUMMARY_FILES:
+        summary = {
+            "command": name.removesuffix("_summary.json"),
+            "status": "success" if exit_code == 0 else "partial",
+            "exit_code": exit_code,
+            "counts": {"total": 10, "success": 10, "failed": 0},
+        }
+        (reports / name).write_text(json.dumps(summary))
+
+
+def test_check_month_requires_clean_summaries():
+    with pytest.raises(PublishError, match="missing run summaries"):
+        check_month({})
+    summaries = {name: {"exit_code": 0} for name in SUMMARY_FILES}
+    check_month(summaries)
+    summaries[SUMMARY_FILES[0]] = {"exit_code": 2, "status": "partial"}
+    with pytest.raises(PublishError, match="partial"):
+        check_month(summaries)
+    check_month(summaries, allow_partial=True)
+
+
+def test_publish_creates_release_and_uploads_assets(tmp_path):
+    _make_month(tmp_path / "data", "2025-01")
+    session = MagicMock()
+    session.headers = {}
+    session.post.return_value = MagicMock(status_code=201)
+    session.post.return_value.json.return_value = {"id": 7, "html_url": "u"}
+
+    assets = publish(
+        "2025-01",
+        tmp_path / "data",
+        tmp_path / "releases",
+        "owner/repo",
+        "token",
+        session=session,
+    )
+
+    names = [p.name for p in assets]
+    assert names == [
+        "fide-glicko-2025.01.tar.gz",
+        "fide-glicko-2025.01.tar.gz.sha256",
+        "summary-2025-01.json",
+    ]
+    create = session.post.call_args_list[0]
+    assert create.args[0] == "https://api.github.com/repos/owner/repo/releases"
+    assert create.kwargs["json"]["tag_name"] == "data-2025-01"
+    uploads = session.post.call_args_list[1:]
+    assert [c.kwargs["params"]["name"] for c in uploads] == names
+    assert session.headers["Authorization"] == "Bearer token"
+    summary = json.loads((tmp_path / "releases" / "summary-2025-01.json").read_text())
+    assert summary["manifest"]["months"] == ["2025-01"]
+
+
+def test_publish_refuses_partial_month_and_missing_token(tmp_path):
+    _make_month(tmp_path / "data", "2025-01", exit_code=2)
+    with pytest.raises(PublishError):
+        publish("2025-01", tmp_path / "data", tmp_path / "out", "o/r", "token")
+    assert not (tmp_path / "out").exists()
+    with pytest.raises(PublishError, match="GITHUB_TOKEN"):
+        publish(
+            "2025-01",
+            tmp_path / "data",
+            tmp_path / "out",
+            "o/r",
+            None,
+            allow_partial=True,
+        )
+
+
+def test_publish_github_error(tmp_path):
+    _make_month(tmp_path / "data", "2025-01")
+    session = MagicMock()
+    session.headers = {}
+    session.post.return_value = MagicMock(status_code=422, text="already_exists")
+    with pytest.raises(PublishError, match="HTTP 422"):
+        publish(
+            "2025-01", tmp_path / "data", tmp_path / "out", "o/r", "t", session=session
+        )
+
+
+def _write_prod_summaries(root: Path, month: str, chunks: list) -> None:
+    """Chunk summaries as the Step Function writes them, merged as merge_chunks does."""
+    reports = root / "prod" / month / "reports"
+    for stage, key in (("details", "tournament_id"), ("reports", "tournament_code")):
+        chunk_summaries = []
+        for i, results in enumerate(chunks):
+            n_success = sum(1 for r in results if r["success"])
+            n_failed = sum(1 for r in results if not r["success"] and not r["skipped"])
+            summary = build_summary(
+                f"tournament_{stage}",
+                exit_code_for(n_success, n_failed),
+                results,
+                key=key,
+            )
+            path = reports / f"tournament_{stage}_chunks"
+            path = path / f"{stage}_chunk_{i}_of_{len(chunks)}_summary.json"
+            path.parent.mkdir(parents=True, exist_ok=True)
+            write_summary(summary, str(path))
+            chunk_summaries.append(summary)
+        merged = merge_summaries(
+            chunk_summaries, f"tournament_{stage}", expected=len(chunks)
+        )
+        write_summary(merged, str(reports / f"tournament_{stage}_summary.json"))
+
+
+def _result(item: str, success: bool = True, error=None, skipped=False) -> dict:
+    return {
+        "tournament_id": item,
+        "tournament_code": item,
+        "success": success,
+        "error": error,
+        "skipped": skipped,
+    }
+
+
+def test_check_month_reads_summaries_merged_from_chunks(tmp_path):
+    root = tmp_path / "data"
+    _write_prod_summaries(
+        root,
+        "2025-01",
+        [
+            [_result("1"), _result("2")],
+            [_result("3"), _result("4", False, "report_updated_or_replaced", True)],
+        ],
+    )
+    summaries = read_run_summaries(root, "2025-01")
+    check_month(summaries)
+    details = summaries["tournament_details_summary.json"]
+    assert details["counts"] == {"total": 4, "success": 3, "skipped": 1, "failed": 0}
+    assert details["chunks"] == 2
+
+    root = tmp_path / "partial"
+    _write_prod_summaries(
+        root,
+        "2025-01",
+        [[_result("1")], [_result("2"), _result("3", False, "Timeout after 45s")]],
+    )
+    summaries = read_run_summaries(root, "2025-01")
+    with pytest.raises(PublishError, match="partial"):
+        check_month(summaries)
+    check_month(summaries, allow_partial=True)
@@ -0,0 +1,84 @@
+"""Unit tests for request spacing and achieved-rate tracking (rate_limiter.py)."""
+
+import pytest
+
+import rate_limiter
+from rate_limiter import RateLimiter
+
+
+class FakeClock:
+    """perf_counter/sleep pair; advance() stands in for fetch time."""
+
+    def __init__(self):
+        self.now = 100.0
+        self.slept = []
+
+    def perf_counter(self):
+        return self.now
+
+    def sleep(self, seconds):
+        self.slept.append(seconds)
+        self.now += seconds
+
+    def advance(self, seconds):
+        self.now += seconds
+
+
+@pytest.fixture
+def clock(monkeypatch):
+    fake = FakeClock()
+    monkeypatch.setattr(rate_limiter.time, "perf_counter", fake.perf_counter)
+    monkeypatch.setattr(rate_limiter.time, "sleep", fake.sleep)
+    return fake
+
+
+def test_spacing_and_limiter_bound_when_requests_are_fast(clock):
+    limiter = RateLimiter(0.5)
+    limiter.wait()
+    assert limiter.get_actual_rate() == 0.0
+    for _ in range(5):
+        clock.advance(0.5)
+        limiter.wait()
+    assert clock.slept == pytest.approx([1.5] * 5)
+    assert limiter.get_actual_rate() == pytest.approx(0.5)
+    assert limiter.limiter_bound()
+    assert limiter.describe() == "Rate: 0.50/s target, 0.50/s actual (limiter)"
+
+
+def test_server_bound_when_requests_are_slower_than_target(clock):
+    limiter = RateLimiter(1.0)
+    limiter.wait()
+    for _ in range(5):
+        clock.advance(4.0)
+        limiter.wait()
+    assert clock.slept == []
+    assert limiter.get_actual_rate() == pytest.approx(0.25)
+    assert not limiter.limiter_bound()
+    assert limiter.stats() == {
+        "target_per_s": 1.0,
+        "actual_per_s": 0.25,
+        "limiter_bound": False,
+    }
+
+
+def test_ewma_follows_rate_changes(clock):
+    limiter = RateLimiter(0, alpha=0.5)
+    limiter.wait()
+    clock.advance(1.0)
+    limiter.wait()
+    assert limiter.interval_ewma == pytest.approx(1.0)
+    clock.advance(3.0)
+    limiter.wait()
+    assert limiter.interval_ewma == pytest.approx(2.0)
+    assert limiter.get_actual_rate() == pytest.approx(0.5)
+
+
+def test_unlimited_never_sleeps(clock):
+    limiter = RateLimiter(0)
+    for _ in range(3):
+        limiter.wait()
+        clock.advance(0.1)
+    assert clock.slept == []
+    assert limiter.get_rate() == 0.0
+    assert not limiter.limiter_bound()
+    assert limiter.describe().startswith("Rate: unlimited target, 10.00/s actual")
@@ -0,0 +1,116 @@
+"""
+Tests for rating_input.py.
+
+Offline: forfeit/unplayed exclusion, per-period counts, rating period policies
+and game weights.
+"""
+
+import json
+
+import pandas as pd
+import pytest
+
+from rating_input import (
+    EXCLUDE_FORFEIT,
+    EXCLUDE_UNPLAYED,
+    PERIOD_RECEIVED,
+    UNKNOWN_PERIOD,
+    RatingInputPolicy,
+    apply_policy,
+    game_weights,
+    load_weights,
+)
+
+
+def _games():
+    return pd.DataFrame(
+        {
+            "white_player_id": ["1", "3", "5", "7", ""],
+            "black_player_id": ["2", "4", "6", "7", "8"],
+            "tournament_id": ["100"] * 5,
+            "round_number": [1, 1, 2, 3, 4],
+            "round_date": pd.to_datetime(
+                ["2025-01-05", "2025-01-05", "2025-02-01", None, "2025-02-02"]
+            ),
+            "score": [1.0, 0.5, 1.0, 0.0, 1.0],
+            "forfeit": ["", "", "+", "", ""],
+        }
+    )
+
+
+class TestApplyPolicy:
+    def test_default_excludes_forfeits_and_unplayed(self):
+        rated, counts = apply_policy(_games(), RatingInputPolicy())
+        assert list(rated["white_player_id"]) == ["1", "3"]
+        assert counts["2025-01"] == {
+            "games": 2,
+            "rated": 2,
+            EXCLUDE_FORFEIT: 0,
+            EXCLUDE_UNPLAYED: 0,
+        }
+        assert counts["2025-02"][EXCLUDE_FORFEIT] == 1
+        assert counts["2025-02"][EXCLUDE_UNPLAYED] == 1
+        assert counts[UNKNOWN_PERIOD][EXCLUDE_UNPLAYED] == 1
+
+    def test_include_forfeits(self):
+        rated, counts = apply_policy(_games(), RatingInputPolicy(include_forfeits=True))
+        assert list(rated["white_player_id"]) == ["1", "3", "5"]
+        assert counts["2025-02"][EXCLUDE_FORFEIT] == 0
+        assert counts["2025-02"]["rated"] == 1
+
+
+class TestRatingPeriod:
+    def test_played_period_is_round_date_month(self):
+        rated, _ = apply_policy(_games(), RatingInputPolicy())
+        assert list(rated["rating_period"]) == ["2025-01", "2025-01"]
+
+    def test_received_period_uses_details_with_fallback(self):
+        games = _games()
+        games.loc[1, "tournament_id"] = "200"
+        details = pd.DataFrame(
+            {
+                "success": [True],
+                "id": ["100"],
+                "date_received": pd.to_datetime(["2025-03-20"]),
+            }
+        )
+        policy = RatingInputPolicy(period=PERIOD_RECEIVED)
+        rated, counts = apply_policy(games, policy, details)
+        assert list(rated["rating_period"]) == ["2025-03", "2025-01"]
+        assert counts["2025-01"]["rated"] == 1
+        assert counts["2025-03"] == {
+            "games": 4,
+            "rated": 1,
+            EXCLUDE_FORFEIT: 1,
+            EXCLUDE_UNPLAYED: 2,
+        }
+
+    def test_received_period_needs_details(self):
+        with pytest.raises(ValueError, match="details"):
+            apply_policy(_games(), RatingInputPolicy(period=PERIOD_RECEIVED))
+
+
+class TestGameWeights:
+    def test_weights_multiply_per_attribute(self):
+        games = pd.DataFrame({"tournament_id": ["100", "200", "300", "400"]})
+        details = pd.DataFrame(
+            {
+                "success": [True, True, True, False],
+                "id": ["100", "200", "300", "400"],
+                "time_control": ["S", "R", "R", "B"],
+                "hybrid": [None, "Yes", None, None],
+            }
+        )
+        weights = {"time_control": {"r": 0.5, "b": 0.25}, "hybrid": {"yes": 0.5}}
+        assert list(game_weights(games, details, weights)) == [1.0, 0.25, 0.5, 1.0]
+
+    def test_load_weights_lowercases_and_validates(self, tmp_path):
+        path = tmp_path / "weights.json"
+        path.write_text(json.dumps({"time_control": {"R": 0.5}}))
+        assert load_weights(path) == {"time_control": {"r": 0.5}}
+        path.write_text(json.dumps({"rating": {"x": 1}}))
+        with pytest.raises(ValueError, match="unknown attribute"):
+            load_weights(path)
+        path.write_text(json.dumps({"type": {"team": -1}}))
+        with pytest.raises(ValueError, match=">= 0"):
+            load_weights(path)
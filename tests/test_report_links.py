@@ -0,0 +1,63 @@
+"""Tests for report_links.py: View Report hrefs sorted by kind, system and event."""
+
+import pytest
+
+from report_links import (
+    OTHER,
+    PAIRING_PROGRAM,
+    RATING_REPORT,
+    SOURCE_REPORT,
+    ReportLink,
+    parse_report_link,
+    report_url,
+)
+
+
+@pytest.mark.parametrize(
+    "href,kind,system,event",
+    [
+        ("report.phtml?event=368261", RATING_REPORT, "fide", "368261"),
+        ("/report.phtml?event=399495", RATING_REPORT, "fide", "399495"),
+        (
+            "https://ratings.fide.com/tournament_src_report.phtml?code=368261",
+            SOURCE_REPORT,
+            "fide",
+            "368261",
+        ),
+        (
+            "https://chess-results.com/tnr123456.aspx?lan=1",
+            PAIRING_PROGRAM,
+            "swiss-manager",
+            "123456",
+        ),
+        (
+            "http://s2.chess-results.com/tnr.aspx?tnr=98765",
+            PAIRING_PROGRAM,
+            "swiss-manager",
+            "98765",
+        ),
+        ("https://www.vegachess.com/ns/web/t/1", PAIRING_PROGRAM, "vega", None),
+        ("https://example.org/results?id=7", OTHER, "example.org", "7"),
+        ("calendar.phtml?id=5", OTHER, "fide", "5"),
+    ],
+)
+def test_parse_report_link(href, kind, system, event):
+    link = parse_report_link(href, "Rated for May 2024\xa0 as Standard ")
+    assert (link.kind, link.system, link.event) == (kind, system, event)
+    assert link.href == href
+    assert link.text == "Rated for May 2024 as Standard"
+
+
+def test_empty_href_is_no_link():
+    assert parse_report_link("") is None
+    assert parse_report_link(None, "text") is None
+
+
+def test_report_url_dispatch():
+    fide = parse_report_link("report.phtml?event=368261")
+    assert report_url(fide) == (
+        "https://ratings.fide.com/tournament_src_report.phtml?code=368261"
+    )
+    assert report_url(parse_report_link("https://chess-results.com/tnr1.aspx")) is None
+    assert report_url(ReportLink(RATING_REPORT, "fide", None, "report.phtml")) is None
+    assert report_url(None) is None
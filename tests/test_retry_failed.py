@@ -0,0 +1,98 @@
+"""Unit tests for retrying failed tournaments (retry_failed.py)."""
+
+import json
+import os
+
+import pandas as pd
+import pytest
+
+from retry_failed import (
+    atomic_write,
+    failed_details_ids,
+    merge_details,
+    merge_reports,
+    missing_report_codes,
+    read_failed_codes,
+)
+
+
+def test_failed_details_ids_skips_ids_that_succeeded_on_a_retry_pass():
+    df = pd.DataFrame(
+        {
+            "tournament_id": ["1", "2", "2", "3", "3"],
+            "success": [True, False, True, False, False],
+        }
+    )
+    assert failed_details_ids(df) == ["3"]
+
+
+def test_merge_details_replaces_retried_rows():
+    old = pd.DataFrame(
+        {
+            "tournament_id": ["1", "2"],
+            "success": [True, False],
+            "error": ["", "timeout: x"],
+            "n_players": [10.0, None],
+        }
+    )
+    new = pd.DataFrame(
+        {"tournament_id": ["2"], "success": [True], "error": [""], "n_players": [8]}
+    )
+    merged = merge_details(old, new)
+    assert list(merged["tournament_id"]) == ["1", "2"]
+    assert merged["success"].all()
+    assert merged["n_players"].dtype == "float64"
+
+
+class TestReadFailedCodes:
+    def test_failures_json(self, tmp_path):
+        path = tmp_path / "x_failures.json"
+        path.write_text(
+            json.dumps(
+                [
+                    {"tournament_code": "A1", "error": "HTTP 500"},
+                    {"tournament_id": "B2", "error": "timeout: x"},
+                    {"tournament_code": "A1", "error": "HTTP 500"},
+                ]
+            )
+        )
+        assert read_failed_codes(str(path)) == ["A1", "B2"]
+
+    def test_code_list(self, tmp_path):
+        path = tmp_path / "codes.txt"
+        path.write_text("A1\n\nB2\n")
+        assert read_failed_codes(str(path)) == ["A1", "B2"]
+
+
+def test_missing_report_codes():
+    players = pd.DataFrame({"tournament_id": ["A1", "A1", "C3"]})
+    assert missing_report_codes(["A1", "B2", "C3", "D4"], players) == ["B2", "D4"]
+
+
+def test_merge_reports_drops_stale_rows_for_recovered_codes():
+    old = pd.DataFrame({"tournament_id": ["A1", "B2"], "player_id": ["1", "2"]})
+    new = pd.DataFrame({"tournament_id": ["B2", "B2"], "player_id": ["2", "3"]})
+    merged = merge_reports(old, new, {"B2"})
+    assert list(merged["player_id"]) == ["1", "2", "3"]
+
+
+class TestAtomicWrite:
+    def test_replaces_file(self, tmp_path):
+        path = tmp_path / "out.parquet"
+        path.write_bytes(b"old")
+        atomic_write(str(path), b"new")
+        assert path.read_bytes() == b"new"
+        assert [p.name for p in tmp_path.iterdir()] == ["out.parquet"]
+
+    def test_failure_keeps_original(self, tmp_path, monkeypatch):
+        path = tmp_path / "out.parquet"
+        path.write_bytes(b"old")
+
+        def boom(src, dst):
+            raise OSError("disk full")
+
+        monkeypatch.setattr(os, "replace", boom)
+        with pytest.raises(OSError):
+            atomic_write(str(path), b"new")
+        assert path.read_bytes() == b"old"
+        assert [p.name for p in tmp_path.iterdir()] == ["out.parquet"]
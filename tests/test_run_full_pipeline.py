@@ -0,0 +1,125 @@
+"""
+Tests for scripts/run_full_pipeline.py.
+
+Offline: stage DAG ordering and make-style out-of-date planning on a temp tree.
+"""
+
+import argparse
+import os
+import sys
+from pathlib import Path
+
+import pytest
+
+sys.path.insert(0, str(Path(__file__).parent.parent / "scripts"))
+
+from run_full_pipeline import (
+    STAGES_BY_NAME,
+    RunContext,
+    parse_month,
+    plan,
+    required_stages,
+)
+
+
+def _names(stages):
+    return [s.name for s in stages]
+
+
+def _touch(path: Path, mtime: float) -> None:
+    path.parent.mkdir(parents=True, exist_ok=True)
+    path.write_text("x")
+    os.utime(path, (mtime, mtime))
+
+
+@pytest.fixture
+def ctx(tmp_path):
+    return RunContext(base_dir=tmp_path, year=2024, month=6)
+
+
+def _build_all(ctx: RunContext, mtime: float = 1000.0) -> None:
+    root = ctx.base_dir / "data"
+    _touch(root / "federations/data/federations_20240601-000000.csv", mtime)
+    _touch(root / "player_lists/data/player_list_20240601-000000.parquet", mtime)
+    for name in (
+        "tournament_ids.txt",
+        "tournament_details.parquet",
+        "tournament_reports_players.parquet",
+        "tournament_reports_games.parquet",
+        "rating_input_games.parquet",
+    ):
+        _touch(ctx.run_path("data", name), mtime)
+
+
+class TestRequiredStages:
+    def test_includes_transitive_dependencies_in_order(self):
+        assert _names(required_stages("rating_input")) == [
+            "federations",
+            "ids",
+            "details",
+            "players",
+            "reports",
+            "rating_input",
+        ]
+
+    def test_first_stage_needs_nothing_else(self):
+        assert _names(required_stages("federations")) == ["federations"]
+
+
+class TestPlan:
+    def test_empty_tree_runs_everything_required(self, ctx):
+        steps = plan("details", ctx)
+        assert [s.name for s, _ in steps] == ["federations", "ids", "details"]
+        assert steps[0][1].startswith("missing")
+
+    def test_up_to_date_tree_runs_nothing(self, ctx):
+        _build_all(ctx)
+        assert plan("rating_input", ctx) == []
+
+    def test_newer_input_reruns_downstream_stages(self, ctx):
+        _build_all(ctx)
+        _touch(ctx.run_path("data", "tournament_details.parquet"), 2000.0)
+        steps = plan("rating_input", ctx)
+        assert [s.name for s, _ in steps] == ["reports", "rating_input"]
+        assert steps[0][1] == "older than details outputs"
+        assert steps[1][1] == "reports reruns"
+
+    def test_newer_shared_list_is_order_only(self, ctx):
+        _build_all(ctx)
+        newer = "player_lists/data/player_list_20240701-000000.parquet"
+        _touch(ctx.base_dir / "data" / newer, 2000.0)
+        assert plan("rating_input", ctx) == []
+
+    def test_force_and_skip(self, ctx):
+        _build_all(ctx)
+        steps = plan("reports", ctx, force=True, skip={"federations", "players"})
+        assert [(s.name, r) for s, r in steps] == [
+            ("ids", "forced"),
+            ("details", "forced"),
+            ("reports", "forced"),
+        ]
+
+
+    def test_validation_report_under_local_root(self, tmp_path):
+        ctx = RunContext(base_dir=tmp_path, year=2024, month=6, local_root="alt")
+        report = tmp_path / "alt" / "validation_reports" / "2024_06.txt"
+        stage = STAGES_BY_NAME["validate"]
+        assert stage.outputs(ctx) == [report]
+        cmd = stage.command(ctx)
+        assert cmd[cmd.index("--output") + 1] == str(report)
+
+    def test_player_list_download_is_kept_for_resume(self, ctx):
+        cmd = STAGES_BY_NAME["players"].command(ctx)
+        i = cmd.index("--download-dir")
+        assert cmd[i + 1] == str(ctx.base_dir / "data" / "player_lists" / "download")
+
+
+class TestParseMonth:
+    def test_plain_and_year_month(self):
+        assert parse_month("6") == (None, 6)
+        assert parse_month("2024-06") == (2024, 6)
+
+    @pytest.mark.parametrize("bad", ["June", "2024-13", "2024-06-01"])
+    def test_invalid(self, bad):
+        with pytest.raises(argparse.ArgumentTypeError):
+            parse_month(bad)
@@ -0,0 +1,67 @@
+"""
+Tests for scripts/run_prod_backfill.py.
+
+Offline: active-hours window parsing, argument checks.
+"""
+
+import argparse
+import sys
+from datetime import time
+from pathlib import Path
+
+import pytest
+
+sys.path.insert(0, str(Path(__file__).parent.parent / "scripts"))
+
+from run_prod_backfill import (
+    in_active_window,
+    main,
+    parse_active_hours,
+    per_chunk_rate_limit,
+)
+
+
+class TestActiveHours:
+    def test_parse(self):
+        assert parse_active_hours("00:00-06:00") == (time(0, 0), time(6, 0))
+
+    @pytest.mark.parametrize("bad", ["", "00:00", "25:00-06:00", "06:00-06:00"])
+    def test_parse_invalid(self, bad):
+        with pytest.raises(argparse.ArgumentTypeError):
+            parse_active_hours(bad)
+
+    def test_no_window_always_active(self):
+        assert in_active_window(time(12, 0), None)
+
+    def test_same_day_window(self):
+        window = (time(0, 0), time(6, 0))
+        assert in_active_window(time(0, 0), window)
+        assert in_active_window(time(5, 59), window)
+        assert not in_active_window(time(6, 0), window)
+        assert not in_active_window(time(23, 0), window)
+
+    def test_window_wraps_midnight(self):
+        window = (time(22, 0), time(4, 0))
+        assert in_active_window(time(23, 30), window)
+        assert in_active_window(time(3, 59), window)
+        assert not in_active_window(time(4, 0), window)
+        assert not in_active_window(time(12, 0), window)
+
+
+class TestPerChunkRateLimit:
+    def test_splits_budget_across_executions_and_chunks(self):
+        assert per_chunk_rate_limit(2.0, 4, 5) == pytest.approx(0.1)
+
+    def test_single_execution_single_chunk_gets_full_budget(self):
+        assert per_chunk_rate_limit(0.5, 1, 1) == 0.5
+
+
+class TestArguments:
+    def test_rejects_max_concurrency_below_one(self, monkeypatch):
+        monkeypatch.setattr(
+            sys,
+            "argv",
+            ["run_prod_backfill.py", "--start", "2024-01", "--end", "2024-01"]
+            + ["--max-concurrency", "0"],
+        )
+        assert main() == 1
@@ -0,0 +1,228 @@
+"""Unit tests for exit codes and the final summary JSON (run_summary.py)."""
+
+import io
+import json
+
+import pytest
+
+from run_summary import (
+    EXIT_FATAL,
+    EXIT_PARTIAL,
+    EXIT_SUCCESS,
+    build_summary,
+    classify_failures,
+    exit_code_for,
+    failure_class,
+    format_failure_groups,
+    group_failures,
+    latest_results,
+    merge_summaries,
+    summary_path,
+    use_color,
+    write_summary,
+)
+
+
+class TestFailureClass:
+    @pytest.mark.parametrize(
+        "error,cls",
+        [
+            ("timeout: read timed out", "timeout"),
+            ("max retries exceeded: timeout: read timed out", "timeout"),
+            ("network error: ('Connection aborted.', ...)", "network"),
+            ("connection error: HTTPSConnectionPool(...)", "network"),
+            ("max retries exceeded: HTTP 503", "http"),
+            ("no data found", "no_data"),
+            ("no players found", "no_data"),
+            ("report_updated_or_replaced", "report_replaced"),
+            ("pairing_program_report", "pairing_program"),
+            ("parse error: list index out of range", "parse"),
+            ("fetch failed", "other"),
+            (None, "other"),
+        ],
+    )
+    def test_classes(self, error, cls):
+        assert failure_class(error) == cls
+
+    def test_counts_largest_first(self):
+        counts = classify_failures(["HTTP 404", "timeout: x", "HTTP 500"])
+        assert counts == {"http": 2, "timeout": 1}
+        assert list(counts) == ["http", "timeout"]
+
+
+class TestExitCode:
+    def test_all_succeeded(self):
+        assert exit_code_for(10, 0) == EXIT_SUCCESS
+
+    def test_some_failed(self):
+        assert exit_code_for(9, 1) == EXIT_PARTIAL
+
+    def test_none_succeeded(self):
+        assert exit_code_for(0, 3) == EXIT_FATAL
+
+    def test_empty_run_is_success(self):
+        assert exit_code_for(0, 0) == EXIT_SUCCESS
+
+
+def test_latest_results_keeps_retry_outcome():
+    results = [
+        {"tournament_id": "1", "success": False, "error": "timeout: x"},
+        {"tournament_id": "2", "success": True},
+        {"tournament_id": "1", "success": True},
+    ]
+    final = latest_results(results, "tournament_id")
+    assert len(final) == 2
+    assert all(r["success"] for r in final)
+
+
+class TestFailureGroups:
+    RESULTS = [
+        {"tournament_id": "1", "success": False, "error": "timeout: a"},
+        {"tournament_id": "2", "success": False, "error": "HTTP 404"},
+        {"tournament_id": "3", "success": True},
+        {"tournament_id": "4", "success": False, "error": "timeout: b"},
+        {"tournament_id": "5", "success": False, "error": "timeout: c"},
+        {"tournament_id": "6", "success": False, "error": "timeout: d"},
+    ]
+
+    def test_group_largest_first_with_three_examples(self):
+        groups = group_failures(self.RESULTS, "tournament_id")
+        assert groups == {
+            "timeout": {"count": 4, "examples": ["1", "4", "5"]},
+            "http": {"count": 1, "examples": ["2"]},
+        }
+        assert list(groups) == ["timeout", "http"]
+
+    def test_format_plain_and_colored(self):
+        groups = group_failures(self.RESULTS, "tournament_id")
+        assert format_failure_groups(groups) == [
+            "  timeout: 4 (e.g. 1, 4, 5)",
+            "  http: 1 (e.g. 2)",
+        ]
+        colored = format_failure_groups(groups, color=True)
+        assert colored[0] == "  \033[33mtimeout: 4\033[0m (e.g. 1, 4, 5)"
+        assert colored[1].startswith("  \033[31mhttp: 1")
+
+    def test_use_color(self, monkeypatch):
+        monkeypatch.delenv("NO_COLOR", raising=False)
+        assert not use_color(io.StringIO())
+
+        class Tty(io.StringIO):
+            def isatty(self):
+                return True
+
+        assert use_color(Tty())
+        monkeypatch.setenv("NO_COLOR", "1")
+        assert not use_color(Tty())
+
+
+class TestBuildSummary:
+    def test_counts_and_outputs(self):
+        results = [
+            {"success": True},
+            {"success": False, "error": "HTTP 404"},
+        ]
+        summary = build_summary(
+            "tournament_details",
+            EXIT_PARTIAL,
+            results,
+            outputs={"parquet": "out.parquet", "json_sample": None},
+        )
+        assert summary["command"] == "tournament_details"
+        assert summary["status"] == "partial"
+        assert summary["exit_code"] == EXIT_PARTIAL
+        assert summary["counts"] == {
+            "total": 2,
+            "success": 1,
+            "skipped": 0,
+            "failed": 1,
+        }
+        assert summary["failure_classes"] == {"http": 1}
+        assert summary["outputs"] == {"parquet": "out.parquet"}
+        assert summary["error"] is None
+        assert "failure_groups" not in summary
+
+    def test_failure_groups_with_key(self):
+        results = [{"tournament_code": "9", "success": False, "error": "HTTP 500"}]
+        summary = build_summary(
+            "tournament_reports", EXIT_FATAL, results, key="tournament_code"
+        )
+        assert summary["failure_groups"] == {"http": {"count": 1, "examples": ["9"]}}
+
+    def test_skipped_are_not_failures(self):
+        results = [
+            {"tournament_code": "1", "success": True},
+            {
+                "tournament_code": "2",
+                "success": False,
+                "skipped": True,
+                "error": "report_updated_or_replaced",
+            },
+            {
+                "tournament_code": "3",
+                "success": False,
+                "skipped": True,
+                "error": "no players found",
+            },
+        ]
+        summary = build_summary(
+            "tournament_reports", EXIT_SUCCESS, results, key="tournament_code"
+        )
+        assert summary["counts"] == {
+            "total": 3,
+            "success": 1,
+            "skipped": 2,
+            "failed": 0,
+        }
+        assert summary["failure_classes"] == {}
+        assert summary["skipped_classes"] == {"report_replaced": 1, "no_data": 1}
+        assert summary["failure_groups"] == {}
+
+    def test_fatal_with_message(self):
+        summary = build_summary("tournament_reports", EXIT_FATAL, error="No codes")
+        assert summary["status"] == "fatal"
+        assert summary["counts"]["total"] == 0
+        assert summary["error"] == "No codes"
+
+
+def test_write_summary_round_trip(tmp_path):
+    path = summary_path(str(tmp_path / "reports" / "tournament_details"))
+    assert path.endswith("tournament_details_summary.json")
+    summary = build_summary("tournament_details", EXIT_SUCCESS, [{"success": True}])
+    write_summary(summary, path)
+    with open(path, encoding="utf-8") as f:
+        assert json.load(f) == summary
+
+
+class TestMergeSummaries:
+    def _chunk(self, code, ok, failed_errors=()):
+        results = [{"id": str(i), "success": True} for i in range(ok)]
+        results += [{"id": "x", "success": False, "error": e} for e in failed_errors]
+        return build_summary("tournament_details", code, results, key="id")
+
+    def test_adds_up_chunks(self):
+        merged = merge_summaries(
+            [
+                self._chunk(EXIT_SUCCESS, 3),
+                self._chunk(EXIT_PARTIAL, 2, ["timeout", "HTTP 500"]),
+            ],
+            "tournament_details",
+            expected=2,
+            outputs={"parquet": "s3://b/k.parquet"},
+        )
+        assert merged["exit_code"] == EXIT_PARTIAL
+        assert merged["counts"] == {"total": 7, "success": 5, "skipped": 0, "failed": 2}
+        assert merged["failure_classes"] == {"timeout": 1, "http": 1}
+        assert merged["failure_groups"]["timeout"]["count"] == 1
+        assert merged["outputs"] == {"parquet": "s3://b/k.parquet"}
+
+    def test_exit_codes(self):
+        ok = self._chunk(EXIT_SUCCESS, 1)
+        fatal = self._chunk(EXIT_FATAL, 0, ["timeout"])
+        assert merge_summaries([ok, ok], "c", expected=2)["exit_code"] == EXIT_SUCCESS
+        assert merge_summaries([fatal, fatal], "c")["exit_code"] == EXIT_FATAL
+        assert merge_summaries([ok, fatal], "c")["exit_code"] == EXIT_PARTIAL
+        assert merge_summaries([], "c", expected=2)["exit_code"] == EXIT_FATAL
+        missing = merge_summaries([ok], "c", expected=2)
+        assert missing["exit_code"] == EXIT_PARTIAL
+        assert missing["error"] == "1 of 2 chunk summaries missing"
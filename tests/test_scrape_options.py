@@ -0,0 +1,56 @@
+"""Tests for the chunk Lambdas' scraper safeguards (handlers/scrape_options.py)."""
+
+import sys
+from pathlib import Path
+
+import pytest
+
+REPO_ROOT = Path(__file__).resolve().parent.parent
+sys.path.insert(0, str(REPO_ROOT))
+
+import anomaly  # noqa: E402
+import circuit_breaker  # noqa: E402
+from handlers import scrape_options  # noqa: E402
+
+
+@pytest.fixture(autouse=True)
+def _reset_anomaly():
+    yield
+    anomaly.disable("details")
+
+
+def test_setup_defaults():
+    options = scrape_options.setup("details", {}, "fide-glicko", "prod", "2024-01", 3)
+    breaker = options["breaker"]
+    assert isinstance(breaker, circuit_breaker.CircuitBreaker)
+    assert breaker.cooldown == scrape_options.LAMBDA_BREAKER_COOLDOWN
+    assert breaker.max_trips == scrape_options.LAMBDA_BREAKER_MAX_TRIPS
+    assert options["control_file"] == (
+        "s3://fide-glicko/prod/2024-01/control/details.json"
+    )
+    assert options["control_poll"] == 30.0
+    monitor = anomaly._monitors["details"]
+    assert monitor.pause_seconds == scrape_options.LAMBDA_ANOMALY_PAUSE
+
+
+def test_setup_event_overrides():
+    event = {
+        "circuit_breaker": False,
+        "anomaly_check": False,
+        "control_file": "s3://b/control.json",
+        "control_poll": "10",
+    }
+    options = scrape_options.setup("details", event, "b", "test", None, 0)
+    assert options["breaker"] is None
+    assert "details" not in anomaly._monitors
+    assert options["control_file"] == "s3://b/control.json"
+    assert options["control_poll"] == 10.0
+
+
+@pytest.mark.parametrize(
+    "event",
+    [{"breaker_cooldown": "soon"}, {"breaker_error_ratio": 2}, {"anomaly_pause": True}],
+)
+def test_setup_rejects_invalid_values(event):
+    with pytest.raises(ValueError):
+        scrape_options.setup("details", event, "b", "test", None, 0)
@@ -0,0 +1,73 @@
+"""
+Tests for scripts/selftest.py.
+
+Offline: field checks, the zip header check against a mocked Range response, and
+a failing check not stopping the others. The live checks run from the script.
+"""
+
+import io
+import sys
+import zipfile
+from pathlib import Path
+from unittest.mock import MagicMock
+
+sys.path.insert(0, str(Path(__file__).parent.parent / "src" / "scraper"))
+sys.path.insert(0, str(Path(__file__).parent.parent / "scripts"))
+
+import pytest
+
+import selftest
+from selftest import field_errors, run_checks, zip_first_entry
+
+
+def _zip_bytes(name: str) -> bytes:
+    buf = io.BytesIO()
+    with zipfile.ZipFile(buf, "w") as zf:
+        zf.writestr(name, "<playerslist>" + "x" * 2000 + "</playerslist>")
+    return buf.getvalue()
+
+
+def test_field_errors():
+    expected = {"name": "A", "fed": None, "byear": 1990}
+    assert field_errors({"name": "A", "fed": "NOR", "byear": 1990}, expected) == []
+    assert field_errors(
+        {"name": "B", "fed": "", "byear": 1990}, expected
+    ) == ["name='B', expected 'A'", "fed missing"]
+    assert field_errors(None, expected) == ["nothing parsed"]
+
+
+def test_zip_first_entry():
+    assert zip_first_entry(_zip_bytes("players_list_xml_foa.xml")[:1024]) == (
+        "players_list_xml_foa.xml"
+    )
+    with pytest.raises(ValueError, match="not a zip"):
+        zip_first_entry(b"<html>maintenance</html>")
+
+
+def test_player_list_check_reads_only_the_header():
+    response = MagicMock(status_code=206, headers={"Content-Range": "bytes 0-1023/2"})
+    response.iter_content.return_value = iter([_zip_bytes("players.xml")[:1024]])
+    session = MagicMock()
+    session.get.return_value = response
+
+    (result,) = run_checks(["player_list"], session)
+
+    assert result.ok, result.detail
+    assert result.detail.startswith("players.xml")
+    assert session.get.call_args.kwargs["headers"]["Range"] == "bytes=0-1023"
+    response.close.assert_called_once()
+
+
+def test_failed_check_does_not_stop_the_rest(monkeypatch):
+    def boom(session):
+        raise ValueError("HTTP 503")
+
+    monkeypatch.setitem(selftest.CHECKS, "details", boom)
+    monkeypatch.setitem(selftest.CHECKS, "profile", lambda session: "ok")
+
+    results = run_checks(["details", "profile"], MagicMock())
+
+    assert [(r.check, r.ok, r.detail) for r in results] == [
+        ("details", False, "HTTP 503"),
+        ("profile", True, "ok"),
+    ]
@@ -0,0 +1,74 @@
+"""
+Tests for sex_consistency.py.
+
+Offline: changes between two player lists, problems within one list, and a scan
+over snapshot files.
+"""
+
+import pandas as pd
+
+from provenance import dataframe_to_parquet_bytes
+from sex_consistency import check_snapshots, list_problems, sex_changes
+
+
+def _list(rows):
+    return pd.DataFrame(rows, columns=["id", "name", "sex", "w_title"])
+
+
+PREV = _list(
+    [
+        (1, "A", "M", None),
+        (2, "B", "F", "WGM"),
+        (3, "C", "F", None),
+        (4, "D", None, None),
+        (5, "E", "m", None),
+        (6, "F", "M", None),
+    ]
+)
+CURR = _list(
+    [
+        (1, "A", "M", None),
+        (2, "B", "F", "WGM"),
+        (3, "C", "M", None),
+        (4, "D", "F", None),
+        (5, "E", None, None),
+        (7, "G", "x", "WFM"),
+    ]
+)
+
+
+def test_sex_changes():
+    changes = sex_changes(PREV, CURR)
+    assert changes.sort_values("id").values.tolist() == [
+        ["3", "C", "changed", "F", "M"],
+        ["4", "D", "set", None, "F"],
+        ["5", "E", "cleared", "M", None],
+    ]
+
+
+def test_lowercase_is_not_a_change():
+    prev = _list([(1, "A", "f", None)])
+    curr = _list([(1, "A", "F", None)])
+    assert sex_changes(prev, curr).empty
+
+
+def test_list_problems():
+    problems = list_problems(CURR)
+    assert problems[["id", "flag", "sex_after"]].values.tolist() == [
+        ["7", "invalid", "X"]
+    ]
+    men_with_w_title = list_problems(_list([(8, "H", "M", "WIM")]))
+    assert men_with_w_title["flag"].tolist() == ["w_title_not_f"]
+
+
+def test_check_snapshots(tmp_path):
+    for ts, df in (("20250101-000000", PREV), ("20250201-000000", CURR)):
+        path = tmp_path / f"player_list_{ts}.parquet"
+        path.write_bytes(dataframe_to_parquet_bytes(df))
+    flags = check_snapshots(tmp_path)
+    assert sorted(flags["flag"]) == ["changed", "cleared", "invalid", "set"]
+    changed = flags[flags["flag"] == "changed"].iloc[0]
+    assert (changed["list"], changed["prev_list"]) == (
+        "20250201-000000",
+        "20250101-000000",
+    )
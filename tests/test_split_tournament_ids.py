@@ -0,0 +1,112 @@
+"""Unit tests for streaming, resumable ID splitting (split_tournament_ids.py)."""
+
+import json
+
+import pytest
+
+import split_tournament_ids
+from s3_io import iter_lines
+from split_tournament_ids import SPLIT_PROGRESS_KEY, chunk_sizes, even_split, run
+
+
+def _ids_file(tmp_path, ids):
+    path = tmp_path / "prod" / "2024-01" / "data" / "tournament_ids.txt"
+    path.parent.mkdir(parents=True)
+    path.write_text("\n".join(ids) + "\n", encoding="utf-8")
+    return path
+
+
+def _chunk_ids(chunk):
+    with open(chunk["input_path"], encoding="utf-8") as f:
+        return [line.strip() for line in f if line.strip()]
+
+
+def test_iter_lines_offsets_resume_after_a_line(tmp_path):
+    path = tmp_path / "ids.txt"
+    path.write_bytes(b"1\r\n22\n\n333")
+    lines = list(iter_lines(str(path)))
+    assert lines == [("1", 3), ("22", 6), ("", 7), ("333", 10)]
+    assert list(iter_lines(str(path), start=6)) == [("", 7), ("333", 10)]
+    assert list(iter_lines(str(path), start=10)) == []
+
+
+class TestChunkSizes:
+    def test_even_with_larger_first(self):
+        assert chunk_sizes(10, 3) == [4, 3, 3]
+
+    def test_more_chunks_than_items(self):
+        assert chunk_sizes(2, 4) == [1, 1, 0, 0]
+
+    def test_matches_even_split(self):
+        items = [str(i) for i in range(11)]
+        assert [len(c) for c in even_split(items, 4)] == chunk_sizes(11, 4)
+
+    def test_invalid(self):
+        with pytest.raises(ValueError):
+            chunk_sizes(5, 0)
+
+
+def test_run_streams_chunks_and_records_progress(tmp_path):
+    ids = [str(100 + i) for i in range(7)]
+    ids_path = _ids_file(tmp_path, ids)
+    chunks = run(str(ids_path), chunk_size=3)
+    assert [c["tournament_count"] for c in chunks] == [3, 2, 2]
+    assert sum((_chunk_ids(c) for c in chunks), []) == ids
+    assert chunks[0]["output_path"].endswith("details_chunk_0_of_3")
+
+    meta = json.loads((ids_path.parent.parent / "run_metadata.json").read_text())
+    progress = meta[SPLIT_PROGRESS_KEY]
+    assert progress["next_chunk"] == 3
+    assert progress["offset"] == ids_path.stat().st_size
+
+
+def test_run_resumes_from_recorded_offset(tmp_path, monkeypatch):
+    ids = [str(100 + i) for i in range(6)]
+    ids_path = _ids_file(tmp_path, ids)
+
+    writes = []
+    real_write = split_tournament_ids._write_chunk
+
+    def failing_write(content, path):
+        if len(writes) == 1:
+            raise OSError("interrupted")
+        writes.append(path)
+        real_write(content, path)
+
+    monkeypatch.setattr(split_tournament_ids, "_write_chunk", failing_write)
+    with pytest.raises(OSError):
+        run(str(ids_path), chunk_count=3)
+    monkeypatch.setattr(split_tournament_ids, "_write_chunk", real_write)
+
+    starts = []
+    real_iter = split_tournament_ids.iter_lines
+
+    def recording_iter(path, start=0):
+        starts.append(start)
+        return real_iter(path, start)
+
+    monkeypatch.setattr(split_tournament_ids, "iter_lines", recording_iter)
+    chunks = run(str(ids_path), chunk_count=3)
+    # No count pass: the total comes from the recorded progress
+    assert starts == [len("100\n101\n")]
+    assert [_chunk_ids(c) for c in chunks] == [ids[0:2], ids[2:4], ids[4:6]]
+
+
+def test_run_starts_over_when_the_file_changed(tmp_path):
+    ids_path = _ids_file(tmp_path, [str(100 + i) for i in range(4)])
+    run(str(ids_path), chunk_count=2)
+
+    ids = [str(200 + i) for i in range(6)]
+    ids_path.write_text("\n".join(ids) + "\n", encoding="utf-8")
+    chunks = run(str(ids_path), chunk_count=2)
+    assert [_chunk_ids(c) for c in chunks] == [ids[0:3], ids[3:6]]
+    meta = json.loads((ids_path.parent.parent / "run_metadata.json").read_text())
+    assert meta[SPLIT_PROGRESS_KEY]["total"] == 6
+    assert meta[SPLIT_PROGRESS_KEY]["offset"] == ids_path.stat().st_size
+
+
+def test_run_more_chunks_than_ids_writes_empty_chunks(tmp_path):
+    ids_path = _ids_file(tmp_path, ["1", "2"])
+    chunks = run(str(ids_path), chunk_count=3)
+    assert [c["tournament_count"] for c in chunks] == [1, 1, 0]
+    assert _chunk_ids(chunks[2]) == []
@@ -0,0 +1,37 @@
+"""Unit tests for timestamp conventions (timestamps.py)."""
+
+from datetime import date, datetime, timedelta, timezone
+
+from timestamps import fide_date, utc_compact_stamp, utc_timestamp
+
+
+class TestUtcTimestamps:
+    def test_offset_aware_input_converted_to_z(self):
+        dt = datetime(2025, 1, 31, 23, 30, tzinfo=timezone(timedelta(hours=-5)))
+        assert utc_timestamp(dt) == "2025-02-01T04:30:00Z"
+        assert utc_compact_stamp(dt) == "20250201T043000Z"
+
+    def test_now_is_utc(self):
+        assert utc_timestamp().endswith("Z")
+
+
+class TestFideDate:
+    def test_naive_date_becomes_utc_midnight(self):
+        assert fide_date(datetime(2024, 1, 31)) == datetime(
+            2024, 1, 31, tzinfo=timezone.utc
+        )
+        assert fide_date(date(2024, 1, 31)).tzinfo == timezone.utc
+
+    def test_month_end_date_stays_in_month(self):
+        d = fide_date(datetime(2024, 1, 31))
+        assert d.astimezone(timezone.utc).strftime("%Y-%m") == "2024-01"
+
+    def test_aware_value_keeps_published_day(self):
+        pst = timezone(timedelta(hours=-8))
+        late_evening = datetime(2024, 1, 31, 23, 0, tzinfo=pst)
+        assert fide_date(late_evening).date() == date(2024, 1, 31)
+
+    def test_missing(self):
+        assert fide_date(None) is None
+        assert fide_date(float("nan")) is None
+        assert fide_date("2024-01-31") is None
@@ -0,0 +1,47 @@
+"""
+Tests for tracing.py.
+
+Offline: tracing stays off (and span() is a no-op) without an endpoint or without
+the OpenTelemetry packages.
+"""
+
+import argparse
+import builtins
+
+import tracing
+
+
+def test_off_without_endpoint(monkeypatch):
+    monkeypatch.delenv(tracing.ENDPOINT_ENV, raising=False)
+    assert tracing.configure("test") is False
+    assert not tracing.enabled()
+    with tracing.span("work", item="1") as s:
+        assert s is None
+    tracing.set_attributes(s, error="ignored")
+
+
+def test_off_when_opentelemetry_missing(monkeypatch):
+    real_import = builtins.__import__
+
+    def no_otel(name, *args, **kwargs):
+        if name.startswith("opentelemetry"):
+            raise ImportError(name)
+        return real_import(name, *args, **kwargs)
+
+    monkeypatch.setattr(builtins, "__import__", no_otel)
+    assert tracing.configure("test", "http://localhost:4318") is False
+    assert not tracing.enabled()
+
+
+def test_child_env_unchanged_when_off(monkeypatch):
+    monkeypatch.delenv(tracing.TRACEPARENT_ENV, raising=False)
+    env = tracing.child_env({"A": "1"})
+    assert env == {"A": "1"}
+
+
+def test_add_arguments():
+    parser = argparse.ArgumentParser()
+    tracing.add_arguments(parser)
+    args = parser.parse_args(["--otlp-endpoint", "http://collector:4318"])
+    assert args.otlp_endpoint == "http://collector:4318"
+    assert parser.parse_args([]).otlp_endpoint is None
@@ -0,0 +1,65 @@
+"""Unit tests for browser identity selection (user_agents.py)."""
+
+import json
+
+import pytest
+
+import user_agents
+
+
+@pytest.fixture(autouse=True)
+def reset_config():
+    user_agents.configure()
+    yield
+    user_agents.configure()
+
+
+class TestIdentityForWorker:
+    def test_default_worker_keeps_original_chrome_identity(self):
+        headers = user_agents.browser_headers()
+        assert "X11; Linux x86_64" in headers["User-Agent"]
+        assert "Chrome/120" in headers["User-Agent"]
+
+    def test_workers_round_robin_over_pool(self):
+        pool = user_agents.DEFAULT_IDENTITIES
+        agents = [
+            user_agents.identity_for_worker(w)["User-Agent"] for w in range(len(pool))
+        ]
+        assert len(set(agents)) == len(pool)
+        assert user_agents.identity_for_worker(len(pool)) == pool[0]
+
+    def test_headers_stay_consistent_with_user_agent(self):
+        for identity in user_agents.DEFAULT_IDENTITIES:
+            ua = identity["User-Agent"]
+            if "Firefox" in ua:
+                assert "image/avif" in identity["Accept"]
+            elif "Chrome" in ua:
+                assert identity["Accept"] == user_agents._CHROME_ACCEPT
+
+    def test_extra_headers_do_not_mutate_pool(self):
+        headers = user_agents.browser_headers({"Connection": "close"})
+        assert headers["Connection"] == "close"
+        assert "Connection" not in user_agents.DEFAULT_IDENTITIES[0]
+
+
+class TestLoadIdentities:
+    def test_file_pool_used_for_worker(self, tmp_path):
+        path = tmp_path / "agents.json"
+        pool = [
+            {"User-Agent": "UA-0", "Accept-Language": "de"},
+            {"User-Agent": "UA-1", "Accept-Language": "fr"},
+        ]
+        path.write_text(json.dumps(pool))
+
+        user_agents.configure(user_agents.load_identities(path), worker=3)
+
+        assert user_agents.browser_headers() == pool[1]
+
+    @pytest.mark.parametrize(
+        "pool", [[], {"User-Agent": "x"}, [{"Accept": "x"}], [{"User-Agent": 1}]]
+    )
+    def test_invalid_pool_rejected(self, tmp_path, pool):
+        path = tmp_path / "agents.json"
+        path.write_text(json.dumps(pool))
+        with pytest.raises(ValueError):
+            user_agents.load_identities(path)